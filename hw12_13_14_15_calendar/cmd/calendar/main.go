@@ -3,9 +3,13 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
+	// базы часовых поясов может не быть в образе, а она нужна для рабочего времени пользователей.
+	_ "time/tzdata"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app"
 	config "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/calendar"
@@ -15,6 +19,10 @@ var configFile string
 
 func init() {
 	flag.StringVar(&configFile, "config", "/etc/calendar/config.json", "Path to configuration file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [version | migrate up|down|status]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 }
 
 func main() {
@@ -29,8 +37,14 @@ func main() {
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	app.Execute(ctx, app.NewCalendar(cfg))
+	if flag.Arg(0) == "migrate" {
+		if err = app.Migrate(ctx, cfg, flag.Arg(1), os.Stdout); err != nil {
+			log.Fatal(err) //nolint:gocritic // контекст освобождается вместе с процессом
+		}
+		return
+	}
+	app.Execute(ctx, app.NewCalendar(cfg, configFile))
 }
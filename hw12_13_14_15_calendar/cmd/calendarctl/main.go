@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/ctl"
+)
+
+// configEnv переменная окружения с путем к файлу профилей.
+const configEnv = "CALENDARCTL_CONFIG"
+
+func main() {
+	configFile := os.Getenv(configEnv)
+	if configFile == "" {
+		configFile = ctl.DefaultConfigFile()
+	}
+	env := ctl.NewEnv(os.Stdout, os.Stderr, configFile)
+	env.Version = versionString()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := ctl.Run(ctx, env, os.Args[1:])
+	cancel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ошибка:", err)
+		if errors.Is(err, ctl.ErrUsage) || errors.Is(err, ctl.ErrUnknownCommand) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import "fmt"
+
+var (
+	release   = "UNKNOWN"
+	buildDate = "UNKNOWN"
+	gitHash   = "UNKNOWN"
+)
+
+func versionString() string {
+	return fmt.Sprintf("%s (%s, %s)", release, gitHash, buildDate)
+}
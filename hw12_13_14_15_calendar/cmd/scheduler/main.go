@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -15,6 +17,10 @@ var configFile string
 
 func init() {
 	flag.StringVar(&configFile, "config", "/etc/scheduler/config.json", "Path to configuration file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [version | jobs]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 }
 
 func main() {
@@ -27,10 +33,16 @@ func main() {
 	if err != nil {
 		log.Fatalf("error reading configuaration from '%s': %v", configFile, err)
 	}
+	if flag.Arg(0) == "jobs" {
+		if err = app.PrintJobs(cfg, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	app.Execute(ctx, app.NewScheduler(cfg))
+	app.Execute(ctx, app.NewScheduler(cfg, configFile))
 }
@@ -29,8 +29,8 @@ func main() {
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	app.Execute(ctx, app.NewSender(cfg))
+	app.Execute(ctx, app.NewSender(cfg, configFile))
 }
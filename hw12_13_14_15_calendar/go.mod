@@ -3,23 +3,28 @@ module github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar
 go 1.16
 
 require (
+	github.com/andybalholm/brotli v1.0.5
 	github.com/benbjohnson/clock v1.3.0
 	github.com/cenkalti/backoff/v3 v3.2.2
-	github.com/felixge/httpsnoop v1.0.1
-	github.com/google/uuid v1.3.0
+	github.com/felixge/httpsnoop v1.0.3
+	github.com/google/uuid v1.5.0
 	github.com/gorilla/mux v1.8.0
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.15.0
 	github.com/jackc/pgx/v4 v4.17.2
-	github.com/kr/pretty v0.2.1 // indirect
 	github.com/leporo/sqlf v1.3.0
 	github.com/lib/pq v1.10.6 // indirect
 	github.com/mattn/go-sqlite3 v1.14.14 // indirect
+	github.com/oapi-codegen/runtime v1.1.1
 	github.com/pkg/errors v0.9.1
+	github.com/pressly/goose/v3 v3.5.3
 	github.com/shopspring/decimal v1.3.1 // indirect
 	github.com/sirupsen/logrus v1.9.0
 	github.com/streadway/amqp v1.0.0
-	github.com/stretchr/testify v1.8.0
+	github.com/stretchr/testify v1.8.4
 	github.com/t-tomalak/logrus-easy-formatter v0.0.0-20190827215021-c074f06c5816
-	golang.org/x/sys v0.0.0-20220811171246-fbc7d0a398ab // indirect
+	google.golang.org/genproto v0.0.0-20221207170731-23e4bf6bdc37
 	google.golang.org/grpc v1.51.0
-	google.golang.org/protobuf v1.28.1
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -2,9 +2,17 @@ package app
 
 import (
 	"context"
+	"fmt"
 	stdlog "log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/jackc/pgx/v4/stdlib" // pgx driver for database/sql
+	config "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
 )
 
 type App interface {
@@ -13,6 +21,11 @@ type App interface {
 	Close()
 }
 
+// Reloadable приложение, перечитывающее конфигурацию по SIGHUP без перезапуска.
+type Reloadable interface {
+	Reload(ctx context.Context) error
+}
+
 // Execute шаблонная функция выполнения приложения.
 func Execute(ctx context.Context, app App) {
 	// пропишем defer на закрытие приложения до инициализации.
@@ -21,7 +34,65 @@ func Execute(ctx context.Context, app App) {
 	if err := app.Initialize(ctx); err != nil {
 		stdlog.Fatalf("не удалось инициализировать приложение: %s", err)
 	}
+	if reloadable, ok := app.(Reloadable); ok {
+		watchReload(ctx, reloadable)
+	}
 	if err := app.Run(ctx); err != nil {
 		stdlog.Fatalf("не удалось запустить приложение: %s", err)
 	}
 }
+
+// watchReload вызывает Reload на каждый SIGHUP до завершения ctx, ошибка перезагрузки
+// не останавливает приложение: продолжает действовать прежняя конфигурация.
+func watchReload(ctx context.Context, app Reloadable) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := app.Reload(ctx); err != nil {
+					stdlog.Printf("не удалось перечитать конфигурацию: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// newLogger логгер приложения по секции logger конфигурации.
+func newLogger(cfg config.Logger) (logger.Logger, error) {
+	logCfg, err := cfg.LoggerConfig()
+	if err != nil {
+		return nil, err
+	}
+	l, err := logger.New(logCfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable start logger: %w", err)
+	}
+	return l, nil
+}
+
+// apiClientOptions подключение к API календаря, с TLS - если задана секция tls,
+// сертификат клиента перечитывается при изменении файлов.
+func apiClientOptions(api config.API, logger logger.Logger) ([]grpc.ClientOption, error) {
+	if !api.TLS.IsEnabled() {
+		return nil, nil
+	}
+	source, err := certs.NewSource(api.TLS.CertFiles(), api.TLS.CheckInterval(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("error loading API certificates: %w", err)
+	}
+	return []grpc.ClientOption{grpc.WithTLS(source.ClientConfig(api.TLS.ServerName))}, nil
+}
+
+// reloadLogLevel применение уровня логирования из перечитанной конфигурации.
+func reloadLogLevel(l logger.Logger, level string) error {
+	logLevel, err := logger.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("'%s': %w", level, err)
+	}
+	return l.SetLevel(logLevel)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -12,33 +14,30 @@ import (
 	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/service"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore/local"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/closer"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 )
 
 type Calendar struct {
-	config   config.Config
-	logger   logger.Logger
-	deps     *deps.Deps
-	services *deps.Services
-	closer   *closer.Closer
+	config     config.Config
+	configFile string
+	mu         sync.Mutex
+	logger     logger.Logger
+	deps       *deps.Deps
+	services   *deps.Services
+	closer     *closer.Closer
 }
 
-func NewCalendar(config config.Config) App {
-	return &Calendar{config: config, closer: closer.NewCloser()}
+func NewCalendar(config config.Config, configFile string) App {
+	return &Calendar{config: config, configFile: configFile, closer: closer.NewCloser()}
 }
 
 func (ca *Calendar) Initialize(ctx context.Context) error {
-	logLevel, err := logger.ParseLevel(ca.config.Logger.Level)
-	if err != nil {
-		return fmt.Errorf("'%s': %w", ca.config.Logger.Level, err)
-	}
-	ca.logger, err = logger.NewLogrus(logger.Config{
-		Level:    logLevel,
-		FileName: ca.config.Logger.FileName,
-	})
-	if err != nil {
-		return fmt.Errorf("unable start logger: %w", err)
+	var err error
+	if ca.logger, err = newLogger(ca.config.Logger); err != nil {
+		return err
 	}
 
 	var dbPool *sql.DB
@@ -47,9 +46,12 @@ func (ca *Calendar) Initialize(ctx context.Context) error {
 		ca.closer.Register("DB", closeFn)
 
 		if pool == nil {
-			return fmt.Errorf("unable start logger: %w", err)
+			return ErrDBConnect
 		}
 		dbPool = pool
+		if err = prepareSchema(ctx, dbPool, ca.config.Storage.AutoMigrate, ca.logger); err != nil {
+			return err
+		}
 		// устанавливаем диалект билдера запросов
 		sqlf.SetDialect(sqlf.PostgreSQL)
 		// это костыль, так как при большом количестве запросов он подтекает
@@ -75,6 +77,18 @@ func (ca *Calendar) Initialize(ctx context.Context) error {
 		Logger: ca.logger,
 		Clock:  clock.New(),
 	}
+	ca.deps.IdempotencyTTL, _ = ca.config.Idempotency.TTL.AsDuration()
+	ca.deps.EventQuota = ca.config.Quotas.MaxEvents
+	if attachments := ca.config.Attachments; attachments.Path != "" {
+		if ca.deps.Blobs, err = local.NewStore(attachments.Path); err != nil {
+			return fmt.Errorf("error init attachments storage: %w", err)
+		}
+	}
+	ca.deps.AttachmentLimits = service.AttachmentLimits{
+		MaxSize:      ca.config.Attachments.MaxSize(),
+		MaxCount:     ca.config.Attachments.MaxCount,
+		AllowedTypes: ca.config.Attachments.AllowedTypes,
+	}
 
 	ca.services = deps.NewServices(ca.deps)
 
@@ -86,9 +100,10 @@ func (ca *Calendar) Run(ctx context.Context) error {
 	defer cancel()
 
 	restServer, closeFn := http.NewHandledServer(ca.config.Servers.HTTP, ca.services, ca.deps)
-	ca.closer.Register("REST Server", closeFn)
+	// серверы завершаются параллельно и раньше БД, чтобы обрабатываемые запросы успели выполниться.
+	ca.closer.Register("REST Server", closeFn, closer.Group("servers"), closer.DependsOn("DB"))
 	grpcServer, closeFn := grpc.NewHandledServer(ca.config.Servers.GRPC, ca.services, ca.deps)
-	ca.closer.Register("GRPC Server", closeFn)
+	ca.closer.Register("GRPC Server", closeFn, closer.Group("servers"), closer.DependsOn("DB"))
 
 	go func() {
 		ca.logger.Info("HTTP server starting")
@@ -116,6 +131,29 @@ func (ca *Calendar) Close() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	ca.closer.Close(ctx, ca.logger)
+	if err := ca.closer.Close(ctx, ca.logger); err != nil {
+		ca.logger.Error("calendar stopped with errors: %s", err.Error())
+		return
+	}
 	ca.logger.Info("calendar stopped")
 }
+
+// Reload перечитывает конфигурацию, без перезапуска применяется только уровень логирования.
+func (ca *Calendar) Reload(_ context.Context) error {
+	cfg, err := config.New(ca.configFile)
+	if err != nil {
+		return err
+	}
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if err = reloadLogLevel(ca.logger, cfg.Logger.Level); err != nil {
+		return err
+	}
+	ca.config.Logger.Level = cfg.Logger.Level
+	if !reflect.DeepEqual(ca.config, cfg) {
+		ca.logger.Warn("configuration reloaded, changes except logger.level require restart")
+		return nil
+	}
+	ca.logger.Info("configuration reloaded")
+	return nil
+}
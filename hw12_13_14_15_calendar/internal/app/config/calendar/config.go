@@ -1,9 +1,22 @@
 package calendar
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 
 	common "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/jsonx"
+)
+
+const defIdempotencyTTL = "1d"
+
+var (
+	ErrNegativeQuota   = errors.New("quota must not be negative")
+	ErrNegativeLimit   = errors.New("limit must not be negative")
+	ErrAttachmentsPath = errors.New("attachments path is required for persistent storage")
+	ErrContentType     = errors.New("content type must be type/subtype or type/*")
 )
 
 type Config struct {
@@ -14,13 +27,86 @@ type Config struct {
 		HTTP common.Server `json:"http"`
 		GRPC common.Server `json:"grpc"`
 	} `json:"servers"`
-	Storage common.Storage `json:"storage"`
+	Storage     common.Storage `json:"storage"`
+	Idempotency Idempotency    `json:"idempotency"`
+	Quotas      Quotas         `json:"quotas"`
+	Attachments Attachments    `json:"attachments"`
+}
+
+// Quotas ограничения на объем данных пользователя, 0 - без ограничений.
+type Quotas struct {
+	MaxEvents int64 `json:"maxEvents"`
+}
+
+// Attachments вложения событий: хранилище файлов и ограничения.
+type Attachments struct {
+	// Path каталог хранилища файлов, пусто - файлы хранятся в памяти (только для storage.type memory).
+	Path string `json:"path"`
+	// MaxSizeMb размер файла, 0 - по умолчанию.
+	MaxSizeMb int64 `json:"maxSizeMb"`
+	// MaxCount количество вложений события, 0 - без ограничений.
+	MaxCount int `json:"maxCount"`
+	// AllowedTypes допустимые типы файлов: application/pdf, image/*; пусто - любые.
+	AllowedTypes []string `json:"allowedTypes"`
+}
+
+// MaxSize размер файла в байтах.
+func (a Attachments) MaxSize() int64 {
+	return a.MaxSizeMb << 20
 }
 
+// Validate storageType - тип хранилища событий: метаданные в БД без файлов на диске теряют смысл.
+func (a Attachments) Validate(v *common.Validator, field, storageType string) {
+	if a.Path == "" && storageType != "memory" {
+		v.Add(field+".path", ErrAttachmentsPath)
+	}
+	if a.MaxSizeMb < 0 {
+		v.Add(field+".maxSizeMb", ErrNegativeLimit)
+	}
+	if a.MaxCount < 0 {
+		v.Add(field+".maxCount", ErrNegativeLimit)
+	}
+	for i, contentType := range a.AllowedTypes {
+		if mediaType, subType, ok := strings.Cut(contentType, "/"); !ok || mediaType == "" || subType == "" ||
+			strings.ContainsAny(contentType, " ;,") {
+			v.Add(fmt.Sprintf("%s.allowedTypes[%d]", field, i), ErrContentType)
+		}
+	}
+}
+
+// Idempotency хранение ключей идемпотентности запросов на создание событий.
+type Idempotency struct {
+	TTL jsonx.Duration `json:"ttl"` // с единицей измерения: 1d
+}
+
+// EnvPrefix префикс переменных окружения, переопределяющих конфигурацию: CALENDAR_SERVERS_HTTP_PORT.
+const EnvPrefix = "CALENDAR"
+
 func New(fileName string) (Config, error) {
 	var cfg Config
-	if err := common.New(fileName, &cfg); err != nil {
+	if err := common.New(fileName, EnvPrefix, &cfg); err != nil {
 		return cfg, fmt.Errorf("error reading configuaration from '%s': %w", fileName, err)
 	}
+	if !cfg.Idempotency.TTL.Valid() {
+		log.Printf("wrong ttl idempotency config value, set default '%s'\n", defIdempotencyTTL)
+		cfg.Idempotency.TTL, _ = jsonx.ParseDuration(defIdempotencyTTL)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid configuaration in '%s': %w", fileName, err)
+	}
 	return cfg, nil
 }
+
+// Validate проверка всех полей, ошибки возвращаются одним списком errx.NamedErrors.
+func (cfg Config) Validate() error {
+	var v common.Validator
+	cfg.Logger.Validate(&v, "logger")
+	cfg.Servers.HTTP.Validate(&v, "servers.http")
+	cfg.Servers.GRPC.Validate(&v, "servers.grpc")
+	cfg.Storage.Validate(&v, "storage")
+	if cfg.Quotas.MaxEvents < 0 {
+		v.Add("quotas.maxEvents", ErrNegativeQuota)
+	}
+	cfg.Attachments.Validate(&v, "attachments", cfg.Storage.Type)
+	return v.Err()
+}
@@ -2,27 +2,182 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/jsonx"
+	"gopkg.in/yaml.v3"
 )
 
 type Logger struct {
 	FileName string `json:"fileName"`
 	Level    string `json:"level"`
+	// Backend реализация: logrus (по умолчанию) или slog.
+	Backend string `json:"backend"`
+	// Format формат записей: text (по умолчанию) или json.
+	Format string `json:"format"`
+	// Rotation ротация файла fileName, при отсутствии секции не выполняется.
+	Rotation LogRotation `json:"rotation"`
+}
+
+type LogRotation struct {
+	MaxSizeMB  int            `json:"maxSizeMb"`
+	Interval   jsonx.Duration `json:"interval"` // с единицей измерения: 1d
+	MaxAge     jsonx.Duration `json:"maxAge"`   // срок хранения ротированных файлов: 2w
+	MaxBackups int            `json:"maxBackups"`
+}
+
+// LoggerConfig конфигурация pkg/logger, значения должны быть проверены Logger.Validate.
+func (l Logger) LoggerConfig() (logger.Config, error) {
+	level, err := logger.ParseLevel(l.Level)
+	if err != nil {
+		return logger.Config{}, fmt.Errorf("'%s': %w", l.Level, err)
+	}
+	backend, err := logger.ParseBackend(l.Backend)
+	if err != nil {
+		return logger.Config{}, fmt.Errorf("'%s': %w", l.Backend, err)
+	}
+	format, err := logger.ParseFormat(l.Format)
+	if err != nil {
+		return logger.Config{}, fmt.Errorf("'%s': %w", l.Format, err)
+	}
+	return logger.Config{
+		Level:    level,
+		FileName: l.FileName,
+		Backend:  backend,
+		Format:   format,
+		Rotation: logger.Rotation{
+			MaxSize:    int64(l.Rotation.MaxSizeMB) << 20,
+			Interval:   optionalDuration(l.Rotation.Interval),
+			MaxAge:     optionalDuration(l.Rotation.MaxAge),
+			MaxBackups: l.Rotation.MaxBackups,
+		},
+	}, nil
+}
+
+// optionalDuration незаданная продолжительность - 0.
+func optionalDuration(d jsonx.Duration) time.Duration {
+	value, err := d.AsDuration()
+	if err != nil {
+		return 0
+	}
+	return value
 }
 
 type Server struct {
 	Host string `json:"host"`
 	Port int    `json:"port"`
+	// RateLimit лимиты частоты запросов, при отсутствии секции не ограничены.
+	RateLimit ratelimit.Config `json:"rateLimit"`
+	// Timeouts предельное время обработки запросов REST-сервером, незаданные - по умолчанию.
+	Timeouts ServerTimeouts `json:"timeouts"`
+	// CORS кросс-доменные запросы к REST-серверу, при отсутствии секции не обслуживаются.
+	CORS ServerCORS `json:"cors"`
+	// TLS шифрование соединений, без certFile сервер работает без TLS.
+	TLS ServerTLS `json:"tls"`
+}
+
+type ServerTimeouts struct {
+	Read    jsonx.Duration `json:"read"`    // чтение запроса с телом: 10s
+	Write   jsonx.Duration `json:"write"`   // запись ответа: 30s
+	Handler jsonx.Duration `json:"handler"` // выполнение обработчика: 30s
+}
+
+type ServerCORS struct {
+	AllowedOrigins   []string       `json:"allowedOrigins"`
+	AllowedHeaders   []string       `json:"allowedHeaders"`
+	AllowCredentials bool           `json:"allowCredentials"`
+	MaxAge           jsonx.Duration `json:"maxAge"` // кэширование предварительного запроса: 10m
+}
+
+type ServerTLS struct {
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// ClientCAFile корневые сертификаты для проверки сертификатов клиентов (mTLS).
+	ClientCAFile string `json:"clientCaFile"`
+	// ServiceAccounts логины учетных записей по идентификаторам сертификатов клиентов:
+	// URI, DNS-имени, e-mail или CN.
+	ServiceAccounts map[string]string `json:"serviceAccounts"`
+	ReloadInterval  jsonx.Duration    `json:"reloadInterval"` // проверка изменения файлов: 10s
+}
+
+// ServersConfig конфигурация pkg/servers.
+func (s Server) ServersConfig() servers.Config {
+	return servers.NewConfig(s.Host, s.Port).
+		WithRateLimit(s.RateLimit).
+		WithTimeouts(servers.Timeouts{
+			Read:    optionalDuration(s.Timeouts.Read),
+			Write:   optionalDuration(s.Timeouts.Write),
+			Handler: optionalDuration(s.Timeouts.Handler),
+		}).
+		WithCORS(servers.CORS{
+			AllowedOrigins:   s.CORS.AllowedOrigins,
+			AllowedHeaders:   s.CORS.AllowedHeaders,
+			AllowCredentials: s.CORS.AllowCredentials,
+			MaxAge:           optionalDuration(s.CORS.MaxAge),
+		}).
+		WithTLS(servers.TLS{
+			CertFile:        s.TLS.CertFile,
+			KeyFile:         s.TLS.KeyFile,
+			ClientCAFile:    s.TLS.ClientCAFile,
+			ReloadInterval:  optionalDuration(s.TLS.ReloadInterval),
+			ServiceAccounts: s.TLS.ServiceAccounts,
+		})
 }
 
 type API struct {
 	Type    string `json:"type"`
 	Address string `json:"address"`
+	// TLS подключение с шифрованием, при отсутствии секции - без него.
+	TLS ClientTLS `json:"tls"`
+}
+
+type ClientTLS struct {
+	// Enabled шифрование без файлов: сервер проверяется по системным корневым сертификатам.
+	Enabled bool   `json:"enabled"`
+	CAFile  string `json:"caFile"`
+	// CertFile и KeyFile сертификат клиента для mTLS.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// ServerName имя в сертификате сервера, если отличается от хоста в адресе.
+	ServerName     string         `json:"serverName"`
+	ReloadInterval jsonx.Duration `json:"reloadInterval"` // проверка изменения файлов: 10s
+}
+
+func (t ClientTLS) IsEnabled() bool {
+	return t.Enabled || t.CAFile != "" || t.CertFile != ""
+}
+
+// HasCert предъявляет ли клиент сертификат, тогда сервер авторизует его по сертификату.
+func (t ClientTLS) HasCert() bool {
+	return t.CertFile != ""
+}
+
+// CertFiles файлы сертификатов для certs.NewSource.
+func (t ClientTLS) CertFiles() certs.Files {
+	return certs.Files{Cert: t.CertFile, Key: t.KeyFile, CA: t.CAFile}
+}
+
+// CheckInterval как часто проверяются изменения файлов сертификата.
+func (t ClientTLS) CheckInterval() time.Duration {
+	if interval := optionalDuration(t.ReloadInterval); interval > 0 {
+		return interval
+	}
+	return certs.DefaultCheckInterval
 }
 
 type Storage struct {
 	Type   string  `json:"type"`
 	PGConn SQLConn `json:"pgsql"`
+	// AutoMigrate применять встроенные миграции при запуске, иначе только проверка версии схемы.
+	AutoMigrate bool `json:"autoMigrate"`
 }
 
 type Queue struct {
@@ -52,13 +207,37 @@ type Mailer struct {
 	Type         string `json:"type"`
 	DefaultFrom  string `json:"defaultFrom"`
 	TemplatePath string `json:"templatePath"`
+	// DefaultLocale язык писем пользователей без своего языка и последний шаг поиска шаблона.
+	DefaultLocale string `json:"defaultLocale"`
 }
 
-// New используем обычный encode/json.
-func New(fileName string, config interface{}) error {
+// New читает конфигурацию из файла в формате json или yaml (по расширению .yaml, .yml) и
+// переопределяет поля переменными окружения с префиксом envPrefix, см. ApplyEnv.
+func New(fileName, envPrefix string, config interface{}) error {
 	bs, err := os.ReadFile(fileName)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(bs, config)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		// yaml переводится в json, чтобы использовать json-теги и разбор значений вроде jsonx.Duration.
+		if bs, err = yamlToJSON(bs); err != nil {
+			return err
+		}
+	}
+	if err = json.Unmarshal(bs, config); err != nil {
+		return err
+	}
+	return ApplyEnv(envPrefix, config, os.LookupEnv)
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(doc)
 }
@@ -0,0 +1,234 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	common "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/scheduler"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/sender"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+const calendarYAML = `
+serviceId: calendar
+logger:
+  level: info
+servers:
+  http:
+    host: 127.0.0.1
+    port: 8080
+  grpc:
+    port: 8088
+storage:
+  type: memory
+idempotency:
+  ttl: 2d
+`
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+	return fileName
+}
+
+func envLookup(env map[string]string) common.LookupEnvFunc {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	var cfg calendar.Config
+	cfg.Storage.Type = "memory"
+	err := common.ApplyEnv("CALENDAR", &cfg, envLookup(map[string]string{
+		"CALENDAR_SERVERS_HTTP_PORT":                        "9090",
+		"CALENDAR_STORAGE_TYPE":                             "pgsql",
+		"CALENDAR_STORAGE_PGSQL_HOST":                       "db",
+		"CALENDAR_STORAGE_PGSQL_DB_NAME":                    "calendar",
+		"CALENDAR_IDEMPOTENCY_TTL":                          `"12h"`,
+		"CALENDAR_SERVERS_GRPC_RATE_LIMIT_PER_IP_READ_RATE": "2.5",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, 9090, cfg.Servers.HTTP.Port)
+	require.Equal(t, "pgsql", cfg.Storage.Type)
+	// поля встроенной структуры Conn на уровне pgsql.
+	require.Equal(t, "db", cfg.Storage.PGConn.Host)
+	require.Equal(t, "calendar", cfg.Storage.PGConn.DBName)
+	ttl, err := cfg.Idempotency.TTL.AsDuration()
+	require.NoError(t, err)
+	require.Equal(t, 12*time.Hour, ttl)
+	require.Equal(t, 2.5, cfg.Servers.GRPC.RateLimit.PerIP.Read.Rate)
+
+	t.Run("wrong values", func(t *testing.T) {
+		var cfg calendar.Config
+		err := common.ApplyEnv("CALENDAR", &cfg, envLookup(map[string]string{
+			"CALENDAR_SERVERS_HTTP_PORT": "http",
+			"CALENDAR_QUOTAS_MAX_EVENTS": "many",
+		}))
+		var errs errx.NamedErrors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 2)
+	})
+
+	t.Run("plain durations", func(t *testing.T) {
+		var cfg scheduler.Config
+		err := common.ApplyEnv("CALENDAR", &cfg, envLookup(map[string]string{
+			"CALENDAR_NOTIFY_CHECKING_TIME": "5s",
+			"CALENDAR_NOTIFY_TIMEOUT":       `"30s"`,
+		}))
+		require.NoError(t, err)
+		period, err := cfg.Notify.CheckingTime.AsDuration()
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, period)
+		timeout, err := cfg.Notify.Timeout.AsDuration()
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Second, timeout)
+
+		err = common.ApplyEnv("CALENDAR", &cfg, envLookup(map[string]string{"CALENDAR_NOTIFY_CHECKING_TIME": "5 sec"}))
+		var errs errx.NamedErrors
+		require.True(t, errors.As(err, &errs))
+		require.Equal(t, "CALENDAR_NOTIFY_CHECKING_TIME", errs[0].Field)
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		require.ErrorIs(t, common.ApplyEnv("CALENDAR", calendar.Config{}, envLookup(nil)), common.ErrEnvTarget)
+	})
+}
+
+func TestNewYAML(t *testing.T) {
+	t.Setenv("CALENDAR_LOGGER_LEVEL", "warn")
+	cfg, err := calendar.New(writeConfig(t, "config.yaml", calendarYAML))
+	require.NoError(t, err)
+	require.Equal(t, "calendar", cfg.ServiceID)
+	require.Equal(t, "warn", cfg.Logger.Level)
+	require.Equal(t, 8080, cfg.Servers.HTTP.Port)
+	ttl, err := cfg.Idempotency.TTL.AsDuration()
+	require.NoError(t, err)
+	require.Equal(t, 48*time.Hour, ttl)
+}
+
+func TestValidate(t *testing.T) {
+	_, err := scheduler.New(writeConfig(t, "config.json", `{
+		"logger": {"level": "verbose"},
+		"api": {"calendar": {"type": "grpc"}},
+		"amqp": {"type": "rabbitMq", "rabbitMq": {"host": "mq", "port": 70000}},
+		"notify": {"checkingTime": "1m"}
+	}`))
+	var errs errx.NamedErrors
+	require.True(t, errors.As(err, &errs))
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	require.ElementsMatch(t, []string{
+		"logger.level", "apiLogin", "api.calendar.address", "amqp.rabbitMq.port", "notify.queuePublish",
+	}, fields)
+	require.ErrorIs(t, err, scheduler.ErrEmptyQueuePublish)
+}
+
+func TestLoggerConfig(t *testing.T) {
+	cfg, err := sender.New(writeConfig(t, "config.json", `{
+		"logger": {
+			"level": "info",
+			"backend": "slog",
+			"format": "json",
+			"rotation": {"maxSizeMb": 10, "interval": "1d", "maxBackups": 3}
+		},
+		"apiLogin": "sender@otus.ru",
+		"api": {"calendar": {"address": "calendar:8081"}},
+		"amqp": {"type": "rabbitMq", "rabbitMq": {"host": "mq", "port": 5672}},
+		"notify": {"queueListen": "notify"},
+		"mailer": {"templatePath": "./templates/mail", "defaultLocale": "ru"}
+	}`))
+	require.NoError(t, err)
+	logCfg, err := cfg.Logger.LoggerConfig()
+	require.NoError(t, err)
+	require.Equal(t, logger.BackendSlog, logCfg.Backend)
+	require.Equal(t, logger.FormatJSON, logCfg.Format)
+	require.Equal(t, logger.Rotation{MaxSize: 10 << 20, Interval: 24 * time.Hour, MaxBackups: 3}, logCfg.Rotation)
+
+	var v common.Validator
+	common.Logger{
+		Level: "info", Backend: "zap", Format: "xml", Rotation: common.LogRotation{MaxSizeMB: -1},
+	}.Validate(&v, "logger")
+	var errs errx.NamedErrors
+	require.True(t, errors.As(v.Err(), &errs))
+	require.Len(t, errs, 3)
+}
+
+func TestServersConfig(t *testing.T) {
+	cfg, err := calendar.New(writeConfig(t, "config.json", `{
+		"serviceId": "calendar",
+		"logger": {"level": "info"},
+		"servers": {
+			"http": {
+				"port": 8080,
+				"timeouts": {"write": "1m"},
+				"cors": {"allowedOrigins": ["https://app.example"], "allowCredentials": true, "maxAge": "10m"}
+			},
+			"grpc": {"port": 8088}
+		},
+		"storage": {"type": "memory"}
+	}`))
+	require.NoError(t, err)
+	servers := cfg.Servers.HTTP.ServersConfig()
+	timeouts := servers.GetTimeouts()
+	require.Equal(t, 10*time.Second, timeouts.Read)
+	require.Equal(t, time.Minute, timeouts.Write)
+	require.Equal(t, 30*time.Second, timeouts.Handler)
+	cors := servers.GetCORS()
+	require.True(t, cors.Enabled())
+	require.True(t, cors.AllowCredentials)
+	require.Equal(t, 10*time.Minute, cors.MaxAge)
+
+	var v common.Validator
+	common.Server{
+		Port: 8080, CORS: common.ServerCORS{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+	}.Validate(&v, "servers.http")
+	require.ErrorIs(t, v.Err(), common.ErrCORSWildcard)
+}
+
+func TestTLSConfig(t *testing.T) {
+	var v common.Validator
+	common.Server{Port: 8088, TLS: common.ServerTLS{
+		KeyFile: "server-key.pem", ServiceAccounts: map[string]string{"scheduler": "scheduler@otus.ru"},
+	}}.Validate(&v, "servers.grpc")
+	var errs errx.NamedErrors
+	require.True(t, errors.As(v.Err(), &errs))
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, v.Err(), common.ErrTLSRequired)
+
+	v = common.Validator{}
+	common.Server{Port: 8088, TLS: common.ServerTLS{
+		CertFile: "server.pem", ServiceAccounts: map[string]string{"scheduler": "scheduler@otus.ru"},
+	}}.Validate(&v, "servers.grpc")
+	require.True(t, errors.As(v.Err(), &errs))
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	require.ElementsMatch(t, []string{"servers.grpc.tls.keyFile", "servers.grpc.tls.clientCaFile"}, fields)
+
+	// с сертификатом клиента логин для API не нужен.
+	cfg, err := sender.New(writeConfig(t, "config.json", `{
+		"logger": {"level": "info"},
+		"api": {"calendar": {"address": "calendar:8088", "tls": {
+			"caFile": "ca.pem", "certFile": "sender.pem", "keyFile": "sender-key.pem"
+		}}},
+		"amqp": {"type": "rabbitMq", "rabbitMq": {"host": "mq", "port": 5672}},
+		"notify": {"queueListen": "notify"},
+		"mailer": {"templatePath": "./templates/mail", "defaultLocale": "ru"}
+	}`))
+	require.NoError(t, err)
+	require.True(t, cfg.API.Calendar.TLS.IsEnabled())
+	require.Equal(t, "sender.pem", cfg.API.Calendar.TLS.CertFiles().Cert)
+}
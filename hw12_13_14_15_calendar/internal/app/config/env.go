@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var (
+	ErrEnvTarget = errors.New("config must be a pointer to struct")
+
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// LookupEnvFunc источник переменных окружения, в тестах подменяется.
+type LookupEnvFunc func(string) (string, bool)
+
+// ApplyEnv переопределяет поля конфигурации переменными окружения. Имя переменной - префикс и
+// путь к полю по json-тегам в верхнем регистре через "_": поле storage.pgsql.dbName для
+// префикса CALENDAR задается переменной CALENDAR_STORAGE_PGSQL_DB_NAME. Строки берутся как есть,
+// остальные значения разбираются как json, например CALENDAR_SERVERS_HTTP_PORT=8080. Значения
+// типов со своим разбором json, например продолжительности, задаются и без кавычек: CALENDAR_IDEMPOTENCY_TTL=12h.
+func ApplyEnv(prefix string, config interface{}, lookup LookupEnvFunc) error {
+	v := reflect.ValueOf(config)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return ErrEnvTarget
+	}
+	var errs errx.NamedErrors
+	applyEnvValue(v.Elem(), prefix, lookup, &errs)
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+func applyEnvValue(v reflect.Value, name string, lookup LookupEnvFunc, errs *errx.NamedErrors) {
+	if v.Kind() != reflect.Struct || reflect.PtrTo(v.Type()).Implements(unmarshalerType) {
+		value, ok := lookup(name)
+		if !ok {
+			return
+		}
+		if err := setEnvValue(v, value); err != nil {
+			errs.Add(errx.NamedError{Field: name, Err: err})
+		}
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		// поля встроенной структуры без тега json находятся на том же уровне.
+		if field.Anonymous && tag == "" {
+			applyEnvValue(v.Field(i), name, lookup, errs)
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		applyEnvValue(v.Field(i), name+"_"+envName(tag), lookup, errs)
+	}
+}
+
+func setEnvValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+	target := v.Addr().Interface()
+	if unmarshaler, ok := target.(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("wrong value '%s': %w", value, err)
+		}
+		return nil
+	}
+	err := json.Unmarshal([]byte(value), target)
+	custom := v.Type().Implements(unmarshalerType) || reflect.PtrTo(v.Type()).Implements(unmarshalerType)
+	if err != nil && custom {
+		// строковые значения типов вроде jsonx.Duration допускаются без кавычек: 5s вместо "5s".
+		if quotedErr := json.Unmarshal([]byte(strconv.Quote(value)), target); quotedErr == nil {
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("wrong value '%s': %w", value, err)
+	}
+	return nil
+}
+
+// envName имя поля в стиле переменных окружения: checkingTime -> CHECKING_TIME.
+func envName(field string) string {
+	var b strings.Builder
+	runes := []rune(field)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(runes[i-1]) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -4,53 +4,94 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	common "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/jsonx"
 )
 
-var ErrEmptyQueuePublish = errors.New("empty queue name for notifier publishing")
+var (
+	ErrEmptyQueuePublish = errors.New("empty queue name for notifier publishing")
+	ErrElectionTTL       = errors.New("lease ttl must be at least 3s")
+	ErrTimezone          = errors.New("unknown timezone")
+)
 
 const (
 	defCleanupCheckingTime = "1d"
 	defCleanupStoreTime    = "1y"
 	defNotifyCheckingTime  = "1m"
+	defDigestCheckingTime  = "1m"
+	defTrashCheckingTime   = "1d"
+	defTrashRetentionTime  = "1n"
+	defIdempotencyChecking = "1h"
+	defElectionLease       = "scheduler"
+	defElectionTTL         = "15s"
 )
 
 type Config struct {
-	ServiceID   string `json:"serviceId"`
-	ServiceName string `json:"serviceName"`
-	Logger      Logger `json:"logger"`
-	APILogin    string `json:"apiLogin"`
+	ServiceID   string        `json:"serviceId"`
+	ServiceName string        `json:"serviceName"`
+	Logger      common.Logger `json:"logger"`
+	APILogin    string        `json:"apiLogin"`
 	API         struct {
 		Calendar common.API `json:"calendar"`
 	} `json:"api"`
 	AMQP    common.Queue `json:"amqp"`
 	Cleanup Cleanup      `json:"cleanup"`
+	Trash   Trash        `json:"trash"`
 	Notify  Notify       `json:"notify"`
-}
+	Digest  Digest       `json:"digest"`
+
+	Idempotency Idempotency `json:"idempotency"`
+	Election    Election    `json:"election"`
 
-type Logger struct {
-	FileName string `json:"fileName"`
-	Level    string `json:"level"`
+	// StateFile файл с временем последнего запуска задач, пустое значение - не сохранять.
+	StateFile string `json:"stateFile"`
+	// Timezone часовой пояс cron-выражений, по умолчанию - локальный.
+	Timezone string `json:"timezone"`
 }
 
 type Cleanup struct {
-	CheckingTime jsonx.Duration `json:"checkingTime"` // с единицей измерения: 1d
-	StoreTime    jsonx.Duration `json:"storeTime"`    // с единицей измерения: 1y
+	Job
+	StoreTime jsonx.Duration `json:"storeTime"` // с единицей измерения: 1y
+}
+
+type Trash struct {
+	Job
+	RetentionTime jsonx.Duration `json:"retentionTime"` // с единицей измерения: 1n
+}
+
+// Idempotency удаление истекших ключей идемпотентности.
+type Idempotency struct {
+	Job
+}
+
+// Election выбор ведущего среди запущенных экземпляров, задачи выполняет только ведущий.
+type Election struct {
+	Lease string         `json:"lease"` // имя аренды, общее для всех экземпляров
+	TTL   jsonx.Duration `json:"ttl"`   // с единицей измерения: 15s
+}
+
+// Digest сводки событий публикуются в очередь оповещений notify.queuePublish,
+// задача запускается часто: время отправки у каждого пользователя свое.
+type Digest struct {
+	Job
 }
 
 type Notify struct {
-	CheckingTime jsonx.Duration `json:"checkingTime"` // с единицей измерения: 1m
-	QueuePublish string         `json:"queuePublish"`
+	Job
+	QueuePublish string `json:"queuePublish"`
 }
 
+// EnvPrefix префикс переменных окружения, переопределяющих конфигурацию: SCHEDULER_NOTIFY_QUEUE_PUBLISH.
+const EnvPrefix = "SCHEDULER"
+
 func New(fileName string) (Config, error) {
 	var cfg Config
-	if err := common.New(fileName, &cfg); err != nil {
+	if err := common.New(fileName, EnvPrefix, &cfg); err != nil {
 		return cfg, fmt.Errorf("error reading configuaration from '%s': %w", fileName, err)
 	}
-	if !cfg.Cleanup.CheckingTime.Valid() {
+	if cfg.Cleanup.Schedule == "" && !cfg.Cleanup.CheckingTime.Valid() {
 		log.Printf(
 			"wrong checkingTime cleaner config value, set default '%s'\n", defCleanupCheckingTime,
 		)
@@ -64,17 +105,93 @@ func New(fileName string) (Config, error) {
 		cfg.Cleanup.StoreTime, _ = jsonx.ParseDuration(defCleanupStoreTime)
 	}
 
-	if !cfg.Notify.CheckingTime.Valid() {
+	if cfg.Trash.Schedule == "" && !cfg.Trash.CheckingTime.Valid() {
+		log.Printf(
+			"wrong checkingTime trash config value, set default '%s'\n", defTrashCheckingTime,
+		)
+		cfg.Trash.CheckingTime, _ = jsonx.ParseDuration(defTrashCheckingTime)
+	}
+
+	if !cfg.Trash.RetentionTime.Valid() {
+		log.Printf(
+			"wrong retentionTime trash config value, set default '%s'\n", defTrashRetentionTime,
+		)
+		cfg.Trash.RetentionTime, _ = jsonx.ParseDuration(defTrashRetentionTime)
+	}
+
+	if cfg.Idempotency.Schedule == "" && !cfg.Idempotency.CheckingTime.Valid() {
+		log.Printf(
+			"wrong checkingTime idempotency config value, set default '%s'\n", defIdempotencyChecking,
+		)
+		cfg.Idempotency.CheckingTime, _ = jsonx.ParseDuration(defIdempotencyChecking)
+	}
+
+	if cfg.Notify.Schedule == "" && !cfg.Notify.CheckingTime.Valid() {
 		log.Printf(
 			"wrong checkingTime notifier config value, set default '%s'\n", defNotifyCheckingTime,
 		)
 		cfg.Notify.CheckingTime, _ = jsonx.ParseDuration(defNotifyCheckingTime)
 	}
 
-	if len(cfg.Notify.QueuePublish) == 0 {
-		err := ErrEmptyQueuePublish
-		log.Println(err.Error())
-		return Config{}, err
+	if cfg.Digest.Schedule == "" && !cfg.Digest.CheckingTime.Valid() {
+		log.Printf(
+			"wrong checkingTime digest config value, set default '%s'\n", defDigestCheckingTime,
+		)
+		cfg.Digest.CheckingTime, _ = jsonx.ParseDuration(defDigestCheckingTime)
+	}
+
+	if len(cfg.Election.Lease) == 0 {
+		cfg.Election.Lease = defElectionLease
+	}
+
+	if !cfg.Election.TTL.Valid() {
+		log.Printf(
+			"wrong ttl election config value, set default '%s'\n", defElectionTTL,
+		)
+		cfg.Election.TTL, _ = jsonx.ParseDuration(defElectionTTL)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid configuaration in '%s': %w", fileName, err)
 	}
 	return cfg, nil
 }
+
+// Validate проверка всех полей, ошибки возвращаются одним списком errx.NamedErrors.
+func (cfg Config) Validate() error {
+	var v common.Validator
+	cfg.Logger.Validate(&v, "logger")
+	// с сертификатом клиента сервер авторизует по нему, логин не нужен.
+	if !cfg.API.Calendar.TLS.HasCert() {
+		v.Required("apiLogin", cfg.APILogin)
+	}
+	cfg.API.Calendar.Validate(&v, "api.calendar")
+	cfg.AMQP.Validate(&v, "amqp")
+	if len(cfg.Notify.QueuePublish) == 0 {
+		v.Add("notify.queuePublish", ErrEmptyQueuePublish)
+	}
+	if ttl, _ := cfg.Election.TTL.AsDuration(); ttl < 3*time.Second {
+		v.Add("election.ttl", ErrElectionTTL)
+	}
+	if _, err := cfg.Location(); err != nil {
+		v.Add("timezone", err)
+	}
+	cfg.Cleanup.Validate(&v, "cleanup")
+	cfg.Trash.Validate(&v, "trash")
+	cfg.Notify.Validate(&v, "notify")
+	cfg.Digest.Validate(&v, "digest")
+	cfg.Idempotency.Validate(&v, "idempotency")
+	return v.Err()
+}
+
+// Location часовой пояс cron-выражений.
+func (cfg Config) Location() (*time.Location, error) {
+	if cfg.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("'%s': %w", cfg.Timezone, ErrTimezone)
+	}
+	return loc, nil
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	common "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/cron"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/jsonx"
+)
+
+// Job расписание периодической задачи. Cron-выражение schedule приоритетнее периода checkingTime:
+// период отсчитывается от запуска сервиса, а "0 3 * * *" запускает задачу ночью.
+type Job struct {
+	CheckingTime jsonx.Duration `json:"checkingTime"` // с единицей измерения: 1m
+	Schedule     string         `json:"schedule"`     // cron: "0 3 * * *", @daily, @every 90m
+	Timeout      jsonx.Duration `json:"timeout"`      // предельное время выполнения, по умолчанию не ограничено
+	Jitter       jsonx.Duration `json:"jitter"`       // случайная задержка запуска
+	Overlap      string         `json:"overlap"`      // skip (по умолчанию) или wait: запуск при незавершенном
+	CatchUp      bool           `json:"catchUp"`      // выполнить пропущенный за время остановки запуск
+}
+
+func (j Job) Validate(v *common.Validator, field string) {
+	if j.Schedule != "" {
+		if _, err := cron.Parse(j.Schedule, nil); err != nil {
+			v.Add(field+".schedule", err)
+		}
+	}
+	if _, err := cron.ParsePolicy(j.Overlap); err != nil {
+		v.Add(field+".overlap", err)
+	}
+}
+
+// Spec описание задачи планировщика, незаданные timeout и jitter не ограничивают запуск.
+func (j Job) Spec(name string, run func(context.Context), loc *time.Location) (cron.Job, error) {
+	spec := cron.Job{Name: name, Run: run, CatchUp: j.CatchUp}
+	var err error
+	if j.Schedule != "" {
+		if spec.Schedule, err = cron.Parse(j.Schedule, loc); err != nil {
+			return spec, err
+		}
+	} else {
+		var period time.Duration
+		if period, err = j.CheckingTime.AsDuration(); err != nil {
+			return spec, err
+		}
+		spec.Schedule = cron.Every(period)
+	}
+	if spec.Policy, err = cron.ParsePolicy(j.Overlap); err != nil {
+		return spec, err
+	}
+	spec.Timeout, _ = j.Timeout.AsDuration()
+	spec.Jitter, _ = j.Jitter.AsDuration()
+	return spec, nil
+}
@@ -3,7 +3,6 @@ package sender
 import (
 	"errors"
 	"fmt"
-	"log"
 
 	common "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
 )
@@ -11,10 +10,10 @@ import (
 var ErrEmptyQueueListen = errors.New("empty queue name for sender listening")
 
 type Config struct {
-	ServiceID   string `json:"serviceId"`
-	ServiceName string `json:"serviceName"`
-	Logger      Logger `json:"logger"`
-	APILogin    string `json:"apiLogin"`
+	ServiceID   string        `json:"serviceId"`
+	ServiceName string        `json:"serviceName"`
+	Logger      common.Logger `json:"logger"`
+	APILogin    string        `json:"apiLogin"`
 	API         struct {
 		Calendar common.API `json:"calendar"`
 	} `json:"api"`
@@ -23,24 +22,37 @@ type Config struct {
 	Notify Notify        `json:"notify"`
 }
 
-type Logger struct {
-	FileName string `json:"fileName"`
-	Level    string `json:"level"`
-}
-
 type Notify struct {
 	QueueListen string `json:"queueListen"`
 }
 
+// EnvPrefix префикс переменных окружения, переопределяющих конфигурацию: SENDER_NOTIFY_QUEUE_LISTEN.
+const EnvPrefix = "SENDER"
+
 func New(fileName string) (Config, error) {
 	var cfg Config
-	if err := common.New(fileName, &cfg); err != nil {
+	if err := common.New(fileName, EnvPrefix, &cfg); err != nil {
 		return cfg, fmt.Errorf("error reading configuaration from '%s': %w", fileName, err)
 	}
-	if len(cfg.Notify.QueueListen) == 0 {
-		err := ErrEmptyQueueListen
-		log.Println(err.Error())
-		return Config{}, err
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid configuaration in '%s': %w", fileName, err)
 	}
 	return cfg, nil
 }
+
+// Validate проверка всех полей, ошибки возвращаются одним списком errx.NamedErrors.
+func (cfg Config) Validate() error {
+	var v common.Validator
+	cfg.Logger.Validate(&v, "logger")
+	// с сертификатом клиента сервер авторизует по нему, логин не нужен.
+	if !cfg.API.Calendar.TLS.HasCert() {
+		v.Required("apiLogin", cfg.APILogin)
+	}
+	cfg.API.Calendar.Validate(&v, "api.calendar")
+	cfg.Mailer.Validate(&v, "mailer")
+	cfg.AMQP.Validate(&v, "amqp")
+	if len(cfg.Notify.QueueListen) == 0 {
+		v.Add("notify.queueListen", ErrEmptyQueueListen)
+	}
+	return v.Err()
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var (
+	ErrEmptyValue     = errors.New("value is required")
+	ErrWrongPort      = errors.New("port must be in range 0..65535")
+	ErrUnknownStorage = errors.New("unknown storage type, expected memory or pgsql")
+	ErrUnknownQueue   = errors.New("unknown amqp type, expected rabbitMq")
+	ErrNegativeLimit  = errors.New("rate limit must not be negative")
+	ErrNegativeValue  = errors.New("value must not be negative")
+	ErrCORSWildcard   = errors.New("allowCredentials is not allowed with origin '*'")
+	ErrTLSRequired    = errors.New("value requires tls.certFile")
+	ErrKeyPair        = errors.New("certFile and keyFile must be set together")
+)
+
+// Validator накапливает ошибки проверки конфигурации с путями полей по json-тегам.
+type Validator struct {
+	errs errx.NamedErrors
+}
+
+func (v *Validator) Add(field string, err error) {
+	v.errs.Add(errx.NamedError{Field: field, Err: err})
+}
+
+// Required ошибка, если строковое значение пусто.
+func (v *Validator) Required(field, value string) {
+	if value == "" {
+		v.Add(field, ErrEmptyValue)
+	}
+}
+
+func (v *Validator) Port(field string, port int) {
+	if port < 0 || port > 65535 {
+		v.Add(field, ErrWrongPort)
+	}
+}
+
+// Err все накопленные ошибки, nil - конфигурация корректна.
+func (v *Validator) Err() error {
+	if v.errs.Empty() {
+		return nil
+	}
+	return v.errs
+}
+
+func (l Logger) Validate(v *Validator, field string) {
+	if _, err := logger.ParseLevel(l.Level); err != nil {
+		v.Add(field+".level", err)
+	}
+	if _, err := logger.ParseBackend(l.Backend); err != nil {
+		v.Add(field+".backend", err)
+	}
+	if _, err := logger.ParseFormat(l.Format); err != nil {
+		v.Add(field+".format", err)
+	}
+	if l.Rotation.MaxSizeMB < 0 {
+		v.Add(field+".rotation.maxSizeMb", ErrNegativeValue)
+	}
+	if l.Rotation.MaxBackups < 0 {
+		v.Add(field+".rotation.maxBackups", ErrNegativeValue)
+	}
+}
+
+func (s Server) Validate(v *Validator, field string) {
+	v.Port(field+".port", s.Port)
+	validateBudget(v, field+".rateLimit.perUser", s.RateLimit.PerUser)
+	validateBudget(v, field+".rateLimit.perIp", s.RateLimit.PerIP)
+	if s.CORS.AllowCredentials {
+		for _, origin := range s.CORS.AllowedOrigins {
+			if origin == "*" {
+				v.Add(field+".cors.allowCredentials", ErrCORSWildcard)
+			}
+		}
+	}
+	s.TLS.Validate(v, field+".tls")
+}
+
+func (t ServerTLS) Validate(v *Validator, field string) {
+	if t.CertFile == "" {
+		if t.KeyFile != "" || t.ClientCAFile != "" {
+			v.Add(field+".certFile", ErrEmptyValue)
+		}
+		if len(t.ServiceAccounts) > 0 {
+			v.Add(field+".serviceAccounts", ErrTLSRequired)
+		}
+		return
+	}
+	v.Required(field+".keyFile", t.KeyFile)
+	if len(t.ServiceAccounts) > 0 {
+		v.Required(field+".clientCaFile", t.ClientCAFile)
+	}
+}
+
+func validateBudget(v *Validator, field string, budget ratelimit.Budget) {
+	for name, limit := range map[string]ratelimit.Limit{"read": budget.Read, "write": budget.Write} {
+		if limit.Rate < 0 || limit.Burst < 0 {
+			v.Add(field+"."+name, ErrNegativeLimit)
+		}
+	}
+}
+
+func (a API) Validate(v *Validator, field string) {
+	v.Required(field+".address", a.Address)
+	if (a.TLS.CertFile == "") != (a.TLS.KeyFile == "") {
+		v.Add(field+".tls.keyFile", ErrKeyPair)
+	}
+}
+
+func (s Storage) Validate(v *Validator, field string) {
+	switch s.Type {
+	case "memory":
+	case "pgsql":
+		s.PGConn.Conn.Validate(v, field+".pgsql")
+		v.Required(field+".pgsql.dbName", s.PGConn.DBName)
+	default:
+		v.Add(field+".type", ErrUnknownStorage)
+	}
+}
+
+func (q Queue) Validate(v *Validator, field string) {
+	if q.Type != "rabbitMq" {
+		v.Add(field+".type", ErrUnknownQueue)
+		return
+	}
+	q.RabbitMQ.Validate(v, field+".rabbitMq")
+}
+
+func (c Conn) Validate(v *Validator, field string) {
+	v.Required(field+".host", c.Host)
+	v.Port(field+".port", c.Port)
+}
+
+func (m Mailer) Validate(v *Validator, field string) {
+	v.Required(field+".templatePath", m.TemplatePath)
+	v.Required(field+".defaultLocale", m.DefaultLocale)
+}
@@ -0,0 +1,78 @@
+// Package calendartest зависимости календаря для тестов обработчиков: репозитории в памяти,
+// сервисы и заранее зарегистрированные пользователи.
+package calendartest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/service"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+)
+
+type options struct {
+	level logger.Level
+	deps  deps.Deps
+	users []model.UserCreate
+}
+
+// Option настройка зависимостей теста.
+type Option func(*options)
+
+// WithUser зарегистрированный пользователь, авторизация в тестах - по email.
+func WithUser(name, email string) Option {
+	return func(o *options) {
+		o.users = append(o.users, model.UserCreate{Name: name, Email: email})
+	}
+}
+
+// WithLogLevel уровень логирования, по умолчанию logger.LevelInfo.
+func WithLogLevel(level logger.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+func WithClock(clk clock.Clock) Option {
+	return func(o *options) {
+		o.deps.Clock = clk
+	}
+}
+
+func WithEventQuota(quota int64) Option {
+	return func(o *options) {
+		o.deps.EventQuota = quota
+	}
+}
+
+func WithAttachmentLimits(limits service.AttachmentLimits) Option {
+	return func(o *options) {
+		o.deps.AttachmentLimits = limits
+	}
+}
+
+// New зависимости с репозиториями в памяти и сервисы поверх них.
+func New(t testing.TB, opts ...Option) (*deps.Deps, *deps.Services) {
+	t.Helper()
+	o := options{level: logger.LevelInfo}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	logs, err := logger.NewLogrus(logger.Config{Level: o.level})
+	require.NoError(t, err)
+	repos, err := deps.NewRepos(config.Storage{Type: "memory"}, nil)
+	require.NoError(t, err)
+	dependencies := &o.deps
+	dependencies.Repos, dependencies.Logger = repos, logs
+	services := deps.NewServices(dependencies)
+	for _, user := range o.users {
+		_, err = services.User.Add(context.Background(), user)
+		require.NoError(t, err)
+	}
+	return dependencies, services
+}
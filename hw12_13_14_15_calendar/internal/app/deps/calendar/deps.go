@@ -3,6 +3,7 @@ package calendar
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	common "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
@@ -10,14 +11,23 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository/memory"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository/pgsql"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/service"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore"
+	memblob "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore/memory"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
 )
 
 // Repos регистр репозиториев.
 type Repos struct {
-	Event repository.Event
-	User  repository.User
+	Event        repository.Event
+	User         repository.User
+	Tag          repository.Tag
+	Resource     repository.Resource
+	Attachment   repository.Attachment
+	Availability repository.Availability
+	Idempotency  repository.Idempotency
+	Lease        repository.Lease
+	Digest       repository.Digest
 }
 
 func NewRepos(store common.Storage, dbPool *sql.DB) (*Repos, error) {
@@ -27,14 +37,30 @@ func NewRepos(store common.Storage, dbPool *sql.DB) (*Repos, error) {
 	)
 	switch store.Type {
 	case "memory":
+		tagRepo := memory.NewTagRepo()
+		resourceRepo := memory.NewResourceRepo()
 		repos = &Repos{
-			Event: memory.NewEventRepo(),
-			User:  memory.NewUserRepo(),
+			Event:        memory.NewEventRepo(tagRepo, resourceRepo),
+			User:         memory.NewUserRepo(),
+			Tag:          tagRepo,
+			Resource:     resourceRepo,
+			Attachment:   memory.NewAttachmentRepo(),
+			Availability: memory.NewAvailabilityRepo(),
+			Idempotency:  memory.NewIdempotencyRepo(),
+			Lease:        memory.NewLeaseRepo(),
+			Digest:       memory.NewDigestRepo(),
 		}
 	case "pgsql":
 		repos = &Repos{
-			Event: pgsql.NewEventRepo(dbPool),
-			User:  pgsql.NewUserRepo(dbPool),
+			Event:        pgsql.NewEventRepo(dbPool),
+			User:         pgsql.NewUserRepo(dbPool),
+			Tag:          pgsql.NewTagRepo(dbPool),
+			Resource:     pgsql.NewResourceRepo(dbPool),
+			Attachment:   pgsql.NewAttachmentRepo(dbPool),
+			Availability: pgsql.NewAvailabilityRepo(dbPool),
+			Idempotency:  pgsql.NewIdempotencyRepo(dbPool),
+			Lease:        pgsql.NewLeaseRepo(dbPool),
+			Digest:       pgsql.NewDigestRepo(dbPool),
 		}
 	default:
 		err = fmt.Errorf("unknown storage type '%s", store.Type)
@@ -47,28 +73,68 @@ type Deps struct {
 	Repos  *Repos
 	Logger logger.Logger
 	Clock  clock.Clock
+	// IdempotencyTTL срок хранения ключей идемпотентности, 0 - значение по умолчанию.
+	IdempotencyTTL time.Duration
+	// EventQuota максимальное количество событий пользователя, 0 - без ограничений.
+	EventQuota int64
+	// Blobs хранилище содержимого вложений, nil - хранение в памяти.
+	Blobs            blobstore.Store
+	AttachmentLimits service.AttachmentLimits
 }
 
 // Services регистр сервисов.
 type Services struct {
-	EventCRUD   service.EventCRUD
-	EventNotify service.EventNotify
-	EventClean  service.EventClean
-	User        service.User
-	Logger      logger.Logger
-	Auth        servers.AuthService
+	EventCRUD       service.EventCRUD
+	EventIdempotent service.EventIdempotent
+	TagCRUD         service.TagCRUD
+	Resource        service.Resource
+	Attachment      service.Attachment
+	Availability    service.Availability
+	Digest          service.Digest
+	EventNotify     service.EventNotify
+	EventClean      service.EventClean
+	Lease           service.Lease
+	User            service.User
+	Logger          logger.Logger
+	Auth            servers.AuthService
 }
 
 func NewServices(deps *Deps) *Services {
 	repo := deps.Repos
-	userServ := service.NewUserService(repo.User, deps.Logger)
+	userServ := service.NewUserService(repo.User, repo.Event, deps.Logger)
+	eventCRUD := service.NewEventCRUDService(
+		repo.Event, repo.Tag, repo.Resource, repo.Availability, deps.Logger, userServ, deps.EventQuota,
+	)
+	clk := deps.Clock
+	if clk == nil {
+		clk = clock.New()
+	}
+	blobs := deps.Blobs
+	if blobs == nil {
+		blobs = memblob.NewStore()
+	}
 
 	return &Services{
-		EventCRUD:   service.NewEventCRUDService(repo.Event, deps.Logger, userServ),
-		EventNotify: service.NewEventNotifyService(repo.Event, deps.Logger, deps.Clock),
-		EventClean:  service.NewEventCleanService(repo.Event, deps.Logger, deps.Clock),
-		User:        userServ,
-		Logger:      deps.Logger,
-		Auth:        service.NewAuthService(userServ),
+		EventCRUD: eventCRUD,
+		EventIdempotent: service.NewEventIdempotentService(
+			eventCRUD, repo.Idempotency, userServ, deps.Logger, clk, deps.IdempotencyTTL,
+		),
+		TagCRUD:  service.NewTagCRUDService(repo.Tag, deps.Logger, userServ),
+		Resource: service.NewResourceService(repo.Resource, repo.Event, deps.Logger, userServ),
+		Attachment: service.NewAttachmentService(
+			repo.Attachment, blobs, deps.Logger, userServ, deps.AttachmentLimits,
+		),
+		Availability: service.NewAvailabilityService(repo.Availability, repo.Event, deps.Logger, userServ),
+		Digest: service.NewDigestService(
+			repo.Digest, repo.Availability, repo.Event, deps.Logger, userServ, clk,
+		),
+		EventNotify: service.NewEventNotifyService(repo.Event, deps.Logger, clk),
+		EventClean: service.NewEventCleanService(
+			repo.Event, repo.Attachment, blobs, repo.Idempotency, deps.Logger, clk,
+		),
+		Lease:  service.NewLeaseService(repo.Lease, deps.Logger, userServ, clk),
+		User:   userServ,
+		Logger: deps.Logger,
+		Auth:   service.NewAuthService(userServ),
 	}
 }
@@ -25,6 +25,7 @@ func NewCleaner(
 }
 
 func (cs Cleaner) DoAction(ctx context.Context) {
+	log := logger.FromContext(ctx, cs.logger)
 	_, err := cs.supportAPI.CleanupOldEvents(
 		cs.authAPI(ctx),
 		&events.CleanupReq{
@@ -32,8 +33,8 @@ func (cs Cleaner) DoAction(ctx context.Context) {
 		},
 	)
 	if err != nil {
-		cs.logger.Error("cleaner action: error %s", err.Error())
+		log.Error("cleaner action: error %s", err.Error())
 	} else {
-		cs.logger.Info("cleaner action: OK")
+		log.Info("cleaner action: OK")
 	}
 }
@@ -1,12 +1,19 @@
 package scheduler
 
 import (
+	"context"
+
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/queue"
 )
 
+// Actionable периодическая задача планировщика.
+type Actionable interface {
+	DoAction(context.Context)
+}
+
 type Deps struct {
 	Logger    logger.Logger
 	API       *API
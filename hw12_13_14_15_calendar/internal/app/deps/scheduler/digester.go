@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/queue"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Digester публикация сводок событий в очередь оповещений, время отправки сводки
+// каждого пользователя определяет календарь, задача лишь часто его опрашивает.
+type Digester struct {
+	supportAPI events.SupportClient
+	authAPI    grpc.AuthFn
+	logger     logger.Logger
+
+	mu        sync.RWMutex
+	publisher queue.Producer
+}
+
+func NewDigester(
+	api events.SupportClient, authAPI grpc.AuthFn, publisher queue.Producer, logger logger.Logger,
+) *Digester {
+	return &Digester{
+		supportAPI: api,
+		authAPI:    authAPI,
+		publisher:  publisher,
+		logger:     logger,
+	}
+}
+
+// SetPublisher замена очереди публикации сводок, ждет завершения текущей отправки.
+func (ds *Digester) SetPublisher(publisher queue.Producer) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.publisher = publisher
+}
+
+func (ds *Digester) DoAction(ctx context.Context) {
+	log := logger.FromContext(ctx, ds.logger)
+	digestsPb, err := ds.supportAPI.GetDigests(ds.authAPI(ctx), &emptypb.Empty{})
+	if err != nil {
+		log.Error("digester error getting digests: %s", err.Error())
+		return
+	}
+	digests, err := dto.ToDigestSlice(digestsPb)
+	if err != nil {
+		log.Error("digester error wrong data: %s", err.Error())
+		return
+	}
+	if len(digests) == 0 {
+		log.Debug("digester: no digests to send")
+		return
+	}
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	var sent, failed int
+	for _, digest := range digests {
+		digest := digest
+		// сводка без событий не публикуется, ее отправка только подтверждается.
+		if len(digest.Events) > 0 {
+			if err = ds.publish(ctx, digest); err != nil {
+				// неподтвержденная сводка будет возвращена при следующем запуске.
+				log.Error("digester error sending digest of user %s: %s", digest.UserID.String(), err.Error())
+				failed++
+				continue
+			}
+			sent++
+		}
+		if _, err = ds.supportAPI.SetDigestSent(ds.authAPI(ctx), dto.ToDigestSentReq(digest)); err != nil {
+			log.Error("digester error confirming digest of user %s: %s", digest.UserID.String(), err.Error())
+		}
+	}
+	log.Info("digester: %d digests sent, %d failed", sent, failed)
+}
+
+// publish вызывается под блокировкой публикатора на чтение.
+func (ds *Digester) publish(ctx context.Context, digest model.Digest) error {
+	message, err := queue.EncMessage(&digest)
+	if err != nil {
+		return fmt.Errorf("encoding: %w", err)
+	}
+	return ds.publisher.Produce(ctx, message)
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/queue"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// digestSupport API календаря со сводками в памяти: сводка возвращается, пока не подтверждена.
+type digestSupport struct {
+	events.SupportClient
+	mu      sync.Mutex
+	pending map[uuid.UUID]model.Digest
+}
+
+func (ds *digestSupport) GetDigests(context.Context, *emptypb.Empty, ...grpc.CallOption) (*events.Digests, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	digests := make([]model.Digest, 0, len(ds.pending))
+	for _, digest := range ds.pending {
+		digests = append(digests, digest)
+	}
+	return dto.FromDigestSlice(digests), nil
+}
+
+func (ds *digestSupport) SetDigestSent(
+	_ context.Context, req *events.DigestSentReq, _ ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	userID, _, err := dto.DigestSentReqModel(req)
+	if err != nil {
+		return nil, err
+	}
+	ds.mu.Lock()
+	delete(ds.pending, userID)
+	ds.mu.Unlock()
+	return &emptypb.Empty{}, nil
+}
+
+// failingProducer отклоняет сообщения пользователей из fail, остальные сохраняет.
+type failingProducer struct {
+	fail     map[string]bool
+	produced []model.Digest
+}
+
+func (fp *failingProducer) Produce(_ context.Context, message queue.Message) error {
+	var digest model.Digest
+	if err := json.Unmarshal(message.Body, &digest); err != nil {
+		return err
+	}
+	if fp.fail[digest.NotifyUser.Email] {
+		return errUnavailable
+	}
+	fp.produced = append(fp.produced, digest)
+	return nil
+}
+
+func TestDigester(t *testing.T) {
+	logs, err := logger.NewLogrus(logger.Config{Level: logger.LevelInfo})
+	require.NoError(t, err)
+	noAuth := func(ctx context.Context) context.Context { return ctx }
+
+	sendAt := time.Date(2023, 3, 29, 5, 0, 0, 0, time.UTC)
+	newDigest := func(email string, events int) model.Digest {
+		digest := model.Digest{
+			Kind:       model.MessageDigest,
+			Mode:       "daily",
+			TimeZone:   "UTC",
+			NotifyUser: model.NotifyUser{Email: email},
+			UserID:     uuid.New(),
+			SendAt:     sendAt,
+		}
+		for i := 0; i < events; i++ {
+			digest.Events = append(digest.Events, model.DigestEvent{ID: uuid.New(), Title: "Планерка"})
+		}
+		return digest
+	}
+	failed := newDigest("failed@otus.ru", 1)
+	sent := newDigest("sent@otus.ru", 2)
+	empty := newDigest("empty@otus.ru", 0)
+	api := &digestSupport{pending: map[uuid.UUID]model.Digest{
+		failed.UserID: failed,
+		sent.UserID:   sent,
+		empty.UserID:  empty,
+	}}
+	publisher := &failingProducer{fail: map[string]bool{"failed@otus.ru": true}}
+	digester := NewDigester(api, noAuth, publisher, logs)
+
+	// ошибка публикации одной сводки не мешает остальным, неотправленная сводка не подтверждается.
+	digester.DoAction(context.Background())
+	require.Len(t, publisher.produced, 1)
+	require.Equal(t, "sent@otus.ru", publisher.produced[0].NotifyUser.Email)
+	require.Equal(t, map[uuid.UUID]model.Digest{failed.UserID: failed}, api.pending)
+
+	// при следующем запуске сводка возвращается снова и отправляется.
+	publisher.fail = nil
+	digester.DoAction(context.Background())
+	require.Len(t, publisher.produced, 2)
+	require.Equal(t, "failed@otus.ru", publisher.produced[1].NotifyUser.Email)
+	require.Empty(t, api.pending)
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Elector выбор ведущего экземпляра планировщика через аренду в API календаря.
+// Аренда продлевается каждую треть срока. Если продлить не удалось, экземпляр перестает
+// считать себя ведущим по истечении срока, не дожидаясь ответа API, поэтому два экземпляра
+// не выполняют задачи одновременно. При остановке аренда освобождается сразу.
+type Elector struct {
+	supportAPI events.SupportClient
+	authAPI    grpc.AuthFn
+	logger     logger.Logger
+	lease      string
+	holder     string
+	ttl        time.Duration
+
+	mu sync.RWMutex
+	// stopped закрывается по завершении цикла выборов, nil - выборы не запускались.
+	stopped  chan struct{}
+	leader   bool
+	deadline time.Time
+	// changes количество смен роли этого экземпляра.
+	changes int
+}
+
+func NewElector(
+	api events.SupportClient, authAPI grpc.AuthFn, logger logger.Logger, lease, holder string, ttl time.Duration,
+) *Elector {
+	return &Elector{supportAPI: api, authAPI: authAPI, logger: logger, lease: lease, holder: holder, ttl: ttl}
+}
+
+// HolderID уникальный идентификатор экземпляра: имя хоста, pid и случайный суффикс.
+func HolderID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.New().String()[:8])
+}
+
+func (el *Elector) Holder() string {
+	return el.holder
+}
+
+// IsLeader экземпляр держит аренду и срок ее действия не истек.
+func (el *Elector) IsLeader() bool {
+	el.mu.RLock()
+	defer el.mu.RUnlock()
+	return el.leader && time.Now().Before(el.deadline)
+}
+
+// Run участвует в выборах до завершения ctx.
+func (el *Elector) Run(ctx context.Context) {
+	stopped := make(chan struct{})
+	el.mu.Lock()
+	el.stopped = stopped
+	el.mu.Unlock()
+	el.campaign(ctx)
+	t := time.NewTicker(el.ttl / 3)
+	go func() {
+		defer close(stopped)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				el.campaign(ctx)
+			}
+		}
+	}()
+}
+
+// Resign отказ от роли ведущего после завершения контекста Run, аренда освобождается
+// для других экземпляров. Сначала дожидается завершения цикла выборов, чтобы запрос,
+// выполняемый в этот момент, не захватил аренду повторно.
+func (el *Elector) Resign(ctx context.Context) error {
+	el.mu.RLock()
+	stopped := el.stopped
+	el.mu.RUnlock()
+	if stopped == nil {
+		return nil
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	el.mu.Lock()
+	wasLeader := el.leader
+	el.leader = false
+	el.mu.Unlock()
+	if !wasLeader {
+		return nil
+	}
+	_, err := el.supportAPI.ReleaseLease(el.authAPI(ctx), &events.LeaseReq{Name: el.lease, Holder: el.holder})
+	if err != nil {
+		return fmt.Errorf("error releasing lease '%s': %w", el.lease, err)
+	}
+	el.logger.Info("leader election: lease '%s' released by %s", el.lease, el.holder)
+	return nil
+}
+
+func (el *Elector) campaign(ctx context.Context) {
+	// срок аренды на сервере отсчитывается позже started, локальный срок не превышает его.
+	started := time.Now()
+	lease, err := el.supportAPI.AcquireLease(el.authAPI(ctx), &events.LeaseReq{
+		Name:   el.lease,
+		Holder: el.holder,
+		TTL:    durationpb.New(el.ttl),
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		el.logger.Error("leader election: error %s", err.Error())
+		el.mu.Lock()
+		expired := el.leader && !started.Before(el.deadline)
+		if expired {
+			el.setLeader(false, "lease expired, API unavailable")
+		}
+		el.mu.Unlock()
+		return
+	}
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	if lease.GetHolder() != el.holder {
+		if el.leader {
+			el.setLeader(false, "lease is held by "+lease.GetHolder())
+		} else {
+			el.logger.Debug("leader election: current leader %s", lease.GetHolder())
+		}
+		return
+	}
+	el.deadline = started.Add(el.ttl)
+	if !el.leader {
+		el.setLeader(true, "lease acquired")
+	}
+}
+
+// setLeader смена роли, вызывается под блокировкой.
+func (el *Elector) setLeader(leader bool, reason string) {
+	el.leader = leader
+	el.changes++
+	role := "follower"
+	if leader {
+		role = "leader"
+	}
+	el.logger.Warn("leader election: %s became %s (%s), lease '%s', role changes: %d",
+		el.holder, role, reason, el.lease, el.changes)
+}
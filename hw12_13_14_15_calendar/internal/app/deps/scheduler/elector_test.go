@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository/memory"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errUnavailable = errors.New("api unavailable")
+
+// leaseSupport API календаря с арендой в памяти, остальные методы не используются.
+type leaseSupport struct {
+	events.SupportClient
+	repo repository.Lease
+	down int32
+}
+
+func (ls *leaseSupport) AcquireLease(
+	ctx context.Context, req *events.LeaseReq, _ ...grpc.CallOption,
+) (*events.Lease, error) {
+	if atomic.LoadInt32(&ls.down) == 1 {
+		return nil, errUnavailable
+	}
+	now := time.Now()
+	lease, err := ls.repo.Acquire(ctx, model.Lease{
+		Name:      req.GetName(),
+		Holder:    req.GetHolder(),
+		ExpiresAt: now.Add(req.GetTTL().AsDuration()),
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	return &events.Lease{Name: lease.Name, Holder: lease.Holder, ExpiresAt: timestamppb.New(lease.ExpiresAt)}, nil
+}
+
+func (ls *leaseSupport) ReleaseLease(
+	ctx context.Context, req *events.LeaseReq, _ ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, ls.repo.Release(ctx, req.GetName(), req.GetHolder(), uuid.Nil)
+}
+
+func TestElector(t *testing.T) {
+	logs, err := logger.NewLogrus(logger.Config{Level: logger.LevelInfo})
+	require.NoError(t, err)
+	noAuth := func(ctx context.Context) context.Context { return ctx }
+	api := &leaseSupport{repo: memory.NewLeaseRepo()}
+	ttl := 300 * time.Millisecond
+	first := NewElector(api, noAuth, logs, "scheduler", "first", ttl)
+	second := NewElector(api, noAuth, logs, "scheduler", "second", ttl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	firstCtx, stopFirst := context.WithCancel(ctx)
+
+	first.Run(firstCtx)
+	second.Run(ctx)
+	require.True(t, first.IsLeader())
+	require.False(t, second.IsLeader())
+
+	// аренда продлевается, роль не меняется.
+	time.Sleep(2 * ttl)
+	require.True(t, first.IsLeader())
+	require.False(t, second.IsLeader())
+
+	// при остановке ведущего аренду быстро перехватывает другой экземпляр.
+	stopFirst()
+	require.NoError(t, first.Resign(context.Background()))
+	require.False(t, first.IsLeader())
+	require.Eventually(t, second.IsLeader, ttl, 10*time.Millisecond)
+
+	// без связи с API ведущий слагает полномочия по истечении срока аренды.
+	atomic.StoreInt32(&api.down, 1)
+	require.Eventually(t, func() bool {
+		return !second.IsLeader()
+	}, 2*ttl, 10*time.Millisecond)
+	require.False(t, first.IsLeader())
+}
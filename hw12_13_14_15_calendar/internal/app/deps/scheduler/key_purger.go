@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// KeyPurger удаление истекших ключей идемпотентности.
+type KeyPurger struct {
+	supportAPI events.SupportClient
+	authAPI    grpc.AuthFn
+	logger     logger.Logger
+}
+
+func NewKeyPurger(api events.SupportClient, authAPI grpc.AuthFn, logger logger.Logger) *KeyPurger {
+	return &KeyPurger{supportAPI: api, authAPI: authAPI, logger: logger}
+}
+
+func (kp KeyPurger) DoAction(ctx context.Context) {
+	log := logger.FromContext(ctx, kp.logger)
+	_, err := kp.supportAPI.PurgeIdempotencyKeys(kp.authAPI(ctx), &emptypb.Empty{})
+	if err != nil {
+		log.Error("key purger action: error %s", err.Error())
+	} else {
+		log.Info("key purger action: OK")
+	}
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"sync"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/dto"
@@ -14,9 +15,10 @@ import (
 type Notifier struct {
 	supportAPI events.SupportClient
 	authAPI    grpc.AuthFn
-	publisher  queue.Producer
 	logger     logger.Logger
 
+	mu        sync.RWMutex
+	publisher queue.Producer
 	queueName string
 }
 
@@ -32,33 +34,43 @@ func NewNotifier(
 	}
 }
 
-func (ns Notifier) DoAction(ctx context.Context) {
+// SetPublisher замена очереди публикации уведомлений, ждет завершения текущей отправки.
+func (ns *Notifier) SetPublisher(publisher queue.Producer, queueName string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.publisher, ns.queueName = publisher, queueName
+}
+
+func (ns *Notifier) DoAction(ctx context.Context) {
+	log := logger.FromContext(ctx, ns.logger)
 	notificationsPb, err := ns.supportAPI.GetNotifications(ns.authAPI(ctx), &emptypb.Empty{})
 	if err != nil {
-		ns.logger.Error("notifier error getting notifications: %s", err.Error())
+		log.Error("notifier error getting notifications: %s", err.Error())
 		return
 	}
 	notifications, err := dto.ToNotificationSlice(notificationsPb)
 	if err != nil {
-		ns.logger.Error("notifier error wrong data: %s", err.Error())
+		log.Error("notifier error wrong data: %s", err.Error())
 		return
 	}
 	if notifications == nil {
-		ns.logger.Info("notifier: no new notifications")
+		log.Info("notifier: no new notifications")
 		return
 	}
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
 	for _, note := range notifications {
 		note := note
 		message, err := queue.EncMessage(&note)
 		if err != nil {
-			ns.logger.Info("notifier error encoding notification: %s", err.Error())
+			log.Info("notifier error encoding notification: %s", err.Error())
 			return
 		}
-		err = ns.publisher.Produce(message)
+		err = ns.publisher.Produce(ctx, message)
 		if err != nil {
-			ns.logger.Info("notifier error sending notification: %s", err.Error())
+			log.Info("notifier error sending notification: %s", err.Error())
 			return
 		}
 	}
-	ns.logger.Info("notifier: %d notifications sent", len(notifications))
+	log.Info("notifier: %d notifications sent", len(notifications))
 }
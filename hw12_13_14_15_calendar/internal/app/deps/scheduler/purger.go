@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Purger окончательное удаление событий, пролежавших в корзине дольше retentionTime.
+type Purger struct {
+	supportAPI events.SupportClient
+	authAPI    grpc.AuthFn
+	logger     logger.Logger
+
+	retentionTime time.Duration
+}
+
+func NewPurger(
+	api events.SupportClient, authAPI grpc.AuthFn, logger logger.Logger, retentionTime time.Duration,
+) *Purger {
+	return &Purger{supportAPI: api, authAPI: authAPI, logger: logger, retentionTime: retentionTime}
+}
+
+func (ps Purger) DoAction(ctx context.Context) {
+	log := logger.FromContext(ctx, ps.logger)
+	_, err := ps.supportAPI.PurgeTrash(
+		ps.authAPI(ctx),
+		&events.PurgeTrashReq{
+			RetentionTime: durationpb.New(ps.retentionTime),
+		},
+	)
+	if err != nil {
+		log.Error("purger action: error %s", err.Error())
+	} else {
+		log.Info("purger action: OK")
+	}
+}
@@ -3,6 +3,7 @@ package sender
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
@@ -45,43 +46,100 @@ func (s Sender) Run(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case message := <-msgChan:
-				var note model.Notification
-				err := message.Decode(&note)
-				if err != nil {
-					s.logger.Error("sender can't parse notification: %s", err.Error())
-					break
-				}
-				err = s.sendMailAndConfirm(ctx, note)
-				if err != nil {
-					s.logger.Error("sender can't sending notification: %s", err.Error())
-					break
-				}
-				s.logger.Info(
-					"notification event %s on %s sent to %s",
-					note.EventID.String(), note.EventDate.String(), note.NotifyUser.Email,
-				)
+				s.handle(ctx, message)
 			}
 		}
 	}()
 	return nil
 }
 
-func (s Sender) sendMailAndConfirm(ctx context.Context, note model.Notification) error {
-	dateFmt := "02.01.2006 15:04"
-	sendData := map[string]string{
-		"UserName":       note.NotifyUser.Name,
-		"UserEmail":      note.NotifyUser.Email,
-		"EventTitle":     note.EventTitle,
-		"EventID":        note.EventID.String(),
-		"SenderEmail":    s.defaultFrom,
-		"EventDateStart": note.EventDate.Format(dateFmt),
-		"EventDateEnd":   note.EventDate.Add(note.EventDuration).Format(dateFmt),
+// handle отправка письма по сообщению очереди: сводки отмечены признаком kind,
+// остальные сообщения - оповещения о событиях. Записи журнала и подтверждение оповещения
+// несут идентификатор запроса планировщика, опубликовавшего сообщение.
+func (s Sender) handle(ctx context.Context, message queue.Message) {
+	ctx = message.Context(ctx)
+	log := logger.FromContext(ctx, s.logger)
+	var envelope struct {
+		Kind string `json:"kind"`
+	}
+	if err := message.Decode(&envelope); err != nil {
+		log.Error("sender can't parse message: %s", err.Error())
+		return
+	}
+	if envelope.Kind == model.MessageDigest {
+		var digest model.Digest
+		if err := message.Decode(&digest); err != nil {
+			log.Error("sender can't parse digest: %s", err.Error())
+			return
+		}
+		if err := s.sendDigest(digest); err != nil {
+			log.Error("sender can't sending digest: %s", err.Error())
+			return
+		}
+		log.Info(
+			"%s digest with %d events sent to %s", digest.Mode, len(digest.Events), digest.NotifyUser.Email,
+		)
+		return
+	}
+	var note model.Notification
+	if err := message.Decode(&note); err != nil {
+		log.Error("sender can't parse notification: %s", err.Error())
+		return
+	}
+	if err := s.sendMailAndConfirm(ctx, note); err != nil {
+		log.Error("sender can't sending notification: %s", err.Error())
+		return
+	}
+	log.Info(
+		"notification event %s on %s sent to %s",
+		note.EventID.String(), note.EventDate.String(), note.NotifyUser.Email,
+	)
+}
+
+// notifyData данные шаблона events/notify, даты форматируются в шаблоне функциями языка получателя.
+type notifyData struct {
+	UserName    string
+	UserEmail   string
+	EventTitle  string
+	EventID     string
+	Start       time.Time
+	End         time.Time
+	Duration    time.Duration
+	Links       []notifyLink
+	SenderEmail string
+}
+
+// notifyLink ссылка события, Video - ссылка на видеовстречу, иначе на документ.
+type notifyLink struct {
+	Video bool
+	URL   string
+	Title string
+}
+
+func notifyLinks(links []model.EventLink) []notifyLink {
+	result := make([]notifyLink, len(links))
+	for i, link := range links {
+		result[i] = notifyLink{Video: link.Type == model.LinkVideo, URL: link.URL, Title: link.Title}
 	}
+	return result
+}
+
+func (s Sender) sendMailAndConfirm(ctx context.Context, note model.Notification) error {
 	err := s.mailer.SendMail("events/notify", mailer.Mail{
-		Sender:  s.defaultFrom,
-		To:      []string{note.NotifyUser.Email},
-		Subject: fmt.Sprintf("%s: начнется %s", note.EventTitle, note.EventDate.Format(dateFmt)),
-		Data:    sendData,
+		Sender: s.defaultFrom,
+		To:     []string{note.NotifyUser.Email},
+		Locale: note.NotifyUser.Locale,
+		Data: notifyData{
+			UserName:    note.NotifyUser.Name,
+			UserEmail:   note.NotifyUser.Email,
+			EventTitle:  note.EventTitle,
+			EventID:     note.EventID.String(),
+			Start:       note.EventDate,
+			End:         note.EventDate.Add(note.EventDuration),
+			Duration:    note.EventDuration,
+			Links:       notifyLinks(note.EventLinks),
+			SenderEmail: s.defaultFrom,
+		},
 	})
 	if err != nil {
 		return err
@@ -89,3 +147,54 @@ func (s Sender) sendMailAndConfirm(ctx context.Context, note model.Notification)
 	_, err = s.supportAPI.SetNotified(s.authAPI(ctx), &events.NotificationIDReq{ID: note.EventID.String()})
 	return err
 }
+
+// digestData данные шаблона events/digest, время - в часовом поясе пользователя.
+type digestData struct {
+	UserName  string
+	UserEmail string
+	Weekly    bool
+	// DateStart, DateEnd первый и последний день периода.
+	DateStart   time.Time
+	DateEnd     time.Time
+	TimeZone    string
+	Events      []digestEvent
+	SenderEmail string
+}
+
+type digestEvent struct {
+	Title    string
+	EventID  string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+}
+
+// sendDigest сводка не подтверждается: календарь отмечает ее отправленной при выдаче.
+func (s Sender) sendDigest(digest model.Digest) error {
+	loc := digest.Location()
+	data := digestData{
+		UserName:    digest.NotifyUser.Name,
+		UserEmail:   digest.NotifyUser.Email,
+		Weekly:      digest.Mode == model.DigestWeekly.String(),
+		DateStart:   digest.DateStart.In(loc),
+		DateEnd:     digest.DateEnd.In(loc).Add(-time.Nanosecond),
+		TimeZone:    digest.TimeZone,
+		Events:      make([]digestEvent, len(digest.Events)),
+		SenderEmail: s.defaultFrom,
+	}
+	for i, event := range digest.Events {
+		data.Events[i] = digestEvent{
+			Title:    event.Title,
+			EventID:  event.ID.String(),
+			Start:    event.Date.In(loc),
+			End:      event.Date.Add(event.Duration).In(loc),
+			Duration: event.Duration,
+		}
+	}
+	return s.mailer.SendMail("events/digest", mailer.Mail{
+		Sender: s.defaultFrom,
+		To:     []string{digest.NotifyUser.Email},
+		Locale: digest.NotifyUser.Locale,
+		Data:   data,
+	})
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	config "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/calendar"
+	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/migrations"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/migrator"
+)
+
+var (
+	ErrMigrateCommand = errors.New("неизвестная команда миграций, ожидается up, down или status")
+	ErrMigrateStorage = errors.New("миграции выполняются только для хранилища pgsql")
+	ErrDBConnect      = errors.New("не удалось подключиться к базе данных")
+)
+
+// Migrate выполнение команды миграций up, down или status встроенными миграциями.
+func Migrate(ctx context.Context, cfg config.Config, command string, out io.Writer) error {
+	if cfg.Storage.Type != "pgsql" {
+		return ErrMigrateStorage
+	}
+	log, err := logger.NewLogrus(logger.Config{Level: logger.LevelInfo})
+	if err != nil {
+		return err
+	}
+	db, closeFn := deps.NewPgConn(cfg.ServiceID, cfg.Storage.PGConn, log)
+	if db == nil {
+		return ErrDBConnect
+	}
+	defer func() {
+		_ = closeFn(ctx)
+	}()
+	m := migrator.New(db, migrations.FS, out)
+	switch command {
+	case "up":
+		return m.UpLocked(ctx)
+	case "down":
+		return m.Down()
+	case "status":
+		list, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return migrator.WriteStatus(out, list)
+	}
+	return fmt.Errorf("%w: %s", ErrMigrateCommand, command)
+}
+
+// prepareSchema применение миграций при storage.autoMigrate, затем проверка версии схемы:
+// сервис не запускается со схемой, в которой не хватает его миграций.
+func prepareSchema(ctx context.Context, db *sql.DB, autoMigrate bool, log logger.Logger) error {
+	m := migrator.New(db, migrations.FS, logWriter{log: log})
+	if autoMigrate {
+		if err := m.UpLocked(ctx); err != nil {
+			return fmt.Errorf("ошибка применения миграций: %w", err)
+		}
+	}
+	if err := migrator.CheckVersion(m); err != nil {
+		return fmt.Errorf("%w, выполните calendar migrate up или включите storage.autoMigrate", err)
+	}
+	return nil
+}
+
+// logWriter вывод goose в лог сервиса.
+type logWriter struct {
+	log logger.Logger
+}
+
+func (lw logWriter) Write(p []byte) (int, error) {
+	if msg := strings.TrimSpace(string(p)); msg != "" {
+		lw.log.Info("migrations: %s", msg)
+	}
+	return len(p), nil
+}
@@ -2,7 +2,11 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"sync"
 	"time"
 
 	config "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/scheduler"
@@ -10,36 +14,58 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/queue"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/closer"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/cron"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
 )
 
 type Scheduler struct {
-	config config.Config
-	logger logger.Logger
-	deps   *deps.Deps
-	closer *closer.Closer
+	config     config.Config
+	configFile string
+	logger     logger.Logger
+	deps       *deps.Deps
+	closer     *closer.Closer
+	elector    *deps.Elector
+	cron       *cron.Scheduler
+
+	mu              sync.Mutex
+	notifier        *deps.Notifier
+	digester        *deps.Digester
+	publisherCloser closer.CloseFunc
 }
 
-func NewScheduler(config config.Config) App {
-	return &Scheduler{config: config, closer: closer.NewCloser()}
+// schedulerJob имя задачи и ее настройки в конфигурации.
+type schedulerJob struct {
+	name   string
+	config func(*config.Config) *config.Job
+}
+
+var schedulerJobs = []schedulerJob{
+	{name: "notifier", config: func(c *config.Config) *config.Job { return &c.Notify.Job }},
+	{name: "digester", config: func(c *config.Config) *config.Job { return &c.Digest.Job }},
+	{name: "cleaner", config: func(c *config.Config) *config.Job { return &c.Cleanup.Job }},
+	{name: "purger", config: func(c *config.Config) *config.Job { return &c.Trash.Job }},
+	{name: "key-purger", config: func(c *config.Config) *config.Job { return &c.Idempotency.Job }},
+}
+
+func NewScheduler(config config.Config, configFile string) App {
+	return &Scheduler{config: config, configFile: configFile, closer: closer.NewCloser()}
 }
 
 func (sa *Scheduler) Initialize(_ context.Context) error {
-	logLevel, err := logger.ParseLevel(sa.config.Logger.Level)
-	if err != nil {
-		return fmt.Errorf("'%s': %w", sa.config.Logger.Level, err)
+	var err error
+	if sa.logger, err = newLogger(sa.config.Logger); err != nil {
+		return err
 	}
-	sa.logger, err = logger.NewLogrus(logger.Config{
-		Level:    logLevel,
-		FileName: sa.config.Logger.FileName,
-	})
+
+	apiOpts, err := apiClientOptions(sa.config.API.Calendar, sa.logger)
 	if err != nil {
-		return fmt.Errorf("unable start logger: %w", err)
+		return err
 	}
-
 	supportAPI, authFn, err := grpc.NewSupportClient(
 		sa.config.API.Calendar.Address,
 		sa.config.APILogin,
+		apiOpts...,
 	)
 	if err != nil {
 		return fmt.Errorf("error initialize SupportAPI: %w", err)
@@ -50,6 +76,11 @@ func (sa *Scheduler) Initialize(_ context.Context) error {
 		return fmt.Errorf("error start queue publisher: %w", err)
 	}
 	sa.closer.Register("Queue publisher", closerFn)
+	sa.publisherCloser = closerFn
+
+	ttl, _ := sa.config.Election.TTL.AsDuration()
+	sa.elector = deps.NewElector(supportAPI, authFn, sa.logger, sa.config.Election.Lease, deps.HolderID(), ttl)
+	sa.closer.Register("Leader lease", sa.elector.Resign)
 
 	sa.deps = &deps.Deps{
 		API:       &deps.API{Support: supportAPI},
@@ -57,36 +88,163 @@ func (sa *Scheduler) Initialize(_ context.Context) error {
 		Logger:    sa.logger,
 		Publisher: publisher,
 	}
-	return nil
+	return sa.initJobs()
 }
 
-func (sa *Scheduler) Run(ctx context.Context) error {
+// initJobs регистрация задач, выполняет их только ведущий экземпляр.
+func (sa *Scheduler) initJobs() error {
 	supAPI := sa.deps.API.Support
-
-	notifier := deps.NewNotifier(supAPI, sa.deps.APIAuth, sa.deps.Publisher, sa.logger, sa.config.Notify.QueuePublish)
-	checkingTime, _ := sa.config.Notify.CheckingTime.AsDuration()
-	notifierRun := deps.NewRepeated(notifier, checkingTime, sa.logger)
-
+	sa.notifier = deps.NewNotifier(
+		supAPI, sa.deps.APIAuth, sa.deps.Publisher, sa.logger, sa.config.Notify.QueuePublish,
+	)
+	sa.digester = deps.NewDigester(supAPI, sa.deps.APIAuth, sa.deps.Publisher, sa.logger)
 	storeTime, _ := sa.config.Cleanup.StoreTime.AsDuration()
-	cleaner := deps.NewCleaner(supAPI, sa.deps.APIAuth, sa.logger, storeTime)
+	retentionTime, _ := sa.config.Trash.RetentionTime.AsDuration()
+	services := map[string]deps.Actionable{
+		"notifier":   sa.notifier,
+		"digester":   sa.digester,
+		"cleaner":    deps.NewCleaner(supAPI, sa.deps.APIAuth, sa.logger, storeTime),
+		"purger":     deps.NewPurger(supAPI, sa.deps.APIAuth, sa.logger, retentionTime),
+		"key-purger": deps.NewKeyPurger(supAPI, sa.deps.APIAuth, sa.logger),
+	}
 
-	checkingTime, _ = sa.config.Cleanup.CheckingTime.AsDuration()
-	cleanerRun := deps.NewRepeated(cleaner, checkingTime, sa.logger)
+	var store cron.Store
+	if sa.config.StateFile != "" {
+		store = cron.NewFileStore(sa.config.StateFile)
+	}
+	sa.cron = cron.New(store, sa.logger)
+	sa.cron.SetGuard(sa.elector.IsLeader)
+	loc, err := sa.config.Location()
+	if err != nil {
+		return err
+	}
+	for _, job := range schedulerJobs {
+		spec, err := job.config(&sa.config).Spec(job.name, withRequestID(services[job.name].DoAction), loc)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.name, err)
+		}
+		if err = sa.cron.Add(spec); err != nil {
+			return err
+		}
+	}
+	// задачи останавливаются первыми: выполняющиеся публикуют сообщения и продлевают аренду.
+	sa.closer.Register("Jobs", sa.cron.Stop, closer.DependsOn("Queue publisher", "Leader lease"))
+	return nil
+}
 
-	notifierRun.Repeat(ctx)
-	cleanerRun.Repeat(ctx)
+// withRequestID каждое выполнение задачи получает свой идентификатор запроса: он попадает
+// в записи журнала задачи, вызовы API и заголовки сообщений очереди.
+func withRequestID(run func(context.Context)) func(context.Context) {
+	return func(ctx context.Context) {
+		run(requestid.NewContext(ctx, requestid.New()))
+	}
+}
 
-	sa.logger.Info("scheduler is running...")
+func (sa *Scheduler) Run(ctx context.Context) error {
+	// задачи выполняет только ведущий экземпляр, остальные ждут освобождения аренды.
+	sa.elector.Run(ctx)
+	if err := sa.cron.Start(ctx); err != nil {
+		return fmt.Errorf("error start jobs: %w", err)
+	}
+	for _, state := range sa.cron.Status() {
+		sa.logger.Info("job %s: schedule '%s', next run %s",
+			state.Name, state.Schedule, state.NextRun.Format(time.RFC3339))
+	}
+	sa.logger.Info("scheduler %s is running...", sa.elector.Holder())
 
 	<-ctx.Done()
 	return nil
 }
 
+// Reload перечитывает конфигурацию и применяет без перезапуска уровень логирования,
+// расписание задач и очередь публикации уведомлений.
+func (sa *Scheduler) Reload(ctx context.Context) error {
+	cfg, err := config.New(sa.configFile)
+	if err != nil {
+		return err
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		return err
+	}
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	if err = reloadLogLevel(sa.logger, cfg.Logger.Level); err != nil {
+		return err
+	}
+	if cfg.Notify.QueuePublish != sa.config.Notify.QueuePublish {
+		if err = sa.switchPublisher(ctx, cfg.Notify.QueuePublish); err != nil {
+			return err
+		}
+	}
+	for _, job := range schedulerJobs {
+		spec, err := job.config(&cfg).Spec(job.name, nil, loc)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.name, err)
+		}
+		if err = sa.cron.Update(spec); err != nil {
+			return err
+		}
+		*job.config(&sa.config) = *job.config(&cfg)
+	}
+	sa.config.Logger.Level = cfg.Logger.Level
+	sa.config.Notify.QueuePublish = cfg.Notify.QueuePublish
+	sa.config.Timezone = cfg.Timezone
+	if !reflect.DeepEqual(sa.config, cfg) {
+		sa.logger.Warn("configuration reloaded, changes except logger.level, job schedules " +
+			"and notify.queuePublish require restart")
+		return nil
+	}
+	sa.logger.Info("configuration reloaded")
+	return nil
+}
+
+// switchPublisher подключение к новой очереди, прежнее соединение закрывается после переключения.
+func (sa *Scheduler) switchPublisher(ctx context.Context, queueName string) error {
+	publisher, closerFn, err := queue.NewProducer(sa.config.AMQP, sa.logger, queueName)
+	if err != nil {
+		return fmt.Errorf("error start queue publisher: %w", err)
+	}
+	sa.deps.Publisher = publisher
+	if sa.notifier != nil {
+		sa.notifier.SetPublisher(publisher, queueName)
+	}
+	if sa.digester != nil {
+		sa.digester.SetPublisher(publisher)
+	}
+	prevCloser := sa.publisherCloser
+	sa.closer.Register("Queue publisher", closerFn)
+	sa.publisherCloser = closerFn
+	if err = prevCloser(ctx); err != nil {
+		sa.logger.Error("error closing previous queue publisher: %s", err.Error())
+	}
+	sa.logger.Info("notifications and digests are published to '%s'", queueName)
+	return nil
+}
+
 func (sa *Scheduler) Close() {
 	// 10 секунд на завершение
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	sa.closer.Close(ctx, sa.logger)
+	if err := sa.closer.Close(ctx, sa.logger); err != nil {
+		sa.logger.Error("scheduler stopped with errors: %s", err.Error())
+		return
+	}
 	sa.logger.Info("scheduler stopped")
 }
+
+// ErrNoStateFile состояние задач не сохраняется, просматривать нечего.
+var ErrNoStateFile = errors.New("stateFile не задан в конфигурации, состояние задач не сохраняется")
+
+// PrintJobs таблица задач из файла состояния запущенного планировщика.
+func PrintJobs(cfg config.Config, out io.Writer) error {
+	if cfg.StateFile == "" {
+		return ErrNoStateFile
+	}
+	states, err := cron.NewFileStore(cfg.StateFile).Load()
+	if err != nil {
+		return err
+	}
+	return cron.WriteStatus(out, states)
+}
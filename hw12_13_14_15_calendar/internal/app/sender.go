@@ -3,6 +3,8 @@ package app
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	config "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config/sender"
@@ -11,36 +13,49 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/queue"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/closer"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/mailer"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/mailer/stdout"
+	pkgqueue "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/queue"
 )
 
 type Sender struct {
-	config config.Config
-	logger logger.Logger
-	deps   *deps.Deps
-	closer *closer.Closer
+	config     config.Config
+	configFile string
+	logger     logger.Logger
+	deps       *deps.Deps
+	closer     *closer.Closer
+	templates  *mailer.Registry
+
+	mu sync.Mutex
+	// listenerCloser и stopListen закрытие соединения и остановка текущего обработчика очереди.
+	listenerCloser closer.CloseFunc
+	stopListen     context.CancelFunc
+	runCtx         context.Context
 }
 
-func NewSender(config config.Config) App {
-	return &Sender{config: config, closer: closer.NewCloser()}
+func NewSender(config config.Config, configFile string) App {
+	return &Sender{config: config, configFile: configFile, closer: closer.NewCloser()}
 }
 
 func (sa *Sender) Initialize(_ context.Context) error {
-	logLevel, err := logger.ParseLevel(sa.config.Logger.Level)
-	if err != nil {
-		return fmt.Errorf("'%s': %w", sa.config.Logger.Level, err)
+	var err error
+	if sa.logger, err = newLogger(sa.config.Logger); err != nil {
+		return err
 	}
-	sa.logger, err = logger.NewLogrus(logger.Config{
-		Level:    logLevel,
-		FileName: sa.config.Logger.FileName,
-	})
+
+	sa.templates, err = mailer.NewRegistry(sa.config.Mailer.TemplatePath, sa.config.Mailer.DefaultLocale)
 	if err != nil {
-		return fmt.Errorf("unable start logger: %w", err)
+		return fmt.Errorf("error loading mail templates: %w", err)
 	}
 
+	apiOpts, err := apiClientOptions(sa.config.API.Calendar, sa.logger)
+	if err != nil {
+		return err
+	}
 	supportAPI, authFn, err := grpc.NewSupportClient(
 		sa.config.API.Calendar.Address,
 		sa.config.APILogin,
+		apiOpts...,
 	)
 	if err != nil {
 		return fmt.Errorf("error initialize SupportAPI: %w", err)
@@ -51,6 +66,7 @@ func (sa *Sender) Initialize(_ context.Context) error {
 		return fmt.Errorf("error start queue listener: %w", err)
 	}
 	sa.closer.Register("Queue listener", closerFn)
+	sa.listenerCloser = closerFn
 
 	sa.deps = &deps.Deps{
 		Logger:   sa.logger,
@@ -58,7 +74,7 @@ func (sa *Sender) Initialize(_ context.Context) error {
 		APIAuth:  authFn,
 		Listener: listener,
 		Mailer: stdout.NewMailer(&stdout.Config{
-			TmplPath:    sa.config.Mailer.TemplatePath,
+			Templates:   sa.templates,
 			DefaultFrom: sa.config.Mailer.DefaultFrom,
 		}),
 	}
@@ -70,17 +86,85 @@ func (sa *Sender) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	sa.mu.Lock()
+	sa.runCtx = ctx
+	err := sa.listen(sa.deps.Listener, sa.config.Notify.QueueListen)
+	sa.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	sa.logger.Info("sender is running...")
+
+	<-ctx.Done()
+	return nil
+}
+
+// listen запуск обработчика очереди queueName, вызывается под sa.mu.
+func (sa *Sender) listen(listener pkgqueue.Consumer, queueName string) error {
+	ctx, cancel := context.WithCancel(sa.runCtx)
 	service := deps.NewSender(
-		sa.deps.API.Support, sa.deps.APIAuth, sa.deps.Listener, sa.logger, sa.deps.Mailer,
-		sa.config.Notify.QueueListen, sa.config.Mailer.DefaultFrom,
+		sa.deps.API.Support, sa.deps.APIAuth, listener, sa.logger, sa.deps.Mailer,
+		queueName, sa.config.Mailer.DefaultFrom,
 	)
-
 	if err := service.Run(ctx); err != nil {
+		cancel()
 		return err
 	}
-	sa.logger.Info("sender is running...")
+	sa.stopListen = cancel
+	return nil
+}
 
-	<-ctx.Done()
+// Reload перечитывает конфигурацию и шаблоны писем, применяет без перезапуска уровень логирования
+// и очередь уведомлений.
+func (sa *Sender) Reload(ctx context.Context) error {
+	cfg, err := config.New(sa.configFile)
+	if err != nil {
+		return err
+	}
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	if err = reloadLogLevel(sa.logger, cfg.Logger.Level); err != nil {
+		return err
+	}
+	if err = sa.templates.Reload(); err != nil {
+		return fmt.Errorf("error reloading mail templates: %w", err)
+	}
+	if cfg.Notify.QueueListen != sa.config.Notify.QueueListen && sa.runCtx != nil {
+		if err = sa.switchListener(ctx, cfg.Notify.QueueListen); err != nil {
+			return err
+		}
+	}
+	sa.config.Logger.Level = cfg.Logger.Level
+	sa.config.Notify.QueueListen = cfg.Notify.QueueListen
+	if !reflect.DeepEqual(sa.config, cfg) {
+		sa.logger.Warn("configuration reloaded, changes except logger.level and notify.queueListen require restart")
+		return nil
+	}
+	sa.logger.Info("configuration reloaded")
+	return nil
+}
+
+// switchListener подписка на новую очередь, прежний обработчик останавливается после запуска нового.
+func (sa *Sender) switchListener(ctx context.Context, queueName string) error {
+	listener, closerFn, err := queue.NewConsumer(sa.config.AMQP, sa.logger, queueName)
+	if err != nil {
+		return fmt.Errorf("error start queue listener: %w", err)
+	}
+	stopPrev, closePrev := sa.stopListen, sa.listenerCloser
+	if err = sa.listen(listener, queueName); err != nil {
+		if errClose := closerFn(ctx); errClose != nil {
+			sa.logger.Error("error closing queue listener: %s", errClose.Error())
+		}
+		return err
+	}
+	sa.deps.Listener = listener
+	sa.closer.Register("Queue listener", closerFn)
+	sa.listenerCloser = closerFn
+	stopPrev()
+	if err = closePrev(ctx); err != nil {
+		sa.logger.Error("error closing previous queue listener: %s", err.Error())
+	}
+	sa.logger.Info("notifications are consumed from '%s'", queueName)
 	return nil
 }
 
@@ -89,6 +173,9 @@ func (sa *Sender) Close() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	sa.closer.Close(ctx, sa.logger)
+	if err := sa.closer.Close(ctx, sa.logger); err != nil {
+		sa.logger.Error("sender stopped with errors: %s", err.Error())
+		return
+	}
 	sa.logger.Info("sender stopped")
 }
@@ -0,0 +1,96 @@
+package ctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+func completionCommand() *Command {
+	return &Command{
+		Name:    "completion",
+		Summary: "скрипт автодополнения: source <(calendarctl completion bash)",
+		Args:    "<bash|zsh>",
+		Values:  []string{"bash", "zsh"},
+		Setup: func(fs *flag.FlagSet) Handler {
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := argsN(args, 1, "completion <bash|zsh>"); err != nil {
+					return err
+				}
+				switch args[0] {
+				case "bash":
+					writeBashCompletion(env.Out, Root())
+				case "zsh":
+					// zsh выполняет bash-скрипты дополнения через bashcompinit.
+					fmt.Fprintln(env.Out, "autoload -U +X bashcompinit && bashcompinit")
+					writeBashCompletion(env.Out, Root())
+				default:
+					return fmt.Errorf("%w: оболочка %s не поддерживается", ErrUsage, args[0])
+				}
+				return nil
+			}
+		},
+	}
+}
+
+// completionWords варианты дополнения для каждого пути команд: вложенные команды или флаги.
+func completionWords(cmd *Command, path string, words map[string][]string) {
+	if cmd.Setup != nil {
+		fs := flag.NewFlagSet(path, flag.ContinueOnError)
+		fs.String("profile", "", "")
+		fs.String("o", "", "")
+		cmd.Setup(fs)
+		words[path] = append(words[path], cmd.Values...)
+		fs.VisitAll(func(f *flag.Flag) {
+			words[path] = append(words[path], "-"+f.Name)
+		})
+		sort.Strings(words[path])
+		return
+	}
+	for _, sub := range cmd.Commands {
+		words[path] = append(words[path], sub.Name)
+		completionWords(sub, strings.TrimSpace(path+" "+sub.Name), words)
+	}
+	sort.Strings(words[path])
+}
+
+func writeBashCompletion(w io.Writer, root *Command) {
+	words := make(map[string][]string)
+	completionWords(root, "", words)
+	paths := make([]string, 0, len(words))
+	for path := range words {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintln(w, "_calendarctl() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" path="" i`)
+	fmt.Fprintln(w, "    for ((i = 1; i < COMP_CWORD; i++)); do")
+	fmt.Fprintln(w, `        [[ "${COMP_WORDS[i]}" == -* ]] && break`)
+	fmt.Fprintln(w, `        path="${path:+$path }${COMP_WORDS[i]}"`)
+	fmt.Fprintln(w, "    done")
+	fmt.Fprintln(w, `    case "$path" in`)
+	for _, path := range paths {
+		fmt.Fprintf(w, "        %q) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", path, strings.Join(words[path], " "))
+	}
+	fmt.Fprintln(w, `        *) COMPREPLY=($(compgen -f -- "$cur")) ;;`)
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -F _calendarctl calendarctl")
+}
+
+func versionCommand() *Command {
+	return &Command{
+		Name:    "version",
+		Summary: "версия calendarctl",
+		Setup: func(fs *flag.FlagSet) Handler {
+			return func(ctx context.Context, env *Env, args []string) error {
+				fmt.Fprintln(env.Out, env.Version)
+				return nil
+			}
+		},
+	}
+}
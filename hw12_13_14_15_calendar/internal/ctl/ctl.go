@@ -0,0 +1,177 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/client/calendar"
+)
+
+var (
+	ErrUsage          = errors.New("неверные аргументы команды")
+	ErrUnknownCommand = errors.New("неизвестная команда")
+	ErrUnknownFormat  = errors.New("неизвестный формат вывода, ожидается table или json")
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// Handler выполнение команды, args - аргументы после флагов.
+type Handler func(ctx context.Context, env *Env, args []string) error
+
+// Command команда calendarctl: либо группа вложенных команд, либо команда с обработчиком.
+type Command struct {
+	Name    string
+	Summary string
+	// Args описание позиционных аргументов для справки: "<id>".
+	Args string
+	// Values варианты позиционного аргумента для автодополнения.
+	Values []string
+	// Setup регистрирует флаги команды и возвращает обработчик, читающий их значения.
+	Setup    func(fs *flag.FlagSet) Handler
+	Commands []*Command
+}
+
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Env окружение выполнения команд.
+type Env struct {
+	Out io.Writer
+	Err io.Writer
+	// ConfigFile файл профилей, см. Profiles.
+	ConfigFile string
+	// Profile имя профиля, пусто - текущий профиль из файла.
+	Profile string
+	Format  string
+	Version string
+
+	// NewClient и NewSupportClient подключение к API, в тестах подменяются.
+	NewClient        func(baseURL, login string) calendar.Client
+	NewSupportClient func(addr, login string, opts ...grpc.ClientOption) (events.SupportClient, grpc.AuthFn, error)
+}
+
+// NewEnv окружение с клиентами API по умолчанию.
+func NewEnv(out, errOut io.Writer, configFile string) *Env {
+	return &Env{
+		Out:              out,
+		Err:              errOut,
+		ConfigFile:       configFile,
+		Format:           FormatTable,
+		NewClient:        calendar.NewClient,
+		NewSupportClient: grpc.NewSupportClient,
+	}
+}
+
+// Root дерево команд calendarctl.
+func Root() *Command {
+	return &Command{
+		Name:    "calendarctl",
+		Summary: "администрирование сервиса «Календарь»",
+		Commands: []*Command{
+			loginCommand(),
+			profileCommand(),
+			eventsCommand(),
+			cleanupCommand(),
+			notificationsCommand(),
+			migrateCommand(),
+			completionCommand(),
+			versionCommand(),
+		},
+	}
+}
+
+// Run выполнение команды по аргументам командной строки без имени программы.
+func Run(ctx context.Context, env *Env, args []string) error {
+	root := Root()
+	cmd, path := root, []string{root.Name}
+	for len(args) > 0 && cmd.Setup == nil {
+		if args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+			printHelp(env.Out, cmd, path)
+			return nil
+		}
+		sub := cmd.find(args[0])
+		if sub == nil {
+			printHelp(env.Err, cmd, path)
+			return fmt.Errorf("%w: %s", ErrUnknownCommand, strings.Join(append(path[1:], args[0]), " "))
+		}
+		cmd, path, args = sub, append(path, sub.Name), args[1:]
+	}
+	if cmd.Setup == nil {
+		printHelp(env.Err, cmd, path)
+		return ErrUsage
+	}
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	fs.SetOutput(env.Err)
+	fs.StringVar(&env.Profile, "profile", env.Profile, "имя профиля подключения")
+	fs.StringVar(&env.Format, "o", env.Format, "формат вывода: table или json")
+	handler := cmd.Setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Err, "Использование: %s [флаги] %s\n\n%s\n\n", fs.Name(), cmd.Args, cmd.Summary)
+		fs.PrintDefaults()
+	}
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrUsage, err.Error())
+	}
+	if env.Format != FormatTable && env.Format != FormatJSON {
+		return ErrUnknownFormat
+	}
+	return handler(ctx, env, args)
+}
+
+// parseInterspersed разбор флагов до и после позиционных аргументов: events update <id> -title ...,
+// после "--" все аргументы позиционные.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional, args = append(positional, rest[0]), rest[1:]
+	}
+}
+
+func printHelp(w io.Writer, cmd *Command, path []string) {
+	fmt.Fprintf(w, "%s - %s\n\nКоманды:\n", strings.Join(path, " "), cmd.Summary)
+	subs := append([]*Command(nil), cmd.Commands...)
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].Name < subs[j].Name
+	})
+	for _, sub := range subs {
+		fmt.Fprintf(w, "  %-14s %s\n", sub.Name, sub.Summary)
+	}
+}
+
+// argsN проверка числа позиционных аргументов.
+func argsN(args []string, n int, usage string) error {
+	if len(args) != n {
+		return fmt.Errorf("%w, ожидается: %s", ErrUsage, usage)
+	}
+	return nil
+}
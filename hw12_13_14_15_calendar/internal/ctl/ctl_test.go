@@ -0,0 +1,196 @@
+package ctl_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/ctl"
+	grpcHandler "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	handler "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const userEmail = "ctl@otus.ru"
+
+// fakeSupport внутреннее API без gRPC-сервера, вызовы остальных методов паникуют.
+type fakeSupport struct {
+	events.SupportClient
+	storeTime time.Duration
+}
+
+func (fs *fakeSupport) CleanupOldEvents(
+	_ context.Context, in *events.CleanupReq, _ ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	fs.storeTime = in.StoreTime.AsDuration()
+	return &emptypb.Empty{}, nil
+}
+
+func (fs *fakeSupport) GetPendingNotifications(
+	context.Context, *emptypb.Empty, ...grpc.CallOption,
+) (*events.Notifies, error) {
+	return &events.Notifies{List: []*events.Notification{{
+		ID:        "6e0c9d3c-1b8e-4a52-9c63-0b5f4a8d6a11",
+		Title:     "Ретроспектива",
+		Date:      timestamppb.New(time.Date(2023, 3, 3, 12, 0, 0, 0, time.UTC)),
+		UserName:  "Тестовый",
+		UserEmail: userEmail,
+	}}}, nil
+}
+
+type CtlSuiteTest struct {
+	suite.Suite
+	testServer *httptest.Server
+	support    *fakeSupport
+	env        *ctl.Env
+	out        *bytes.Buffer
+}
+
+func (cs *CtlSuiteTest) SetupTest() {
+	dependencies, services := calendartest.New(cs.T(), calendartest.WithUser("Тестовый", userEmail))
+	restServer, _ := handler.NewHandledServer(config.Server{}, services, dependencies)
+	cs.testServer = httptest.NewServer(restServer)
+
+	cs.out = new(bytes.Buffer)
+	cs.support = &fakeSupport{}
+	cs.env = ctl.NewEnv(cs.out, new(bytes.Buffer), filepath.Join(cs.T().TempDir(), "config.yaml"))
+	cs.env.NewSupportClient = func(
+		string, string, ...grpcHandler.ClientOption,
+	) (events.SupportClient, grpcHandler.AuthFn, error) {
+		return cs.support, func(ctx context.Context) context.Context { return ctx }, nil
+	}
+}
+
+func (cs *CtlSuiteTest) TearDownTest() {
+	cs.testServer.Close()
+}
+
+// run выполнение команды, флаги -o и -profile не переносятся между вызовами.
+func (cs *CtlSuiteTest) run(args ...string) (string, error) {
+	cs.out.Reset()
+	cs.env.Format, cs.env.Profile = ctl.FormatTable, ""
+	err := ctl.Run(context.Background(), cs.env, args)
+	return cs.out.String(), err
+}
+
+func (cs *CtlSuiteTest) login() {
+	_, err := cs.run("login", "-api", cs.testServer.URL, userEmail)
+	cs.Require().NoError(err)
+}
+
+func (cs *CtlSuiteTest) TestLogin() {
+	_, err := cs.run("events", "list")
+	cs.Require().ErrorIs(err, ctl.ErrNoProfile)
+
+	_, err = cs.run("login", "-api", cs.testServer.URL, "unknown@otus.ru")
+	cs.Require().Error(err)
+
+	cs.login()
+	_, err = cs.run("login", "-api", cs.testServer.URL, "-profile", "second", userEmail)
+	cs.Require().NoError(err)
+
+	out, err := cs.run("profile", "list")
+	cs.Require().NoError(err)
+	cs.Require().Contains(out, "*  second")
+
+	_, err = cs.run("profile", "use", "default")
+	cs.Require().NoError(err)
+	out, err = cs.run("profile", "list", "-o", "json")
+	cs.Require().NoError(err)
+	var profiles ctl.Profiles
+	cs.Require().NoError(json.Unmarshal([]byte(out), &profiles))
+	cs.Require().Equal("default", profiles.Current)
+	cs.Require().Equal(cs.testServer.URL, profiles.Profiles["second"].API)
+
+	_, err = cs.run("profile", "use", "missing")
+	cs.Require().ErrorIs(err, ctl.ErrProfileMissing)
+}
+
+func (cs *CtlSuiteTest) TestEvents() {
+	cs.login()
+	out, err := cs.run("events", "create",
+		"-title", "Ретроспектива", "-date", "2023-03-03T15:00:00+03:00", "-duration", "1h30m", "-notify", "15m")
+	cs.Require().NoError(err)
+	eventID := strings.TrimSpace(out)
+	cs.Require().NotEmpty(eventID)
+
+	_, err = cs.run("events", "update", eventID, "-title", "Ретроспектива (перенос)")
+	cs.Require().NoError(err)
+
+	out, err = cs.run("events", "list", "-range", "month", "-date", "2023-03-01")
+	cs.Require().NoError(err)
+	cs.Require().Contains(out, eventID)
+	cs.Require().Contains(out, "Ретроспектива (перенос)")
+
+	out, err = cs.run("events", "get", eventID, "-o", "json")
+	cs.Require().NoError(err)
+	var event dto.Event
+	cs.Require().NoError(json.Unmarshal([]byte(out), &event))
+	cs.Require().Equal("1h30m0s", event.Duration)
+
+	exportFile := filepath.Join(cs.T().TempDir(), "events.json")
+	_, err = cs.run("events", "export", "-date", "2023-03-01", "-file", exportFile)
+	cs.Require().NoError(err)
+
+	_, err = cs.run("events", "delete", eventID)
+	cs.Require().NoError(err)
+	out, err = cs.run("events", "list", "-range", "month", "-date", "2023-03-01", "-o", "json")
+	cs.Require().NoError(err)
+	cs.Require().Equal("null", strings.TrimSpace(out))
+
+	// выгрузка загружается обратно как новые события.
+	out, err = cs.run("events", "import", exportFile)
+	cs.Require().NoError(err)
+	cs.Require().Contains(out, "импортировано событий: 1 из 1")
+	out, err = cs.run("events", "list", "-range", "month", "-date", "2023-03-01")
+	cs.Require().NoError(err)
+	cs.Require().Contains(out, "Ретроспектива (перенос)")
+	cs.Require().NotContains(out, eventID)
+
+	_, err = cs.run("events", "create", "-title", "Без даты", "-date", "завтра")
+	cs.Require().ErrorIs(err, ctl.ErrDateFormat)
+}
+
+func (cs *CtlSuiteTest) TestSupport() {
+	cs.login()
+	_, err := cs.run("cleanup", "-store-time", "2d")
+	cs.Require().NoError(err)
+	cs.Require().Equal(48*time.Hour, cs.support.storeTime)
+
+	out, err := cs.run("notifications", "list")
+	cs.Require().NoError(err)
+	cs.Require().Contains(out, "Ретроспектива")
+	cs.Require().Contains(out, userEmail)
+}
+
+func (cs *CtlSuiteTest) TestUsage() {
+	_, err := cs.run("events", "rename")
+	cs.Require().ErrorIs(err, ctl.ErrUnknownCommand)
+	_, err = cs.run("events", "get")
+	cs.Require().ErrorIs(err, ctl.ErrUsage)
+	_, err = cs.run("profile", "list", "-o", "xml")
+	cs.Require().ErrorIs(err, ctl.ErrUnknownFormat)
+	_, err = cs.run("migrate", "status")
+	cs.Require().ErrorIs(err, ctl.ErrEmptyDatabase)
+
+	out, err := cs.run("completion", "bash")
+	cs.Require().NoError(err)
+	cs.Require().Contains(out, `"events list") COMPREPLY=($(compgen -W "-date -o -profile -range -tags"`)
+	cs.Require().Contains(out, "complete -F _calendarctl calendarctl")
+}
+
+func TestCtl(t *testing.T) {
+	suite.Run(t, new(CtlSuiteTest))
+}
@@ -0,0 +1,399 @@
+package ctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/client/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+var ErrDateFormat = errors.New("неверный формат даты, ожидается ГГГГ-ММ-ДД, \"ГГГГ-ММ-ДД ЧЧ:ММ\" или RFC3339")
+
+// dateLayouts форматы дат в аргументах, без часового пояса - локальное время.
+var dateLayouts = []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04"}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %s", ErrDateFormat, value)
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// client REST-клиент выбранного профиля.
+func (env *Env) client() (calendar.Client, error) {
+	profile, err := env.profile()
+	if err != nil {
+		return nil, err
+	}
+	return env.NewClient(profile.API, profile.Login), nil
+}
+
+// listFlags флаги выборки событий за период.
+type listFlags struct {
+	rangeType string
+	date      string
+	tags      string
+}
+
+func (lf *listFlags) register(fs *flag.FlagSet, defRange string) {
+	fs.StringVar(&lf.rangeType, "range", defRange, "период: day, week или month")
+	fs.StringVar(&lf.date, "date", "", "дата внутри периода, по умолчанию сегодня")
+	fs.StringVar(&lf.tags, "tags", "", "только события с метками, идентификаторы через запятую")
+}
+
+func (lf listFlags) list(ctx context.Context, client calendar.Client) ([]dto.Event, error) {
+	date, err := parseDate(lf.date)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetListOnDate(ctx, lf.rangeType, date, splitList(lf.tags)...)
+}
+
+// eventFlags поля события для create и update.
+type eventFlags struct {
+	title       string
+	date        string
+	duration    string
+	description string
+	notify      string
+	tags        string
+}
+
+func (ef *eventFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&ef.title, "title", "", "заголовок")
+	fs.StringVar(&ef.date, "date", "", "начало события")
+	fs.StringVar(&ef.duration, "duration", "", "продолжительность: 45m, 1h30m")
+	fs.StringVar(&ef.description, "description", "", "описание")
+	fs.StringVar(&ef.notify, "notify", "", "оповестить заранее: 15m, 1h")
+	fs.StringVar(&ef.tags, "tags", "", "метки, идентификаторы через запятую")
+}
+
+func eventsCommand() *Command {
+	return &Command{
+		Name:    "events",
+		Summary: "события пользователя",
+		Commands: []*Command{
+			eventsListCommand(),
+			eventsGetCommand(),
+			eventsCreateCommand(),
+			eventsUpdateCommand(),
+			eventsDeleteCommand(),
+			eventsImportCommand(),
+			eventsExportCommand(),
+		},
+	}
+}
+
+func eventsListCommand() *Command {
+	return &Command{
+		Name:    "list",
+		Summary: "события за день, неделю или месяц",
+		Setup: func(fs *flag.FlagSet) Handler {
+			var lf listFlags
+			lf.register(fs, "day")
+			return func(ctx context.Context, env *Env, args []string) error {
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				events, err := lf.list(ctx, client)
+				if err != nil {
+					return err
+				}
+				return env.renderEvents(events)
+			}
+		},
+	}
+}
+
+func eventsGetCommand() *Command {
+	return &Command{
+		Name:    "get",
+		Summary: "событие по идентификатору",
+		Args:    "<id>",
+		Setup: func(fs *flag.FlagSet) Handler {
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := argsN(args, 1, "events get <id>"); err != nil {
+					return err
+				}
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				event, err := client.GetByID(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				if env.Format == FormatJSON {
+					return env.render(event, nil)
+				}
+				return env.renderEvents([]dto.Event{*event})
+			}
+		},
+	}
+}
+
+func eventsCreateCommand() *Command {
+	return &Command{
+		Name:    "create",
+		Summary: "создать событие",
+		Setup: func(fs *flag.FlagSet) Handler {
+			var ef eventFlags
+			ef.register(fs)
+			return func(ctx context.Context, env *Env, args []string) error {
+				date, err := parseDate(ef.date)
+				if err != nil {
+					return err
+				}
+				input := dto.EventCreate{
+					Title:    ef.title,
+					Date:     date.Format(time.RFC3339),
+					Duration: ef.duration,
+					TagIDs:   splitList(ef.tags),
+				}
+				if ef.description != "" {
+					input.Description = &ef.description
+				}
+				if ef.notify != "" {
+					input.NotifyTerm = &ef.notify
+				}
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				event, err := client.Create(ctx, input)
+				if err != nil {
+					return err
+				}
+				if env.Format == FormatJSON {
+					return env.render(event, nil)
+				}
+				fmt.Fprintln(env.Out, event.ID)
+				return nil
+			}
+		},
+	}
+}
+
+func eventsUpdateCommand() *Command {
+	return &Command{
+		Name:    "update",
+		Summary: "изменить событие, меняются только заданные поля",
+		Args:    "<id>",
+		Setup: func(fs *flag.FlagSet) Handler {
+			var ef eventFlags
+			ef.register(fs)
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := argsN(args, 1, "events update <id>"); err != nil {
+					return err
+				}
+				var (
+					input dto.EventUpdate
+					err   error
+				)
+				fs.Visit(func(f *flag.Flag) {
+					switch f.Name {
+					case "title":
+						input.Title = &ef.title
+					case "date":
+						var date time.Time
+						if date, err = parseDate(ef.date); err == nil {
+							value := date.Format(time.RFC3339)
+							input.Date = &value
+						}
+					case "duration":
+						input.Duration = &ef.duration
+					case "description":
+						input.Description = &ef.description
+					case "notify":
+						input.NotifyTerm = &ef.notify
+					case "tags":
+						tags := splitList(ef.tags)
+						if tags == nil {
+							tags = []string{}
+						}
+						input.TagIDs = &tags
+					}
+				})
+				if err != nil {
+					return err
+				}
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				return client.Update(ctx, args[0], input)
+			}
+		},
+	}
+}
+
+func eventsDeleteCommand() *Command {
+	return &Command{
+		Name:    "delete",
+		Summary: "переместить событие в корзину",
+		Args:    "<id>",
+		Setup: func(fs *flag.FlagSet) Handler {
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := argsN(args, 1, "events delete <id>"); err != nil {
+					return err
+				}
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				return client.Delete(ctx, args[0])
+			}
+		},
+	}
+}
+
+func eventsImportCommand() *Command {
+	return &Command{
+		Name: "import",
+		Summary: "создать события из json-файла: массив объектов как в POST /events, " +
+			"файл events export тоже подходит",
+		Args: "<file|->",
+		Setup: func(fs *flag.FlagSet) Handler {
+			mode := fs.String("mode", "atomic", "режим пакета: atomic или bestEffort")
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := argsN(args, 1, "events import <file|->"); err != nil {
+					return err
+				}
+				var items []dto.EventCreate
+				if err := readJSON(args[0], &items); err != nil {
+					return err
+				}
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				reports := make([]dto.BatchReport, 0, len(items)/model.EventBatchMaxSize+1)
+				applied := 0
+				// сервер ограничивает размер пакета, файл отправляется частями.
+				for start := 0; start < len(items); start += model.EventBatchMaxSize {
+					end := start + model.EventBatchMaxSize
+					if end > len(items) {
+						end = len(items)
+					}
+					report, err := client.CreateBatch(ctx, dto.EventBatchCreate{Mode: *mode, Items: items[start:end]})
+					if err != nil {
+						return fmt.Errorf("ошибка импорта событий %d-%d: %w", start+1, end, err)
+					}
+					for i := range report.Results {
+						report.Results[i].Index += start
+					}
+					applied += report.Applied
+					reports = append(reports, *report)
+				}
+				return env.render(reports, func(w *tabwriter.Writer) {
+					for _, report := range reports {
+						for _, result := range report.Results {
+							if !result.Success {
+								fmt.Fprintf(w, "%d\tошибка\t%v\n", result.Index+1, result.Error)
+							}
+						}
+					}
+					fmt.Fprintf(w, "импортировано событий: %d из %d\n", applied, len(items))
+				})
+			}
+		},
+	}
+}
+
+func eventsExportCommand() *Command {
+	return &Command{
+		Name:    "export",
+		Summary: "выгрузить события за период в json-файл",
+		Setup: func(fs *flag.FlagSet) Handler {
+			var lf listFlags
+			lf.register(fs, "month")
+			file := fs.String("file", "-", "файл для выгрузки, - вывод на экран")
+			return func(ctx context.Context, env *Env, args []string) error {
+				client, err := env.client()
+				if err != nil {
+					return err
+				}
+				events, err := lf.list(ctx, client)
+				if err != nil {
+					return err
+				}
+				if events == nil {
+					events = []dto.Event{}
+				}
+				return writeJSON(env.Out, *file, events)
+			}
+		},
+	}
+}
+
+func (env *Env) renderEvents(events []dto.Event) error {
+	return env.render(events, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tDATE\tDURATION\tTITLE\tTAGS")
+		for _, event := range events {
+			tags := make([]string, len(event.Tags))
+			for i, tag := range event.Tags {
+				tags[i] = tag.Name
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				event.ID, formatTime(event.Date), event.Duration, event.Title, strings.Join(tags, ","))
+		}
+	})
+}
+
+func readJSON(fileName string, value interface{}) error {
+	var (
+		bs  []byte
+		err error
+	)
+	if fileName == "-" {
+		bs, err = io.ReadAll(os.Stdin)
+	} else {
+		bs, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(bs, value); err != nil {
+		return fmt.Errorf("ошибка разбора '%s': %w", fileName, err)
+	}
+	return nil
+}
+
+func writeJSON(out io.Writer, fileName string, value interface{}) error {
+	bs, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	bs = append(bs, '\n')
+	if fileName == "-" {
+		_, err = out.Write(bs)
+		return err
+	}
+	return os.WriteFile(fileName, bs, 0o600)
+}
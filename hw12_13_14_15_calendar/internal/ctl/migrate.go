@@ -0,0 +1,102 @@
+package ctl
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"io/fs"
+	"os"
+
+	_ "github.com/jackc/pgx/v4/stdlib" // pgx driver for database/sql
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/migrations"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/migrator"
+)
+
+// migrateFlags источник миграций и база данных, по умолчанию база из профиля.
+type migrateFlags struct {
+	dir      string
+	database string
+}
+
+func (mf *migrateFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&mf.dir, "dir", "", "каталог с миграциями goose, по умолчанию встроенные миграции")
+	fs.StringVar(&mf.database, "db", "", "строка подключения PostgreSQL, по умолчанию из профиля")
+}
+
+// run выполнение fn с мигратором, соединение закрывается после выполнения.
+func (mf migrateFlags) run(env *Env, fn func(migrator.Migrator) error) error {
+	dsn := mf.database
+	if dsn == "" {
+		if profile, err := env.profile(); err == nil {
+			dsn = profile.Database
+		}
+	}
+	if dsn == "" {
+		return ErrEmptyDatabase
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	var source fs.FS = migrations.FS
+	if mf.dir != "" {
+		source = os.DirFS(mf.dir)
+	}
+	return fn(migrator.New(db, source, env.Err))
+}
+
+func migrateCommand() *Command {
+	return &Command{
+		Name:    "migrate",
+		Summary: "миграции схемы базы данных",
+		Commands: []*Command{
+			{
+				Name:    "up",
+				Summary: "применить новые миграции",
+				Setup: func(fs *flag.FlagSet) Handler {
+					var mf migrateFlags
+					mf.register(fs)
+					return func(ctx context.Context, env *Env, args []string) error {
+						return mf.run(env, func(m migrator.Migrator) error {
+							return m.UpLocked(ctx)
+						})
+					}
+				},
+			},
+			{
+				Name:    "down",
+				Summary: "откатить последнюю миграцию",
+				Setup: func(fs *flag.FlagSet) Handler {
+					var mf migrateFlags
+					mf.register(fs)
+					return func(ctx context.Context, env *Env, args []string) error {
+						return mf.run(env, func(m migrator.Migrator) error {
+							return m.Down()
+						})
+					}
+				},
+			},
+			{
+				Name:    "status",
+				Summary: "состояние миграций",
+				Setup: func(fs *flag.FlagSet) Handler {
+					var mf migrateFlags
+					mf.register(fs)
+					return func(ctx context.Context, env *Env, args []string) error {
+						return mf.run(env, func(m migrator.Migrator) error {
+							list, err := m.Status(ctx)
+							if err != nil {
+								return err
+							}
+							if env.Format == FormatJSON {
+								return env.render(list, nil)
+							}
+							return migrator.WriteStatus(env.Out, list)
+						})
+					}
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package ctl
+
+import (
+	"encoding/json"
+	"text/tabwriter"
+	"time"
+)
+
+// render вывод значения в json или таблицей, которую заполняет table.
+func (env *Env) render(value interface{}, table func(w *tabwriter.Writer)) error {
+	if env.Format == FormatJSON {
+		enc := json.NewEncoder(env.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+	w := tabwriter.NewWriter(env.Out, 0, 0, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,253 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defProfile = "default"
+	defAPI     = "http://127.0.0.1:8080"
+	defGRPC    = "127.0.0.1:8088"
+)
+
+var (
+	ErrNoProfile      = errors.New("профиль не найден, выполните calendarctl login")
+	ErrEmptyLogin     = errors.New("в профиле не задан логин")
+	ErrEmptyDatabase  = errors.New("не задана строка подключения к базе данных")
+	ErrProfileMissing = errors.New("профиль не существует")
+)
+
+// Profile параметры подключения к одному окружению.
+type Profile struct {
+	API   string `yaml:"api" json:"api"`     // адрес REST API: http://127.0.0.1:8080
+	GRPC  string `yaml:"grpc" json:"grpc"`   // адрес gRPC API: 127.0.0.1:8088
+	Login string `yaml:"login" json:"login"` // email пользователя
+	// Database строка подключения PostgreSQL для migrate.
+	Database string `yaml:"database,omitempty" json:"database,omitempty"`
+	// TLS подключение к gRPC API с TLS, сертификат нужен для внутреннего API при mTLS.
+	TLS *ProfileTLS `yaml:"tls,omitempty" json:"tls,omitempty"`
+}
+
+// ProfileTLS файлы PEM, пустой CA - проверка сервера по системным корневым сертификатам.
+type ProfileTLS struct {
+	CAFile   string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+}
+
+// Profiles файл профилей calendarctl.
+type Profiles struct {
+	Current  string             `yaml:"current" json:"current"`
+	Profiles map[string]Profile `yaml:"profiles" json:"profiles"`
+}
+
+// DefaultConfigFile файл профилей в каталоге настроек пользователя.
+func DefaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "calendarctl", "config.yaml")
+}
+
+// LoadProfiles чтение профилей, отсутствующий файл - пустой список.
+func LoadProfiles(fileName string) (*Profiles, error) {
+	profiles := &Profiles{Profiles: make(map[string]Profile)}
+	bs, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(bs, profiles); err != nil {
+		return nil, fmt.Errorf("ошибка чтения профилей из '%s': %w", fileName, err)
+	}
+	if profiles.Profiles == nil {
+		profiles.Profiles = make(map[string]Profile)
+	}
+	return profiles, nil
+}
+
+// Save запись профилей, файл доступен только владельцу.
+func (p Profiles) Save(fileName string) error {
+	bs, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(fileName), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, bs, 0o600)
+}
+
+// profile выбранный профиль: заданный флагом -profile или текущий.
+func (env *Env) profile() (Profile, error) {
+	profiles, err := LoadProfiles(env.ConfigFile)
+	if err != nil {
+		return Profile{}, err
+	}
+	name := env.Profile
+	if name == "" {
+		name = profiles.Current
+	}
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		return Profile{}, ErrNoProfile
+	}
+	if profile.Login == "" {
+		return Profile{}, ErrEmptyLogin
+	}
+	return profile, nil
+}
+
+func loginCommand() *Command {
+	return &Command{
+		Name:    "login",
+		Summary: "сохранить профиль подключения и проверить доступ",
+		Args:    "<email>",
+		Setup: func(fs *flag.FlagSet) Handler {
+			api := fs.String("api", "", "адрес REST API (по умолчанию "+defAPI+")")
+			grpcAddr := fs.String("grpc", "", "адрес gRPC API (по умолчанию "+defGRPC+")")
+			database := fs.String("db", "", "строка подключения PostgreSQL для migrate")
+			var tlsFiles ProfileTLS
+			fs.StringVar(&tlsFiles.CAFile, "ca", "", "корневые сертификаты gRPC API, включает TLS")
+			fs.StringVar(&tlsFiles.CertFile, "cert", "", "сертификат клиента для mTLS")
+			fs.StringVar(&tlsFiles.KeyFile, "key", "", "ключ сертификата клиента")
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := argsN(args, 1, "login <email>"); err != nil {
+					return err
+				}
+				profiles, err := LoadProfiles(env.ConfigFile)
+				if err != nil {
+					return err
+				}
+				name := env.Profile
+				if name == "" {
+					name = defProfile
+				}
+				profile := profiles.Profiles[name]
+				profile.Login = args[0]
+				setDefault(&profile.API, *api, defAPI)
+				setDefault(&profile.GRPC, *grpcAddr, defGRPC)
+				if *database != "" {
+					profile.Database = *database
+				}
+				if tlsFiles != (ProfileTLS{}) {
+					profile.TLS = &tlsFiles
+				}
+				// авторизация по email, отдельного входа нет: проверяем доступ любым запросом.
+				if _, err = env.NewClient(profile.API, profile.Login).GetTags(ctx); err != nil {
+					return fmt.Errorf("ошибка входа: %w", err)
+				}
+				profiles.Profiles[name] = profile
+				profiles.Current = name
+				if err = profiles.Save(env.ConfigFile); err != nil {
+					return err
+				}
+				fmt.Fprintf(env.Out, "вход выполнен: %s, профиль %s\n", profile.Login, name)
+				return nil
+			}
+		},
+	}
+}
+
+// setDefault значение флага, иначе прежнее значение, иначе значение по умолчанию.
+func setDefault(field *string, value, def string) {
+	switch {
+	case value != "":
+		*field = value
+	case *field == "":
+		*field = def
+	}
+}
+
+func profileCommand() *Command {
+	return &Command{
+		Name:    "profile",
+		Summary: "профили подключения",
+		Commands: []*Command{
+			{
+				Name:    "list",
+				Summary: "список профилей",
+				Setup: func(fs *flag.FlagSet) Handler {
+					return func(ctx context.Context, env *Env, args []string) error {
+						profiles, err := LoadProfiles(env.ConfigFile)
+						if err != nil {
+							return err
+						}
+						return env.render(profiles, func(w *tabwriter.Writer) {
+							fmt.Fprintln(w, "\tNAME\tLOGIN\tAPI\tGRPC")
+							names := make([]string, 0, len(profiles.Profiles))
+							for name := range profiles.Profiles {
+								names = append(names, name)
+							}
+							sort.Strings(names)
+							for _, name := range names {
+								mark, p := "", profiles.Profiles[name]
+								if name == profiles.Current {
+									mark = "*"
+								}
+								fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", mark, name, p.Login, p.API, p.GRPC)
+							}
+						})
+					}
+				},
+			},
+			{
+				Name:    "use",
+				Summary: "сделать профиль текущим",
+				Args:    "<name>",
+				Setup: func(fs *flag.FlagSet) Handler {
+					return func(ctx context.Context, env *Env, args []string) error {
+						if err := argsN(args, 1, "profile use <name>"); err != nil {
+							return err
+						}
+						profiles, err := LoadProfiles(env.ConfigFile)
+						if err != nil {
+							return err
+						}
+						if _, ok := profiles.Profiles[args[0]]; !ok {
+							return fmt.Errorf("%w: %s", ErrProfileMissing, args[0])
+						}
+						profiles.Current = args[0]
+						return profiles.Save(env.ConfigFile)
+					}
+				},
+			},
+			{
+				Name:    "delete",
+				Summary: "удалить профиль",
+				Args:    "<name>",
+				Setup: func(fs *flag.FlagSet) Handler {
+					return func(ctx context.Context, env *Env, args []string) error {
+						if err := argsN(args, 1, "profile delete <name>"); err != nil {
+							return err
+						}
+						profiles, err := LoadProfiles(env.ConfigFile)
+						if err != nil {
+							return err
+						}
+						if _, ok := profiles.Profiles[args[0]]; !ok {
+							return fmt.Errorf("%w: %s", ErrProfileMissing, args[0])
+						}
+						delete(profiles.Profiles, args[0])
+						if profiles.Current == args[0] {
+							profiles.Current = ""
+						}
+						return profiles.Save(env.ConfigFile)
+					}
+				},
+			},
+		},
+	}
+}
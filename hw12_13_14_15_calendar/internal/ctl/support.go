@@ -0,0 +1,99 @@
+package ctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/jsonx"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// supportClient gRPC-клиент внутреннего API выбранного профиля.
+func (env *Env) supportClient() (events.SupportClient, grpc.AuthFn, error) {
+	profile, err := env.profile()
+	if err != nil {
+		return nil, nil, err
+	}
+	if profile.TLS == nil {
+		return env.NewSupportClient(profile.GRPC, profile.Login)
+	}
+	files := certs.Files{Cert: profile.TLS.CertFile, Key: profile.TLS.KeyFile, CA: profile.TLS.CAFile}
+	source, err := certs.NewSource(files, certs.DefaultCheckInterval, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка чтения сертификатов профиля: %w", err)
+	}
+	return env.NewSupportClient(profile.GRPC, profile.Login, grpc.WithTLS(source.ClientConfig("")))
+}
+
+func cleanupCommand() *Command {
+	return &Command{
+		Name:    "cleanup",
+		Summary: "переместить в корзину события старше срока хранения",
+		Setup: func(fs *flag.FlagSet) Handler {
+			storeTime := fs.String("store-time", "1y", "срок хранения с единицей измерения: 6n, 1y")
+			return func(ctx context.Context, env *Env, args []string) error {
+				duration, err := jsonx.ParseDuration(*storeTime)
+				if err != nil {
+					return fmt.Errorf("неверный срок хранения '%s': %w", *storeTime, err)
+				}
+				storeDuration, err := duration.AsDuration()
+				if err != nil {
+					return err
+				}
+				client, auth, err := env.supportClient()
+				if err != nil {
+					return err
+				}
+				_, err = client.CleanupOldEvents(auth(ctx), &events.CleanupReq{StoreTime: durationpb.New(storeDuration)})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(env.Out, "события старше %s перемещены в корзину\n", *storeTime)
+				return nil
+			}
+		},
+	}
+}
+
+func notificationsCommand() *Command {
+	return &Command{
+		Name:    "notifications",
+		Summary: "оповещения о событиях",
+		Commands: []*Command{
+			{
+				Name:    "list",
+				Summary: "оповещения, ожидающие отправки",
+				Setup: func(fs *flag.FlagSet) Handler {
+					return func(ctx context.Context, env *Env, args []string) error {
+						client, auth, err := env.supportClient()
+						if err != nil {
+							return err
+						}
+						notifiesPb, err := client.GetPendingNotifications(auth(ctx), &emptypb.Empty{})
+						if err != nil {
+							return err
+						}
+						notifications, err := dto.ToNotificationSlice(notifiesPb)
+						if err != nil {
+							return err
+						}
+						return env.render(notifications, func(w *tabwriter.Writer) {
+							fmt.Fprintln(w, "EVENT\tDATE\tTITLE\tEMAIL")
+							for _, note := range notifications {
+								fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+									note.EventID, formatTime(note.EventDate), note.EventTitle, note.NotifyUser.Email)
+							}
+						})
+					}
+				},
+			},
+		},
+	}
+}
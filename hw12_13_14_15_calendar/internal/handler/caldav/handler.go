@@ -0,0 +1,608 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+/*
+Подмножество CalDAV (RFC 4791) поверх сервиса EventCRUD. У каждого пользователя один
+календарь, ресурсы календаря - события. Адрес события, созданного клиентом CalDAV, - {UID}.ics,
+где UID - идентификатор события в iCalendar клиента, остальных событий - {eventID}.ics.
+
+	/dav/                    корень, указывает на принципала
+	/dav/principal/          текущий пользователь, указывает на домашнюю коллекцию
+	/dav/calendars/          домашняя коллекция календарей
+	/dav/calendars/events/   календарь пользователя
+
+PUT с UID уже сохраненного события изменяет это событие, поэтому повтор PUT не создает дубликат.
+Если адрес PUT не совпадает с {UID}.ics, событие доступно по адресу из заголовка Location ответа.
+*/
+
+const (
+	BasePath      = "/dav/"
+	principalPath = BasePath + "principal/"
+	homePath      = BasePath + "calendars/"
+	calendarPath  = homePath + "events/"
+
+	icsExt         = ".ics"
+	icsContentType = "text/calendar; charset=utf-8"
+	xmlContentType = "application/xml; charset=utf-8"
+	maxBodySize    = 1 << 20
+)
+
+type Handler struct {
+	services *deps.Services
+	logger   logger.Logger
+}
+
+func NewHandler(services *deps.Services, logger logger.Logger) http.Handler {
+	return &Handler{services: services, logger: logger}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	path := r.URL.Path
+	if !strings.HasSuffix(path, "/") && !strings.HasSuffix(path, icsExt) {
+		path += "/"
+	}
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+	case "PROPFIND":
+		h.propfind(w, r, path)
+	case "REPORT":
+		h.report(w, r, path)
+	case http.MethodGet, http.MethodHead:
+		h.get(w, r, path)
+	case http.MethodPut:
+		h.put(w, r, path)
+	case http.MethodDelete:
+		h.delete(w, r, path)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// log логгер с полями запроса: идентификатором запроса и пользователем.
+func (h *Handler) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
+
+func (h *Handler) currentUser(ctx context.Context) (*model.User, error) {
+	user, err := h.services.User.GetCurrent(ctx)
+	if err != nil {
+		return nil, errx.PermsNew(err)
+	}
+	return user, nil
+}
+
+// userEvents события текущего пользователя, при dateRgn != nil - пересекающиеся с интервалом.
+func (h *Handler) userEvents(ctx context.Context, dateRgn *model.DateRange) ([]model.Event, error) {
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.services.EventCRUD.GetEvents(ctx, model.EventSearch{
+		OwnerID:     &user.ID,
+		DateRange:   dateRgn,
+		TacDuration: true,
+	})
+}
+
+// eventByPath событие текущего пользователя по адресу ресурса, чужие события не видны.
+func (h *Handler) eventByPath(ctx context.Context, path string) (*model.Event, error) {
+	name, ok := resourceName(path)
+	if !ok {
+		return nil, errx.NotFoundNew(model.ErrEventNotFound, nil)
+	}
+	event, err := h.eventByUID(ctx, name)
+	nfErr := errx.NotFound{}
+	if !errors.As(err, &nfErr) {
+		return event, err
+	}
+	eventID, err := uuid.Parse(name)
+	if err != nil {
+		return nil, errx.NotFoundNew(model.ErrEventNotFound, nil)
+	}
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	event, err = h.services.EventCRUD.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Owner == nil || event.Owner.ID != user.ID {
+		return nil, errx.NotFoundNew(model.ErrEventNotFound, nil)
+	}
+	return event, nil
+}
+
+// eventByUID событие текущего пользователя, созданное клиентом CalDAV с идентификатором uid.
+func (h *Handler) eventByUID(ctx context.Context, uid string) (*model.Event, error) {
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events, err := h.services.EventCRUD.GetEvents(ctx, model.EventSearch{OwnerID: &user.ID, ICalUID: &uid})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, errx.NotFoundNew(model.ErrEventNotFound, nil)
+	}
+	return &events[0], nil
+}
+
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request, path string) {
+	var req propfindRequest
+	if err := decodeXMLBody(r, &req); err != nil {
+		h.writeError(w, r, errx.InvalidNew(err.Error(), nil))
+		return
+	}
+	var names []xml.Name
+	if req.Prop != nil && req.AllProp == nil && req.PropName == nil {
+		names = req.Prop.list()
+	}
+	ctx := r.Context()
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	depthOne := r.Header.Get("Depth") != "0"
+	var responses []msResponse
+	switch {
+	case path == BasePath:
+		responses = append(responses, selectProps(path, rootProps(), names))
+	case path == principalPath:
+		responses = append(responses, selectProps(path, principalProps(user), names))
+	case path == homePath:
+		responses = append(responses, selectProps(path, homeProps(user), names))
+		if depthOne {
+			events, err := h.userEvents(ctx, nil)
+			if err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			responses = append(responses, selectProps(calendarPath, calendarProps(events), names))
+		}
+	case path == calendarPath:
+		events, err := h.userEvents(ctx, nil)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		responses = append(responses, selectProps(path, calendarProps(events), names))
+		if depthOne {
+			for _, event := range events {
+				responses = append(responses, selectProps(eventPath(event), eventProps(event, false), names))
+			}
+		}
+	case strings.HasPrefix(path, calendarPath):
+		event, err := h.eventByPath(ctx, path)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		responses = append(responses, selectProps(path, eventProps(*event, false), names))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeMultistatus(w, responses)
+}
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, path string) {
+	if path != calendarPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var req reportRequest
+	if err := decodeXMLBody(r, &req); err != nil {
+		h.writeError(w, r, errx.InvalidNew(err.Error(), nil))
+		return
+	}
+	var names []xml.Name
+	if req.Prop != nil {
+		names = req.Prop.list()
+	}
+	ctx := r.Context()
+	var responses []msResponse
+	switch req.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		dateRgn, match, err := queryRange(req)
+		if err != nil {
+			h.writeError(w, r, errx.InvalidNew(err.Error(), nil))
+			return
+		}
+		if match {
+			events, err := h.userEvents(ctx, dateRgn)
+			if err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			for _, event := range events {
+				responses = append(responses, selectProps(eventPath(event), eventProps(event, true), names))
+			}
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		for _, href := range req.Hrefs {
+			href = strings.TrimSpace(href)
+			path, err := url.PathUnescape(href)
+			if err != nil {
+				path = href
+			}
+			event, err := h.eventByPath(ctx, path)
+			if err != nil {
+				nfErr := errx.NotFound{}
+				if !errors.As(err, &nfErr) {
+					h.writeError(w, r, err)
+					return
+				}
+				responses = append(responses, msResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, selectProps(href, eventProps(*event, true), names))
+		}
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	writeMultistatus(w, responses)
+}
+
+// queryRange интервал фильтра calendar-query; match == false - фильтр не может выбрать события.
+func queryRange(req reportRequest) (*model.DateRange, bool, error) {
+	if req.Filter == nil {
+		return nil, true, nil
+	}
+	calFilter := req.Filter.CompFilter
+	if !strings.EqualFold(calFilter.Name, "VCALENDAR") {
+		return nil, false, nil
+	}
+	if len(calFilter.CompFilters) == 0 {
+		return nil, true, nil
+	}
+	eventFilter := calFilter.CompFilters[0]
+	if !strings.EqualFold(eventFilter.Name, "VEVENT") {
+		return nil, false, nil
+	}
+	if eventFilter.TimeRange == nil {
+		return nil, true, nil
+	}
+	// границы интервала необязательны.
+	from, to := time.Unix(0, 0).UTC(), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	var err error
+	if eventFilter.TimeRange.Start != "" {
+		if from, err = time.Parse(icalDateTimeUTC, eventFilter.TimeRange.Start); err != nil {
+			return nil, false, fmt.Errorf("%w: %s", ErrICalWrongDate, eventFilter.TimeRange.Start)
+		}
+	}
+	if eventFilter.TimeRange.End != "" {
+		if to, err = time.Parse(icalDateTimeUTC, eventFilter.TimeRange.End); err != nil {
+			return nil, false, fmt.Errorf("%w: %s", ErrICalWrongDate, eventFilter.TimeRange.End)
+		}
+	}
+	dateRgn := model.DateRgnFromDates(from, to)
+	return &dateRgn, true, nil
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, path string) {
+	var (
+		body string
+		etag string
+	)
+	switch {
+	case path == calendarPath:
+		// весь календарь одним файлом, удобно для экспорта.
+		events, err := h.userEvents(r.Context(), nil)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		body, etag = encodeICal(events...), calendarCTag(events)
+	case strings.HasPrefix(path, calendarPath):
+		event, err := h.eventByPath(r.Context(), path)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		body, etag = encodeICal(*event), eventETag(*event)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", icsContentType)
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, path string) {
+	if !strings.HasPrefix(path, calendarPath) || !strings.HasSuffix(path, icsExt) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ical, err := decodeICalEvent(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		h.writeError(w, r, errx.InvalidNew(err.Error(), nil))
+		return
+	}
+	ctx := r.Context()
+	event, err := h.eventByPath(ctx, path)
+	nfErr := errx.NotFound{}
+	if errors.As(err, &nfErr) && ical.UID != "" {
+		// клиент мог повторить PUT по своему адресу, не учтя Location предыдущего ответа.
+		event, err = h.eventByUID(ctx, ical.UID)
+	}
+	switch {
+	case errors.As(err, &nfErr):
+		event = nil
+	case err != nil:
+		h.writeError(w, r, err)
+		return
+	}
+	if !checkPreconditions(r, event) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if event == nil {
+		h.create(w, r, path, ical)
+		return
+	}
+	input := model.EventUpdate{
+		Title:       &ical.Summary,
+		Date:        &ical.Start,
+		Duration:    &ical.Duration,
+		Description: ical.Description,
+		NotifyTerm:  ical.NotifyTerm,
+	}
+	if input.Description == nil {
+		empty := ""
+		input.Description = &empty
+	}
+	if err = h.services.EventCRUD.Update(ctx, *event, input); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	if event, err = h.services.EventCRUD.GetByID(ctx, event.ID); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.log(ctx).Info("caldav: событие изменено: eventID=%s", event.ID.String())
+	w.Header().Set("ETag", eventETag(*event))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, path string, ical *icalEvent) {
+	event, err := h.services.EventCRUD.Add(r.Context(), model.EventCreate{
+		Title:       ical.Summary,
+		Date:        ical.Start,
+		Duration:    ical.Duration,
+		Description: ical.Description,
+		NotifyTerm:  ical.NotifyTerm,
+		ICalUID:     ical.UID,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.log(r.Context()).Info("caldav: событие добавлено: eventID=%s", event.ID.String())
+	if location := eventPath(*event); location != path {
+		w.Header().Set("Location", location)
+	}
+	w.Header().Set("ETag", eventETag(*event))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, path string) {
+	if !strings.HasPrefix(path, calendarPath) || !strings.HasSuffix(path, icsExt) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	event, err := h.eventByPath(ctx, path)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	if !checkPreconditions(r, event) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if err = h.services.EventCRUD.Delete(ctx, *event); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.log(ctx).Info("caldav: событие удалено: eventID=%s", event.ID.String())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkPreconditions проверка заголовков If-Match и If-None-Match, event == nil - ресурса нет.
+func checkPreconditions(r *http.Request, event *model.Event) bool {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if event == nil {
+			return false
+		}
+		if ifMatch != "*" && !containsETag(ifMatch, eventETag(*event)) {
+			return false
+		}
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && event != nil {
+		if ifNoneMatch == "*" || containsETag(ifNoneMatch, eventETag(*event)) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsETag(header, etag string) bool {
+	for _, item := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(item), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError перевод ошибки сервиса в HTTP-статус, тело - текст ошибки.
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	code := http.StatusInternalServerError
+	var (
+		logErr  errx.Logic
+		invErr  errx.Invalid
+		nfErr   errx.NotFound
+		baseErr errx.Base
+	)
+	switch {
+	case errors.As(err, &logErr):
+		// например, дата занята или вне рабочего времени.
+		code = http.StatusConflict
+	case errors.As(err, &invErr):
+		code = http.StatusBadRequest
+	case errors.As(err, &nfErr):
+		code = http.StatusNotFound
+	case errors.As(err, &baseErr) && baseErr.Kind() == errx.TypePerms:
+		code = http.StatusForbidden
+	}
+	if code == http.StatusInternalServerError {
+		h.log(r.Context()).Error("caldav: %s %s - %s", r.Method, r.URL.Path, err.Error())
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = io.WriteString(w, err.Error())
+}
+
+func rootProps() []prop {
+	return []prop{
+		davProp("resourcetype", "<d:collection/>"),
+		davProp("current-user-principal", hrefXML(principalPath)),
+	}
+}
+
+func principalProps(user *model.User) []prop {
+	return []prop{
+		davProp("resourcetype", "<d:principal/>"),
+		davProp("displayname", escapeXML(user.Name)),
+		davProp("current-user-principal", hrefXML(principalPath)),
+		davProp("principal-URL", hrefXML(principalPath)),
+		calProp("calendar-home-set", hrefXML(homePath)),
+		calProp("calendar-user-address-set", hrefXML("mailto:"+user.Email)),
+	}
+}
+
+func homeProps(user *model.User) []prop {
+	return []prop{
+		davProp("resourcetype", "<d:collection/>"),
+		davProp("displayname", escapeXML(user.Name)),
+		davProp("current-user-principal", hrefXML(principalPath)),
+	}
+}
+
+func calendarProps(events []model.Event) []prop {
+	ctag := calendarCTag(events)
+	return []prop{
+		davProp("resourcetype", "<d:collection/><c:calendar/>"),
+		davProp("displayname", "Календарь"),
+		davProp("owner", hrefXML(principalPath)),
+		davProp("current-user-principal", hrefXML(principalPath)),
+		davProp("current-user-privilege-set",
+			"<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>"),
+		davProp("supported-report-set",
+			"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>"+
+				"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"),
+		davProp("getetag", escapeXML(ctag)),
+		calProp("supported-calendar-component-set", `<c:comp name="VEVENT"/>`),
+		prop{name: xml.Name{Space: nsCS, Local: "getctag"}, inner: escapeXML(ctag)},
+	}
+}
+
+// eventProps свойства события; calendar-data возвращается только в REPORT.
+func eventProps(event model.Event, withData bool) []prop {
+	props := []prop{
+		davProp("resourcetype", ""),
+		davProp("getetag", escapeXML(eventETag(event))),
+		davProp("getcontenttype", icsContentType),
+		davProp("getlastmodified", event.UpdatedAt.UTC().Format(http.TimeFormat)),
+		davProp("displayname", escapeXML(event.Title)),
+	}
+	if withData {
+		props = append(props, calProp("calendar-data", escapeXML(encodeICal(event))))
+	}
+	return props
+}
+
+func eventPath(event model.Event) string {
+	if event.ICalUID != "" {
+		return calendarPath + url.PathEscape(event.ICalUID) + icsExt
+	}
+	return calendarPath + event.ID.String() + icsExt
+}
+
+// resourceName имя ресурса календаря без расширения: UID или идентификатор события.
+func resourceName(path string) (string, bool) {
+	if !strings.HasPrefix(path, calendarPath) || !strings.HasSuffix(path, icsExt) {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(path, calendarPath), icsExt)
+	return name, name != ""
+}
+
+// eventETag меняется при каждом изменении события.
+func eventETag(event model.Event) string {
+	return fmt.Sprintf(`"%x"`, event.UpdatedAt.UnixNano())
+}
+
+// calendarCTag меняется при добавлении, изменении и удалении любого события календаря.
+func calendarCTag(events []model.Event) string {
+	sorted := make([]model.Event, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID.String() < sorted[j].ID.String()
+	})
+	hash := fnv.New64a()
+	for _, event := range sorted {
+		_, _ = fmt.Fprintf(hash, "%s:%d;", event.ID, event.UpdatedAt.UnixNano())
+	}
+	return fmt.Sprintf(`"%x"`, hash.Sum64())
+}
+
+func decodeXMLBody(r *http.Request, dest interface{}) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	if err = xml.Unmarshal(body, dest); err != nil {
+		return fmt.Errorf("ошибка разбора xml: %w", err)
+	}
+	return nil
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []msResponse) {
+	w.Header().Set("Content-Type", xmlContentType)
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, multistatus(responses))
+}
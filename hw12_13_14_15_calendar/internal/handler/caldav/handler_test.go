@@ -0,0 +1,237 @@
+package caldav_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	handler "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http"
+)
+
+// Запросы в testdata записаны с клиентов Thunderbird и DAVx5, значения, известные только
+// во время теста, подставляются вместо {{имя}}.
+
+const (
+	userEmail = "auth@otus.ru"
+	// clientUID UID события из 04_put_create.http.
+	clientUID = "4f0c2a7e-client-generated-uid"
+)
+
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Status   string `xml:"status"`
+		Propstat []struct {
+			Prop struct {
+				Inner        string `xml:",innerxml"`
+				ETag         string `xml:"getetag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+				CTag         string `xml:"http://calendarserver.org/ns/ getctag"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+type CalDAVSuiteTest struct {
+	suite.Suite
+	testServer *httptest.Server
+	vars       map[string]string
+}
+
+func (cs *CalDAVSuiteTest) SetupTest() {
+	dependencies, services := calendartest.New(cs.T(), calendartest.WithUser("Тестовый", userEmail))
+	restServer, _ := handler.NewHandledServer(config.Server{}, services, dependencies)
+	cs.testServer = httptest.NewServer(restServer)
+	cs.vars = make(map[string]string)
+}
+
+func (cs *CalDAVSuiteTest) TearDownTest() {
+	cs.testServer.Close()
+}
+
+// replay воспроизводит записанный запрос из testdata.
+func (cs *CalDAVSuiteTest) replay(fixture string) (*http.Response, []byte) {
+	raw, err := os.ReadFile(filepath.Join("testdata", fixture))
+	cs.Require().NoError(err)
+	text := string(raw)
+	for name, value := range cs.vars {
+		text = strings.ReplaceAll(text, "{{"+name+"}}", value)
+	}
+	head, body := text, ""
+	if i := strings.Index(text, "\n\n"); i >= 0 {
+		head, body = text[:i+2], text[i+2:]
+	}
+	req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(head)))
+	cs.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := http.NewRequestWithContext(ctx, req.Method, cs.testServer.URL+req.RequestURI, bytes.NewBufferString(body))
+	cs.Require().NoError(err)
+	out.Header = req.Header
+	res, err := http.DefaultClient.Do(out)
+	cs.Require().NoError(err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	resBody, err := io.ReadAll(res.Body)
+	cs.Require().NoError(err)
+	return res, resBody
+}
+
+func (cs *CalDAVSuiteTest) decode(body []byte) multistatus {
+	var ms multistatus
+	cs.Require().NoError(xml.Unmarshal(body, &ms), string(body))
+	return ms
+}
+
+func (cs *CalDAVSuiteTest) TestAuth() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", cs.testServer.URL+"/dav/", nil)
+	cs.Require().NoError(err)
+	res, err := http.DefaultClient.Do(req)
+	cs.Require().NoError(err)
+	_ = res.Body.Close()
+	cs.Require().Equal(http.StatusUnauthorized, res.StatusCode)
+	cs.Require().Contains(res.Header.Get("WWW-Authenticate"), "Basic")
+}
+
+func (cs *CalDAVSuiteTest) TestDiscovery() {
+	res, body := cs.replay("01_propfind_root.http")
+	cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+	ms := cs.decode(body)
+	cs.Require().Len(ms.Responses, 1)
+	cs.Require().Contains(ms.Responses[0].Propstat[0].Prop.Inner, "/dav/principal/")
+
+	res, body = cs.replay("02_propfind_principal.http")
+	cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+	ms = cs.decode(body)
+	cs.Require().Contains(ms.Responses[0].Propstat[0].Prop.Inner, "/dav/calendars/")
+	cs.Require().Contains(ms.Responses[0].Propstat[0].Prop.Inner, "mailto:"+userEmail)
+
+	res, body = cs.replay("03_propfind_home.http")
+	cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+	ms = cs.decode(body)
+	cs.Require().Len(ms.Responses, 2)
+	calendar := ms.Responses[1]
+	cs.Require().Equal("/dav/calendars/events/", calendar.Href)
+	cs.Require().Contains(calendar.Propstat[0].Prop.Inner, "calendar")
+	cs.Require().NotEmpty(calendar.Propstat[0].Prop.CTag)
+	// calendar-color не поддерживается.
+	cs.Require().Len(calendar.Propstat, 2)
+	cs.Require().Contains(calendar.Propstat[1].Status, "404")
+}
+
+func (cs *CalDAVSuiteTest) TestEventLifecycle() {
+	_, body := cs.replay("03_propfind_home.http")
+	ctagBefore := cs.decode(body).Responses[1].Propstat[0].Prop.CTag
+
+	res, _ := cs.replay("04_put_create.http")
+	cs.Require().Equal(http.StatusCreated, res.StatusCode)
+	// адрес PUT совпадает с {UID}.ics, поэтому событие остается по адресу клиента.
+	cs.Require().Empty(res.Header.Get("Location"))
+	cs.Require().NotEmpty(res.Header.Get("ETag"))
+	href := "/dav/calendars/events/" + clientUID + ".ics"
+	cs.vars["eventHref"] = href
+	cs.vars["eventUID"] = clientUID
+
+	_, body = cs.replay("03_propfind_home.http")
+	cs.Require().NotEqual(ctagBefore, cs.decode(body).Responses[1].Propstat[0].Prop.CTag)
+
+	cs.Run("calendar query", func() {
+		res, body := cs.replay("05_report_query.http")
+		cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+		ms := cs.decode(body)
+		cs.Require().Len(ms.Responses, 1)
+		cs.Require().Equal(href, ms.Responses[0].Href)
+		data := ms.Responses[0].Propstat[0].Prop.CalendarData
+		cs.Require().Contains(data, "SUMMARY:Ретроспектива\\, спринт 12")
+		// 15:00 по Москве.
+		cs.Require().Contains(data, "DTSTART:20230303T120000Z")
+		cs.Require().Contains(data, "DTEND:20230303T133000Z")
+		cs.Require().Contains(data, "TRIGGER:-PT15M")
+		cs.vars["etag"] = ms.Responses[0].Propstat[0].Prop.ETag
+	})
+
+	cs.Run("multiget", func() {
+		res, body := cs.replay("06_report_multiget.http")
+		cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+		ms := cs.decode(body)
+		cs.Require().Len(ms.Responses, 2)
+		cs.Require().Equal(cs.vars["etag"], ms.Responses[0].Propstat[0].Prop.ETag)
+		cs.Require().Contains(ms.Responses[1].Status, "404")
+	})
+
+	cs.Run("update", func() {
+		res, _ := cs.replay("07_put_update.http")
+		cs.Require().Equal(http.StatusNoContent, res.StatusCode)
+		cs.Require().NotEqual(cs.vars["etag"], res.Header.Get("ETag"))
+
+		// устаревший etag.
+		res, _ = cs.replay("07_put_update.http")
+		cs.Require().Equal(http.StatusPreconditionFailed, res.StatusCode)
+
+		res, body := cs.replay("06_report_multiget.http")
+		cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+		ms := cs.decode(body)
+		cs.Require().Contains(ms.Responses[0].Propstat[0].Prop.CalendarData, "(перенос)")
+		cs.Require().Contains(ms.Responses[0].Propstat[0].Prop.CalendarData, "DTEND:20230303T140000Z")
+		cs.vars["etag"] = ms.Responses[0].Propstat[0].Prop.ETag
+	})
+
+	cs.Run("delete", func() {
+		res, _ := cs.replay("08_delete.http")
+		cs.Require().Equal(http.StatusNoContent, res.StatusCode)
+
+		res, body := cs.replay("05_report_query.http")
+		cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+		cs.Require().Empty(cs.decode(body).Responses)
+	})
+}
+
+func (cs *CalDAVSuiteTest) TestRepeatedPut() {
+	res, _ := cs.replay("04_put_create.http")
+	cs.Require().Equal(http.StatusCreated, res.StatusCode)
+	// событие с тем же UID уже есть.
+	res, _ = cs.replay("04_put_create.http")
+	cs.Require().Equal(http.StatusPreconditionFailed, res.StatusCode)
+
+	cs.Run("same href", func() {
+		cs.vars["eventName"] = clientUID
+		res, _ := cs.replay("09_put_repeat.http")
+		cs.Require().Equal(http.StatusNoContent, res.StatusCode)
+	})
+
+	cs.Run("other href", func() {
+		cs.vars["eventName"] = "event-copy"
+		res, _ := cs.replay("09_put_repeat.http")
+		cs.Require().Equal(http.StatusNoContent, res.StatusCode)
+	})
+
+	res, body := cs.replay("05_report_query.http")
+	cs.Require().Equal(http.StatusMultiStatus, res.StatusCode)
+	ms := cs.decode(body)
+	cs.Require().Len(ms.Responses, 1)
+	cs.Require().Equal("/dav/calendars/events/"+clientUID+".ics", ms.Responses[0].Href)
+	data := ms.Responses[0].Propstat[0].Prop.CalendarData
+	cs.Require().Contains(data, "UID:"+clientUID)
+	cs.Require().Contains(data, "(повтор)")
+}
+
+func TestCalDAV(t *testing.T) {
+	suite.Run(t, new(CalDAVSuiteTest))
+}
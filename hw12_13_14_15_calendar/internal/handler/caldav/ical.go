@@ -0,0 +1,347 @@
+package caldav
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+/*
+Поддерживается подмножество iCalendar (RFC 5545), достаточное для одиночных событий:
+SUMMARY, DESCRIPTION, DTSTART, DTEND или DURATION и напоминание VALARM с TRIGGER
+относительно начала. Повторяющиеся события не поддерживаются.
+*/
+
+const (
+	icalDateTimeUTC = "20060102T150405Z"
+	icalDateTime    = "20060102T150405"
+	icalDate        = "20060102"
+	icalLineLimit   = 75
+)
+
+var (
+	ErrICalNoEvent       = errors.New("в календаре нет события VEVENT")
+	ErrICalRecurrence    = errors.New("повторяющиеся события не поддерживаются")
+	ErrICalWrongDate     = errors.New("неверный формат даты")
+	ErrICalWrongDuration = errors.New("неверный формат продолжительности")
+)
+
+// icalEvent событие, прочитанное из iCalendar.
+type icalEvent struct {
+	UID         string
+	Summary     string
+	Description *string
+	Start       time.Time
+	Duration    time.Duration
+	// NotifyTerm срок напоминания, nil - напоминания нет.
+	NotifyTerm *time.Duration
+}
+
+// icalProp строка свойства NAME;PARAM=VALUE:VALUE.
+type icalProp struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// readICalLines читает развернутые (unfolded) строки iCalendar.
+func readICalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseICalProp(line string) (icalProp, error) {
+	prop := icalProp{Params: make(map[string]string)}
+	// двоеточие внутри параметров допускается только в кавычках.
+	colon, quoted := -1, false
+	for i, ch := range line {
+		if ch == '"' {
+			quoted = !quoted
+		}
+		if ch == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("неверная строка iCalendar: %q", line)
+	}
+	prop.Value = line[colon+1:]
+	parts := strings.Split(line[:colon], ";")
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+			prop.Params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return prop, nil
+}
+
+// decodeICalEvent первое событие VEVENT календаря.
+func decodeICalEvent(r io.Reader) (*icalEvent, error) {
+	lines, err := readICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		event               *icalEvent
+		inEvent, inAlarm    bool
+		dtEnd               *time.Time
+		hasDuration, allDay bool
+	)
+	for _, line := range lines {
+		prop, err := parseICalProp(line)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VEVENT"):
+			if event != nil {
+				// исключения повторяющегося события передаются дополнительными VEVENT.
+				return nil, ErrICalRecurrence
+			}
+			event, inEvent = &icalEvent{}, true
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VEVENT"):
+			inEvent = false
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VALARM"):
+			inAlarm = true
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VALARM"):
+			inAlarm = false
+		case inAlarm && inEvent:
+			if prop.Name == "TRIGGER" && event.NotifyTerm == nil && !strings.EqualFold(prop.Params["VALUE"], "DATE-TIME") &&
+				!strings.EqualFold(prop.Params["RELATED"], "END") {
+				trigger, err := parseICalDuration(prop.Value)
+				if err != nil {
+					return nil, err
+				}
+				if trigger < 0 {
+					term := -trigger
+					event.NotifyTerm = &term
+				}
+			}
+		case inEvent:
+			switch prop.Name {
+			case "UID":
+				event.UID = prop.Value
+			case "SUMMARY":
+				event.Summary = unescapeICalText(prop.Value)
+			case "DESCRIPTION":
+				description := unescapeICalText(prop.Value)
+				event.Description = &description
+			case "DTSTART":
+				if event.Start, allDay, err = parseICalTime(prop); err != nil {
+					return nil, err
+				}
+			case "DTEND":
+				end, _, err := parseICalTime(prop)
+				if err != nil {
+					return nil, err
+				}
+				dtEnd = &end
+			case "DURATION":
+				if event.Duration, err = parseICalDuration(prop.Value); err != nil {
+					return nil, err
+				}
+				hasDuration = true
+			case "RRULE", "RDATE", "RECURRENCE-ID":
+				return nil, ErrICalRecurrence
+			}
+		}
+	}
+	if event == nil {
+		return nil, ErrICalNoEvent
+	}
+	switch {
+	case dtEnd != nil:
+		event.Duration = dtEnd.Sub(event.Start)
+	case !hasDuration && allDay:
+		event.Duration = 24 * time.Hour
+	}
+	return event, nil
+}
+
+// parseICalTime дата свойства и признак даты без времени (событие на весь день).
+func parseICalTime(prop icalProp) (time.Time, bool, error) {
+	value := prop.Value
+	if strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len(icalDate) {
+		date, err := time.Parse(icalDate, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %s", ErrICalWrongDate, value)
+		}
+		return date, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		date, err := time.Parse(icalDateTimeUTC, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %s", ErrICalWrongDate, value)
+		}
+		return date, false, nil
+	}
+	loc := time.UTC
+	if tzID, ok := prop.Params["TZID"]; ok {
+		var err error
+		if loc, err = time.LoadLocation(tzID); err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: неизвестный часовой пояс %s", ErrICalWrongDate, tzID)
+		}
+	}
+	date, err := time.ParseInLocation(icalDateTime, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %s", ErrICalWrongDate, value)
+	}
+	return date, false, nil
+}
+
+var icalDurationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICalDuration продолжительность в формате RFC 5545, например -PT15M или P1DT2H.
+func parseICalDuration(value string) (time.Duration, error) {
+	matches := icalDurationRe.FindStringSubmatch(strings.ToUpper(value))
+	if matches == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("%w: %s", ErrICalWrongDuration, value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if matches[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(matches[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrICalWrongDuration, value)
+		}
+		duration += time.Duration(n) * unit
+	}
+	if matches[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+func formatICalDuration(duration time.Duration) string {
+	sign := ""
+	if duration < 0 {
+		sign, duration = "-", -duration
+	}
+	if duration == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	if days := duration / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		duration -= days * 24 * time.Hour
+	}
+	if duration == 0 {
+		return b.String()
+	}
+	b.WriteString("T")
+	if h := duration / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := duration % time.Hour / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if s := duration % time.Minute / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+var (
+	icalEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func escapeICalText(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+func unescapeICalText(text string) string {
+	return icalUnescaper.Replace(text)
+}
+
+// icalWriter пишет строки iCalendar с переносом длинных строк.
+type icalWriter struct {
+	b strings.Builder
+}
+
+func (w *icalWriter) line(name, value string) {
+	line := name + ":" + value
+	for len(line) > icalLineLimit {
+		// перенос не должен разрывать многобайтный символ.
+		cut := icalLineLimit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	w.b.WriteString(line + "\r\n")
+}
+
+func (w *icalWriter) event(event model.Event) {
+	uid := event.ICalUID
+	if uid == "" {
+		uid = event.ID.String()
+	}
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", uid)
+	w.line("DTSTAMP", event.UpdatedAt.UTC().Format(icalDateTimeUTC))
+	w.line("CREATED", event.CreatedAt.UTC().Format(icalDateTimeUTC))
+	w.line("LAST-MODIFIED", event.UpdatedAt.UTC().Format(icalDateTimeUTC))
+	w.line("DTSTART", event.Date.UTC().Format(icalDateTimeUTC))
+	w.line("DTEND", event.Date.Add(event.Duration).UTC().Format(icalDateTimeUTC))
+	w.line("SUMMARY", escapeICalText(event.Title))
+	if event.Description != "" {
+		w.line("DESCRIPTION", escapeICalText(event.Description))
+	}
+	if len(event.Tags) > 0 {
+		names := make([]string, len(event.Tags))
+		for i, tag := range event.Tags {
+			names[i] = escapeICalText(tag.Name)
+		}
+		w.line("CATEGORIES", strings.Join(names, ","))
+	}
+	if event.NotifyTerm > 0 {
+		w.line("BEGIN", "VALARM")
+		w.line("ACTION", "DISPLAY")
+		w.line("DESCRIPTION", escapeICalText(event.Title))
+		w.line("TRIGGER", formatICalDuration(-event.NotifyTerm))
+		w.line("END", "VALARM")
+	}
+	w.line("END", "VEVENT")
+}
+
+// encodeICal календарь iCalendar с указанными событиями.
+func encodeICal(events ...model.Event) string {
+	w := &icalWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//otusgo-hw//calendar//RU")
+	w.line("CALSCALE", "GREGORIAN")
+	for _, event := range events {
+		w.event(event)
+	}
+	w.line("END", "VCALENDAR")
+	return w.b.String()
+}
@@ -0,0 +1,172 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	// nsCS расширения Apple Calendar Server, из них нужен только getctag.
+	nsCS = "http://calendarserver.org/ns/"
+)
+
+// prefixes префиксы пространств имен в ответах.
+var prefixes = map[string]string{
+	nsDAV:    "d",
+	nsCalDAV: "c",
+	nsCS:     "cs",
+}
+
+// propNames имена запрошенных свойств.
+type propNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+func (pn propNames) list() []xml.Name {
+	names := make([]xml.Name, len(pn.Names))
+	for i, name := range pn.Names {
+		names[i] = name.XMLName
+	}
+	return names
+}
+
+// propfindRequest тело PROPFIND, пустое тело равносильно allprop.
+type propfindRequest struct {
+	XMLName  xml.Name   `xml:"DAV: propfind"`
+	AllProp  *struct{}  `xml:"DAV: allprop"`
+	PropName *struct{}  `xml:"DAV: propname"`
+	Prop     *propNames `xml:"DAV: prop"`
+}
+
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	TimeRange   *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// reportRequest тело REPORT calendar-query или calendar-multiget.
+type reportRequest struct {
+	XMLName xml.Name
+	Prop    *propNames `xml:"DAV: prop"`
+	Hrefs   []string   `xml:"DAV: href"`
+	Filter  *struct {
+		CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// prop свойство ресурса с готовым xml-содержимым.
+type prop struct {
+	name  xml.Name
+	inner string
+}
+
+func davProp(local, inner string) prop {
+	return prop{name: xml.Name{Space: nsDAV, Local: local}, inner: inner}
+}
+
+func calProp(local, inner string) prop {
+	return prop{name: xml.Name{Space: nsCalDAV, Local: local}, inner: inner}
+}
+
+func hrefXML(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+func escapeXML(text string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// msResponse элемент response ответа multistatus.
+type msResponse struct {
+	href string
+	// status статус ресурса целиком, если свойства не запрашивались.
+	status  int
+	found   []prop
+	missing []xml.Name
+}
+
+// selectProps разделение свойств ресурса на найденные и отсутствующие; names == nil - все свойства.
+func selectProps(href string, all []prop, names []xml.Name) msResponse {
+	resp := msResponse{href: href}
+	if names == nil {
+		resp.found = all
+		return resp
+	}
+	for _, name := range names {
+		found := false
+		for _, p := range all {
+			if p.name == name {
+				resp.found = append(resp.found, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp.missing = append(resp.missing, name)
+		}
+	}
+	return resp
+}
+
+func writeElement(b *strings.Builder, name xml.Name, inner string) {
+	tag, attr := name.Local, ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag, attr = "x:"+name.Local, fmt.Sprintf(` xmlns:x="%s"`, escapeXML(name.Space))
+	}
+	if inner == "" {
+		fmt.Fprintf(b, "<%s%s/>", tag, attr)
+		return
+	}
+	fmt.Fprintf(b, "<%s%s>%s</%s>", tag, attr, inner, tag)
+}
+
+func statusLine(code int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+// multistatus тело ответа 207 Multi-Status.
+func multistatus(responses []msResponse) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<d:multistatus xmlns:d="%s" xmlns:c="%s" xmlns:cs="%s">`, nsDAV, nsCalDAV, nsCS)
+	for _, resp := range responses {
+		b.WriteString("<d:response>")
+		b.WriteString(hrefXML(resp.href))
+		if resp.status != 0 {
+			b.WriteString(statusLine(resp.status))
+		}
+		if len(resp.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, p := range resp.found {
+				writeElement(&b, p.name, p.inner)
+			}
+			b.WriteString("</d:prop>" + statusLine(http.StatusOK) + "</d:propstat>")
+		}
+		if len(resp.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range resp.missing {
+				writeElement(&b, name, "")
+			}
+			b.WriteString("</d:prop>" + statusLine(http.StatusNotFound) + "</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+	return b.String()
+}
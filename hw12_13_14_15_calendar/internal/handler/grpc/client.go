@@ -2,24 +2,73 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	grpcServ "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
 type AuthFn func(ctx context.Context) context.Context
 
-func NewSupportClient(apiAddr, apiLogin string) (events.SupportClient, AuthFn, error) {
-	conn, err := grpc.Dial(apiAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// ClientOption настройка подключения клиента.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	creds credentials.TransportCredentials
+}
+
+// WithTLS подключение с TLS. Если клиент предъявляет сертификат, сервер авторизует его
+// сопоставленной сертификату учетной записью, а не по логину.
+func WithTLS(config *tls.Config) ClientOption {
+	return func(c *clientConfig) {
+		c.creds = credentials.NewTLS(config)
+	}
+}
+
+// Клиенты возвращают ошибки errx, восстановленные по деталям статуса ответа, и передают
+// в метаданных идентификатор запроса из контекста вызова.
+
+func NewSupportClient(apiAddr, apiLogin string, opts ...ClientOption) (events.SupportClient, AuthFn, error) {
+	conn, err := dial(apiAddr, opts)
 	if err != nil {
-		return nil, nil, fmt.Errorf("can't dial GRPC server: %w", err)
+		return nil, nil, err
 	}
-	return events.NewSupportClient(conn), func(ctx context.Context) context.Context {
+	return events.NewSupportClient(conn), authFn(apiLogin), nil
+}
+
+func NewEventsClient(apiAddr, apiLogin string, opts ...ClientOption) (events.EventsClient, AuthFn, error) {
+	conn, err := dial(apiAddr, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events.NewEventsClient(conn), authFn(apiLogin), nil
+}
+
+func dial(apiAddr string, opts []ClientOption) (*grpc.ClientConn, error) {
+	config := clientConfig{creds: insecure.NewCredentials()}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	conn, err := grpc.Dial(
+		apiAddr,
+		grpc.WithTransportCredentials(config.creds),
+		grpc.WithChainUnaryInterceptor(grpcServ.RequestIDClientInterceptor(), grpcServ.ErrorsClientInterceptor()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't dial GRPC server: %w", err)
+	}
+	return conn, nil
+}
+
+func authFn(apiLogin string) AuthFn {
+	return func(ctx context.Context) context.Context {
 		meta := metadata.New(nil)
 		meta.Append("authorization", apiLogin)
 		return metadata.NewOutgoingContext(ctx, meta)
-	}, nil
+	}
 }
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LinksModel неизвестный тип ссылки не отбрасывается, а отклоняется проверкой в сервисе.
+func LinksModel(list []*events.Link) []model.EventLink {
+	if len(list) == 0 {
+		return nil
+	}
+	links := make([]model.EventLink, 0, len(list))
+	for _, item := range list {
+		if item == nil {
+			continue
+		}
+		linkType, _ := model.ParseLinkType(item.Type)
+		links = append(links, model.EventLink{Type: linkType, URL: item.URL, Title: item.Title})
+	}
+	return links
+}
+
+func FromLinkSlice(items []model.EventLink) []*events.Link {
+	if len(items) == 0 {
+		return nil
+	}
+	links := make([]*events.Link, len(items))
+	for i, item := range items {
+		links[i] = &events.Link{Type: item.Type.String(), URL: item.URL, Title: item.Title}
+	}
+	return links
+}
+
+func FromAttachmentSlice(items []model.Attachment) *events.Attachments {
+	list := make([]*events.Attachment, len(items))
+	for i, item := range items {
+		list[i] = &events.Attachment{
+			ID:          item.ID.String(),
+			EventID:     item.EventID.String(),
+			FileName:    item.FileName,
+			ContentType: item.ContentType,
+			Size:        item.Size,
+			CreatedAt:   timestamppb.New(item.CreatedAt),
+		}
+	}
+	return &events.Attachments{List: list}
+}
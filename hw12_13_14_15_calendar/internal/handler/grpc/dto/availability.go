@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AvailabilityModel неверные значения дня недели и политики проверяются в сервисе.
+func AvailabilityModel(req *events.Availability) model.Availability {
+	if req == nil {
+		return model.Availability{}
+	}
+	input := model.Availability{
+		TimeZone: req.TimeZone,
+		Policy:   model.AvailabilityPolicy(req.Policy),
+		Hours:    make([]model.WorkingHours, 0, len(req.Hours)),
+	}
+	for _, wh := range req.Hours {
+		if wh == nil {
+			continue
+		}
+		input.Hours = append(input.Hours, model.WorkingHours{
+			Weekday: time.Weekday(wh.Weekday),
+			Start:   wh.Start.AsDuration(),
+			End:     wh.End.AsDuration(),
+		})
+	}
+	return input
+}
+
+func FromAvailabilityModel(item model.Availability) *events.Availability {
+	result := &events.Availability{
+		TimeZone: item.TimeZone,
+		Policy:   events.AvailabilityPolicy(item.Policy),
+		Hours:    make([]*events.WorkingHours, len(item.Hours)),
+	}
+	for i, wh := range item.Hours {
+		result.Hours[i] = &events.WorkingHours{
+			Weekday: int32(wh.Weekday),
+			Start:   durationpb.New(wh.Start),
+			End:     durationpb.New(wh.End),
+		}
+	}
+	return result
+}
+
+func OutOfOfficeCreateModel(req *events.AddOutOfOfficeReq) model.OutOfOfficeCreate {
+	if req == nil {
+		return model.OutOfOfficeCreate{}
+	}
+	input := model.OutOfOfficeCreate{Reason: req.Reason}
+	if req.DateFrom != nil {
+		input.DateFrom = req.DateFrom.AsTime()
+	}
+	if req.DateTo != nil {
+		input.DateTo = req.DateTo.AsTime()
+	}
+	return input
+}
+
+func OutOfOfficeIDReqModel(idReq *events.OutOfOfficeIDReq) (uuid.UUID, error) {
+	if idReq == nil {
+		return uuid.UUID{}, errors.New("empty outOfOfficeIDReq")
+	}
+	return uuid.Parse(idReq.ID)
+}
+
+func FromOutOfOfficeModel(item model.OutOfOffice) *events.OutOfOffice {
+	return &events.OutOfOffice{
+		ID:       item.ID.String(),
+		DateFrom: timestamppb.New(item.DateFrom),
+		DateTo:   timestamppb.New(item.DateTo),
+		Reason:   item.Reason,
+	}
+}
+
+func FromOutOfOfficeSlice(items []model.OutOfOffice) *events.OutOfOfficeList {
+	result := &events.OutOfOfficeList{
+		List: make([]*events.OutOfOffice, len(items)),
+	}
+	for i, item := range items {
+		result.List[i] = FromOutOfOfficeModel(item)
+	}
+	return result
+}
+
+func FromSlotSlice(items []model.DateRange) *events.Slots {
+	result := &events.Slots{
+		List: make([]*events.Slot, len(items)),
+	}
+	for i, item := range items {
+		result.List[i] = &events.Slot{
+			Start: timestamppb.New(item.GetFrom()),
+			End:   timestamppb.New(item.GetTo()),
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DigestSettingsModel неверные значения периодичности и дня недели проверяются в сервисе.
+func DigestSettingsModel(req *events.DigestSettings) model.DigestSettings {
+	if req == nil {
+		return model.DigestSettings{}
+	}
+	return model.DigestSettings{
+		Mode:    model.DigestMode(req.Mode),
+		SendAt:  req.SendAt.AsDuration(),
+		Weekday: time.Weekday(req.Weekday),
+	}
+}
+
+func FromDigestSettingsModel(item model.DigestSettings) *events.DigestSettings {
+	return &events.DigestSettings{
+		Mode:    events.DigestMode(item.Mode),
+		SendAt:  durationpb.New(item.SendAt),
+		Weekday: int32(item.Weekday),
+	}
+}
+
+func FromDigestModel(item model.Digest) *events.Digest {
+	result := &events.Digest{
+		Mode:       item.Mode,
+		DateStart:  timestamppb.New(item.DateStart),
+		DateEnd:    timestamppb.New(item.DateEnd),
+		TimeZone:   item.TimeZone,
+		UserName:   item.NotifyUser.Name,
+		UserEmail:  item.NotifyUser.Email,
+		UserLocale: item.NotifyUser.Locale,
+		Events:     make([]*events.DigestEvent, len(item.Events)),
+		UserID:     item.UserID.String(),
+		SendAt:     timestamppb.New(item.SendAt),
+	}
+	for i, event := range item.Events {
+		result.Events[i] = &events.DigestEvent{
+			ID:       event.ID.String(),
+			Title:    event.Title,
+			Date:     timestamppb.New(event.Date),
+			Duration: durationpb.New(event.Duration),
+		}
+	}
+	return result
+}
+
+func FromDigestSlice(items []model.Digest) *events.Digests {
+	result := &events.Digests{
+		List: nil,
+	}
+	if len(items) == 0 {
+		return result
+	}
+	result.List = make([]*events.Digest, len(items))
+	for i, item := range items {
+		result.List[i] = FromDigestModel(item)
+	}
+	return result
+}
+
+func ToDigestModel(item *events.Digest) (model.Digest, error) {
+	if item == nil {
+		return model.Digest{}, nil
+	}
+	userID, err := uuid.Parse(item.UserID)
+	if err != nil {
+		return model.Digest{}, err
+	}
+	digest := model.Digest{
+		Kind:      model.MessageDigest,
+		Mode:      item.Mode,
+		DateStart: item.DateStart.AsTime(),
+		DateEnd:   item.DateEnd.AsTime(),
+		TimeZone:  item.TimeZone,
+		NotifyUser: model.NotifyUser{
+			Name:   item.UserName,
+			Email:  item.UserEmail,
+			Locale: item.UserLocale,
+		},
+		Events: make([]model.DigestEvent, 0, len(item.Events)),
+		UserID: userID,
+		SendAt: item.SendAt.AsTime(),
+	}
+	for _, event := range item.Events {
+		if event == nil {
+			continue
+		}
+		eventID, err := uuid.Parse(event.ID)
+		if err != nil {
+			return model.Digest{}, err
+		}
+		digest.Events = append(digest.Events, model.DigestEvent{
+			ID:       eventID,
+			Title:    event.Title,
+			Date:     event.Date.AsTime(),
+			Duration: event.Duration.AsDuration(),
+		})
+	}
+	return digest, nil
+}
+
+func ToDigestSlice(items *events.Digests) ([]model.Digest, error) {
+	if items == nil {
+		return nil, nil
+	}
+	result := make([]model.Digest, len(items.List))
+	for i, item := range items.List {
+		digest, err := ToDigestModel(item)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = digest
+	}
+	return result, nil
+}
+
+// ToDigestSentReq подтверждение отправки сводки по владельцу и запланированному времени.
+func ToDigestSentReq(item model.Digest) *events.DigestSentReq {
+	return &events.DigestSentReq{
+		UserID: item.UserID.String(),
+		SendAt: timestamppb.New(item.SendAt),
+	}
+}
+
+func DigestSentReqModel(req *events.DigestSentReq) (uuid.UUID, time.Time, error) {
+	if req == nil || req.SendAt == nil {
+		return uuid.UUID{}, time.Time{}, errors.New("empty digestSentReq")
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return uuid.UUID{}, time.Time{}, err
+	}
+	return userID, req.GetSendAt().AsTime(), nil
+}
@@ -42,6 +42,15 @@ func EventCreateModel(createEvent *events.CreateEvent) model.EventCreate {
 		val := createEvent.NotifyTerm.AsDuration()
 		input.NotifyTerm = &val
 	}
+	if len(createEvent.TagIDs) > 0 {
+		input.TagIDs = TagIDsModel(createEvent.TagIDs)
+	}
+	if len(createEvent.Links) > 0 {
+		input.Links = LinksModel(createEvent.Links)
+	}
+	if len(createEvent.ResourceIDs) > 0 {
+		input.ResourceIDs = ResourceIDsModel(createEvent.ResourceIDs)
+	}
 	return input
 }
 
@@ -72,6 +81,18 @@ func EventUpdateModel(updateEvent *events.UpdateEvent) (uuid.UUID, model.EventUp
 		val := updateEvent.NotifyTerm.AsDuration()
 		input.NotifyTerm = &val
 	}
+	if updateEvent.TagIDs != nil {
+		val := TagIDsModel(updateEvent.TagIDs.List)
+		input.TagIDs = &val
+	}
+	if updateEvent.Links != nil {
+		val := LinksModel(updateEvent.Links.List)
+		input.Links = &val
+	}
+	if updateEvent.ResourceIDs != nil {
+		val := ResourceIDsModel(updateEvent.ResourceIDs.List)
+		input.ResourceIDs = &val
+	}
 	guid, err := uuid.Parse(updateEvent.ID)
 	if err != nil {
 		return uuid.UUID{}, model.EventUpdate{}, err
@@ -86,32 +107,67 @@ func EventIDReqModel(idReq *events.EventIDReq) (uuid.UUID, error) {
 	return uuid.Parse(idReq.ID)
 }
 
-func ListOnDateReqModel(req *events.ListOnDateReq) (time.Time, model.RangeKind) {
+func ListOnDateReqModel(req *events.ListOnDateReq) (time.Time, model.RangeKind, []uuid.UUID) {
 	var (
 		date      time.Time
 		rangeType model.RangeKind
+		tagIDs    []uuid.UUID
 	)
 	if req == nil {
-		return date, rangeType
+		return date, rangeType, tagIDs
 	}
 	if req.Date != nil {
 		date = req.Date.AsTime()
 	}
 	rangeType = model.RangeKind(req.RangeType.Number())
+	if len(req.TagIDs) > 0 {
+		tagIDs = TagIDsModel(req.TagIDs)
+	}
 
-	return date, rangeType
+	return date, rangeType, tagIDs
 }
 
 func FromEventModel(item model.Event) *events.Event {
-	return &events.Event{
-		ID:          item.ID.String(),
-		Title:       item.Title,
-		Date:        timestamppb.New(item.Date),
-		Duration:    durationpb.New(item.Duration),
-		Description: item.Description,
-		NotifyTerm:  durationpb.New(item.NotifyTerm),
-		CreatedAt:   timestamppb.New(item.CreatedAt),
-		UpdatedAt:   timestamppb.New(item.UpdatedAt),
+	event := &events.Event{
+		ID:           item.ID.String(),
+		Title:        item.Title,
+		Date:         timestamppb.New(item.Date),
+		Duration:     durationpb.New(item.Duration),
+		Description:  item.Description,
+		NotifyTerm:   durationpb.New(item.NotifyTerm),
+		CreatedAt:    timestamppb.New(item.CreatedAt),
+		UpdatedAt:    timestamppb.New(item.UpdatedAt),
+		Tags:         FromTagSlice(item.Tags).GetList(),
+		Warnings:     item.Warnings,
+		NotifyStatus: item.NotifyStatus.String(),
+		Links:        FromLinkSlice(item.Links),
+		Resources:    FromResourceSlice(item.Resources).GetList(),
+	}
+	if item.DeletedAt != nil {
+		event.DeletedAt = timestamppb.New(*item.DeletedAt)
+	}
+	if item.Owner != nil {
+		event.Owner = FromUserModel(*item.Owner)
+	}
+	return event
+}
+
+func FromUserModel(item model.User) *events.User {
+	return &events.User{
+		ID:     item.ID.String(),
+		Name:   item.Name,
+		Email:  item.Email,
+		Locale: item.Locale,
+	}
+}
+
+func ProfileModel(req *events.UpdateProfileReq) model.UserUpdate {
+	if req == nil {
+		return model.UserUpdate{}
+	}
+	return model.UserUpdate{
+		Name:   req.Name,
+		Locale: req.Locale,
 	}
 }
 
@@ -128,3 +184,21 @@ func FromEventSlice(items []model.Event) *events.Events {
 	}
 	return result
 }
+
+func FromEventFoundSlice(items []model.EventFound) *events.SearchResults {
+	result := &events.SearchResults{
+		List: nil,
+	}
+	if len(items) == 0 {
+		return result
+	}
+	result.List = make([]*events.SearchResult, len(items))
+	for i, item := range items {
+		result.List[i] = &events.SearchResult{
+			Event:   FromEventModel(item.Event),
+			Rank:    item.Rank,
+			Snippet: item.Snippet,
+		}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+func CreateBatchModel(req *events.CreateBatchReq) ([]model.EventCreate, model.BatchMode) {
+	if req == nil {
+		return nil, model.BatchAtomic
+	}
+	inputs := make([]model.EventCreate, len(req.Items))
+	for i, item := range req.Items {
+		inputs[i] = EventCreateModel(item)
+	}
+	return inputs, model.BatchMode(req.Mode.Number())
+}
+
+// UpdateBatchModel неверный идентификатор события в любой операции отклоняет пакет целиком.
+func UpdateBatchModel(req *events.UpdateBatchReq) ([]model.EventBatchUpdate, model.BatchMode, error) {
+	if req == nil {
+		return nil, model.BatchAtomic, errors.New("empty query")
+	}
+	items := make([]model.EventBatchUpdate, len(req.Items))
+	for i, item := range req.Items {
+		eventID, input, err := EventUpdateModel(item)
+		if err != nil {
+			return nil, model.BatchAtomic, fmt.Errorf("операция %d: %w", i, err)
+		}
+		items[i] = model.EventBatchUpdate{ID: eventID, Input: input}
+	}
+	return items, model.BatchMode(req.Mode.Number()), nil
+}
+
+func DeleteBatchModel(req *events.DeleteBatchReq) ([]uuid.UUID, model.BatchMode, error) {
+	if req == nil {
+		return nil, model.BatchAtomic, errors.New("empty query")
+	}
+	eventIDs := make([]uuid.UUID, len(req.IDs))
+	for i, rawID := range req.IDs {
+		eventID, err := uuid.Parse(rawID)
+		if err != nil {
+			return nil, model.BatchAtomic, fmt.Errorf("операция %d: %w", i, err)
+		}
+		eventIDs[i] = eventID
+	}
+	return eventIDs, model.BatchMode(req.Mode.Number()), nil
+}
+
+func FromBatchReport(report model.BatchReport) *events.BatchReport {
+	result := &events.BatchReport{
+		Applied: int32(report.Applied),
+		Results: make([]*events.BatchResult, len(report.Results)),
+	}
+	for i, item := range report.Results {
+		res := &events.BatchResult{Index: int32(i)}
+		if item.EventID.ID() > 0 {
+			res.EventID = item.EventID.String()
+		}
+		if item.Event != nil {
+			res.Event = FromEventModel(*item.Event)
+		}
+		if item.Err != nil {
+			res.Error = fromBatchError(item.Err)
+		}
+		result.Results[i] = res
+	}
+	return result
+}
+
+func fromBatchError(err error) *events.BatchError {
+	s := rqres.FromError(err)
+	batchErr := &events.BatchError{
+		Status:  uint32(s.Code()),
+		Message: s.Message(),
+	}
+	logErr := errx.Logic{}
+	if errors.As(err, &logErr) {
+		batchErr.Code = int32(logErr.Code())
+	}
+	invErr := errx.Invalid{}
+	if errors.As(err, &invErr) {
+		batchErr.Fields = make(map[string]string)
+		for _, namedErr := range invErr.Errors() {
+			batchErr.Fields[namedErr.Field] = namedErr.Err.Error()
+		}
+	}
+	return batchErr
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func LeaseAcquireModel(req *events.LeaseReq) model.LeaseAcquire {
+	return model.LeaseAcquire{
+		Name:   req.GetName(),
+		Holder: req.GetHolder(),
+		TTL:    req.GetTTL().AsDuration(),
+	}
+}
+
+func FromLeaseModel(item model.Lease) *events.Lease {
+	return &events.Lease{
+		Name:      item.Name,
+		Holder:    item.Holder,
+		ExpiresAt: timestamppb.New(item.ExpiresAt),
+	}
+}
@@ -19,12 +19,14 @@ func NotificationIDReqModel(idReq *events.NotificationIDReq) (uuid.UUID, error)
 
 func FromNotificationModel(item model.Notification) *events.Notification {
 	return &events.Notification{
-		ID:        item.EventID.String(),
-		Title:     item.EventTitle,
-		Date:      timestamppb.New(item.EventDate),
-		Duration:  durationpb.New(item.EventDuration),
-		UserName:  item.NotifyUser.Name,
-		UserEmail: item.NotifyUser.Email,
+		ID:         item.EventID.String(),
+		Title:      item.EventTitle,
+		Date:       timestamppb.New(item.EventDate),
+		Duration:   durationpb.New(item.EventDuration),
+		UserName:   item.NotifyUser.Name,
+		UserEmail:  item.NotifyUser.Email,
+		UserLocale: item.NotifyUser.Locale,
+		Links:      FromLinkSlice(item.EventLinks),
 	}
 }
 
@@ -56,9 +58,11 @@ func ToNotificationModel(item *events.Notification) (model.Notification, error)
 		EventDate:     item.Date.AsTime(),
 		EventDuration: item.Duration.AsDuration(),
 		NotifyUser: model.NotifyUser{
-			Name:  item.UserName,
-			Email: item.UserEmail,
+			Name:   item.UserName,
+			Email:  item.UserEmail,
+			Locale: item.UserLocale,
 		},
+		EventLinks: LinksModel(item.Links),
 	}, nil
 }
 
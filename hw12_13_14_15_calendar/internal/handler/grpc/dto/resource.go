@@ -0,0 +1,167 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ResourceIDsModel неверные идентификаторы заменяются на uuid.Nil, такой ресурс не будет найден в сервисе.
+func ResourceIDsModel(rawIDs []string) []uuid.UUID {
+	resourceIDs := make([]uuid.UUID, len(rawIDs))
+	for i, rawID := range rawIDs {
+		resourceID, err := uuid.Parse(rawID)
+		if err != nil {
+			resourceID = uuid.Nil
+		}
+		resourceIDs[i] = resourceID
+	}
+	return resourceIDs
+}
+
+// ResourceCreateModel неизвестный вид ресурса отклоняется проверкой в сервисе.
+func ResourceCreateModel(req *events.CreateResourceReq) model.ResourceCreate {
+	if req == nil {
+		return model.ResourceCreate{}
+	}
+	kind, _ := model.ParseResourceKind(req.Kind)
+	return model.ResourceCreate{
+		Kind:     kind,
+		Name:     req.Name,
+		Capacity: int(req.Capacity),
+		Location: req.Location,
+	}
+}
+
+func ResourceUpdateModel(req *events.UpdateResourceReq) (uuid.UUID, model.ResourceUpdate, error) {
+	if req == nil {
+		return uuid.UUID{}, model.ResourceUpdate{}, errors.New("empty query")
+	}
+	input := model.ResourceUpdate{}
+	if req.Name != nil {
+		val := *req.Name
+		input.Name = &val
+	}
+	if req.Capacity != nil {
+		val := int(*req.Capacity)
+		input.Capacity = &val
+	}
+	if req.Location != nil {
+		val := *req.Location
+		input.Location = &val
+	}
+	if req.ManagerID != nil {
+		managerID, err := uuid.Parse(*req.ManagerID)
+		if err != nil {
+			return uuid.UUID{}, model.ResourceUpdate{}, err
+		}
+		input.ManagerID = &managerID
+	}
+	guid, err := uuid.Parse(req.ID)
+	if err != nil {
+		return uuid.UUID{}, model.ResourceUpdate{}, err
+	}
+	return guid, input, nil
+}
+
+func ResourceIDReqModel(idReq *events.ResourceIDReq) (uuid.UUID, error) {
+	if idReq == nil {
+		return uuid.UUID{}, errors.New("empty resourceIDReq")
+	}
+	return uuid.Parse(idReq.ID)
+}
+
+func ResourcesReqModel(req *events.ResourcesReq) (model.ResourceSearch, error) {
+	return resourceSearchModel(req.GetKind(), req.GetMinCapacity())
+}
+
+func ResourceBookingsReqModel(req *events.ResourceBookingsReq) (uuid.UUID, model.DateRange, error) {
+	if req == nil {
+		return uuid.UUID{}, model.DateRange{}, errors.New("empty resourceBookingsReq")
+	}
+	resourceID, err := uuid.Parse(req.ID)
+	if err != nil {
+		return uuid.UUID{}, model.DateRange{}, err
+	}
+	return resourceID, periodModel(req.From, req.To), nil
+}
+
+func AvailableResourcesReqModel(req *events.AvailableResourcesReq) (model.ResourceSearch, model.DateRange, error) {
+	search, err := resourceSearchModel(req.GetKind(), req.GetMinCapacity())
+	if err != nil {
+		return model.ResourceSearch{}, model.DateRange{}, err
+	}
+	return search, periodModel(req.GetFrom(), req.GetTo()), nil
+}
+
+// periodModel незаданный или обратный период отклоняется проверкой в сервисе.
+func periodModel(from, to *timestamppb.Timestamp) model.DateRange {
+	if from == nil || to == nil {
+		return model.DateRange{}
+	}
+	return model.DateRange{DateStart: from.AsTime(), Duration: to.AsTime().Sub(from.AsTime())}
+}
+
+// resourceSearchModel пустой вид и нулевая вместимость не ограничивают выборку.
+func resourceSearchModel(rawKind string, minCapacity int32) (model.ResourceSearch, error) {
+	search := model.ResourceSearch{}
+	if rawKind != "" {
+		kind, err := model.ParseResourceKind(rawKind)
+		if err != nil {
+			return model.ResourceSearch{}, errx.InvalidNew("неверные параметры", errx.NamedErrors{
+				{Field: "Kind", Err: err},
+			})
+		}
+		search.Kind = &kind
+	}
+	if minCapacity > 0 {
+		capacity := int(minCapacity)
+		search.MinCapacity = &capacity
+	}
+	return search, nil
+}
+
+func FromResourceModel(item model.Resource) *events.Resource {
+	return &events.Resource{
+		ID:        item.ID.String(),
+		Kind:      item.Kind.String(),
+		Name:      item.Name,
+		ManagerID: item.ManagerID.String(),
+		Capacity:  int32(item.Capacity),
+		Location:  item.Location,
+	}
+}
+
+func FromResourceSlice(items []model.Resource) *events.Resources {
+	result := &events.Resources{
+		List: nil,
+	}
+	if len(items) == 0 {
+		return result
+	}
+	result.List = make([]*events.Resource, len(items))
+	for i, item := range items {
+		result.List[i] = FromResourceModel(item)
+	}
+	return result
+}
+
+func FromResourceBookingSlice(items []model.ResourceBooking) *events.ResourceBookings {
+	result := &events.ResourceBookings{
+		List: make([]*events.ResourceBooking, len(items)),
+	}
+	for i, item := range items {
+		result.List[i] = &events.ResourceBooking{
+			ResourceID: item.ResourceID.String(),
+			EventID:    item.EventID.String(),
+			Start:      timestamppb.New(item.DateRange.GetFrom()),
+			End:        timestamppb.New(item.DateRange.GetTo()),
+			BookedBy:   item.BookedBy,
+		}
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+// TagIDsModel неверные идентификаторы заменяются на uuid.Nil, такая метка не будет найдена в сервисе.
+func TagIDsModel(rawIDs []string) []uuid.UUID {
+	tagIDs := make([]uuid.UUID, len(rawIDs))
+	for i, rawID := range rawIDs {
+		tagID, err := uuid.Parse(rawID)
+		if err != nil {
+			tagID = uuid.Nil
+		}
+		tagIDs[i] = tagID
+	}
+	return tagIDs
+}
+
+func TagCreateModel(req *events.CreateTagReq) model.TagCreate {
+	if req == nil {
+		return model.TagCreate{}
+	}
+	return model.TagCreate{
+		Name:  req.Name,
+		Color: req.Color,
+	}
+}
+
+func TagUpdateModel(req *events.UpdateTagReq) (uuid.UUID, model.TagUpdate, error) {
+	if req == nil {
+		return uuid.UUID{}, model.TagUpdate{}, errors.New("empty query")
+	}
+	input := model.TagUpdate{}
+	if req.Name != nil {
+		val := *req.Name
+		input.Name = &val
+	}
+	if req.Color != nil {
+		val := *req.Color
+		input.Color = &val
+	}
+	guid, err := uuid.Parse(req.ID)
+	if err != nil {
+		return uuid.UUID{}, model.TagUpdate{}, err
+	}
+	return guid, input, nil
+}
+
+func TagIDReqModel(idReq *events.TagIDReq) (uuid.UUID, error) {
+	if idReq == nil {
+		return uuid.UUID{}, errors.New("empty tagIDReq")
+	}
+	return uuid.Parse(idReq.ID)
+}
+
+func FromTagModel(item model.Tag) *events.Tag {
+	return &events.Tag{
+		ID:    item.ID.String(),
+		Name:  item.Name,
+		Color: item.Color,
+	}
+}
+
+func FromTagSlice(items []model.Tag) *events.Tags {
+	result := &events.Tags{
+		List: nil,
+	}
+	if len(items) == 0 {
+		return result
+	}
+	result.List = make([]*events.Tag, len(items))
+	for i, item := range items {
+		result.List[i] = FromTagModel(item)
+	}
+	return result
+}
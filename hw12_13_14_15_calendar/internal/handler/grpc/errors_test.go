@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (es *EventsSuiteTest) TestErrorDetails() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	date := time.Date(2023, 3, 6, 9, 0, 0, 0, time.UTC)
+	addEvents(es, []*events.CreateEvent{{
+		Title:    "Планерка",
+		Date:     timestamppb.New(date),
+		Duration: durationpb.New(time.Hour),
+	}})
+
+	es.Run("status details", func() {
+		_, err := es.evClient.Create(auth(ctx, es), &events.CreateEvent{
+			Title:    "Пересечение",
+			Date:     timestamppb.New(date.Add(30 * time.Minute)),
+			Duration: durationpb.New(time.Hour),
+		})
+		st := status.Convert(err)
+		es.Require().Len(st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		es.Require().True(ok)
+		es.Require().Equal(rqres.ReasonLogic, info.Reason)
+		es.Require().Equal(rqres.ErrorDomain, info.Domain)
+		es.Require().Equal("1005", info.Metadata[rqres.MetaCode])
+	})
+
+	client, authFn, err := NewEventsClient("127.0.0.1:50051", ValidUserEmail)
+	es.Require().NoError(err)
+
+	es.Run("logic", func() {
+		_, err := client.Create(authFn(ctx), &events.CreateEvent{
+			Title:    "Пересечение",
+			Date:     timestamppb.New(date.Add(30 * time.Minute)),
+			Duration: durationpb.New(time.Hour),
+		})
+		var logErr errx.Logic
+		es.Require().True(errors.As(err, &logErr), err)
+		es.Require().Equal(model.ErrEventDateBusyCode, logErr.Code())
+		es.Require().Equal(model.ErrEventDateBusy.Error(), logErr.Error())
+	})
+
+	es.Run("invalid", func() {
+		_, err := client.Create(authFn(ctx), &events.CreateEvent{
+			Date:     timestamppb.New(date.Add(24 * time.Hour)),
+			Duration: durationpb.New(-time.Hour),
+		})
+		var invErr errx.Invalid
+		es.Require().True(errors.As(err, &invErr), err)
+		fields := make([]string, 0)
+		for _, namedErr := range invErr.Errors() {
+			fields = append(fields, namedErr.Field)
+		}
+		es.Require().ElementsMatch([]string{"Title", "Duration"}, fields)
+	})
+
+	es.Run("not found", func() {
+		_, err := client.Restore(authFn(ctx), &events.EventIDReq{ID: uuid.New().String()})
+		var nfErr errx.NotFound
+		es.Require().True(errors.As(err, &nfErr), err)
+		params, ok := nfErr.Params.(map[string]string)
+		es.Require().True(ok)
+		es.Require().Contains(params, "eventId")
+	})
+
+	es.Run("perms", func() {
+		_, err := client.GetTags(ctx, &emptypb.Empty{})
+		var base errx.Base
+		es.Require().True(errors.As(err, &base), err)
+		es.Require().Equal(errx.TypePerms, int(base.Kind()))
+	})
+
+	es.Run("request id", func() {
+		// идентификатор из контекста клиента передается серверу и возвращается в заголовке и статусе.
+		var header metadata.MD
+		reqCtx := requestid.NewContext(authFn(ctx), "req-42")
+		_, err := client.Create(reqCtx, &events.CreateEvent{
+			Title:    "Пересечение",
+			Date:     timestamppb.New(date.Add(30 * time.Minute)),
+			Duration: durationpb.New(time.Hour),
+		}, grpc.Header(&header))
+		es.Require().Error(err)
+		es.Require().Equal([]string{"req-42"}, header.Get(requestid.MetaKey))
+
+		_, err = es.evClient.Restore(
+			metadata.AppendToOutgoingContext(auth(ctx, es), requestid.MetaKey, "req-43"),
+			&events.EventIDReq{ID: uuid.New().String()},
+		)
+		info, ok := status.Convert(err).Details()[0].(*errdetails.ErrorInfo)
+		es.Require().True(ok)
+		es.Require().Equal("req-43", info.Metadata[rqres.MetaRequestID])
+
+		// без идентификатора клиента его создает сервер.
+		_, err = es.evClient.GetTags(auth(ctx, es), &emptypb.Empty{}, grpc.Header(&header))
+		es.Require().NoError(err)
+		es.Require().True(requestid.Valid(header.Get(requestid.MetaKey)[0]))
+	})
+}
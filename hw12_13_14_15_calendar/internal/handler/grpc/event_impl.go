@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
@@ -9,10 +10,14 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
-	"google.golang.org/grpc/status"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// IdempotencyKeyMeta ключ метаданных с ключом идемпотентности запроса на создание события.
+const IdempotencyKeyMeta = "idempotency-key"
+
 // EventHandlerImpl расширение генерированного GRPC сервера - для публичных запросов.
 type EventHandlerImpl struct {
 	events.UnimplementedEventsServer
@@ -21,78 +26,404 @@ type EventHandlerImpl struct {
 }
 
 func (e EventHandlerImpl) Create(ctx context.Context, createEvent *events.CreateEvent) (*events.Event, error) {
-	event, err := e.services.EventCRUD.Add(ctx, dto.EventCreateModel(createEvent))
+	event, err := e.services.EventIdempotent.Add(ctx, idempotencyKey(ctx), dto.EventCreateModel(createEvent))
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("ошибка добавления события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка добавления события: %w", err))
 	}
-	e.logger.Info("событие добавлено: eventID=%s", event.ID.String())
+	e.log(ctx).Info("событие добавлено: eventID=%s", event.ID.String())
 	return dto.FromEventModel(*event), nil
 }
 
 func (e EventHandlerImpl) Update(ctx context.Context, updateEvent *events.UpdateEvent) (*emptypb.Empty, error) {
 	eventID, input, err := dto.EventUpdateModel(updateEvent)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("неверные данные обновления: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("неверные данные обновления: %w", err))
 	}
 	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
 	if err != nil {
-		return nil, e.handleError(err)
+		return nil, e.handleError(ctx, err)
 	}
 	err = e.services.EventCRUD.Update(ctx, *event, input)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("ошибка изменения события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка изменения события: %w", err))
 	}
-	e.logger.Info("событие изменено: eventID=%s", event.ID.String())
+	e.log(ctx).Info("событие изменено: eventID=%s", event.ID.String())
 	return &emptypb.Empty{}, nil
 }
 
 func (e EventHandlerImpl) Delete(ctx context.Context, idReq *events.EventIDReq) (*emptypb.Empty, error) {
 	eventID, err := dto.EventIDReqModel(idReq)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("неверный идентификатор события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор события: %w", err))
 	}
 	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
 	if err != nil {
-		e.logger.Error(err.Error())
+		e.log(ctx).Error(err.Error())
 		s := rqres.FromError(err)
-		return nil, status.Error(s.Code(), s.Message())
+		return nil, s.Err()
 	}
 	err = e.services.EventCRUD.Delete(ctx, *event)
 	if err != nil {
 		err := fmt.Errorf("ошибка удаления события: %w", err)
-		e.logger.Error(err.Error())
+		e.log(ctx).Error(err.Error())
 		s := rqres.FromError(err)
-		return nil, status.Error(s.Code(), s.Message())
+		return nil, s.Err()
 	}
-	e.logger.Info("событие удалено: eventID=%s", event.ID.String())
+	e.log(ctx).Info("событие перемещено в корзину: eventID=%s", event.ID.String())
 	return &emptypb.Empty{}, nil
 }
 
 func (e EventHandlerImpl) GetByID(ctx context.Context, idReq *events.EventIDReq) (*events.Event, error) {
 	eventID, err := dto.EventIDReqModel(idReq)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("неверный идентификатор события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор события: %w", err))
 	}
 	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
 	if err != nil {
-		e.logger.Error(err.Error())
+		e.log(ctx).Error(err.Error())
 		s := rqres.FromError(err)
-		return nil, status.Error(s.Code(), s.Message())
+		return nil, s.Err()
 	}
 	return dto.FromEventModel(*event), nil
 }
 
 func (e EventHandlerImpl) GetListOnDate(ctx context.Context, lodReq *events.ListOnDateReq) (*events.Events, error) {
-	date, rangeType := dto.ListOnDateReqModel(lodReq)
-	evList, err := e.services.EventCRUD.GetUserEventsOn(ctx, date, rangeType)
+	date, rangeType, tagIDs := dto.ListOnDateReqModel(lodReq)
+	evList, err := e.services.EventCRUD.GetUserEventsOn(ctx, date, rangeType, tagIDs)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения событий: %w", err))
+	}
+	return dto.FromEventSlice(evList), nil
+}
+
+func (e EventHandlerImpl) GetTrash(ctx context.Context, _ *emptypb.Empty) (*events.Events, error) {
+	evList, err := e.services.EventCRUD.GetTrash(ctx)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("ошибка получения событий: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения событий в корзине: %w", err))
 	}
 	return dto.FromEventSlice(evList), nil
 }
 
-func (e EventHandlerImpl) handleError(err error) error {
-	e.logger.Error(err.Error())
+func (e EventHandlerImpl) Restore(ctx context.Context, idReq *events.EventIDReq) (*emptypb.Empty, error) {
+	eventID, err := dto.EventIDReqModel(idReq)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор события: %w", err))
+	}
+	err = e.services.EventCRUD.Restore(ctx, eventID)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка восстановления события: %w", err))
+	}
+	e.log(ctx).Info("событие восстановлено: eventID=%s", eventID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) GetAttachments(ctx context.Context, idReq *events.EventIDReq) (*events.Attachments, error) {
+	eventID, err := dto.EventIDReqModel(idReq)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор события: %w", err))
+	}
+	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения события: %w", err))
+	}
+	attachments, err := e.services.Attachment.GetList(ctx, *event)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения вложений: %w", err))
+	}
+	return dto.FromAttachmentSlice(attachments), nil
+}
+
+func (e EventHandlerImpl) Search(ctx context.Context, searchReq *events.SearchReq) (*events.SearchResults, error) {
+	found, err := e.services.EventCRUD.Search(ctx, searchReq.GetQuery())
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка поиска событий: %w", err))
+	}
+	return dto.FromEventFoundSlice(found), nil
+}
+
+func (e EventHandlerImpl) CreateBatch(ctx context.Context, req *events.CreateBatchReq) (*events.BatchReport, error) {
+	inputs, mode := dto.CreateBatchModel(req)
+	report, err := e.services.EventCRUD.AddBatch(ctx, inputs, mode)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка пакетного добавления событий: %w", err))
+	}
+	e.log(ctx).Info("пакетное добавление событий: выполнено %d из %d", report.Applied, len(report.Results))
+	return dto.FromBatchReport(*report), nil
+}
+
+func (e EventHandlerImpl) UpdateBatch(ctx context.Context, req *events.UpdateBatchReq) (*events.BatchReport, error) {
+	items, mode, err := dto.UpdateBatchModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверные данные обновления: %w", err))
+	}
+	report, err := e.services.EventCRUD.UpdateBatch(ctx, items, mode)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка пакетного изменения событий: %w", err))
+	}
+	e.log(ctx).Info("пакетное изменение событий: выполнено %d из %d", report.Applied, len(report.Results))
+	return dto.FromBatchReport(*report), nil
+}
+
+func (e EventHandlerImpl) DeleteBatch(ctx context.Context, req *events.DeleteBatchReq) (*events.BatchReport, error) {
+	eventIDs, mode, err := dto.DeleteBatchModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор события: %w", err))
+	}
+	report, err := e.services.EventCRUD.DeleteBatch(ctx, eventIDs, mode)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка пакетного удаления событий: %w", err))
+	}
+	e.log(ctx).Info("пакетное удаление событий: выполнено %d из %d", report.Applied, len(report.Results))
+	return dto.FromBatchReport(*report), nil
+}
+
+func (e EventHandlerImpl) CreateTag(ctx context.Context, req *events.CreateTagReq) (*events.Tag, error) {
+	tag, err := e.services.TagCRUD.Add(ctx, dto.TagCreateModel(req))
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка добавления метки: %w", err))
+	}
+	e.log(ctx).Info("метка добавлена: tagID=%s", tag.ID.String())
+	return dto.FromTagModel(*tag), nil
+}
+
+func (e EventHandlerImpl) UpdateTag(ctx context.Context, req *events.UpdateTagReq) (*emptypb.Empty, error) {
+	tagID, input, err := dto.TagUpdateModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверные данные обновления: %w", err))
+	}
+	tag, err := e.services.TagCRUD.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	if err = e.services.TagCRUD.Update(ctx, *tag, input); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка изменения метки: %w", err))
+	}
+	e.log(ctx).Info("метка изменена: tagID=%s", tag.ID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) DeleteTag(ctx context.Context, idReq *events.TagIDReq) (*emptypb.Empty, error) {
+	tagID, err := dto.TagIDReqModel(idReq)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор метки: %w", err))
+	}
+	tag, err := e.services.TagCRUD.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	if err = e.services.TagCRUD.Delete(ctx, *tag); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка удаления метки: %w", err))
+	}
+	e.log(ctx).Info("метка удалена: tagID=%s", tag.ID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) GetTags(ctx context.Context, _ *emptypb.Empty) (*events.Tags, error) {
+	tags, err := e.services.TagCRUD.GetUserTags(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения меток: %w", err))
+	}
+	return dto.FromTagSlice(tags), nil
+}
+
+func (e EventHandlerImpl) CreateResource(ctx context.Context, req *events.CreateResourceReq) (*events.Resource, error) {
+	resource, err := e.services.Resource.Add(ctx, dto.ResourceCreateModel(req))
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка добавления ресурса: %w", err))
+	}
+	e.log(ctx).Info("ресурс добавлен: resourceID=%s", resource.ID.String())
+	return dto.FromResourceModel(*resource), nil
+}
+
+func (e EventHandlerImpl) UpdateResource(ctx context.Context, req *events.UpdateResourceReq) (*emptypb.Empty, error) {
+	resourceID, input, err := dto.ResourceUpdateModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверные данные обновления: %w", err))
+	}
+	resource, err := e.services.Resource.GetByID(ctx, resourceID)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	if err = e.services.Resource.Update(ctx, *resource, input); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка изменения ресурса: %w", err))
+	}
+	e.log(ctx).Info("ресурс изменен: resourceID=%s", resource.ID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) DeleteResource(ctx context.Context, idReq *events.ResourceIDReq) (*emptypb.Empty, error) {
+	resourceID, err := dto.ResourceIDReqModel(idReq)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор ресурса: %w", err))
+	}
+	resource, err := e.services.Resource.GetByID(ctx, resourceID)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	if err = e.services.Resource.Delete(ctx, *resource); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка удаления ресурса: %w", err))
+	}
+	e.log(ctx).Info("ресурс удален: resourceID=%s", resource.ID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) GetResources(ctx context.Context, req *events.ResourcesReq) (*events.Resources, error) {
+	search, err := dto.ResourcesReqModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	resources, err := e.services.Resource.GetList(ctx, search)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения ресурсов: %w", err))
+	}
+	return dto.FromResourceSlice(resources), nil
+}
+
+func (e EventHandlerImpl) GetResourceBookings(
+	ctx context.Context,
+	req *events.ResourceBookingsReq,
+) (*events.ResourceBookings, error) {
+	resourceID, period, err := dto.ResourceBookingsReqModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор ресурса: %w", err))
+	}
+	resource, err := e.services.Resource.GetByID(ctx, resourceID)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	bookings, err := e.services.Resource.Bookings(ctx, *resource, period)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения брони ресурса: %w", err))
+	}
+	return dto.FromResourceBookingSlice(bookings), nil
+}
+
+func (e EventHandlerImpl) GetAvailableResources(
+	ctx context.Context,
+	req *events.AvailableResourcesReq,
+) (*events.Resources, error) {
+	search, period, err := dto.AvailableResourcesReqModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, err)
+	}
+	resources, err := e.services.Resource.Available(ctx, search, period)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка поиска свободных ресурсов: %w", err))
+	}
+	return dto.FromResourceSlice(resources), nil
+}
+
+func (e EventHandlerImpl) GetAvailability(ctx context.Context, _ *emptypb.Empty) (*events.Availability, error) {
+	settings, err := e.services.Availability.Get(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения рабочего времени: %w", err))
+	}
+	return dto.FromAvailabilityModel(*settings), nil
+}
+
+func (e EventHandlerImpl) UpdateAvailability(ctx context.Context, req *events.Availability) (*emptypb.Empty, error) {
+	if err := e.services.Availability.Update(ctx, dto.AvailabilityModel(req)); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка изменения рабочего времени: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) GetOutOfOffice(ctx context.Context, _ *emptypb.Empty) (*events.OutOfOfficeList, error) {
+	absences, err := e.services.Availability.GetOutOfOffice(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения периодов отсутствия: %w", err))
+	}
+	return dto.FromOutOfOfficeSlice(absences), nil
+}
+
+func (e EventHandlerImpl) AddOutOfOffice(
+	ctx context.Context,
+	req *events.AddOutOfOfficeReq,
+) (*events.OutOfOffice, error) {
+	ooo, err := e.services.Availability.AddOutOfOffice(ctx, dto.OutOfOfficeCreateModel(req))
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка добавления периода отсутствия: %w", err))
+	}
+	e.log(ctx).Info("период отсутствия добавлен: oooID=%s", ooo.ID.String())
+	return dto.FromOutOfOfficeModel(*ooo), nil
+}
+
+func (e EventHandlerImpl) DeleteOutOfOffice(ctx context.Context, idReq *events.OutOfOfficeIDReq) (*emptypb.Empty, error) {
+	oooID, err := dto.OutOfOfficeIDReqModel(idReq)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор периода отсутствия: %w", err))
+	}
+	if err = e.services.Availability.DeleteOutOfOffice(ctx, oooID); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка удаления периода отсутствия: %w", err))
+	}
+	e.log(ctx).Info("период отсутствия удален: oooID=%s", oooID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) GetFreeSlots(ctx context.Context, req *events.FreeSlotsReq) (*events.Slots, error) {
+	slots, err := e.services.Availability.FreeSlots(ctx, req.GetDate().AsTime())
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения свободного времени: %w", err))
+	}
+	return dto.FromSlotSlice(slots), nil
+}
+
+func (e EventHandlerImpl) GetDigest(ctx context.Context, _ *emptypb.Empty) (*events.DigestSettings, error) {
+	settings, err := e.services.Digest.Get(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения настроек сводки: %w", err))
+	}
+	return dto.FromDigestSettingsModel(*settings), nil
+}
+
+func (e EventHandlerImpl) UpdateDigest(ctx context.Context, req *events.DigestSettings) (*emptypb.Empty, error) {
+	if err := e.services.Digest.Update(ctx, dto.DigestSettingsModel(req)); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка изменения настроек сводки: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) GetProfile(ctx context.Context, _ *emptypb.Empty) (*events.User, error) {
+	user, err := e.services.User.GetCurrent(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения профиля: %w", err))
+	}
+	return dto.FromUserModel(*user), nil
+}
+
+func (e EventHandlerImpl) UpdateProfile(ctx context.Context, req *events.UpdateProfileReq) (*emptypb.Empty, error) {
+	user, err := e.services.User.GetCurrent(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка получения профиля: %w", err))
+	}
+	if err = e.services.User.Update(ctx, *user, dto.ProfileModel(req)); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			err = errx.InvalidNew("неверные данные", errs)
+		}
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка изменения профиля: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (e EventHandlerImpl) handleError(ctx context.Context, err error) error {
+	e.log(ctx).Error(err.Error())
 	s := rqres.FromError(err)
-	return status.Error(s.Code(), s.Message())
+	return s.Err()
+}
+
+// log логгер с полями запроса: идентификатором запроса и пользователем.
+func (e EventHandlerImpl) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, e.logger)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	meta, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := meta.Get(IdempotencyKeyMeta); len(values) > 0 {
+		return values[0]
+	}
+	return ""
 }
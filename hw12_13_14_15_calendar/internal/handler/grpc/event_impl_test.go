@@ -10,10 +10,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
-	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
-	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	grpcServ "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -40,18 +39,9 @@ func (es *EventsSuiteTest) SetupTest() {
 		Host: "127.0.0.1",
 		Port: 50051,
 	}
-	// логгер будет писать в stdout и stderr, а мы будем это перехватывать.
-	logs, err := logger.NewLogrus(logger.Config{
-		Level: logger.LevelInfo,
-	})
-	es.Suite.Require().NoError(err)
-
 	// слой репозиториев мы не будем мокать, а будем использовать реализацию memory.
-	repos, err := deps.NewRepos(config.Storage{Type: "memory"}, nil)
-	es.Suite.Require().NoError(err)
-
-	dependencies := &deps.Deps{Repos: repos, Logger: logs}
-	services := deps.NewServices(dependencies)
+	// для того, чтобы пользователь авторизовался
+	dependencies, services := calendartest.New(es.T(), calendartest.WithUser(ValidUserEmail, ValidUserEmail))
 	es.grpcServer, _ = NewHandledServer(cfg, services, dependencies)
 
 	go func() {
@@ -59,6 +49,7 @@ func (es *EventsSuiteTest) SetupTest() {
 		es.Suite.Require().NoError(err)
 	}()
 	// сервер запускается не сразу
+	var err error
 	for i := 0; i < 10; i++ {
 		<-time.After(time.Millisecond * 10)
 		es.conn, err = grpc.Dial(
@@ -70,12 +61,6 @@ func (es *EventsSuiteTest) SetupTest() {
 	}
 	es.Suite.Require().NoError(err)
 	es.evClient = events.NewEventsClient(es.conn)
-	// для того, чтобы пользователь авторизовался
-	_, err = services.User.Add(context.Background(), model.UserCreate{
-		Name:  ValidUserEmail,
-		Email: ValidUserEmail,
-	})
-	es.Suite.Require().NoError(err)
 }
 
 func (es *EventsSuiteTest) TearDownTest() {
@@ -419,6 +404,42 @@ func (es *EventsSuiteTest) TestList() {
 	}
 }
 
+func (es *EventsSuiteTest) TestBatch() {
+	date, _ := time.Parse(time.RFC3339, "2023-03-02T10:00:00Z")
+	items := []*events.CreateEvent{
+		{Title: "Sprint 1", Date: timestamppb.New(date), Duration: durationpb.New(time.Hour)},
+		{Title: "Sprint 2", Date: timestamppb.New(date.Add(time.Minute * 30)), Duration: durationpb.New(time.Hour)},
+		{Title: "Sprint 3", Date: timestamppb.New(date.Add(time.Hour * 24)), Duration: durationpb.New(time.Hour)},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	report, err := es.evClient.CreateBatch(auth(ctx, es), &events.CreateBatchReq{Items: items})
+	es.Suite.Require().NoError(err)
+	es.Suite.Require().Equal(int32(0), report.Applied)
+	es.Suite.Require().Equal(int32(model.ErrEventBatchRejectCode), report.Results[0].Error.Code)
+	es.Suite.Require().Equal(int32(model.ErrEventBatchOverlapCode), report.Results[1].Error.Code)
+	es.Suite.Require().Equal(uint32(codes.InvalidArgument), report.Results[1].Error.Status)
+
+	report, err = es.evClient.CreateBatch(auth(ctx, es), &events.CreateBatchReq{
+		Mode:  events.BatchMode_BATCH_MODE_BEST_EFFORT,
+		Items: items,
+	})
+	es.Suite.Require().NoError(err)
+	es.Suite.Require().Equal(int32(2), report.Applied)
+	es.Suite.Require().Nil(report.Results[2].Error)
+	es.Suite.Require().Equal("Sprint 3", report.Results[2].Event.Title)
+
+	report, err = es.evClient.DeleteBatch(auth(ctx, es), &events.DeleteBatchReq{
+		IDs: []string{report.Results[0].EventID, report.Results[2].EventID},
+	})
+	es.Suite.Require().NoError(err)
+	es.Suite.Require().Equal(int32(2), report.Applied)
+
+	_, err = es.evClient.DeleteBatch(auth(ctx, es), &events.DeleteBatchReq{IDs: []string{"wrong"}})
+	es.Suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+}
+
 func TestEventsApi(t *testing.T) {
 	suite.Run(t, new(EventsSuiteTest))
 }
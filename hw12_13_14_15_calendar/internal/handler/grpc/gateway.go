@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// GatewayPrefix префикс путей REST-фасада, пути в аннотациях proto указаны без него.
+const GatewayPrefix = "/v2"
+
+// NewGatewayHandler REST-фасад gRPC-сервиса событий, сгенерированный grpc-gateway по аннотациям proto.
+// Методы вызываются напрямую, без сетевого соединения, поэтому интерсепторы gRPC-сервера не
+// выполняются: авторизацию проверяет REST-сервер, ошибки возвращаются в формате rqres.
+// Внутренний API Support в фасад не входит: он доступен только по gRPC сервисным учетным записям.
+func NewGatewayHandler(services *deps.Services, logger logger.Logger) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(gatewayError),
+		runtime.WithIncomingHeaderMatcher(gatewayHeader),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+	)
+	ctx := context.Background()
+	err := events.RegisterEventsHandlerServer(ctx, mux, EventHandlerImpl{services: services, logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	return http.StripPrefix(GatewayPrefix, mux), nil
+}
+
+// gatewayError ответ с ошибкой в том же виде, что и у обработчиков REST-сервера.
+func gatewayError(
+	_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error,
+) {
+	response := rs.FromError(rqres.ToError(status.Convert(err)))
+	response = rs.WithRequestID(response, requestid.FromContext(r.Context()))
+	w.Header().Set("Content-type", "application/json; charset=utf-8")
+	w.WriteHeader(response.GetHTTPCode())
+	_ = json.NewEncoder(w).Encode(response.GetHTTPResp())
+}
+
+// gatewayHeader передает в метаданные, кроме стандартных заголовков, ключ идемпотентности.
+func gatewayHeader(key string) (string, bool) {
+	if http.CanonicalHeaderKey(key) == "Idempotency-Key" {
+		return IdempotencyKeyMeta, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -29,6 +29,38 @@ type EventsClient interface {
 	Delete(ctx context.Context, in *EventIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetByID(ctx context.Context, in *EventIDReq, opts ...grpc.CallOption) (*Event, error)
 	GetListOnDate(ctx context.Context, in *ListOnDateReq, opts ...grpc.CallOption) (*Events, error)
+	GetTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Events, error)
+	Restore(ctx context.Context, in *EventIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetAttachments метаданные вложений события, содержимое скачивается через REST API.
+	GetAttachments(ctx context.Context, in *EventIDReq, opts ...grpc.CallOption) (*Attachments, error)
+	Search(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchResults, error)
+	CreateBatch(ctx context.Context, in *CreateBatchReq, opts ...grpc.CallOption) (*BatchReport, error)
+	UpdateBatch(ctx context.Context, in *UpdateBatchReq, opts ...grpc.CallOption) (*BatchReport, error)
+	DeleteBatch(ctx context.Context, in *DeleteBatchReq, opts ...grpc.CallOption) (*BatchReport, error)
+	CreateTag(ctx context.Context, in *CreateTagReq, opts ...grpc.CallOption) (*Tag, error)
+	UpdateTag(ctx context.Context, in *UpdateTagReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteTag(ctx context.Context, in *TagIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Tags, error)
+	// CreateResource текущий пользователь становится ответственным за ресурс.
+	CreateResource(ctx context.Context, in *CreateResourceReq, opts ...grpc.CallOption) (*Resource, error)
+	// UpdateResource, DeleteResource доступны только ответственному за ресурс.
+	UpdateResource(ctx context.Context, in *UpdateResourceReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteResource(ctx context.Context, in *ResourceIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetResources(ctx context.Context, in *ResourcesReq, opts ...grpc.CallOption) (*Resources, error)
+	// GetResourceBookings бронь ресурса событиями в периоде, упорядоченная по дате начала.
+	GetResourceBookings(ctx context.Context, in *ResourceBookingsReq, opts ...grpc.CallOption) (*ResourceBookings, error)
+	// GetAvailableResources ресурсы, свободные на всем протяжении периода.
+	GetAvailableResources(ctx context.Context, in *AvailableResourcesReq, opts ...grpc.CallOption) (*Resources, error)
+	GetAvailability(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Availability, error)
+	UpdateAvailability(ctx context.Context, in *Availability, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOutOfOffice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*OutOfOfficeList, error)
+	AddOutOfOffice(ctx context.Context, in *AddOutOfOfficeReq, opts ...grpc.CallOption) (*OutOfOffice, error)
+	DeleteOutOfOffice(ctx context.Context, in *OutOfOfficeIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetFreeSlots(ctx context.Context, in *FreeSlotsReq, opts ...grpc.CallOption) (*Slots, error)
+	GetDigest(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DigestSettings, error)
+	UpdateDigest(ctx context.Context, in *DigestSettings, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetProfile(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*User, error)
+	UpdateProfile(ctx context.Context, in *UpdateProfileReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type eventsClient struct {
@@ -84,6 +116,249 @@ func (c *eventsClient) GetListOnDate(ctx context.Context, in *ListOnDateReq, opt
 	return out, nil
 }
 
+func (c *eventsClient) GetTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Events, error) {
+	out := new(Events)
+	err := c.cc.Invoke(ctx, "/api.events/GetTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) Restore(ctx context.Context, in *EventIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetAttachments(ctx context.Context, in *EventIDReq, opts ...grpc.CallOption) (*Attachments, error) {
+	out := new(Attachments)
+	err := c.cc.Invoke(ctx, "/api.events/GetAttachments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) Search(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchResults, error) {
+	out := new(SearchResults)
+	err := c.cc.Invoke(ctx, "/api.events/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) CreateBatch(ctx context.Context, in *CreateBatchReq, opts ...grpc.CallOption) (*BatchReport, error) {
+	out := new(BatchReport)
+	err := c.cc.Invoke(ctx, "/api.events/CreateBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) UpdateBatch(ctx context.Context, in *UpdateBatchReq, opts ...grpc.CallOption) (*BatchReport, error) {
+	out := new(BatchReport)
+	err := c.cc.Invoke(ctx, "/api.events/UpdateBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) DeleteBatch(ctx context.Context, in *DeleteBatchReq, opts ...grpc.CallOption) (*BatchReport, error) {
+	out := new(BatchReport)
+	err := c.cc.Invoke(ctx, "/api.events/DeleteBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) CreateTag(ctx context.Context, in *CreateTagReq, opts ...grpc.CallOption) (*Tag, error) {
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, "/api.events/CreateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) UpdateTag(ctx context.Context, in *UpdateTagReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/UpdateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) DeleteTag(ctx context.Context, in *TagIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/DeleteTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Tags, error) {
+	out := new(Tags)
+	err := c.cc.Invoke(ctx, "/api.events/GetTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) CreateResource(ctx context.Context, in *CreateResourceReq, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, "/api.events/CreateResource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) UpdateResource(ctx context.Context, in *UpdateResourceReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/UpdateResource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) DeleteResource(ctx context.Context, in *ResourceIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/DeleteResource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetResources(ctx context.Context, in *ResourcesReq, opts ...grpc.CallOption) (*Resources, error) {
+	out := new(Resources)
+	err := c.cc.Invoke(ctx, "/api.events/GetResources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetResourceBookings(ctx context.Context, in *ResourceBookingsReq, opts ...grpc.CallOption) (*ResourceBookings, error) {
+	out := new(ResourceBookings)
+	err := c.cc.Invoke(ctx, "/api.events/GetResourceBookings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetAvailableResources(ctx context.Context, in *AvailableResourcesReq, opts ...grpc.CallOption) (*Resources, error) {
+	out := new(Resources)
+	err := c.cc.Invoke(ctx, "/api.events/GetAvailableResources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetAvailability(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Availability, error) {
+	out := new(Availability)
+	err := c.cc.Invoke(ctx, "/api.events/GetAvailability", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) UpdateAvailability(ctx context.Context, in *Availability, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/UpdateAvailability", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetOutOfOffice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*OutOfOfficeList, error) {
+	out := new(OutOfOfficeList)
+	err := c.cc.Invoke(ctx, "/api.events/GetOutOfOffice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) AddOutOfOffice(ctx context.Context, in *AddOutOfOfficeReq, opts ...grpc.CallOption) (*OutOfOffice, error) {
+	out := new(OutOfOffice)
+	err := c.cc.Invoke(ctx, "/api.events/AddOutOfOffice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) DeleteOutOfOffice(ctx context.Context, in *OutOfOfficeIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/DeleteOutOfOffice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetFreeSlots(ctx context.Context, in *FreeSlotsReq, opts ...grpc.CallOption) (*Slots, error) {
+	out := new(Slots)
+	err := c.cc.Invoke(ctx, "/api.events/GetFreeSlots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetDigest(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DigestSettings, error) {
+	out := new(DigestSettings)
+	err := c.cc.Invoke(ctx, "/api.events/GetDigest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) UpdateDigest(ctx context.Context, in *DigestSettings, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/UpdateDigest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) GetProfile(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/api.events/GetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventsClient) UpdateProfile(ctx context.Context, in *UpdateProfileReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.events/UpdateProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EventsServer is the server API for Events service.
 // All implementations must embed UnimplementedEventsServer
 // for forward compatibility
@@ -93,6 +368,38 @@ type EventsServer interface {
 	Delete(context.Context, *EventIDReq) (*emptypb.Empty, error)
 	GetByID(context.Context, *EventIDReq) (*Event, error)
 	GetListOnDate(context.Context, *ListOnDateReq) (*Events, error)
+	GetTrash(context.Context, *emptypb.Empty) (*Events, error)
+	Restore(context.Context, *EventIDReq) (*emptypb.Empty, error)
+	// GetAttachments метаданные вложений события, содержимое скачивается через REST API.
+	GetAttachments(context.Context, *EventIDReq) (*Attachments, error)
+	Search(context.Context, *SearchReq) (*SearchResults, error)
+	CreateBatch(context.Context, *CreateBatchReq) (*BatchReport, error)
+	UpdateBatch(context.Context, *UpdateBatchReq) (*BatchReport, error)
+	DeleteBatch(context.Context, *DeleteBatchReq) (*BatchReport, error)
+	CreateTag(context.Context, *CreateTagReq) (*Tag, error)
+	UpdateTag(context.Context, *UpdateTagReq) (*emptypb.Empty, error)
+	DeleteTag(context.Context, *TagIDReq) (*emptypb.Empty, error)
+	GetTags(context.Context, *emptypb.Empty) (*Tags, error)
+	// CreateResource текущий пользователь становится ответственным за ресурс.
+	CreateResource(context.Context, *CreateResourceReq) (*Resource, error)
+	// UpdateResource, DeleteResource доступны только ответственному за ресурс.
+	UpdateResource(context.Context, *UpdateResourceReq) (*emptypb.Empty, error)
+	DeleteResource(context.Context, *ResourceIDReq) (*emptypb.Empty, error)
+	GetResources(context.Context, *ResourcesReq) (*Resources, error)
+	// GetResourceBookings бронь ресурса событиями в периоде, упорядоченная по дате начала.
+	GetResourceBookings(context.Context, *ResourceBookingsReq) (*ResourceBookings, error)
+	// GetAvailableResources ресурсы, свободные на всем протяжении периода.
+	GetAvailableResources(context.Context, *AvailableResourcesReq) (*Resources, error)
+	GetAvailability(context.Context, *emptypb.Empty) (*Availability, error)
+	UpdateAvailability(context.Context, *Availability) (*emptypb.Empty, error)
+	GetOutOfOffice(context.Context, *emptypb.Empty) (*OutOfOfficeList, error)
+	AddOutOfOffice(context.Context, *AddOutOfOfficeReq) (*OutOfOffice, error)
+	DeleteOutOfOffice(context.Context, *OutOfOfficeIDReq) (*emptypb.Empty, error)
+	GetFreeSlots(context.Context, *FreeSlotsReq) (*Slots, error)
+	GetDigest(context.Context, *emptypb.Empty) (*DigestSettings, error)
+	UpdateDigest(context.Context, *DigestSettings) (*emptypb.Empty, error)
+	GetProfile(context.Context, *emptypb.Empty) (*User, error)
+	UpdateProfile(context.Context, *UpdateProfileReq) (*emptypb.Empty, error)
 	mustEmbedUnimplementedEventsServer()
 }
 
@@ -115,6 +422,87 @@ func (UnimplementedEventsServer) GetByID(context.Context, *EventIDReq) (*Event,
 func (UnimplementedEventsServer) GetListOnDate(context.Context, *ListOnDateReq) (*Events, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetListOnDate not implemented")
 }
+func (UnimplementedEventsServer) GetTrash(context.Context, *emptypb.Empty) (*Events, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrash not implemented")
+}
+func (UnimplementedEventsServer) Restore(context.Context, *EventIDReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedEventsServer) GetAttachments(context.Context, *EventIDReq) (*Attachments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttachments not implemented")
+}
+func (UnimplementedEventsServer) Search(context.Context, *SearchReq) (*SearchResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedEventsServer) CreateBatch(context.Context, *CreateBatchReq) (*BatchReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBatch not implemented")
+}
+func (UnimplementedEventsServer) UpdateBatch(context.Context, *UpdateBatchReq) (*BatchReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBatch not implemented")
+}
+func (UnimplementedEventsServer) DeleteBatch(context.Context, *DeleteBatchReq) (*BatchReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBatch not implemented")
+}
+func (UnimplementedEventsServer) CreateTag(context.Context, *CreateTagReq) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedEventsServer) UpdateTag(context.Context, *UpdateTagReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTag not implemented")
+}
+func (UnimplementedEventsServer) DeleteTag(context.Context, *TagIDReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTag not implemented")
+}
+func (UnimplementedEventsServer) GetTags(context.Context, *emptypb.Empty) (*Tags, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTags not implemented")
+}
+func (UnimplementedEventsServer) CreateResource(context.Context, *CreateResourceReq) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateResource not implemented")
+}
+func (UnimplementedEventsServer) UpdateResource(context.Context, *UpdateResourceReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateResource not implemented")
+}
+func (UnimplementedEventsServer) DeleteResource(context.Context, *ResourceIDReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteResource not implemented")
+}
+func (UnimplementedEventsServer) GetResources(context.Context, *ResourcesReq) (*Resources, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResources not implemented")
+}
+func (UnimplementedEventsServer) GetResourceBookings(context.Context, *ResourceBookingsReq) (*ResourceBookings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResourceBookings not implemented")
+}
+func (UnimplementedEventsServer) GetAvailableResources(context.Context, *AvailableResourcesReq) (*Resources, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvailableResources not implemented")
+}
+func (UnimplementedEventsServer) GetAvailability(context.Context, *emptypb.Empty) (*Availability, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvailability not implemented")
+}
+func (UnimplementedEventsServer) UpdateAvailability(context.Context, *Availability) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAvailability not implemented")
+}
+func (UnimplementedEventsServer) GetOutOfOffice(context.Context, *emptypb.Empty) (*OutOfOfficeList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutOfOffice not implemented")
+}
+func (UnimplementedEventsServer) AddOutOfOffice(context.Context, *AddOutOfOfficeReq) (*OutOfOffice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOutOfOffice not implemented")
+}
+func (UnimplementedEventsServer) DeleteOutOfOffice(context.Context, *OutOfOfficeIDReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOutOfOffice not implemented")
+}
+func (UnimplementedEventsServer) GetFreeSlots(context.Context, *FreeSlotsReq) (*Slots, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreeSlots not implemented")
+}
+func (UnimplementedEventsServer) GetDigest(context.Context, *emptypb.Empty) (*DigestSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDigest not implemented")
+}
+func (UnimplementedEventsServer) UpdateDigest(context.Context, *DigestSettings) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDigest not implemented")
+}
+func (UnimplementedEventsServer) GetProfile(context.Context, *emptypb.Empty) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedEventsServer) UpdateProfile(context.Context, *UpdateProfileReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
 func (UnimplementedEventsServer) mustEmbedUnimplementedEventsServer() {}
 
 // UnsafeEventsServer may be embedded to opt out of forward compatibility for this service.
@@ -218,32 +606,626 @@ func _Events_GetListOnDate_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
-// Events_ServiceDesc is the grpc.ServiceDesc for Events service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Events_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "api.events",
-	HandlerType: (*EventsServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Create",
-			Handler:    _Events_Create_Handler,
-		},
-		{
-			MethodName: "Update",
-			Handler:    _Events_Update_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _Events_Delete_Handler,
-		},
-		{
-			MethodName: "GetByID",
-			Handler:    _Events_GetByID_Handler,
-		},
-		{
-			MethodName: "GetListOnDate",
-			Handler:    _Events_GetListOnDate_Handler,
+func _Events_GetTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetTrash(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).Restore(ctx, req.(*EventIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetAttachments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetAttachments(ctx, req.(*EventIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).Search(ctx, req.(*SearchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_CreateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBatchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).CreateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/CreateBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).CreateBatch(ctx, req.(*CreateBatchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_UpdateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBatchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).UpdateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/UpdateBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).UpdateBatch(ctx, req.(*UpdateBatchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_DeleteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBatchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).DeleteBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/DeleteBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).DeleteBatch(ctx, req.(*DeleteBatchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/CreateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).CreateTag(ctx, req.(*CreateTagReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_UpdateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTagReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).UpdateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/UpdateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).UpdateTag(ctx, req.(*UpdateTagReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_DeleteTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).DeleteTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/DeleteTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).DeleteTag(ctx, req.(*TagIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetTags(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_CreateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateResourceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).CreateResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/CreateResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).CreateResource(ctx, req.(*CreateResourceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_UpdateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateResourceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).UpdateResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/UpdateResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).UpdateResource(ctx, req.(*UpdateResourceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_DeleteResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).DeleteResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/DeleteResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).DeleteResource(ctx, req.(*ResourceIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourcesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetResources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetResources(ctx, req.(*ResourcesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetResourceBookings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceBookingsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetResourceBookings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetResourceBookings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetResourceBookings(ctx, req.(*ResourceBookingsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetAvailableResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AvailableResourcesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetAvailableResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetAvailableResources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetAvailableResources(ctx, req.(*AvailableResourcesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetAvailability",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetAvailability(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_UpdateAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Availability)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).UpdateAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/UpdateAvailability",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).UpdateAvailability(ctx, req.(*Availability))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetOutOfOffice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetOutOfOffice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetOutOfOffice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetOutOfOffice(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_AddOutOfOffice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOutOfOfficeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).AddOutOfOffice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/AddOutOfOffice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).AddOutOfOffice(ctx, req.(*AddOutOfOfficeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_DeleteOutOfOffice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutOfOfficeIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).DeleteOutOfOffice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/DeleteOutOfOffice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).DeleteOutOfOffice(ctx, req.(*OutOfOfficeIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetFreeSlots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeSlotsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetFreeSlots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetFreeSlots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetFreeSlots(ctx, req.(*FreeSlotsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetDigest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetDigest(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_UpdateDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigestSettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).UpdateDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/UpdateDigest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).UpdateDigest(ctx, req.(*DigestSettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/GetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).GetProfile(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Events_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.events/UpdateProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServer).UpdateProfile(ctx, req.(*UpdateProfileReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Events_ServiceDesc is the grpc.ServiceDesc for Events service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Events_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.events",
+	HandlerType: (*EventsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Events_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Events_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Events_Delete_Handler,
+		},
+		{
+			MethodName: "GetByID",
+			Handler:    _Events_GetByID_Handler,
+		},
+		{
+			MethodName: "GetListOnDate",
+			Handler:    _Events_GetListOnDate_Handler,
+		},
+		{
+			MethodName: "GetTrash",
+			Handler:    _Events_GetTrash_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Events_Restore_Handler,
+		},
+		{
+			MethodName: "GetAttachments",
+			Handler:    _Events_GetAttachments_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Events_Search_Handler,
+		},
+		{
+			MethodName: "CreateBatch",
+			Handler:    _Events_CreateBatch_Handler,
+		},
+		{
+			MethodName: "UpdateBatch",
+			Handler:    _Events_UpdateBatch_Handler,
+		},
+		{
+			MethodName: "DeleteBatch",
+			Handler:    _Events_DeleteBatch_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _Events_CreateTag_Handler,
+		},
+		{
+			MethodName: "UpdateTag",
+			Handler:    _Events_UpdateTag_Handler,
+		},
+		{
+			MethodName: "DeleteTag",
+			Handler:    _Events_DeleteTag_Handler,
+		},
+		{
+			MethodName: "GetTags",
+			Handler:    _Events_GetTags_Handler,
+		},
+		{
+			MethodName: "CreateResource",
+			Handler:    _Events_CreateResource_Handler,
+		},
+		{
+			MethodName: "UpdateResource",
+			Handler:    _Events_UpdateResource_Handler,
+		},
+		{
+			MethodName: "DeleteResource",
+			Handler:    _Events_DeleteResource_Handler,
+		},
+		{
+			MethodName: "GetResources",
+			Handler:    _Events_GetResources_Handler,
+		},
+		{
+			MethodName: "GetResourceBookings",
+			Handler:    _Events_GetResourceBookings_Handler,
+		},
+		{
+			MethodName: "GetAvailableResources",
+			Handler:    _Events_GetAvailableResources_Handler,
+		},
+		{
+			MethodName: "GetAvailability",
+			Handler:    _Events_GetAvailability_Handler,
+		},
+		{
+			MethodName: "UpdateAvailability",
+			Handler:    _Events_UpdateAvailability_Handler,
+		},
+		{
+			MethodName: "GetOutOfOffice",
+			Handler:    _Events_GetOutOfOffice_Handler,
+		},
+		{
+			MethodName: "AddOutOfOffice",
+			Handler:    _Events_AddOutOfOffice_Handler,
+		},
+		{
+			MethodName: "DeleteOutOfOffice",
+			Handler:    _Events_DeleteOutOfOffice_Handler,
+		},
+		{
+			MethodName: "GetFreeSlots",
+			Handler:    _Events_GetFreeSlots_Handler,
+		},
+		{
+			MethodName: "GetDigest",
+			Handler:    _Events_GetDigest_Handler,
+		},
+		{
+			MethodName: "UpdateDigest",
+			Handler:    _Events_UpdateDigest_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _Events_GetProfile_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _Events_UpdateProfile_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -25,8 +25,21 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SupportClient interface {
 	GetNotifications(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Notifies, error)
+	// GetPendingNotifications оповещения к отправке без блокировки событий, только для просмотра.
+	GetPendingNotifications(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Notifies, error)
 	SetNotified(ctx context.Context, in *NotificationIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetDigests сводки событий, время отправки которых наступило и которые еще не подтверждены.
+	GetDigests(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Digests, error)
+	// SetDigestSent подтверждение отправки сводки, после него сводка повторно не возвращается.
+	SetDigestSent(ctx context.Context, in *DigestSentReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	CleanupOldEvents(ctx context.Context, in *CleanupReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PurgeTrash(ctx context.Context, in *PurgeTrashReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PurgeIdempotencyKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// AcquireLease захват или продление аренды роли ведущего экземпляра, в ответе действующая аренда.
+	// Аренда управляет запуском заданий на всех экземплярах, поэтому методы аренды доступны только
+	// по gRPC, без REST-аннотаций.
+	AcquireLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*Lease, error)
+	ReleaseLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type supportClient struct {
@@ -46,6 +59,15 @@ func (c *supportClient) GetNotifications(ctx context.Context, in *emptypb.Empty,
 	return out, nil
 }
 
+func (c *supportClient) GetPendingNotifications(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Notifies, error) {
+	out := new(Notifies)
+	err := c.cc.Invoke(ctx, "/api.support/GetPendingNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *supportClient) SetNotified(ctx context.Context, in *NotificationIDReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/api.support/SetNotified", in, out, opts...)
@@ -55,6 +77,24 @@ func (c *supportClient) SetNotified(ctx context.Context, in *NotificationIDReq,
 	return out, nil
 }
 
+func (c *supportClient) GetDigests(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Digests, error) {
+	out := new(Digests)
+	err := c.cc.Invoke(ctx, "/api.support/GetDigests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supportClient) SetDigestSent(ctx context.Context, in *DigestSentReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.support/SetDigestSent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *supportClient) CleanupOldEvents(ctx context.Context, in *CleanupReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/api.support/CleanupOldEvents", in, out, opts...)
@@ -64,13 +104,62 @@ func (c *supportClient) CleanupOldEvents(ctx context.Context, in *CleanupReq, op
 	return out, nil
 }
 
+func (c *supportClient) PurgeTrash(ctx context.Context, in *PurgeTrashReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.support/PurgeTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supportClient) PurgeIdempotencyKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.support/PurgeIdempotencyKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supportClient) AcquireLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*Lease, error) {
+	out := new(Lease)
+	err := c.cc.Invoke(ctx, "/api.support/AcquireLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supportClient) ReleaseLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.support/ReleaseLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SupportServer is the server API for Support service.
 // All implementations must embed UnimplementedSupportServer
 // for forward compatibility
 type SupportServer interface {
 	GetNotifications(context.Context, *emptypb.Empty) (*Notifies, error)
+	// GetPendingNotifications оповещения к отправке без блокировки событий, только для просмотра.
+	GetPendingNotifications(context.Context, *emptypb.Empty) (*Notifies, error)
 	SetNotified(context.Context, *NotificationIDReq) (*emptypb.Empty, error)
+	// GetDigests сводки событий, время отправки которых наступило и которые еще не подтверждены.
+	GetDigests(context.Context, *emptypb.Empty) (*Digests, error)
+	// SetDigestSent подтверждение отправки сводки, после него сводка повторно не возвращается.
+	SetDigestSent(context.Context, *DigestSentReq) (*emptypb.Empty, error)
 	CleanupOldEvents(context.Context, *CleanupReq) (*emptypb.Empty, error)
+	PurgeTrash(context.Context, *PurgeTrashReq) (*emptypb.Empty, error)
+	PurgeIdempotencyKeys(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// AcquireLease захват или продление аренды роли ведущего экземпляра, в ответе действующая аренда.
+	// Аренда управляет запуском заданий на всех экземплярах, поэтому методы аренды доступны только
+	// по gRPC, без REST-аннотаций.
+	AcquireLease(context.Context, *LeaseReq) (*Lease, error)
+	ReleaseLease(context.Context, *LeaseReq) (*emptypb.Empty, error)
 	mustEmbedUnimplementedSupportServer()
 }
 
@@ -81,12 +170,33 @@ type UnimplementedSupportServer struct {
 func (UnimplementedSupportServer) GetNotifications(context.Context, *emptypb.Empty) (*Notifies, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNotifications not implemented")
 }
+func (UnimplementedSupportServer) GetPendingNotifications(context.Context, *emptypb.Empty) (*Notifies, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingNotifications not implemented")
+}
 func (UnimplementedSupportServer) SetNotified(context.Context, *NotificationIDReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetNotified not implemented")
 }
+func (UnimplementedSupportServer) GetDigests(context.Context, *emptypb.Empty) (*Digests, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDigests not implemented")
+}
+func (UnimplementedSupportServer) SetDigestSent(context.Context, *DigestSentReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDigestSent not implemented")
+}
 func (UnimplementedSupportServer) CleanupOldEvents(context.Context, *CleanupReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CleanupOldEvents not implemented")
 }
+func (UnimplementedSupportServer) PurgeTrash(context.Context, *PurgeTrashReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeTrash not implemented")
+}
+func (UnimplementedSupportServer) PurgeIdempotencyKeys(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeIdempotencyKeys not implemented")
+}
+func (UnimplementedSupportServer) AcquireLease(context.Context, *LeaseReq) (*Lease, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcquireLease not implemented")
+}
+func (UnimplementedSupportServer) ReleaseLease(context.Context, *LeaseReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLease not implemented")
+}
 func (UnimplementedSupportServer) mustEmbedUnimplementedSupportServer() {}
 
 // UnsafeSupportServer may be embedded to opt out of forward compatibility for this service.
@@ -118,6 +228,24 @@ func _Support_GetNotifications_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Support_GetPendingNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).GetPendingNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/GetPendingNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).GetPendingNotifications(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Support_SetNotified_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NotificationIDReq)
 	if err := dec(in); err != nil {
@@ -136,6 +264,42 @@ func _Support_SetNotified_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Support_GetDigests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).GetDigests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/GetDigests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).GetDigests(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Support_SetDigestSent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigestSentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).SetDigestSent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/SetDigestSent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).SetDigestSent(ctx, req.(*DigestSentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Support_CleanupOldEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CleanupReq)
 	if err := dec(in); err != nil {
@@ -154,6 +318,78 @@ func _Support_CleanupOldEvents_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Support_PurgeTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTrashReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).PurgeTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/PurgeTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).PurgeTrash(ctx, req.(*PurgeTrashReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Support_PurgeIdempotencyKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).PurgeIdempotencyKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/PurgeIdempotencyKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).PurgeIdempotencyKeys(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Support_AcquireLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).AcquireLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/AcquireLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).AcquireLease(ctx, req.(*LeaseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Support_ReleaseLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupportServer).ReleaseLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.support/ReleaseLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupportServer).ReleaseLease(ctx, req.(*LeaseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Support_ServiceDesc is the grpc.ServiceDesc for Support service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -165,14 +401,42 @@ var Support_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNotifications",
 			Handler:    _Support_GetNotifications_Handler,
 		},
+		{
+			MethodName: "GetPendingNotifications",
+			Handler:    _Support_GetPendingNotifications_Handler,
+		},
 		{
 			MethodName: "SetNotified",
 			Handler:    _Support_SetNotified_Handler,
 		},
+		{
+			MethodName: "GetDigests",
+			Handler:    _Support_GetDigests_Handler,
+		},
+		{
+			MethodName: "SetDigestSent",
+			Handler:    _Support_SetDigestSent_Handler,
+		},
 		{
 			MethodName: "CleanupOldEvents",
 			Handler:    _Support_CleanupOldEvents_Handler,
 		},
+		{
+			MethodName: "PurgeTrash",
+			Handler:    _Support_PurgeTrash_Handler,
+		},
+		{
+			MethodName: "PurgeIdempotencyKeys",
+			Handler:    _Support_PurgeIdempotencyKeys_Handler,
+		},
+		{
+			MethodName: "AcquireLease",
+			Handler:    _Support_AcquireLease_Handler,
+		},
+		{
+			MethodName: "ReleaseLease",
+			Handler:    _Support_ReleaseLease_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "SupportService.proto",
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestRateLimit(t *testing.T) {
+	cfg := config.Server{
+		Host: "127.0.0.1",
+		Port: 50052,
+		RateLimit: ratelimit.Config{
+			PerUser: ratelimit.Budget{
+				Read:  ratelimit.Limit{Rate: 0.5, Burst: 1},
+				Write: ratelimit.Limit{Rate: 0.5, Burst: 1},
+			},
+		},
+	}
+	dependencies, services := calendartest.New(t, calendartest.WithUser(ValidUserEmail, ValidUserEmail))
+	server, _ := NewHandledServer(cfg, services, dependencies)
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+	var (
+		conn *grpc.ClientConn
+		err  error
+	)
+	for i := 0; i < 10; i++ {
+		<-time.After(time.Millisecond * 10)
+		conn, err = grpc.Dial(
+			net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err == nil {
+			break
+		}
+	}
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	client := events.NewEventsClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", ValidUserEmail)
+
+	_, err = client.GetTrash(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	var header metadata.MD
+	_, err = client.GetTrash(ctx, &emptypb.Empty{}, grpc.Header(&header))
+	st := status.Convert(err)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Equal(t, []string{"2"}, header.Get("retry-after"))
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, retryInfo.RetryDelay.AsDuration())
+
+	// бюджет записи расходуется отдельно.
+	_, err = client.DeleteBatch(ctx, &events.DeleteBatchReq{})
+	require.NotEqual(t, codes.ResourceExhausted, status.Code(err))
+}
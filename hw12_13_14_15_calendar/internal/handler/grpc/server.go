@@ -15,11 +15,7 @@ import (
 func NewHandledServer(
 	config config.Server, services *deps.Services, deps *deps.Deps,
 ) (*grpcServ.Server, closer.CloseFunc) {
-	server := grpcServ.NewServer(servers.NewConfig(
-		config.Host,
-		config.Port,
-		false,
-	), services.Auth, deps.Logger)
+	server := grpcServ.NewServer(serversConfig(config), services.Auth, deps.Logger)
 
 	server.RegisterHandler(func(s *grpc.Server) {
 		events.RegisterEventsServer(s, EventHandlerImpl{services: services, logger: deps.Logger})
@@ -31,3 +27,14 @@ func NewHandledServer(
 		return nil
 	}
 }
+
+// serversConfig при проверке сертификатов клиентов внутренний API Support доступен только
+// сервисам с сертификатом: планировщику, рассыльщику и администраторам.
+func serversConfig(config config.Server) servers.Config {
+	cfg := config.ServersConfig()
+	if tls := cfg.GetTLS(); tls.ClientCAFile != "" {
+		tls.CertRequired = append(tls.CertRequired, "/"+events.Support_ServiceDesc.ServiceName+"/")
+		cfg = cfg.WithTLS(tls)
+	}
+	return cfg
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/pb/events"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -23,7 +22,15 @@ type SupportHandlerImpl struct {
 func (e SupportHandlerImpl) GetNotifications(ctx context.Context, _ *emptypb.Empty) (*events.Notifies, error) {
 	notifiesList, err := e.services.EventNotify.GetNotifications(ctx)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("ошибка запроса оповещений: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка запроса оповещений: %w", err))
+	}
+	return dto.FromNotificationSlice(notifiesList), nil
+}
+
+func (e SupportHandlerImpl) GetPendingNotifications(ctx context.Context, _ *emptypb.Empty) (*events.Notifies, error) {
+	notifiesList, err := e.services.EventNotify.PendingNotifications(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка запроса оповещений: %w", err))
 	}
 	return dto.FromNotificationSlice(notifiesList), nil
 }
@@ -31,13 +38,33 @@ func (e SupportHandlerImpl) GetNotifications(ctx context.Context, _ *emptypb.Emp
 func (e SupportHandlerImpl) SetNotified(ctx context.Context, idReq *events.NotificationIDReq) (*emptypb.Empty, error) {
 	eventID, err := dto.NotificationIDReqModel(idReq)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("неверный идентификатор события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("неверный идентификатор события: %w", err))
 	}
 	err = e.services.EventNotify.MarkEventNotified(ctx, eventID)
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("ошибка подтверждения оповещения события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка подтверждения оповещения события: %w", err))
+	}
+	e.log(ctx).Info("событие изменено: eventID=%s", eventID.String())
+	return &emptypb.Empty{}, nil
+}
+
+func (e SupportHandlerImpl) GetDigests(ctx context.Context, _ *emptypb.Empty) (*events.Digests, error) {
+	digests, err := e.services.Digest.DueDigests(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка запроса сводок: %w", err))
+	}
+	return dto.FromDigestSlice(digests), nil
+}
+
+func (e SupportHandlerImpl) SetDigestSent(ctx context.Context, req *events.DigestSentReq) (*emptypb.Empty, error) {
+	userID, sendAt, err := dto.DigestSentReqModel(req)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("неверное подтверждение сводки: %w", err))
 	}
-	e.logger.Info("событие изменено: eventID=%s", eventID.String())
+	if err = e.services.Digest.MarkSent(ctx, userID, sendAt); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка подтверждения сводки: %w", err))
+	}
+	e.log(ctx).Info("сводка отправлена: userID=%s", userID.String())
 	return &emptypb.Empty{}, nil
 }
 
@@ -46,14 +73,55 @@ func (e SupportHandlerImpl) CleanupOldEvents(
 ) (*emptypb.Empty, error) {
 	n, err := e.services.EventClean.CleanupOldEvents(ctx, cleanupReq.StoreTime.AsDuration())
 	if err != nil {
-		return nil, e.handleError(fmt.Errorf("ошибка удаления старых события: %w", err))
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка удаления старых события: %w", err))
+	}
+	e.log(ctx).Info("успешно перемещено в корзину старых событий: %d", n)
+	return &emptypb.Empty{}, nil
+}
+
+func (e SupportHandlerImpl) PurgeTrash(
+	ctx context.Context, purgeReq *events.PurgeTrashReq,
+) (*emptypb.Empty, error) {
+	n, err := e.services.EventClean.PurgeTrash(ctx, purgeReq.RetentionTime.AsDuration())
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка очистки корзины: %w", err))
+	}
+	e.log(ctx).Info("успешно удалено событий из корзины: %d", n)
+	return &emptypb.Empty{}, nil
+}
+
+func (e SupportHandlerImpl) PurgeIdempotencyKeys(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	n, err := e.services.EventClean.PurgeIdempotencyKeys(ctx)
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка удаления ключей идемпотентности: %w", err))
+	}
+	e.log(ctx).Info("успешно удалено истекших ключей идемпотентности: %d", n)
+	return &emptypb.Empty{}, nil
+}
+
+func (e SupportHandlerImpl) AcquireLease(ctx context.Context, req *events.LeaseReq) (*events.Lease, error) {
+	lease, err := e.services.Lease.Acquire(ctx, dto.LeaseAcquireModel(req))
+	if err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка захвата аренды: %w", err))
 	}
-	e.logger.Info("успешно удалено старых событий: %d", n)
+	return dto.FromLeaseModel(*lease), nil
+}
+
+func (e SupportHandlerImpl) ReleaseLease(ctx context.Context, req *events.LeaseReq) (*emptypb.Empty, error) {
+	if err := e.services.Lease.Release(ctx, req.GetName(), req.GetHolder()); err != nil {
+		return nil, e.handleError(ctx, fmt.Errorf("ошибка освобождения аренды: %w", err))
+	}
+	e.log(ctx).Info("аренда '%s' освобождена: holder=%s", req.GetName(), req.GetHolder())
 	return &emptypb.Empty{}, nil
 }
 
-func (e SupportHandlerImpl) handleError(err error) error {
-	e.logger.Error(err.Error())
+func (e SupportHandlerImpl) handleError(ctx context.Context, err error) error {
+	e.log(ctx).Error(err.Error())
 	s := rqres.FromError(err)
-	return status.Error(s.Code(), s.Message())
+	return s.Err()
+}
+
+// log логгер с полями запроса: идентификатором запроса и пользователем.
+func (e SupportHandlerImpl) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, e.logger)
 }
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestSupportDigests(t *testing.T) {
+	clk := clock.NewMock()
+	dependencies, services := calendartest.New(t,
+		calendartest.WithClock(clk),
+		calendartest.WithUser("Иван", ValidUserEmail),
+	)
+	support := SupportHandlerImpl{services: services, logger: dependencies.Logger}
+
+	user, err := services.User.GetByEmail(context.Background(), ValidUserEmail)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), servers.CtxKey{}, map[string]string{"id": user.ID.String()})
+
+	// среда 2023-03-29 по Москве: два события сегодня, одно завтра.
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	today := time.Date(2023, 3, 29, 0, 0, 0, 0, moscow)
+	require.NoError(t, services.Availability.Update(ctx, model.Availability{TimeZone: "Europe/Moscow"}))
+	for _, item := range []struct {
+		title string
+		date  time.Time
+	}{
+		{title: "Ретро", date: today.Add(17 * time.Hour)},
+		{title: "Планерка", date: today.Add(10 * time.Hour)},
+		{title: "Завтра", date: today.Add(34 * time.Hour)},
+	} {
+		_, err = services.EventCRUD.Add(ctx, model.EventCreate{
+			Title: item.title, Date: item.date, Duration: 30 * time.Minute, OwnerID: user.ID,
+		})
+		require.NoError(t, err)
+	}
+	daily := model.DigestSettings{Mode: model.DigestDaily, SendAt: 8 * time.Hour}
+	require.NoError(t, services.Digest.Update(ctx, daily))
+
+	getDigests := func() []model.Digest {
+		digestsPb, err := support.GetDigests(context.Background(), &emptypb.Empty{})
+		require.NoError(t, err)
+		digests, err := dto.ToDigestSlice(digestsPb)
+		require.NoError(t, err)
+		return digests
+	}
+	// 07:59 по Москве - время отправки не наступило.
+	clk.Set(today.Add(8*time.Hour - time.Minute))
+	require.Empty(t, getDigests())
+
+	clk.Add(2 * time.Minute)
+	digests := getDigests()
+	require.Len(t, digests, 1)
+	require.Equal(t, model.MessageDigest, digests[0].Kind)
+	require.Equal(t, "daily", digests[0].Mode)
+	require.Equal(t, "Europe/Moscow", digests[0].TimeZone)
+	require.Equal(t, ValidUserEmail, digests[0].NotifyUser.Email)
+	require.True(t, today.Equal(digests[0].DateStart))
+	require.Len(t, digests[0].Events, 2)
+	require.Equal(t, "Планерка", digests[0].Events[0].Title)
+	require.Equal(t, "Ретро", digests[0].Events[1].Title)
+
+	require.Equal(t, user.ID, digests[0].UserID)
+	require.True(t, today.Add(8*time.Hour).Equal(digests[0].SendAt))
+
+	// до подтверждения отправки сводка выдается повторно, после - нет.
+	clk.Add(time.Hour)
+	require.Len(t, getDigests(), 1)
+	_, err = support.SetDigestSent(context.Background(), dto.ToDigestSentReq(digests[0]))
+	require.NoError(t, err)
+	require.Empty(t, getDigests())
+	_, err = support.SetDigestSent(context.Background(), dto.ToDigestSentReq(digests[0]))
+	require.NoError(t, err)
+
+	// после отказа от сводки на следующий день она не формируется.
+	daily.Mode = model.DigestOff
+	require.NoError(t, services.Digest.Update(ctx, daily))
+	clk.Add(24 * time.Hour)
+	require.Empty(t, getDigests())
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs/certstest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const serviceAccount = "scheduler@otus.ru"
+
+func TestMutualTLS(t *testing.T) {
+	ca := certstest.NewCA(t)
+	certFile, keyFile := ca.Issue(t, "calendar", "127.0.0.1")
+	cfg := config.Server{
+		TLS: config.ServerTLS{
+			CertFile:        certFile,
+			KeyFile:         keyFile,
+			ClientCAFile:    ca.File,
+			ServiceAccounts: map[string]string{"scheduler": serviceAccount},
+		},
+	}
+	dependencies, services := calendartest.New(t,
+		calendartest.WithLogLevel(logger.LevelFatal),
+		calendartest.WithUser(ValidUserEmail, ValidUserEmail),
+		calendartest.WithUser(serviceAccount, serviceAccount))
+	server, _ := NewHandledServer(cfg, services, dependencies)
+	socket, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(socket)
+	}()
+	defer server.Stop()
+	address := socket.Addr().String()
+
+	// withCert клиент с сертификатом name, пустое имя - только проверка сервера.
+	withCert := func(name string) ClientOption {
+		files := certs.Files{CA: ca.File}
+		if name != "" {
+			files.Cert, files.Key = ca.Issue(t, name)
+		}
+		source, err := certs.NewSource(files, 0, nil)
+		require.NoError(t, err)
+		return WithTLS(source.ClientConfig(""))
+	}
+	callSupport := func(login string, opts ...ClientOption) error {
+		support, authFn, err := NewSupportClient(address, login, opts...)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = support.GetNotifications(authFn(ctx), &emptypb.Empty{})
+		return err
+	}
+	callEvents := func(login string, opts ...ClientOption) error {
+		eventsAPI, authFn, err := NewEventsClient(address, login, opts...)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = eventsAPI.GetTrash(authFn(ctx), &emptypb.Empty{})
+		return err
+	}
+
+	t.Run("service account", func(t *testing.T) {
+		require.NoError(t, callSupport("", withCert("scheduler")))
+		require.NoError(t, callEvents("", withCert("scheduler")))
+	})
+
+	t.Run("user without certificate", func(t *testing.T) {
+		err := callSupport(ValidUserEmail, withCert(""))
+		require.True(t, isPerms(err), err)
+		require.NoError(t, callEvents(ValidUserEmail, withCert("")))
+	})
+
+	t.Run("unmapped certificate", func(t *testing.T) {
+		// логин из метаданных не используется, если сертификат предъявлен.
+		err := callEvents(ValidUserEmail, withCert("intruder"))
+		require.True(t, isPerms(err), err)
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		err := callEvents(ValidUserEmail)
+		require.Error(t, err)
+		require.False(t, isPerms(err))
+	})
+}
+
+func isPerms(err error) bool {
+	var base errx.Base
+	return errors.As(err, &base) && base.Kind() == errx.TypePerms
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/client/apiclient"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+// TestAPIClient проверяет, что клиент, сгенерированный по спецификации, работает с маршрутами сервера.
+func (es *EventsSuiteTest) TestAPIClient() {
+	client, err := apiclient.NewClientWithResponses(es.testServer.URL,
+		apiclient.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", ValidUserEmail)
+			return nil
+		}))
+	es.Require().NoError(err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tagResp, err := client.CreateTagWithResponse(ctx, apiclient.TagCreate{Name: "работа", Color: "#ff0000"})
+	es.Require().NoError(err)
+	es.Require().Equal(http.StatusOK, tagResp.StatusCode(), string(tagResp.Body))
+	es.Require().NotNil(tagResp.JSON200.Data)
+	tag := *tagResp.JSON200.Data
+
+	resourceResp, err := client.CreateResourceWithResponse(ctx, apiclient.ResourceCreate{
+		Kind: apiclient.ResourceCreateKindRoom,
+		Name: "Переговорная",
+	})
+	es.Require().NoError(err)
+	es.Require().Equal(http.StatusOK, resourceResp.StatusCode(), string(resourceResp.Body))
+	resource := *resourceResp.JSON200.Data
+
+	date := time.Date(2023, 3, 6, 9, 0, 0, 0, time.UTC)
+	eventResp, err := client.CreateEventWithResponse(ctx, nil, apiclient.EventCreate{
+		Title:       "Планерка",
+		Date:        date,
+		Duration:    "30m",
+		TagIds:      &[]apiclient.TagID{*tag.Id},
+		ResourceIds: &[]apiclient.ResourceID{*resource.Id},
+	})
+	es.Require().NoError(err)
+	es.Require().Equal(http.StatusOK, eventResp.StatusCode(), string(eventResp.Body))
+	es.Require().Equal(apiclient.EnvelopeStatusSuccess, eventResp.JSON200.Status)
+	eventID := *eventResp.JSON200.Data.Id
+
+	es.Run("read", func() {
+		getResp, err := client.GetEventWithResponse(ctx, eventID)
+		es.Require().NoError(err)
+		es.Require().Equal(http.StatusOK, getResp.StatusCode())
+		es.Require().Equal("Планерка", *getResp.JSON200.Title)
+		es.Require().Len(*getResp.JSON200.Tags, 1)
+		es.Require().Len(*getResp.JSON200.Resources, 1)
+
+		listResp, err := client.GetEventsOnDateWithResponse(ctx, apiclient.Day,
+			&apiclient.GetEventsOnDateParams{Date: date})
+		es.Require().NoError(err)
+		es.Require().Equal(http.StatusOK, listResp.StatusCode(), string(listResp.Body))
+		es.Require().Len(*listResp.JSON200, 1)
+
+		tagsResp, err := client.GetTagsWithResponse(ctx)
+		es.Require().NoError(err)
+		es.Require().Len(*tagsResp.JSON200, 1)
+
+		resourcesResp, err := client.GetResourcesWithResponse(ctx, nil)
+		es.Require().NoError(err)
+		es.Require().Len(*resourcesResp.JSON200, 1)
+	})
+
+	es.Run("errors", func() {
+		invalidResp, err := client.CreateEventWithResponse(ctx, nil, apiclient.EventCreate{Date: date, Duration: "30m"})
+		es.Require().NoError(err)
+		es.Require().Equal(http.StatusUnprocessableEntity, invalidResp.StatusCode())
+		es.Require().NotNil(invalidResp.JSON422.Errors)
+		es.Require().Contains(*invalidResp.JSON422.Errors, "Title")
+
+		busyResp, err := client.CreateEventWithResponse(ctx, nil, apiclient.EventCreate{
+			Title:    "Совещание",
+			Date:     date,
+			Duration: "1h",
+		})
+		es.Require().NoError(err)
+		es.Require().Equal(http.StatusBadRequest, busyResp.StatusCode())
+		es.Require().Equal(model.ErrEventDateBusyCode, busyResp.JSON400.Code)
+	})
+
+	es.Run("delete", func() {
+		deleteResp, err := client.DeleteEventWithResponse(ctx, eventID)
+		es.Require().NoError(err)
+		es.Require().Equal(http.StatusOK, deleteResp.StatusCode(), string(deleteResp.Body))
+
+		getResp, err := client.GetEventWithResponse(ctx, eventID)
+		es.Require().NoError(err)
+		es.Require().Equal(http.StatusNotFound, getResp.StatusCode())
+	})
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+)
+
+// AttachmentField поле формы multipart/form-data с загружаемым файлом.
+const AttachmentField = "file"
+
+var ErrAttachmentNoFile = errors.New("в запросе нет поля file с загружаемым файлом")
+
+type Attachments struct {
+	*Handler
+}
+
+func (a *Attachments) GetList(request *rs.Request) rs.Response {
+	const actionName = "получение списка вложений"
+	ctx := request.Context()
+	event, err := a.event(ctx, request)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	attachments, err := a.services.Attachment.GetList(ctx, *event)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	return rs.Data(dto.FromAttachmentSlice(attachments))
+}
+
+// Upload загрузка файла формой multipart/form-data: содержимое передается в хранилище потоком,
+// без буферизации всего файла в памяти или во временном файле.
+func (a *Attachments) Upload(request *rs.Request) rs.Response {
+	const actionName = "загрузка вложения"
+	ctx := request.Context()
+	event, err := a.event(ctx, request)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	reader, err := request.MultipartReader()
+	if err != nil {
+		return a.handleError(ctx, actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return a.handleError(ctx, actionName, ErrAttachmentNoFile)
+		}
+		if err != nil {
+			return a.handleError(ctx, actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+		if part.FormName() != AttachmentField {
+			continue
+		}
+		attachment, err := a.services.Attachment.Upload(ctx, *event, model.AttachmentUpload{
+			FileName:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Content:     part,
+		})
+		if err != nil {
+			return a.handleError(ctx, actionName, err)
+		}
+		a.log(ctx).Info("вложение добавлено: eventID=%s, attachmentID=%s", event.ID, attachment.ID)
+		return rs.OK("вложение добавлено", dto.FromAttachmentModel(*attachment))
+	}
+}
+
+func (a *Attachments) Download(request *rs.Request) rs.Response {
+	const actionName = "скачивание вложения"
+	ctx := request.Context()
+	event, err := a.event(ctx, request)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	attachment, err := a.attachment(ctx, request, *event)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	content, err := a.services.Attachment.Open(ctx, *attachment)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	return rs.File(attachment.FileName, attachment.ContentType, attachment.Size, content)
+}
+
+func (a *Attachments) Delete(request *rs.Request) rs.Response {
+	const actionName = "удаление вложения"
+	ctx := request.Context()
+	event, err := a.event(ctx, request)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	attachment, err := a.attachment(ctx, request, *event)
+	if err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	if err = a.services.Attachment.Delete(ctx, *event, *attachment); err != nil {
+		return a.handleError(ctx, actionName, err)
+	}
+	a.log(ctx).Info("вложение удалено: eventID=%s, attachmentID=%s", event.ID, attachment.ID)
+	return rs.OK("вложение удалено", nil)
+}
+
+func (a *Attachments) event(ctx context.Context, request *rs.Request) (*model.Event, error) {
+	eventID, err := uuid.Parse(request.Param("eventID"))
+	if err != nil {
+		return nil, fmt.Errorf("неверный eventID: %w", err)
+	}
+	return a.services.EventCRUD.GetByID(ctx, eventID)
+}
+
+func (a *Attachments) attachment(
+	ctx context.Context, request *rs.Request, event model.Event,
+) (*model.Attachment, error) {
+	attachmentID, err := uuid.Parse(request.Param("attachmentID"))
+	if err != nil {
+		return nil, fmt.Errorf("неверный attachmentID: %w", err)
+	}
+	return a.services.Attachment.GetByID(ctx, event, attachmentID)
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/service"
+)
+
+// pngHeader сигнатура PNG, по которой http.DetectContentType опознает изображение.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+// uploadFile загрузка файла формой multipart/form-data.
+func uploadFile(t *testing.T, server *httptest.Server, resource, fileName, contentType string, content []byte) (
+	*http.Response, ErrorResponseDTO,
+) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.NoError(t, form.WriteField("comment", "поле до файла пропускается"))
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	part, err := form.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	res, resBody := sendRequest(t, server, http.MethodPost, resource, ValidUserEmail, body.Bytes(),
+		http.Header{"Content-Type": []string{form.FormDataContentType()}})
+	var resp ErrorResponseDTO
+	_ = json.Unmarshal(resBody, &resp)
+	return res, resp
+}
+
+func TestAttachments(t *testing.T) {
+	server := newTestServer(t, config.Server{}, calendartest.WithAttachmentLimits(service.AttachmentLimits{
+		MaxSize:      1024,
+		MaxCount:     2,
+		AllowedTypes: []string{"image/*", "text/plain"},
+	}))
+	res, resp := apiRequest(t, server, http.MethodPost, "/events", ValidUserEmail, []byte(`{
+		"title": "Встреча", "date": "2023-04-12T10:00:00Z", "duration": "1h",
+		"links": [{"type": "video", "url": "https://meet.example.com/room", "title": "Zoom"}]
+	}`))
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var event dto.Event
+	require.NoError(t, json.Unmarshal(resp.Data, &event))
+	require.Equal(t, []dto.Link{{Type: "video", URL: "https://meet.example.com/room", Title: "Zoom"}}, event.Links)
+	resource := "/events/" + event.ID + "/attachments"
+
+	t.Run("upload", func(t *testing.T) {
+		content := append(append([]byte{}, pngHeader...), make([]byte, 100)...)
+		res, resp := uploadFile(t, server, resource, `C:\photos\схема.png`, "", content)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var attachment dto.Attachment
+		require.NoError(t, json.Unmarshal(resp.Data, &attachment))
+		require.Equal(t, "схема.png", attachment.FileName)
+		require.Equal(t, "image/png", attachment.ContentType)
+		require.Equal(t, int64(len(content)), attachment.Size)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			server.URL+resource+"/"+attachment.ID, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", ValidUserEmail)
+		download, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = download.Body.Close()
+		}()
+		require.Equal(t, http.StatusOK, download.StatusCode)
+		require.Equal(t, "image/png", download.Header.Get("Content-Type"))
+		require.Equal(t, "nosniff", download.Header.Get("X-Content-Type-Options"))
+		require.Contains(t, download.Header.Get("Content-Disposition"), "attachment")
+		body, err := io.ReadAll(download.Body)
+		require.NoError(t, err)
+		require.Equal(t, content, body)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		// html под видом текста.
+		res, resp := uploadFile(t, server, resource, "page.txt", "text/plain", []byte("<html><script></script>"))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrAttachmentTypeCode, resp.Code)
+		// тип не из списка разрешенных.
+		res, resp = uploadFile(t, server, resource, "doc.pdf", "", []byte("%PDF-1.4 document"))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrAttachmentTypeCode, resp.Code)
+		res, resp = uploadFile(t, server, resource, "big.txt", "text/plain", bytes.Repeat([]byte("a"), 2048))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrAttachmentTooLargeCode, resp.Code)
+		res, _ = uploadFile(t, server, resource, "empty.txt", "text/plain", nil)
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
+
+	t.Run("count and delete", func(t *testing.T) {
+		res, _ := uploadFile(t, server, resource, "notes.txt", "text/plain", []byte("заметки"))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		res, resp := uploadFile(t, server, resource, "more.txt", "text/plain", []byte("еще"))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrAttachmentCountCode, resp.Code)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+resource, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", ValidUserEmail)
+		res, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		var list []dto.Attachment
+		err = json.NewDecoder(res.Body).Decode(&list)
+		_ = res.Body.Close()
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+
+		res, _ = apiRequest(t, server, http.MethodDelete, resource+"/"+list[0].ID, ValidUserEmail, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		res, _ = apiRequest(t, server, http.MethodGet, resource+"/"+list[0].ID, ValidUserEmail, nil)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type Availability struct {
+	*Handler
+}
+
+func (a *Availability) Get(request *rs.Request) rs.Response {
+	const actionName = "получение рабочего времени"
+	settings, err := a.services.Availability.Get(request.Context())
+	if err != nil {
+		return a.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromAvailabilityModel(*settings))
+}
+
+func (a *Availability) Update(request *rs.Request) rs.Response {
+	const actionName = "изменение рабочего времени"
+	var input dto.Availability
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				a.log(request.Context()).Error("изменение рабочего времени - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return a.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	inputUpdate, vErrs := input.Model()
+	if vErrs != nil {
+		return a.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	if err := a.services.Availability.Update(request.Context(), inputUpdate); err != nil {
+		return a.handleError(request.Context(), actionName, err)
+	}
+	return rs.OK("рабочее время изменено", nil)
+}
+
+func (a *Availability) GetOutOfOffice(request *rs.Request) rs.Response {
+	const actionName = "получение периодов отсутствия"
+	absences, err := a.services.Availability.GetOutOfOffice(request.Context())
+	if err != nil {
+		return a.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromOutOfOfficeSlice(absences))
+}
+
+func (a *Availability) AddOutOfOffice(request *rs.Request) rs.Response {
+	const actionName = "добавление периода отсутствия"
+	var input dto.OutOfOfficeCreate
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				a.log(request.Context()).Error("добавление периода отсутствия - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return a.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	inputCreate, vErrs := input.Model()
+	if vErrs != nil {
+		return a.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	ooo, err := a.services.Availability.AddOutOfOffice(request.Context(), inputCreate)
+	if err != nil {
+		return a.handleError(request.Context(), actionName, err)
+	}
+	a.log(request.Context()).Info("период отсутствия добавлен: oooID=%s", ooo.ID.String())
+	return rs.OK("период отсутствия добавлен", dto.FromOutOfOfficeModel(*ooo))
+}
+
+func (a *Availability) DeleteOutOfOffice(request *rs.Request) rs.Response {
+	const actionName = "удаление периода отсутствия"
+	oooID, err := uuid.Parse(request.Param("oooID"))
+	if err != nil {
+		return a.handleError(request.Context(), actionName, fmt.Errorf("неверный oooID: %w", err))
+	}
+	if err = a.services.Availability.DeleteOutOfOffice(request.Context(), oooID); err != nil {
+		return a.handleError(request.Context(), actionName, err)
+	}
+	a.log(request.Context()).Info("период отсутствия удален: oooID=%s", oooID.String())
+	return rs.OK("период отсутствия удален", nil)
+}
+
+func (a *Availability) GetFreeSlots(request *rs.Request) rs.Response {
+	const actionName = "получение свободного времени"
+	date, err := dto.ParseSlotsDate(request.URL.Query().Get("date"))
+	if err != nil {
+		return a.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", errx.NamedErrors{
+			{Field: "date", Err: err},
+		}))
+	}
+	slots, err := a.services.Availability.FreeSlots(request.Context(), date)
+	if err != nil {
+		return a.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromSlotSlice(slots))
+}
@@ -0,0 +1,5 @@
+// Package apiclient содержит REST-клиент календаря, сгенерированный по спецификации OpenAPI.
+// Для перегенерации нужен oapi-codegen (make install-generate-deps), клиент обновляется через make generate.
+package apiclient
+
+//go:generate oapi-codegen -config oapi-codegen.yaml ../../openapi/openapi.yaml
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
@@ -15,8 +16,30 @@ type Client interface {
 	Create(context.Context, dto.EventCreate) (*dto.Event, error)
 	Update(context.Context, string, dto.EventUpdate) error
 	GetByID(context.Context, string) (*dto.Event, error)
-	GetListOnDate(context.Context, string, time.Time) ([]dto.Event, error)
+	// GetListOnDate при заданных метках - только события, отмеченные хотя бы одной из них.
+	GetListOnDate(context.Context, string, time.Time, ...string) ([]dto.Event, error)
 	Delete(context.Context, string) error
+	GetTrash(context.Context) ([]dto.Event, error)
+	Restore(context.Context, string) error
+	Search(context.Context, string) ([]dto.EventFound, error)
+	CreateBatch(context.Context, dto.EventBatchCreate) (*dto.BatchReport, error)
+	UpdateBatch(context.Context, dto.EventBatchUpdate) (*dto.BatchReport, error)
+	DeleteBatch(context.Context, dto.EventBatchDelete) (*dto.BatchReport, error)
+	CreateTag(context.Context, dto.TagCreate) (*dto.Tag, error)
+	UpdateTag(context.Context, string, dto.TagUpdate) error
+	DeleteTag(context.Context, string) error
+	GetTags(context.Context) ([]dto.Tag, error)
+	GetAvailability(context.Context) (*dto.Availability, error)
+	UpdateAvailability(context.Context, dto.Availability) error
+	GetOutOfOffice(context.Context) ([]dto.OutOfOffice, error)
+	AddOutOfOffice(context.Context, dto.OutOfOfficeCreate) (*dto.OutOfOffice, error)
+	DeleteOutOfOffice(context.Context, string) error
+	// GetFreeSlots дата передается в формате ГГГГ-ММ-ДД.
+	GetFreeSlots(context.Context, string) ([]dto.Slot, error)
+	GetDigest(context.Context) (*dto.Digest, error)
+	UpdateDigest(context.Context, dto.Digest) error
+	GetProfile(context.Context) (*dto.User, error)
+	UpdateProfile(context.Context, dto.Profile) error
 }
 
 type Auth struct {
@@ -85,12 +108,21 @@ func (c ClientImpl) Delete(ctx context.Context, id string) error {
 	return rest.EncodeResponse(resp, nil, true)
 }
 
-func (c ClientImpl) GetListOnDate(ctx context.Context, rangeType string, t time.Time) ([]dto.Event, error) {
+func (c ClientImpl) GetListOnDate(
+	ctx context.Context,
+	rangeType string,
+	t time.Time,
+	tagIDs ...string,
+) ([]dto.Event, error) {
 	var events []dto.Event
+	params := map[string]interface{}{"date": t.Format(time.RFC3339)}
+	if len(tagIDs) > 0 {
+		params["tags"] = strings.Join(tagIDs, ",")
+	}
 	resp, err := c.api.Get( //nolint:bodyclose // it close in EncodeResponse
 		ctx,
 		fmt.Sprintf("/events/list/%s", rangeType),
-		map[string]interface{}{"date": t.Format(time.RFC3339)},
+		params,
 	)
 	if err != nil {
 		return nil, err
@@ -100,3 +132,227 @@ func (c ClientImpl) GetListOnDate(ctx context.Context, rangeType string, t time.
 	}
 	return events, nil
 }
+
+func (c ClientImpl) GetTrash(ctx context.Context) ([]dto.Event, error) {
+	var events []dto.Event
+	resp, err := c.api.Get(ctx, "/events/trash", nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, &events, false); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (c ClientImpl) Restore(ctx context.Context, id string) error {
+	resp, err := c.api.Post(ctx, fmt.Sprintf("/events/%s/restore", id), nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
+
+func (c ClientImpl) Search(ctx context.Context, query string) ([]dto.EventFound, error) {
+	var found []dto.EventFound
+	resp, err := c.api.Get( //nolint:bodyclose // it close in EncodeResponse
+		ctx,
+		"/events/search",
+		map[string]interface{}{"q": query},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, &found, false); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func (c ClientImpl) CreateBatch(ctx context.Context, input dto.EventBatchCreate) (*dto.BatchReport, error) {
+	report := new(dto.BatchReport)
+	resp, err := c.api.Post(ctx, "/events/batch", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, report, true); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c ClientImpl) UpdateBatch(ctx context.Context, input dto.EventBatchUpdate) (*dto.BatchReport, error) {
+	report := new(dto.BatchReport)
+	resp, err := c.api.Put(ctx, "/events/batch", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, report, true); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c ClientImpl) DeleteBatch(ctx context.Context, input dto.EventBatchDelete) (*dto.BatchReport, error) {
+	report := new(dto.BatchReport)
+	resp, err := c.api.Post(ctx, "/events/batch/delete", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, report, true); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c ClientImpl) CreateTag(ctx context.Context, input dto.TagCreate) (*dto.Tag, error) {
+	tag := new(dto.Tag)
+	resp, err := c.api.Post(ctx, "/tags", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, tag, true); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (c ClientImpl) UpdateTag(ctx context.Context, id string, input dto.TagUpdate) error {
+	resp, err := c.api.Put(ctx, fmt.Sprintf("/tags/%s", id), input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
+
+func (c ClientImpl) DeleteTag(ctx context.Context, id string) error {
+	resp, err := c.api.Delete(ctx, fmt.Sprintf("/tags/%s", id), nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
+
+func (c ClientImpl) GetTags(ctx context.Context) ([]dto.Tag, error) {
+	var tags []dto.Tag
+	resp, err := c.api.Get(ctx, "/tags", nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, &tags, false); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (c ClientImpl) GetAvailability(ctx context.Context) (*dto.Availability, error) {
+	settings := new(dto.Availability)
+	resp, err := c.api.Get(ctx, "/availability", nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, settings, false); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (c ClientImpl) UpdateAvailability(ctx context.Context, input dto.Availability) error {
+	resp, err := c.api.Put(ctx, "/availability", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
+
+func (c ClientImpl) GetOutOfOffice(ctx context.Context) ([]dto.OutOfOffice, error) {
+	var absences []dto.OutOfOffice
+	resp, err := c.api.Get(ctx, "/availability/ooo", nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, &absences, false); err != nil {
+		return nil, err
+	}
+	return absences, nil
+}
+
+func (c ClientImpl) AddOutOfOffice(ctx context.Context, input dto.OutOfOfficeCreate) (*dto.OutOfOffice, error) {
+	ooo := new(dto.OutOfOffice)
+	resp, err := c.api.Post(ctx, "/availability/ooo", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, ooo, true); err != nil {
+		return nil, err
+	}
+	return ooo, nil
+}
+
+func (c ClientImpl) DeleteOutOfOffice(ctx context.Context, id string) error {
+	resp, err := c.api.Delete( //nolint:bodyclose // it close in EncodeResponse
+		ctx,
+		fmt.Sprintf("/availability/ooo/%s", id),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
+
+func (c ClientImpl) GetFreeSlots(ctx context.Context, date string) ([]dto.Slot, error) {
+	var slots []dto.Slot
+	resp, err := c.api.Get( //nolint:bodyclose // it close in EncodeResponse
+		ctx,
+		"/availability/slots",
+		map[string]interface{}{"date": date},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, &slots, false); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (c ClientImpl) GetDigest(ctx context.Context) (*dto.Digest, error) {
+	settings := new(dto.Digest)
+	resp, err := c.api.Get(ctx, "/digest", nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, settings, false); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (c ClientImpl) UpdateDigest(ctx context.Context, input dto.Digest) error {
+	resp, err := c.api.Put(ctx, "/digest", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
+
+func (c ClientImpl) GetProfile(ctx context.Context) (*dto.User, error) {
+	user := new(dto.User)
+	resp, err := c.api.Get(ctx, "/profile", nil) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return nil, err
+	}
+	if err = rest.EncodeResponse(resp, user, false); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c ClientImpl) UpdateProfile(ctx context.Context, input dto.Profile) error {
+	resp, err := c.api.Put(ctx, "/profile", input) //nolint:bodyclose // it close in EncodeResponse
+	if err != nil {
+		return err
+	}
+	return rest.EncodeResponse(resp, nil, true)
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type Digest struct {
+	*Handler
+}
+
+func (d *Digest) Get(request *rs.Request) rs.Response {
+	const actionName = "получение настроек сводки"
+	settings, err := d.services.Digest.Get(request.Context())
+	if err != nil {
+		return d.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromDigestModel(*settings))
+}
+
+func (d *Digest) Update(request *rs.Request) rs.Response {
+	const actionName = "изменение настроек сводки"
+	var input dto.Digest
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				d.log(request.Context()).Error("изменение настроек сводки - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return d.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	inputUpdate, vErrs := input.Model()
+	if vErrs != nil {
+		return d.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	if err := d.services.Digest.Update(request.Context(), inputUpdate); err != nil {
+		return d.handleError(request.Context(), actionName, err)
+	}
+	return rs.OK("настройки сводки изменены", nil)
+}
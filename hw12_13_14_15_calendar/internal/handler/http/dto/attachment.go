@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+var ErrLinkTypeWrongFormat = errors.New("неизвестный тип ссылки, ожидается video или document")
+
+// Link ссылка события: type - video (видеовстреча) или document.
+type Link struct {
+	Type  string `json:"type"`
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+func ParseLinks(items []Link) ([]model.EventLink, error) {
+	links := make([]model.EventLink, len(items))
+	for i, item := range items {
+		linkType, err := model.ParseLinkType(item.Type)
+		if err != nil {
+			return nil, errors.Wrap(ErrLinkTypeWrongFormat, fmt.Sprintf("links[%d]: %s", i, item.Type))
+		}
+		links[i] = model.EventLink{Type: linkType, URL: item.URL, Title: item.Title}
+	}
+	return links, nil
+}
+
+func FromLinkSlice(items []model.EventLink) []Link {
+	if items == nil {
+		return nil
+	}
+	result := make([]Link, len(items))
+	for i, item := range items {
+		result[i] = Link{Type: item.Type.String(), URL: item.URL, Title: item.Title}
+	}
+	return result
+}
+
+type Attachment struct {
+	ID          string    `json:"id"`
+	EventID     string    `json:"eventId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func FromAttachmentModel(item model.Attachment) Attachment {
+	return Attachment{
+		ID:          item.ID.String(),
+		EventID:     item.EventID.String(),
+		FileName:    item.FileName,
+		ContentType: item.ContentType,
+		Size:        item.Size,
+		CreatedAt:   item.CreatedAt,
+	}
+}
+
+func FromAttachmentSlice(items []model.Attachment) []Attachment {
+	result := make([]Attachment, len(items))
+	for i, item := range items {
+		result[i] = FromAttachmentModel(item)
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var (
+	ErrWeekdayWrongFormat   = errors.New("неверный день недели, ожидается monday..sunday")
+	ErrDayTimeWrongFormat   = errors.New("неверный формат времени, ожидается ЧЧ:ММ")
+	ErrPolicyWrongFormat    = errors.New("неверная политика, ожидается ignore, warn или reject")
+	ErrOOODateWrongFormat   = errors.New("неверный формат даты периода отсутствия, ожидается RFC3339")
+	ErrSlotsDateWrongFormat = errors.New("неверный формат даты, ожидается RFC3339 или ГГГГ-ММ-ДД")
+)
+
+// WorkingHours рабочий интервал: день недели и время в формате ЧЧ:ММ, конец дня - 24:00.
+type WorkingHours struct {
+	Weekday string `json:"weekday"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+type Availability struct {
+	TimeZone string         `json:"timeZone"`
+	Policy   string         `json:"policy"` // ignore, warn, reject.
+	Hours    []WorkingHours `json:"hours"`
+}
+
+// Model возвращает связанную модель model.Availability.
+func (a Availability) Model() (model.Availability, errx.NamedErrors) {
+	var errs errx.NamedErrors
+	input := model.Availability{
+		TimeZone: a.TimeZone,
+		Hours:    make([]model.WorkingHours, 0, len(a.Hours)),
+	}
+	if a.Policy != "" {
+		policy, err := model.ParseAvailabilityPolicy(a.Policy)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: "policy", Err: ErrPolicyWrongFormat})
+		}
+		input.Policy = policy
+	}
+	for i, wh := range a.Hours {
+		weekday, err := ParseWeekday(wh.Weekday)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: fmt.Sprintf("hours[%d].weekday", i), Err: err})
+			continue
+		}
+		start, err := ParseDayTime(wh.Start)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: fmt.Sprintf("hours[%d].start", i), Err: err})
+			continue
+		}
+		end, err := ParseDayTime(wh.End)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: fmt.Sprintf("hours[%d].end", i), Err: err})
+			continue
+		}
+		input.Hours = append(input.Hours, model.WorkingHours{Weekday: weekday, Start: start, End: end})
+	}
+	if errs.Empty() {
+		return input, nil
+	}
+	return model.Availability{}, errs
+}
+
+func FromAvailabilityModel(item model.Availability) Availability {
+	result := Availability{
+		TimeZone: item.TimeZone,
+		Policy:   item.Policy.String(),
+		Hours:    make([]WorkingHours, len(item.Hours)),
+	}
+	for i, wh := range item.Hours {
+		result.Hours[i] = WorkingHours{
+			Weekday: strings.ToLower(wh.Weekday.String()),
+			Start:   FormatDayTime(wh.Start),
+			End:     FormatDayTime(wh.End),
+		}
+	}
+	return result
+}
+
+// ParseWeekday день недели по английскому названию без учета регистра.
+func ParseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, nil
+		}
+	}
+	return time.Sunday, ErrWeekdayWrongFormat
+}
+
+// ParseDayTime смещение от полуночи по времени ЧЧ:ММ.
+func ParseDayTime(value string) (time.Duration, error) {
+	var hours, minutes int
+	if n, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil || n != 2 || len(value) != 5 {
+		return 0, ErrDayTimeWrongFormat
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes > 0) {
+		return 0, ErrDayTimeWrongFormat
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+func FormatDayTime(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+type OutOfOfficeCreate struct {
+	DateFrom string `json:"dateFrom"`
+	DateTo   string `json:"dateTo"`
+	Reason   string `json:"reason"` // опционально.
+}
+
+func (oc OutOfOfficeCreate) Model() (model.OutOfOfficeCreate, errx.NamedErrors) {
+	var errs errx.NamedErrors
+	input := model.OutOfOfficeCreate{Reason: oc.Reason}
+	if date, err := time.Parse(time.RFC3339, oc.DateFrom); err != nil {
+		errs.Add(errx.NamedError{Field: "dateFrom", Err: errors.Wrap(ErrOOODateWrongFormat, err.Error())})
+	} else {
+		input.DateFrom = date
+	}
+	if date, err := time.Parse(time.RFC3339, oc.DateTo); err != nil {
+		errs.Add(errx.NamedError{Field: "dateTo", Err: errors.Wrap(ErrOOODateWrongFormat, err.Error())})
+	} else {
+		input.DateTo = date
+	}
+	if errs.Empty() {
+		return input, nil
+	}
+	return model.OutOfOfficeCreate{}, errs
+}
+
+type OutOfOffice struct {
+	ID       string    `json:"id"`
+	DateFrom time.Time `json:"dateFrom"`
+	DateTo   time.Time `json:"dateTo"`
+	Reason   string    `json:"reason"`
+}
+
+func FromOutOfOfficeModel(item model.OutOfOffice) OutOfOffice {
+	return OutOfOffice{
+		ID:       item.ID.String(),
+		DateFrom: item.DateFrom,
+		DateTo:   item.DateTo,
+		Reason:   item.Reason,
+	}
+}
+
+func FromOutOfOfficeSlice(items []model.OutOfOffice) []OutOfOffice {
+	result := make([]OutOfOffice, len(items))
+	for i, item := range items {
+		result[i] = FromOutOfOfficeModel(item)
+	}
+	return result
+}
+
+// Slot свободный интервал.
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func FromSlotSlice(items []model.DateRange) []Slot {
+	result := make([]Slot, len(items))
+	for i, item := range items {
+		result[i] = Slot{Start: item.GetFrom(), End: item.GetTo()}
+	}
+	return result
+}
+
+// ParseSlotsDate дата дня: RFC3339 или ГГГГ-ММ-ДД, учитывается только календарная дата.
+func ParseSlotsDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, ErrSlotsDateWrongFormat
+	}
+	return date, nil
+}
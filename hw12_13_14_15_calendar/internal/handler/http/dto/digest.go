@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var ErrDigestModeWrongFormat = errors.New("неверная периодичность сводки, ожидается off, daily или weekly")
+
+// Digest настройки сводки событий, незаданные поля принимают значения по умолчанию.
+type Digest struct {
+	Mode    string `json:"mode"`    // off, daily, weekly.
+	SendAt  string `json:"sendAt"`  // ЧЧ:ММ в часовом поясе пользователя.
+	Weekday string `json:"weekday"` // день отправки еженедельной сводки.
+}
+
+// Model возвращает связанную модель model.DigestSettings.
+func (d Digest) Model() (model.DigestSettings, errx.NamedErrors) {
+	var errs errx.NamedErrors
+	input := model.DefaultDigestSettings(uuid.Nil)
+	if d.Mode != "" {
+		mode, err := model.ParseDigestMode(d.Mode)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: "mode", Err: ErrDigestModeWrongFormat})
+		}
+		input.Mode = mode
+	}
+	if d.SendAt != "" {
+		sendAt, err := ParseDayTime(d.SendAt)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: "sendAt", Err: err})
+		}
+		input.SendAt = sendAt
+	}
+	if d.Weekday != "" {
+		weekday, err := ParseWeekday(d.Weekday)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: "weekday", Err: err})
+		}
+		input.Weekday = weekday
+	}
+	if errs.Empty() {
+		return input, nil
+	}
+	return model.DigestSettings{}, errs
+}
+
+func FromDigestModel(item model.DigestSettings) Digest {
+	return Digest{
+		Mode:    item.Mode.String(),
+		SendAt:  FormatDayTime(item.SendAt),
+		Weekday: strings.ToLower(item.Weekday.String()),
+	}
+}
@@ -15,11 +15,14 @@ var (
 )
 
 type EventCreate struct {
-	Title       string  `json:"title"`
-	Date        string  `json:"date"`
-	Duration    string  `json:"duration"`    // с единицей измерения.
-	Description *string `json:"description"` // опционально.
-	NotifyTerm  *string `json:"notifyTerm"`  // опционально, с единицей измерения.
+	Title       string   `json:"title"`
+	Date        string   `json:"date"`
+	Duration    string   `json:"duration"`    // с единицей измерения.
+	Description *string  `json:"description"` // опционально.
+	NotifyTerm  *string  `json:"notifyTerm"`  // опционально, с единицей измерения.
+	TagIDs      []string `json:"tagIds"`      // опционально.
+	Links       []Link   `json:"links"`       // опционально.
+	ResourceIDs []string `json:"resourceIds"` // опционально.
 }
 
 // Model возвращает связанную модель model.EventCreate.
@@ -49,6 +52,27 @@ func (ec EventCreate) Model() (model.EventCreate, errx.NamedErrors) {
 			input.NotifyTerm = &notifyTerm
 		}
 	}
+	if ec.TagIDs != nil {
+		if tagIDs, err := ParseTagIDs(ec.TagIDs); err != nil {
+			errs.Add(errx.NamedError{Field: "tagIds", Err: err})
+		} else {
+			input.TagIDs = tagIDs
+		}
+	}
+	if ec.Links != nil {
+		if links, err := ParseLinks(ec.Links); err != nil {
+			errs.Add(errx.NamedError{Field: "links", Err: err})
+		} else {
+			input.Links = links
+		}
+	}
+	if ec.ResourceIDs != nil {
+		if resourceIDs, err := ParseResourceIDs(ec.ResourceIDs); err != nil {
+			errs.Add(errx.NamedError{Field: "resourceIds", Err: err})
+		} else {
+			input.ResourceIDs = resourceIDs
+		}
+	}
 	if errs.Empty() {
 		return input, nil
 	}
@@ -56,11 +80,14 @@ func (ec EventCreate) Model() (model.EventCreate, errx.NamedErrors) {
 }
 
 type EventUpdate struct {
-	Title       *string `json:"title"`
-	Date        *string `json:"date"`
-	Duration    *string `json:"duration"` // с единицей измерения.
-	Description *string `json:"description"`
-	NotifyTerm  *string `json:"notifyTerm"` // с единицей измерения.
+	Title       *string   `json:"title"`
+	Date        *string   `json:"date"`
+	Duration    *string   `json:"duration"` // с единицей измерения.
+	Description *string   `json:"description"`
+	NotifyTerm  *string   `json:"notifyTerm"`  // с единицей измерения.
+	TagIDs      *[]string `json:"tagIds"`      // новый набор меток, [] - снять все метки.
+	Links       *[]Link   `json:"links"`       // новый набор ссылок, [] - удалить все ссылки.
+	ResourceIDs *[]string `json:"resourceIds"` // новый набор ресурсов, [] - снять всю бронь.
 }
 
 func (eu EventUpdate) Model() (model.EventUpdate, errx.NamedErrors) {
@@ -94,6 +121,27 @@ func (eu EventUpdate) Model() (model.EventUpdate, errx.NamedErrors) {
 			input.NotifyTerm = &notifyTerm
 		}
 	}
+	if eu.TagIDs != nil {
+		if tagIDs, err := ParseTagIDs(*eu.TagIDs); err != nil {
+			errs.Add(errx.NamedError{Field: "tagIds", Err: err})
+		} else {
+			input.TagIDs = &tagIDs
+		}
+	}
+	if eu.Links != nil {
+		if links, err := ParseLinks(*eu.Links); err != nil {
+			errs.Add(errx.NamedError{Field: "links", Err: err})
+		} else {
+			input.Links = &links
+		}
+	}
+	if eu.ResourceIDs != nil {
+		if resourceIDs, err := ParseResourceIDs(*eu.ResourceIDs); err != nil {
+			errs.Add(errx.NamedError{Field: "resourceIds", Err: err})
+		} else {
+			input.ResourceIDs = &resourceIDs
+		}
+	}
 	if errs.Empty() {
 		return input, nil
 	}
@@ -101,16 +149,21 @@ func (eu EventUpdate) Model() (model.EventUpdate, errx.NamedErrors) {
 }
 
 type Event struct {
-	ID           string    `json:"id"`
-	Title        string    `json:"title"`
-	Date         time.Time `json:"date"`
-	Duration     string    `json:"duration"`
-	Owner        *User     `json:"owner,omitempty"`
-	Description  string    `json:"description"`
-	NotifyTerm   string    `json:"notifyTerm"`
-	NotifyStatus string    `json:"notifyStatus"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Date         time.Time  `json:"date"`
+	Duration     string     `json:"duration"`
+	Owner        *User      `json:"owner,omitempty"`
+	Description  string     `json:"description"`
+	NotifyTerm   string     `json:"notifyTerm"`
+	NotifyStatus string     `json:"notifyStatus"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"`
+	Tags         []Tag      `json:"tags,omitempty"`
+	Links        []Link     `json:"links,omitempty"`
+	Resources    []Resource `json:"resources,omitempty"`
+	Warnings     []string   `json:"warnings,omitempty"`
 }
 
 func FromEventModel(item model.Event) Event {
@@ -124,6 +177,11 @@ func FromEventModel(item model.Event) Event {
 		NotifyStatus: item.NotifyStatus.String(),
 		CreatedAt:    item.CreatedAt,
 		UpdatedAt:    item.UpdatedAt,
+		DeletedAt:    item.DeletedAt,
+		Tags:         FromTagSlice(item.Tags),
+		Links:        FromLinkSlice(item.Links),
+		Resources:    FromResourceSlice(item.Resources),
+		Warnings:     item.Warnings,
 	}
 	if item.Owner != nil {
 		user := FromUserModel(*item.Owner)
@@ -142,3 +200,24 @@ func FromEventSlice(items []model.Event) []Event {
 	}
 	return result
 }
+
+type EventFound struct {
+	Event
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func FromEventFoundSlice(items []model.EventFound) []EventFound {
+	if items == nil {
+		return nil
+	}
+	result := make([]EventFound, len(items))
+	for i, item := range items {
+		result[i] = EventFound{
+			Event:   FromEventModel(item.Event),
+			Rank:    item.Rank,
+			Snippet: item.Snippet,
+		}
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var ErrEventIDWrongFormat = errors.New("неверный идентификатор события")
+
+/*
+Ошибки формата в любой операции пакета отклоняют запрос целиком, имена полей
+ошибок дополняются индексом операции: items[2].date.
+*/
+
+type EventBatchCreate struct {
+	Mode  string        `json:"mode"` // atomic (по умолчанию) или bestEffort.
+	Items []EventCreate `json:"items"`
+}
+
+func (bc EventBatchCreate) Model() ([]model.EventCreate, model.BatchMode, errx.NamedErrors) {
+	var errs errx.NamedErrors
+	mode := parseBatchMode(bc.Mode, &errs)
+	inputs := make([]model.EventCreate, len(bc.Items))
+	for i, item := range bc.Items {
+		input, vErrs := item.Model()
+		addItemErrors(&errs, i, vErrs)
+		inputs[i] = input
+	}
+	if errs.Empty() {
+		return inputs, mode, nil
+	}
+	return nil, mode, errs
+}
+
+type EventBatchUpdateItem struct {
+	ID string `json:"id"`
+	EventUpdate
+}
+
+type EventBatchUpdate struct {
+	Mode  string                 `json:"mode"`
+	Items []EventBatchUpdateItem `json:"items"`
+}
+
+func (bu EventBatchUpdate) Model() ([]model.EventBatchUpdate, model.BatchMode, errx.NamedErrors) {
+	var errs errx.NamedErrors
+	mode := parseBatchMode(bu.Mode, &errs)
+	items := make([]model.EventBatchUpdate, len(bu.Items))
+	for i, item := range bu.Items {
+		eventID, err := uuid.Parse(item.ID)
+		if err != nil {
+			errs.Add(errx.NamedError{
+				Field: fmt.Sprintf("items[%d].id", i),
+				Err:   errors.Wrap(ErrEventIDWrongFormat, err.Error()),
+			})
+		}
+		input, vErrs := item.EventUpdate.Model()
+		addItemErrors(&errs, i, vErrs)
+		items[i] = model.EventBatchUpdate{ID: eventID, Input: input}
+	}
+	if errs.Empty() {
+		return items, mode, nil
+	}
+	return nil, mode, errs
+}
+
+type EventBatchDelete struct {
+	Mode string   `json:"mode"`
+	IDs  []string `json:"ids"`
+}
+
+func (bd EventBatchDelete) Model() ([]uuid.UUID, model.BatchMode, errx.NamedErrors) {
+	var errs errx.NamedErrors
+	mode := parseBatchMode(bd.Mode, &errs)
+	eventIDs := make([]uuid.UUID, len(bd.IDs))
+	for i, rawID := range bd.IDs {
+		eventID, err := uuid.Parse(rawID)
+		if err != nil {
+			errs.Add(errx.NamedError{
+				Field: fmt.Sprintf("ids[%d]", i),
+				Err:   errors.Wrap(ErrEventIDWrongFormat, err.Error()),
+			})
+		}
+		eventIDs[i] = eventID
+	}
+	if errs.Empty() {
+		return eventIDs, mode, nil
+	}
+	return nil, mode, errs
+}
+
+// BatchResult результат операции пакета, Error - тело ответа, которое вернула бы одиночная операция.
+type BatchResult struct {
+	Index   int         `json:"index"`
+	Success bool        `json:"success"`
+	EventID string      `json:"eventId,omitempty"`
+	Event   *Event      `json:"event,omitempty"`
+	Error   interface{} `json:"error,omitempty"`
+}
+
+type BatchReport struct {
+	Applied int           `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+func FromBatchReport(report model.BatchReport) BatchReport {
+	result := BatchReport{
+		Applied: report.Applied,
+		Results: make([]BatchResult, len(report.Results)),
+	}
+	for i, item := range report.Results {
+		res := BatchResult{
+			Index:   i,
+			Success: item.Err == nil,
+		}
+		if item.EventID.ID() > 0 {
+			res.EventID = item.EventID.String()
+		}
+		if item.Event != nil {
+			event := FromEventModel(*item.Event)
+			res.Event = &event
+		}
+		if item.Err != nil {
+			res.Error = rqres.FromError(item.Err).GetHTTPResp()
+		}
+		result.Results[i] = res
+	}
+	return result
+}
+
+func parseBatchMode(sMode string, errs *errx.NamedErrors) model.BatchMode {
+	mode, err := model.ParseBatchMode(sMode)
+	if err != nil {
+		errs.Add(errx.NamedError{Field: "mode", Err: err})
+	}
+	return mode
+}
+
+func addItemErrors(errs *errx.NamedErrors, index int, itemErrs errx.NamedErrors) {
+	for _, itemErr := range itemErrs {
+		errs.Add(errx.NamedError{
+			Field: fmt.Sprintf("items[%d].%s", index, itemErr.Field),
+			Err:   itemErr.Err,
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var (
+	ErrExportFormat     = errors.New("неверный формат выгрузки, ожидается json или ndjson")
+	ErrExportDateFormat = errors.New("неверный формат даты, ожидается RFC3339")
+	ErrExportDateRange  = errors.New("дата окончания периода раньше даты начала")
+)
+
+// NDJSONType тип содержимого выгрузки построчно.
+const NDJSONType = "application/x-ndjson"
+
+// EventExport параметры выгрузки событий.
+type EventExport struct {
+	// NDJSON события построчно, иначе JSON-массивом.
+	NDJSON bool
+	// DateRange период, nil - все события.
+	DateRange *model.DateRange
+}
+
+// ParseEventExport параметры выгрузки из запроса: format (json, ndjson) важнее заголовка Accept,
+// при одной границе периода другая не ограничена.
+func ParseEventExport(query url.Values, accept string) (EventExport, error) {
+	var (
+		errs   errx.NamedErrors
+		export EventExport
+	)
+	switch query.Get("format") {
+	case "":
+		export.NDJSON = strings.Contains(accept, NDJSONType)
+	case "ndjson":
+		export.NDJSON = true
+	case "json":
+	default:
+		errs.Add(errx.NamedError{Field: "format", Err: ErrExportFormat})
+	}
+	from, fromOK := parseExportDate(query, "from", &errs)
+	to, toOK := parseExportDate(query, "to", &errs)
+	if !errs.Empty() {
+		return EventExport{}, errx.InvalidNew("неверные параметры выгрузки", errs)
+	}
+	if !fromOK && !toOK {
+		return export, nil
+	}
+	// открытая граница - на столетие от заданной.
+	if !fromOK {
+		from = to.AddDate(-100, 0, 0)
+	}
+	if !toOK {
+		to = from.AddDate(100, 0, 0)
+	}
+	if to.Before(from) {
+		errs.Add(errx.NamedError{Field: "to", Err: ErrExportDateRange})
+		return EventExport{}, errx.InvalidNew("неверные параметры выгрузки", errs)
+	}
+	dateRgn := model.DateRgnFromDates(from, to)
+	export.DateRange = &dateRgn
+	return export, nil
+}
+
+func parseExportDate(query url.Values, field string, errs *errx.NamedErrors) (time.Time, bool) {
+	value := query.Get(field)
+	if value == "" {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		errs.Add(errx.NamedError{Field: field, Err: errors.Wrap(ErrExportDateFormat, err.Error())})
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,182 @@
+package dto
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var (
+	ErrResourceIDWrongFormat   = errors.New("неверный идентификатор ресурса")
+	ErrManagerIDWrongFormat    = errors.New("неверный идентификатор ответственного")
+	ErrResourceKindWrongFormat = errors.New("неверный вид ресурса, ожидается room или equipment")
+	ErrCapacityWrongFormat     = errors.New("неверная вместимость, ожидается целое число")
+	ErrPeriodWrongFormat       = errors.New("неверный формат даты, ожидается RFC3339")
+	ErrPeriodWrongRange        = errors.New("конец периода должен быть позже начала")
+)
+
+type ResourceCreate struct {
+	Kind     string `json:"kind"` // room, equipment.
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"` // опционально, 0 - не ограничена.
+	Location string `json:"location"` // опционально.
+}
+
+// Model неизвестный вид ресурса отклоняется проверкой в сервисе.
+func (rc ResourceCreate) Model() model.ResourceCreate {
+	kind, _ := model.ParseResourceKind(rc.Kind)
+	return model.ResourceCreate{
+		Kind:     kind,
+		Name:     rc.Name,
+		Capacity: rc.Capacity,
+		Location: rc.Location,
+	}
+}
+
+type ResourceUpdate struct {
+	Name      *string `json:"name"`
+	Capacity  *int    `json:"capacity"`
+	Location  *string `json:"location"`
+	ManagerID *string `json:"managerId"` // передача ответственности.
+}
+
+func (ru ResourceUpdate) Model() (model.ResourceUpdate, errx.NamedErrors) {
+	input := model.ResourceUpdate{
+		Name:     ru.Name,
+		Capacity: ru.Capacity,
+		Location: ru.Location,
+	}
+	if ru.ManagerID != nil {
+		managerID, err := uuid.Parse(*ru.ManagerID)
+		if err != nil {
+			return model.ResourceUpdate{}, errx.NamedErrors{
+				{Field: "managerId", Err: errors.Wrap(ErrManagerIDWrongFormat, err.Error())},
+			}
+		}
+		input.ManagerID = &managerID
+	}
+	return input, nil
+}
+
+type Resource struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	ManagerID string `json:"managerId"`
+	Capacity  int    `json:"capacity"`
+	Location  string `json:"location,omitempty"`
+}
+
+func FromResourceModel(item model.Resource) Resource {
+	return Resource{
+		ID:        item.ID.String(),
+		Kind:      item.Kind.String(),
+		Name:      item.Name,
+		ManagerID: item.ManagerID.String(),
+		Capacity:  item.Capacity,
+		Location:  item.Location,
+	}
+}
+
+func FromResourceSlice(items []model.Resource) []Resource {
+	if items == nil {
+		return nil
+	}
+	result := make([]Resource, len(items))
+	for i, item := range items {
+		result[i] = FromResourceModel(item)
+	}
+	return result
+}
+
+type ResourceBooking struct {
+	ResourceID string    `json:"resourceId"`
+	EventID    string    `json:"eventId"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	BookedBy   string    `json:"bookedBy,omitempty"`
+}
+
+func FromResourceBookingSlice(items []model.ResourceBooking) []ResourceBooking {
+	if items == nil {
+		return nil
+	}
+	result := make([]ResourceBooking, len(items))
+	for i, item := range items {
+		result[i] = ResourceBooking{
+			ResourceID: item.ResourceID.String(),
+			EventID:    item.EventID.String(),
+			Start:      item.DateRange.GetFrom(),
+			End:        item.DateRange.GetTo(),
+			BookedBy:   item.BookedBy,
+		}
+	}
+	return result
+}
+
+// ParseResourceIDs разбирает список идентификаторов ресурсов.
+func ParseResourceIDs(rawIDs []string) ([]uuid.UUID, error) {
+	resourceIDs := make([]uuid.UUID, 0, len(rawIDs))
+	for _, rawID := range rawIDs {
+		resourceID, err := uuid.Parse(strings.TrimSpace(rawID))
+		if err != nil {
+			return nil, errors.Wrap(ErrResourceIDWrongFormat, err.Error())
+		}
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	return resourceIDs, nil
+}
+
+// ParseResourceSearch условия поиска ресурсов: kind и minCapacity, оба опциональны.
+func ParseResourceSearch(query url.Values) (model.ResourceSearch, error) {
+	var (
+		errs   errx.NamedErrors
+		search model.ResourceSearch
+	)
+	if rawKind := query.Get("kind"); rawKind != "" {
+		kind, err := model.ParseResourceKind(rawKind)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: "kind", Err: ErrResourceKindWrongFormat})
+		} else {
+			search.Kind = &kind
+		}
+	}
+	if rawCapacity := query.Get("minCapacity"); rawCapacity != "" {
+		capacity, err := strconv.Atoi(rawCapacity)
+		if err != nil {
+			errs.Add(errx.NamedError{Field: "minCapacity", Err: errors.Wrap(ErrCapacityWrongFormat, err.Error())})
+		} else {
+			search.MinCapacity = &capacity
+		}
+	}
+	if !errs.Empty() {
+		return model.ResourceSearch{}, errx.InvalidNew("неверные параметры", errs)
+	}
+	return search, nil
+}
+
+// ParsePeriod обязательный период from..to в RFC 3339.
+func ParsePeriod(query url.Values) (model.DateRange, error) {
+	var errs errx.NamedErrors
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		errs.Add(errx.NamedError{Field: "from", Err: errors.Wrap(ErrPeriodWrongFormat, err.Error())})
+	}
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		errs.Add(errx.NamedError{Field: "to", Err: errors.Wrap(ErrPeriodWrongFormat, err.Error())})
+	}
+	if errs.Empty() && !to.After(from) {
+		errs.Add(errx.NamedError{Field: "to", Err: ErrPeriodWrongRange})
+	}
+	if !errs.Empty() {
+		return model.DateRange{}, errx.InvalidNew("неверные параметры", errs)
+	}
+	return model.DateRgnFromDates(from, to), nil
+}
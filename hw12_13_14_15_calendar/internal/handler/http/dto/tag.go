@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+var ErrTagIDWrongFormat = errors.New("неверный идентификатор метки")
+
+type TagCreate struct {
+	Name  string `json:"name"`
+	Color string `json:"color"` // #RRGGBB.
+}
+
+func (tc TagCreate) Model() model.TagCreate {
+	return model.TagCreate{
+		Name:  tc.Name,
+		Color: tc.Color,
+	}
+}
+
+type TagUpdate struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+func (tu TagUpdate) Model() model.TagUpdate {
+	return model.TagUpdate{
+		Name:  tu.Name,
+		Color: tu.Color,
+	}
+}
+
+type Tag struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func FromTagModel(item model.Tag) Tag {
+	return Tag{
+		ID:    item.ID.String(),
+		Name:  item.Name,
+		Color: item.Color,
+	}
+}
+
+func FromTagSlice(items []model.Tag) []Tag {
+	if items == nil {
+		return nil
+	}
+	result := make([]Tag, len(items))
+	for i, item := range items {
+		result[i] = FromTagModel(item)
+	}
+	return result
+}
+
+// ParseTagIDs разбирает список идентификаторов меток.
+func ParseTagIDs(rawIDs []string) ([]uuid.UUID, error) {
+	tagIDs := make([]uuid.UUID, 0, len(rawIDs))
+	for _, rawID := range rawIDs {
+		tagID, err := uuid.Parse(strings.TrimSpace(rawID))
+		if err != nil {
+			return nil, errors.Wrap(ErrTagIDWrongFormat, err.Error())
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+	return tagIDs, nil
+}
@@ -5,20 +5,23 @@ import (
 )
 
 type UserCreate struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Locale string `json:"locale"`
 }
 
 func (uc UserCreate) Model() model.UserCreate {
 	return model.UserCreate{
-		Name:  uc.Name,
-		Email: uc.Email,
+		Name:   uc.Name,
+		Email:  uc.Email,
+		Locale: uc.Locale,
 	}
 }
 
 type UserUpdate struct {
-	Name  *string `json:"name"`
-	Email *string `json:"email"`
+	Name   *string `json:"name"`
+	Email  *string `json:"email"`
+	Locale *string `json:"locale"`
 }
 
 func (uu UserUpdate) Model() model.UserUpdate {
@@ -29,20 +32,38 @@ func (uu UserUpdate) Model() model.UserUpdate {
 	if uu.Email != nil {
 		input.Email = uu.Email
 	}
+	if uu.Locale != nil {
+		input.Locale = uu.Locale
+	}
 	return input
 }
 
+// Profile изменение профиля текущим пользователем, E-mail используется для входа и не меняется.
+type Profile struct {
+	Name   *string `json:"name"`
+	Locale *string `json:"locale"` // язык писем: ru, en-US; пустая строка - язык по умолчанию.
+}
+
+func (p Profile) Model() model.UserUpdate {
+	return model.UserUpdate{
+		Name:   p.Name,
+		Locale: p.Locale,
+	}
+}
+
 type User struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Locale string `json:"locale"`
 }
 
 func FromUserModel(item model.User) User {
 	return User{
-		ID:    item.ID.String(),
-		Name:  item.Name,
-		Email: item.Email,
+		ID:     item.ID.String(),
+		Name:   item.Name,
+		Email:  item.Email,
+		Locale: item.Locale,
 	}
 }
 
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +14,9 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
 )
 
+// IdempotencyKeyHeader заголовок с ключом идемпотентности запроса на создание события.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 type Events struct {
 	*Handler
 }
@@ -21,32 +26,83 @@ func (e *Events) GetListOnDate(request *rs.Request) rs.Response {
 	// проверка правильности date и rangeType будет в сервисе
 	rangeType, err := model.ParseRangeType(request.Param("rangeType"))
 	if err != nil {
-		e.handleError(actionName, fmt.Errorf("неверный rangeType %w", err))
+		e.handleError(request.Context(), actionName, fmt.Errorf("неверный rangeType %w", err))
 	}
 	fmt.Println(err)
 	date, err := time.Parse(time.RFC3339, request.URL.Query().Get("date"))
 	if err != nil {
-		e.handleError(actionName, fmt.Errorf("неверная дата %w", err))
+		e.handleError(request.Context(), actionName, fmt.Errorf("неверная дата %w", err))
+	}
+	var tagIDs []uuid.UUID
+	if rawTags := request.URL.Query().Get("tags"); rawTags != "" {
+		if tagIDs, err = dto.ParseTagIDs(strings.Split(rawTags, ",")); err != nil {
+			return e.handleError(request.Context(), actionName, err)
+		}
 	}
-	events, err := e.services.EventCRUD.GetUserEventsOn(request.Context(), date, rangeType)
+	events, err := e.services.EventCRUD.GetUserEventsOn(request.Context(), date, rangeType, tagIDs)
 	if err != nil {
 		err = fmt.Errorf("error events quering: %w", err)
-		e.logger.Error(err.Error())
+		e.log(request.Context()).Error(err.Error())
 		return rs.FromError(err)
 	}
 	return rs.Data(dto.FromEventSlice(events))
 }
 
+func (e *Events) Search(request *rs.Request) rs.Response {
+	const actionName = "поиск событий"
+	found, err := e.services.EventCRUD.Search(request.Context(), request.URL.Query().Get("q"))
+	if err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromEventFoundSlice(found))
+}
+
+// Export выгрузка событий пользователя потоком: NDJSON при format=ndjson или Accept: application/x-ndjson,
+// иначе JSON-массив. Необязательный период from..to в RFC 3339.
+func (e *Events) Export(request *rs.Request) rs.Response {
+	const actionName = "выгрузка событий"
+	ctx := request.Context()
+	params, err := dto.ParseEventExport(request.URL.Query(), request.Header.Get("Accept"))
+	if err != nil {
+		return e.handleError(ctx, actionName, err)
+	}
+	user, err := e.services.User.GetCurrent(ctx)
+	if err != nil {
+		return e.handleError(ctx, actionName, err)
+	}
+	events, err := e.services.EventCRUD.GetEvents(ctx, model.EventSearch{
+		OwnerID:     &user.ID,
+		DateRange:   params.DateRange,
+		TacDuration: true,
+	})
+	if err != nil {
+		return e.handleError(ctx, actionName, err)
+	}
+	format := rs.JSONArray
+	if params.NDJSON {
+		format = rs.NDJSON
+	}
+	return rs.Stream(format, func(ctx context.Context, emit rs.Emit) error {
+		for _, event := range events {
+			if err := emit(dto.FromEventModel(event)); err != nil {
+				return err
+			}
+		}
+		e.log(ctx).Info("%s: %d событий", actionName, len(events))
+		return nil
+	})
+}
+
 func (e *Events) GetByID(request *rs.Request) rs.Response {
 	const actionName = "получение события по ID"
 	ctx := request.Context()
 	eventID, err := uuid.Parse(request.Param("eventID"))
 	if err != nil {
-		return e.handleError(actionName, fmt.Errorf("неверный eventID: %w", err))
+		return e.handleError(request.Context(), actionName, fmt.Errorf("неверный eventID: %w", err))
 	}
 	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
 	if err != nil {
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
 	return rs.Data(dto.FromEventModel(*event))
 }
@@ -57,25 +113,26 @@ func (e *Events) Create(request *rs.Request) rs.Response {
 	if request.ContentLength > 0 {
 		defer func() {
 			if err := request.Body.Close(); err != nil {
-				e.logger.Error("добавление события - request.Body.Close(): %s", err.Error())
+				e.log(request.Context()).Error("добавление события - request.Body.Close(): %s", err.Error())
 			}
 		}()
 		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
-			return e.handleError(actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+			return e.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
 		}
 	}
 	inputCreate, vErrs := input.Model()
 	if vErrs != nil {
 		err := errx.InvalidNew("неверные данные", vErrs)
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
-	event, err := e.services.EventCRUD.Add(request.Context(), inputCreate)
+	idempotencyKey := request.Header.Get(IdempotencyKeyHeader)
+	event, err := e.services.EventIdempotent.Add(request.Context(), idempotencyKey, inputCreate)
 	if err != nil {
 		err := fmt.Errorf("ошибка добавления события: %w", err)
-		e.logger.Error(err.Error())
+		e.log(request.Context()).Error(err.Error())
 		return rs.FromError(err)
 	}
-	e.logger.Info("событие добавлено: eventID=%s", event.ID.String())
+	e.log(request.Context()).Info("событие добавлено: eventID=%s", event.ID.String())
 	return rs.OK("событие добавлено", dto.FromEventModel(*event))
 }
 
@@ -84,33 +141,33 @@ func (e *Events) Update(request *rs.Request) rs.Response {
 	var input dto.EventUpdate
 	eventID, err := uuid.Parse(request.Param("eventID"))
 	if err != nil {
-		return e.handleError(actionName, fmt.Errorf("неверный eventID: %w", err))
+		return e.handleError(request.Context(), actionName, fmt.Errorf("неверный eventID: %w", err))
 	}
 	ctx := request.Context()
 	if request.ContentLength > 0 {
 		defer func() {
 			if err := request.Body.Close(); err != nil {
-				e.logger.Error("изменение события - request.Body.Close(): %s", err.Error())
+				e.log(request.Context()).Error("изменение события - request.Body.Close(): %s", err.Error())
 			}
 		}()
 		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
-			return e.handleError(actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+			return e.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
 		}
 	}
 	inputUpdate, vErrs := input.Model()
 	if vErrs != nil {
 		err = errx.InvalidNew("неверные данные", vErrs)
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
 	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
 	if err != nil {
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
 	err = e.services.EventCRUD.Update(request.Context(), *event, inputUpdate)
 	if err != nil {
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
-	e.logger.Info("событие изменено: eventID=%s", event.ID.String())
+	e.log(request.Context()).Info("событие изменено: eventID=%s", event.ID.String())
 	return rs.OK("событие изменено", nil)
 }
 
@@ -118,17 +175,40 @@ func (e *Events) Delete(request *rs.Request) rs.Response {
 	const actionName = "удаление события"
 	eventID, err := uuid.Parse(request.Param("eventID"))
 	if err != nil {
-		return e.handleError(actionName, fmt.Errorf("неверный eventID: %w", err))
+		return e.handleError(request.Context(), actionName, fmt.Errorf("неверный eventID: %w", err))
 	}
 	ctx := request.Context()
 	event, err := e.services.EventCRUD.GetByID(ctx, eventID)
 	if err != nil {
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
 	err = e.services.EventCRUD.Delete(request.Context(), *event)
 	if err != nil {
-		return e.handleError(actionName, err)
+		return e.handleError(request.Context(), actionName, err)
 	}
-	e.logger.Info("событие удалено: eventID=%s", event.ID.String())
+	e.log(request.Context()).Info("событие перемещено в корзину: eventID=%s", event.ID.String())
 	return rs.OK("событие удалено", dto.FromEventModel(*event))
 }
+
+func (e *Events) GetTrash(request *rs.Request) rs.Response {
+	const actionName = "получение списка событий в корзине"
+	events, err := e.services.EventCRUD.GetTrash(request.Context())
+	if err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromEventSlice(events))
+}
+
+func (e *Events) Restore(request *rs.Request) rs.Response {
+	const actionName = "восстановление события"
+	eventID, err := uuid.Parse(request.Param("eventID"))
+	if err != nil {
+		return e.handleError(request.Context(), actionName, fmt.Errorf("неверный eventID: %w", err))
+	}
+	err = e.services.EventCRUD.Restore(request.Context(), eventID)
+	if err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	e.log(request.Context()).Info("событие восстановлено: eventID=%s", eventID.String())
+	return rs.OK("событие восстановлено", nil)
+}
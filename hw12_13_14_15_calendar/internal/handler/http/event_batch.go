@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+func (e *Events) CreateBatch(request *rs.Request) rs.Response {
+	const actionName = "пакетное добавление событий"
+	var input dto.EventBatchCreate
+	if err := e.decodeBatch(request, actionName, &input); err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	inputs, mode, vErrs := input.Model()
+	if vErrs != nil {
+		return e.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	report, err := e.services.EventCRUD.AddBatch(request.Context(), inputs, mode)
+	if err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	return e.batchResponse(request.Context(), actionName, *report)
+}
+
+func (e *Events) UpdateBatch(request *rs.Request) rs.Response {
+	const actionName = "пакетное изменение событий"
+	var input dto.EventBatchUpdate
+	if err := e.decodeBatch(request, actionName, &input); err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	items, mode, vErrs := input.Model()
+	if vErrs != nil {
+		return e.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	report, err := e.services.EventCRUD.UpdateBatch(request.Context(), items, mode)
+	if err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	return e.batchResponse(request.Context(), actionName, *report)
+}
+
+func (e *Events) DeleteBatch(request *rs.Request) rs.Response {
+	const actionName = "пакетное удаление событий"
+	var input dto.EventBatchDelete
+	if err := e.decodeBatch(request, actionName, &input); err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	eventIDs, mode, vErrs := input.Model()
+	if vErrs != nil {
+		return e.handleError(request.Context(), actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	report, err := e.services.EventCRUD.DeleteBatch(request.Context(), eventIDs, mode)
+	if err != nil {
+		return e.handleError(request.Context(), actionName, err)
+	}
+	return e.batchResponse(request.Context(), actionName, *report)
+}
+
+func (e *Events) decodeBatch(request *rs.Request, actionName string, input interface{}) error {
+	if request.ContentLength == 0 {
+		return nil
+	}
+	defer func() {
+		if err := request.Body.Close(); err != nil {
+			e.log(request.Context()).Error("%s - request.Body.Close(): %s", actionName, err.Error())
+		}
+	}()
+	if err := json.NewDecoder(request.Body).Decode(input); err != nil {
+		return fmt.Errorf("ошибка парсинга входных данных: %w", err)
+	}
+	return nil
+}
+
+func (e *Events) batchResponse(ctx context.Context, actionName string, report model.BatchReport) rs.Response {
+	e.log(ctx).Info("%s: выполнено %d из %d", actionName, report.Applied, len(report.Results))
+	message := "пакет выполнен"
+	if report.Applied < len(report.Results) {
+		message = fmt.Sprintf("пакет выполнен частично: %d из %d", report.Applied, len(report.Results))
+	}
+	if report.Applied == 0 {
+		message = "пакет не выполнен"
+	}
+	return rs.OK(message, dto.FromBatchReport(report))
+}
@@ -5,19 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
-	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
-	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest"
 )
 
 type EventsSuiteTest struct {
@@ -26,11 +29,12 @@ type EventsSuiteTest struct {
 }
 
 type ErrorResponseDTO struct {
-	Status  string            `json:"status"`
-	Code    int               `json:"code"`
-	Message string            `json:"message"`
-	Data    json.RawMessage   `json:"data"`
-	Errors  map[string]string `json:"errors"`
+	Status    string            `json:"status"`
+	Code      int               `json:"code"`
+	Message   string            `json:"message"`
+	Data      json.RawMessage   `json:"data"`
+	Errors    map[string]string `json:"errors"`
+	RequestID string            `json:"requestId"`
 }
 
 const (
@@ -38,33 +42,61 @@ const (
 )
 
 func (es *EventsSuiteTest) SetupTest() {
-	// логгер будет писать в stdout и stderr, а мы будем это перехватывать.
-	logs, err := logger.NewLogrus(logger.Config{
-		Level: logger.LevelInfo,
-	})
-	es.Suite.Require().NoError(err)
-
 	// слой репозиториев мы не будем мокать, а будем использовать реализацию memory.
-	repos, err := deps.NewRepos(config.Storage{Type: "memory"}, nil)
-	es.Suite.Require().NoError(err)
-
-	dependencies := &deps.Deps{Repos: repos, Logger: logs}
-	services := deps.NewServices(dependencies)
+	es.testServer = newTestServer(es.T(), config.Server{})
+}
 
-	restServer, _ := NewHandledServer(config.Server{}, services, dependencies)
+// newRestServer REST-сервер на зависимостях calendartest, пользователь ValidUserEmail уже зарегистрирован.
+func newRestServer(t *testing.T, cfg config.Server, opts ...calendartest.Option) *rest.Server {
+	t.Helper()
+	opts = append([]calendartest.Option{calendartest.WithUser(ValidUserEmail, ValidUserEmail)}, opts...)
+	dependencies, services := calendartest.New(t, opts...)
+	restServer, _ := NewHandledServer(cfg, services, dependencies)
+	return restServer
+}
 
-	es.testServer = httptest.NewServer(restServer)
+// newTestServer запущенный newRestServer, останавливается по завершении теста.
+func newTestServer(t *testing.T, cfg config.Server, opts ...calendartest.Option) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(newRestServer(t, cfg, opts...))
+	t.Cleanup(server.Close)
+	return server
+}
 
-	// для того, чтобы пользователь авторизовался
-	_, err = services.User.Add(context.Background(), model.UserCreate{
-		Name:  ValidUserEmail,
-		Email: ValidUserEmail,
-	})
-	es.Suite.Require().NoError(err)
+// sendRequest запрос от имени login, пустой login - без авторизации.
+func sendRequest(
+	t *testing.T, server *httptest.Server, method, resource, login string, body []byte, header http.Header,
+) (*http.Response, []byte) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, server.URL+resource, bytes.NewBuffer(body))
+	require.NoError(t, err)
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	if login != "" {
+		req.Header.Set("Authorization", login)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	resBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res, resBody
 }
 
-func (es *EventsSuiteTest) TearDownTest() {
-	es.testServer.Close()
+// apiRequest запрос, ответ которого - стандартная обертка.
+func apiRequest(t *testing.T, server *httptest.Server, method, resource, login string, body []byte) (
+	*http.Response, ErrorResponseDTO,
+) {
+	t.Helper()
+	res, resBody := sendRequest(t, server, method, resource, login, body, nil)
+	var resp ErrorResponseDTO
+	_ = json.Unmarshal(resBody, &resp)
+	return res, resp
 }
 
 func (es *EventsSuiteTest) TestCreate() {
@@ -534,6 +566,599 @@ func (es *EventsSuiteTest) TestList() {
 	}
 }
 
+func (es *EventsSuiteTest) TestTrash() {
+	events := addEvents(es, [][]byte{
+		[]byte(`{
+				"title": "Test event 1",
+				"date": "2023-02-19T20:00:00.417Z",
+				"duration": "45m"
+			}`),
+		[]byte(`{
+				"title": "Test event 2",
+				"date": "2023-02-20T20:00:00.417Z",
+				"duration": "45m"
+			}`),
+	})
+	doRequest := func(method, resource string) (int, []byte) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		requestURL := fmt.Sprintf("%s%s", es.testServer.URL, resource)
+		req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+		es.Suite.Require().NoError(err)
+		req.Header.Set("Authorization", ValidUserEmail)
+
+		res, err := http.DefaultClient.Do(req)
+		es.Suite.Require().NoError(err)
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		var body bytes.Buffer
+		_, err = body.ReadFrom(res.Body)
+		es.Suite.Require().NoError(err)
+		return res.StatusCode, body.Bytes()
+	}
+	trashIDs := func() []string {
+		code, body := doRequest(http.MethodGet, "/events/trash")
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var resp []dto.Event
+		es.Suite.Require().NoError(json.Unmarshal(body, &resp))
+		ids := make([]string, 0, len(resp))
+		for _, event := range resp {
+			es.Suite.Require().NotNil(event.DeletedAt)
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+
+	es.Suite.Run("deleted event moved to trash", func() {
+		code, _ := doRequest(http.MethodDelete, fmt.Sprintf("/events/%s", events[0].ID))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal([]string{events[0].ID}, trashIDs())
+
+		code, _ = doRequest(http.MethodGet, fmt.Sprintf("/events/%s", events[0].ID))
+		es.Suite.Require().Equal(http.StatusNotFound, code)
+	})
+
+	es.Suite.Run("restoring trashed event", func() {
+		code, _ := doRequest(http.MethodPost, fmt.Sprintf("/events/%s/restore", events[0].ID))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Empty(trashIDs())
+
+		code, _ = doRequest(http.MethodGet, fmt.Sprintf("/events/%s", events[0].ID))
+		es.Suite.Require().Equal(http.StatusOK, code)
+	})
+
+	es.Suite.Run("restoring not trashed event", func() {
+		code, _ := doRequest(http.MethodPost, fmt.Sprintf("/events/%s/restore", events[1].ID))
+		es.Suite.Require().Equal(http.StatusNotFound, code)
+	})
+
+	es.Suite.Run("restoring event on busy date", func() {
+		code, _ := doRequest(http.MethodDelete, fmt.Sprintf("/events/%s", events[1].ID))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		addEvents(es, [][]byte{
+			[]byte(`{
+				"title": "Test event 3",
+				"date": "2023-02-20T20:30:00.417Z",
+				"duration": "45m"
+			}`),
+		})
+		code, body := doRequest(http.MethodPost, fmt.Sprintf("/events/%s/restore", events[1].ID))
+		es.Suite.Require().Equal(http.StatusBadRequest, code)
+		var resp ErrorResponseDTO
+		es.Suite.Require().NoError(json.Unmarshal(body, &resp))
+		es.Suite.Require().Equal(model.ErrEventDateBusyCode, resp.Code)
+	})
+}
+
+func (es *EventsSuiteTest) TestSearch() {
+	events := addEvents(es, [][]byte{
+		[]byte(`{
+				"title": "Встреча в Zoom",
+				"date": "2023-02-19T20:00:00.417Z",
+				"duration": "45m",
+				"description": "Обсуждение календаря"
+			}`),
+		[]byte(`{
+				"title": "Демо календаря",
+				"date": "2023-02-20T20:00:00.417Z",
+				"duration": "45m"
+			}`),
+	})
+	testCases := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedIDs  []string
+	}{
+		{
+			name:         "prefix ranked search",
+			query:        "календ",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []string{events[1].ID, events[0].ID},
+		}, {
+			name:         "all words required",
+			query:        "zoom календ",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []string{events[0].ID},
+		}, {
+			name:         "nothing found",
+			query:        "отпуск",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []string{},
+		}, {
+			name:         "empty query",
+			query:        " ,. ",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		es.Suite.Run(tc.name, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			requestURL := fmt.Sprintf("%s/events/search?q=%s", es.testServer.URL, url.QueryEscape(tc.query))
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+			es.Suite.Require().NoError(err)
+			req.Header.Set("Authorization", ValidUserEmail)
+
+			res, err := http.DefaultClient.Do(req)
+			es.Suite.Require().NoError(err)
+			defer func() {
+				_ = res.Body.Close()
+			}()
+			es.Suite.Require().Equal(tc.expectedCode, res.StatusCode)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			var resp []dto.EventFound
+			err = json.NewDecoder(res.Body).Decode(&resp)
+			es.Suite.Require().NoError(err)
+			actualIDs := make([]string, 0)
+			for _, found := range resp {
+				es.Suite.Require().Contains(found.Snippet, "<b>")
+				actualIDs = append(actualIDs, found.ID)
+			}
+			es.Suite.Require().Equal(tc.expectedIDs, actualIDs)
+		})
+	}
+}
+
+func (es *EventsSuiteTest) TestTags() {
+	doRequest, decodeResp := es.doRequest, es.decodeResp
+	addTag := func(input string) dto.Tag {
+		code, body := doRequest(http.MethodPost, "/tags", []byte(input))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var tag dto.Tag
+		es.Suite.Require().NoError(json.Unmarshal(decodeResp(body).Data, &tag))
+		return tag
+	}
+	work := addTag(`{"name": "Работа", "color": "#FF0000"}`)
+	home := addTag(`{"name": "Дом", "color": "#00ff00"}`)
+
+	es.Suite.Run("tag validation", func() {
+		code, body := doRequest(http.MethodPost, "/tags", []byte(`{"name": "", "color": "red"}`))
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		resp := decodeResp(body)
+		es.Suite.Require().Contains(resp.Errors, "Name")
+		es.Suite.Require().Contains(resp.Errors, "Color")
+
+		code, body = doRequest(http.MethodPost, "/tags", []byte(`{"name": "работа", "color": "#000000"}`))
+		es.Suite.Require().Equal(http.StatusBadRequest, code)
+		resp = decodeResp(body)
+		es.Suite.Require().Equal(model.ErrTagNameBusyCode, resp.Code)
+	})
+
+	events := addEvents(es, [][]byte{
+		[]byte(fmt.Sprintf(`{
+				"title": "Test event 1",
+				"date": "2023-02-19T20:00:00.417Z",
+				"duration": "45m",
+				"tagIds": ["%s"]
+			}`, work.ID)),
+		[]byte(fmt.Sprintf(`{
+				"title": "Test event 2",
+				"date": "2023-02-20T20:00:00.417Z",
+				"duration": "45m",
+				"tagIds": ["%s", "%s"]
+			}`, work.ID, home.ID)),
+		[]byte(`{
+				"title": "Test event 3",
+				"date": "2023-02-21T20:00:00.417Z",
+				"duration": "45m"
+			}`),
+	})
+	es.Suite.Require().Equal([]dto.Tag{work}, events[0].Tags)
+	es.Suite.Require().Empty(events[2].Tags)
+
+	listIDs := func(tags ...string) []string {
+		query := url.Values{}
+		query.Set("date", "2023-02-01T00:00:00Z")
+		if len(tags) > 0 {
+			query.Set("tags", strings.Join(tags, ","))
+		}
+		code, body := doRequest(http.MethodGet, "/events/list/month?"+query.Encode(), nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var list []dto.Event
+		es.Suite.Require().NoError(json.Unmarshal(body, &list))
+		ids := make([]string, 0, len(list))
+		for _, event := range list {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+
+	es.Suite.Run("filter by tags", func() {
+		es.Suite.Require().ElementsMatch([]string{events[0].ID, events[1].ID, events[2].ID}, listIDs())
+		es.Suite.Require().ElementsMatch([]string{events[0].ID, events[1].ID}, listIDs(work.ID))
+		es.Suite.Require().ElementsMatch([]string{events[1].ID}, listIDs(home.ID))
+	})
+
+	es.Suite.Run("unknown tag on event", func() {
+		code, body := doRequest(
+			http.MethodPut,
+			fmt.Sprintf("/events/%s", events[2].ID),
+			[]byte(fmt.Sprintf(`{"tagIds": ["%s"]}`, uuid.New().String())),
+		)
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		es.Suite.Require().Contains(decodeResp(body).Errors, "TagIDs")
+	})
+
+	es.Suite.Run("rename and delete tag", func() {
+		code, _ := doRequest(http.MethodPut, fmt.Sprintf("/tags/%s", home.ID), []byte(`{"color": "#0000FF"}`))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		code, body := doRequest(http.MethodGet, fmt.Sprintf("/events/%s", events[1].ID), nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var event dto.Event
+		es.Suite.Require().NoError(json.Unmarshal(body, &event))
+		es.Suite.Require().Equal(2, len(event.Tags))
+
+		code, _ = doRequest(http.MethodDelete, fmt.Sprintf("/tags/%s", work.ID), nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		code, body = doRequest(http.MethodGet, fmt.Sprintf("/events/%s", events[1].ID), nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		event = dto.Event{}
+		es.Suite.Require().NoError(json.Unmarshal(body, &event))
+		es.Suite.Require().Equal([]dto.Tag{{ID: home.ID, Name: home.Name, Color: "#0000FF"}}, event.Tags)
+		es.Suite.Require().Empty(listIDs(work.ID))
+	})
+}
+
+func (es *EventsSuiteTest) TestBatch() {
+	existing := addEvents(es, [][]byte{
+		[]byte(`{
+				"title": "Existing event",
+				"date": "2023-03-01T10:00:00Z",
+				"duration": "1h"
+			}`),
+	})
+	doBatch := func(method, resource, body string) (int, dto.BatchReport, ErrorResponseDTO) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		requestURL := fmt.Sprintf("%s%s", es.testServer.URL, resource)
+		req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewBufferString(body))
+		es.Suite.Require().NoError(err)
+		req.Header.Set("Authorization", ValidUserEmail)
+
+		res, err := http.DefaultClient.Do(req)
+		es.Suite.Require().NoError(err)
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		var (
+			resp   ErrorResponseDTO
+			report dto.BatchReport
+		)
+		es.Suite.Require().NoError(json.NewDecoder(res.Body).Decode(&resp))
+		if len(resp.Data) > 0 {
+			es.Suite.Require().NoError(json.Unmarshal(resp.Data, &report))
+		}
+		return res.StatusCode, report, resp
+	}
+	errorCode := func(result dto.BatchResult) int {
+		es.Suite.Require().False(result.Success)
+		raw, err := json.Marshal(result.Error)
+		es.Suite.Require().NoError(err)
+		var resp ErrorResponseDTO
+		es.Suite.Require().NoError(json.Unmarshal(raw, &resp))
+		return resp.Code
+	}
+	createBody := func(mode string) string {
+		return fmt.Sprintf(`{"mode": "%s", "items": [
+			{"title": "Sprint 1", "date": "2023-03-02T10:00:00Z", "duration": "1h"},
+			{"title": "Sprint 2", "date": "2023-03-02T10:30:00Z", "duration": "1h"},
+			{"title": "Sprint 3", "date": "2023-03-01T10:15:00Z", "duration": "15m"},
+			{"title": "", "date": "2023-03-03T10:00:00Z", "duration": "1h"},
+			{"title": "Sprint 5", "date": "2023-03-04T10:00:00Z", "duration": "1h"}
+		]}`, mode)
+	}
+	var created []string
+
+	es.Suite.Run("atomic create rejected", func() {
+		code, report, _ := doBatch(http.MethodPost, "/events/batch", createBody("atomic"))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal(0, report.Applied)
+		es.Suite.Require().Equal(5, len(report.Results))
+		es.Suite.Require().Equal(model.ErrEventBatchRejectCode, errorCode(report.Results[0]))
+		es.Suite.Require().Equal(model.ErrEventBatchOverlapCode, errorCode(report.Results[1]))
+		es.Suite.Require().Equal(model.ErrEventDateBusyCode, errorCode(report.Results[2]))
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, errorCode(report.Results[3]))
+		es.Suite.Require().Equal(model.ErrEventBatchRejectCode, errorCode(report.Results[4]))
+	})
+
+	es.Suite.Run("best effort create", func() {
+		code, report, _ := doBatch(http.MethodPost, "/events/batch", createBody("bestEffort"))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal(2, report.Applied)
+		for _, i := range []int{0, 4} {
+			es.Suite.Require().True(report.Results[i].Success)
+			es.Suite.Require().NotNil(report.Results[i].Event)
+			created = append(created, report.Results[i].EventID)
+		}
+		es.Suite.Require().Equal("Sprint 5", report.Results[4].Event.Title)
+	})
+
+	es.Suite.Run("malformed item rejects request", func() {
+		code, _, resp := doBatch(http.MethodPost, "/events/batch", `{"items": [
+			{"title": "Sprint", "date": "tomorrow", "duration": "1h"}
+		]}`)
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		es.Suite.Require().Contains(resp.Errors, "items[0].date")
+	})
+
+	es.Suite.Run("batch size", func() {
+		code, _, resp := doBatch(http.MethodPost, "/events/batch", `{"items": []}`)
+		es.Suite.Require().Equal(http.StatusBadRequest, code)
+		es.Suite.Require().Equal(model.ErrEventBatchSizeCode, resp.Code)
+	})
+
+	es.Suite.Run("swap events in one batch", func() {
+		// события меняются местами: по отдельности каждое изменение заняло бы дату другого.
+		code, report, _ := doBatch(http.MethodPut, "/events/batch", fmt.Sprintf(`{"items": [
+			{"id": "%s", "date": "2023-03-04T10:00:00Z"},
+			{"id": "%s", "date": "2023-03-02T10:00:00Z"}
+		]}`, created[0], created[1]))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal(2, report.Applied)
+	})
+
+	es.Suite.Run("atomic update rejected", func() {
+		code, report, _ := doBatch(http.MethodPut, "/events/batch", fmt.Sprintf(`{"items": [
+			{"id": "%s", "title": "Renamed"},
+			{"id": "%s", "date": "2023-03-01T10:30:00Z"},
+			{"id": "%s", "title": "Unknown"}
+		]}`, created[0], created[1], uuid.New().String()))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal(0, report.Applied)
+		es.Suite.Require().Equal(model.ErrEventBatchRejectCode, errorCode(report.Results[0]))
+		es.Suite.Require().Equal(model.ErrEventDateBusyCode, errorCode(report.Results[1]))
+		es.Suite.Require().Equal(http.StatusNotFound, errorCode(report.Results[2]))
+	})
+
+	es.Suite.Run("best effort keeps rejected event in place", func() {
+		// первое изменение отклоняется, событие остается на прежнем месте, и второе
+		// событие не может занять его интервал.
+		code, report, _ := doBatch(http.MethodPut, "/events/batch", fmt.Sprintf(`{"mode": "bestEffort", "items": [
+			{"id": "%s", "title": "", "date": "2023-03-05T10:00:00Z"},
+			{"id": "%s", "date": "2023-03-04T10:30:00Z"}
+		]}`, created[0], created[1]))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal(0, report.Applied)
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, errorCode(report.Results[0]))
+		es.Suite.Require().Equal(model.ErrEventDateBusyCode, errorCode(report.Results[1]))
+
+		code, body := es.doRequest(http.MethodGet, "/events/"+created[1], nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var event dto.Event
+		es.Suite.Require().NoError(json.Unmarshal(body, &event))
+		es.Suite.Require().Equal("2023-03-02T10:00:00Z", event.Date.UTC().Format(time.RFC3339))
+	})
+
+	es.Suite.Run("best effort delete", func() {
+		code, report, _ := doBatch(http.MethodPost, "/events/batch/delete", fmt.Sprintf(
+			`{"mode": "bestEffort", "ids": ["%s", "%s", "%s", "%s"]}`,
+			created[0], existing[0].ID, created[0], uuid.New().String(),
+		))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		es.Suite.Require().Equal(2, report.Applied)
+		es.Suite.Require().True(report.Results[0].Success)
+		es.Suite.Require().True(report.Results[1].Success)
+		es.Suite.Require().Equal(model.ErrEventBatchDuplCode, errorCode(report.Results[2]))
+		es.Suite.Require().Equal(http.StatusNotFound, errorCode(report.Results[3]))
+	})
+}
+
+func (es *EventsSuiteTest) TestDigest() {
+	doRequest, decodeResp := es.doRequest, es.decodeResp
+
+	es.Suite.Run("defaults", func() {
+		code, body := doRequest(http.MethodGet, "/digest", nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var settings dto.Digest
+		es.Suite.Require().NoError(json.Unmarshal(body, &settings))
+		es.Suite.Require().Equal(dto.Digest{Mode: "off", SendAt: "08:00", Weekday: "monday"}, settings)
+	})
+
+	es.Suite.Run("validation", func() {
+		code, body := doRequest(http.MethodPut, "/digest", []byte(`{
+			"mode": "hourly",
+			"sendAt": "25:00",
+			"weekday": "someday"
+		}`))
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		resp := decodeResp(body)
+		es.Suite.Require().Contains(resp.Errors, "mode")
+		es.Suite.Require().Contains(resp.Errors, "sendAt")
+		es.Suite.Require().Contains(resp.Errors, "weekday")
+
+		code, body = doRequest(http.MethodPut, "/digest", []byte(`{"mode": "daily", "sendAt": "24:00"}`))
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		es.Suite.Require().Contains(decodeResp(body).Errors, "SendAt")
+	})
+
+	es.Suite.Run("opt in and out", func() {
+		code, _ := doRequest(http.MethodPut, "/digest", []byte(`{
+			"mode": "weekly",
+			"sendAt": "07:45",
+			"weekday": "friday"
+		}`))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		code, body := doRequest(http.MethodGet, "/digest", nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var settings dto.Digest
+		es.Suite.Require().NoError(json.Unmarshal(body, &settings))
+		es.Suite.Require().Equal(dto.Digest{Mode: "weekly", SendAt: "07:45", Weekday: "friday"}, settings)
+
+		code, _ = doRequest(http.MethodPut, "/digest", []byte(`{"mode": "off"}`))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		_, body = doRequest(http.MethodGet, "/digest", nil)
+		es.Suite.Require().NoError(json.Unmarshal(body, &settings))
+		es.Suite.Require().Equal("off", settings.Mode)
+	})
+}
+
+func (es *EventsSuiteTest) TestProfile() {
+	doRequest, decodeResp := es.doRequest, es.decodeResp
+
+	code, body := doRequest(http.MethodGet, "/profile", nil)
+	es.Suite.Require().Equal(http.StatusOK, code)
+	var user dto.User
+	es.Suite.Require().NoError(json.Unmarshal(body, &user))
+	es.Suite.Require().Equal(ValidUserEmail, user.Email)
+	es.Suite.Require().Equal("", user.Locale)
+
+	code, body = doRequest(http.MethodPut, "/profile", []byte(`{"locale": "english"}`))
+	es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+	es.Suite.Require().Contains(decodeResp(body).Errors, "Locale")
+
+	code, _ = doRequest(http.MethodPut, "/profile", []byte(`{"locale": "en-US"}`))
+	es.Suite.Require().Equal(http.StatusOK, code)
+	_, body = doRequest(http.MethodGet, "/profile", nil)
+	es.Suite.Require().NoError(json.Unmarshal(body, &user))
+	es.Suite.Require().Equal("en-US", user.Locale)
+	es.Suite.Require().Equal(ValidUserEmail, user.Name)
+}
+
+func (es *EventsSuiteTest) TestAvailability() {
+	doRequest, decodeResp := es.doRequest, es.decodeResp
+
+	es.Suite.Run("defaults", func() {
+		code, body := doRequest(http.MethodGet, "/availability", nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var settings dto.Availability
+		es.Suite.Require().NoError(json.Unmarshal(body, &settings))
+		es.Suite.Require().Equal(dto.Availability{TimeZone: "UTC", Policy: "ignore", Hours: []dto.WorkingHours{}}, settings)
+	})
+
+	es.Suite.Run("settings validation", func() {
+		code, body := doRequest(http.MethodPut, "/availability", []byte(`{
+			"timeZone": "Mars/Olympus",
+			"policy": "reject",
+			"hours": [{"weekday": "monday", "start": "18:00", "end": "09:00"}]
+		}`))
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		resp := decodeResp(body)
+		es.Suite.Require().Contains(resp.Errors, "TimeZone")
+		es.Suite.Require().Contains(resp.Errors, "Hours")
+
+		code, body = doRequest(http.MethodPut, "/availability", []byte(`{
+			"timeZone": "UTC",
+			"hours": [{"weekday": "someday", "start": "9:00", "end": "18:00"}]
+		}`))
+		es.Suite.Require().Equal(http.StatusUnprocessableEntity, code)
+		es.Suite.Require().Contains(decodeResp(body).Errors, "hours[0].weekday")
+	})
+
+	// понедельник 2023-03-06, рабочее время 09:00-13:00 и 14:00-18:00 по Москве (UTC+3).
+	code, _ := doRequest(http.MethodPut, "/availability", []byte(`{
+		"timeZone": "Europe/Moscow",
+		"policy": "reject",
+		"hours": [
+			{"weekday": "monday", "start": "09:00", "end": "13:00"},
+			{"weekday": "monday", "start": "14:00", "end": "18:00"}
+		]
+	}`))
+	es.Suite.Require().Equal(http.StatusOK, code)
+
+	es.Suite.Run("reject outside hours", func() {
+		code, body := doRequest(http.MethodPost, "/events", []byte(`{
+			"title": "Поздний созвон",
+			"date": "2023-03-06T17:30:00+03:00",
+			"duration": "1h"
+		}`))
+		es.Suite.Require().Equal(http.StatusBadRequest, code)
+		es.Suite.Require().Equal(model.ErrEventOutOfHoursCode, decodeResp(body).Code)
+	})
+
+	events := addEvents(es, [][]byte{
+		[]byte(`{
+				"title": "Планерка",
+				"date": "2023-03-06T07:00:00Z",
+				"duration": "30m"
+			}`),
+	})
+	es.Suite.Require().Empty(events[0].Warnings)
+
+	code, body := doRequest(http.MethodPost, "/availability/ooo", []byte(`{
+		"dateFrom": "2023-03-06T16:00:00+03:00",
+		"dateTo": "2023-03-07T00:00:00+03:00",
+		"reason": "врач"
+	}`))
+	es.Suite.Require().Equal(http.StatusOK, code)
+	var ooo dto.OutOfOffice
+	es.Suite.Require().NoError(json.Unmarshal(decodeResp(body).Data, &ooo))
+
+	es.Suite.Run("free slots", func() {
+		code, body := doRequest(http.MethodGet, "/availability/slots?date=2023-03-06", nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var slots []dto.Slot
+		es.Suite.Require().NoError(json.Unmarshal(body, &slots))
+		expected := [][2]string{
+			{"2023-03-06T09:00:00+03:00", "2023-03-06T10:00:00+03:00"},
+			{"2023-03-06T10:30:00+03:00", "2023-03-06T13:00:00+03:00"},
+			{"2023-03-06T14:00:00+03:00", "2023-03-06T16:00:00+03:00"},
+		}
+		es.Suite.Require().Len(slots, len(expected))
+		for i, slot := range slots {
+			start, _ := time.Parse(time.RFC3339, expected[i][0])
+			end, _ := time.Parse(time.RFC3339, expected[i][1])
+			es.Suite.Require().True(start.Equal(slot.Start), slot.Start.String())
+			es.Suite.Require().True(end.Equal(slot.End), slot.End.String())
+		}
+	})
+
+	es.Suite.Run("warn policy", func() {
+		code, _ := doRequest(http.MethodPut, "/availability", []byte(`{
+			"timeZone": "Europe/Moscow",
+			"policy": "warn",
+			"hours": [{"weekday": "monday", "start": "09:00", "end": "18:00"}]
+		}`))
+		es.Suite.Require().Equal(http.StatusOK, code)
+		// событие попадает на период отсутствия.
+		warned := addEvents(es, [][]byte{
+			[]byte(`{
+				"title": "Созвон",
+				"date": "2023-03-06T16:30:00+03:00",
+				"duration": "30m"
+			}`),
+		})
+		es.Suite.Require().Equal([]string{model.ErrEventOutOfHours.Error()}, warned[0].Warnings)
+	})
+
+	es.Suite.Run("delete out of office", func() {
+		code, body := doRequest(http.MethodGet, "/availability/ooo", nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		var absences []dto.OutOfOffice
+		es.Suite.Require().NoError(json.Unmarshal(body, &absences))
+		es.Suite.Require().Len(absences, 1)
+		es.Suite.Require().Equal("врач", absences[0].Reason)
+
+		code, _ = doRequest(http.MethodDelete, fmt.Sprintf("/availability/ooo/%s", ooo.ID), nil)
+		es.Suite.Require().Equal(http.StatusOK, code)
+		code, _ = doRequest(http.MethodDelete, fmt.Sprintf("/availability/ooo/%s", ooo.ID), nil)
+		es.Suite.Require().Equal(http.StatusNotFound, code)
+	})
+}
+
 func TestEventsApi(t *testing.T) {
 	suite.Run(t, new(EventsSuiteTest))
 }
@@ -566,3 +1191,20 @@ func addEvents(es *EventsSuiteTest, jsonBodies [][]byte) []dto.Event {
 
 	return result
 }
+
+// doRequest запрос от имени авторизованного пользователя, возвращает код и тело ответа.
+func (es *EventsSuiteTest) doRequest(method, resource string, body []byte) (int, []byte) {
+	return es.doRequestHeader(method, resource, body, nil)
+}
+
+// doRequestHeader запрос с дополнительными заголовками.
+func (es *EventsSuiteTest) doRequestHeader(method, resource string, body []byte, header http.Header) (int, []byte) {
+	res, resBody := sendRequest(es.T(), es.testServer, method, resource, ValidUserEmail, body, header)
+	return res.StatusCode, resBody
+}
+
+func (es *EventsSuiteTest) decodeResp(body []byte) ErrorResponseDTO {
+	var resp ErrorResponseDTO
+	es.Suite.Require().NoError(json.Unmarshal(body, &resp))
+	return resp
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+)
+
+func TestEventExport(t *testing.T) {
+	server := newTestServer(t, config.Server{})
+	for _, date := range []string{"2023-03-01T09:00:00Z", "2023-03-10T09:00:00Z", "2023-04-01T09:00:00Z"} {
+		res, _ := apiRequest(t, server, http.MethodPost, "/events", ValidUserEmail, []byte(`{
+			"title": "Событие",
+			"date": "`+date+`",
+			"duration": "30m"
+		}`))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	}
+	export := func(query, accept string) (*http.Response, []byte) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events/export"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", ValidUserEmail)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, body
+	}
+
+	t.Run("json", func(t *testing.T) {
+		res, body := export("", "")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, res.Header.Get("Content-Type"), "application/json")
+		var events []dto.Event
+		require.NoError(t, json.Unmarshal(body, &events))
+		require.Len(t, events, 3)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		res, body := export("?from=2023-03-01T00:00:00Z&to=2023-03-31T00:00:00Z", "application/x-ndjson")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, res.Header.Get("Content-Type"), "application/x-ndjson")
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		count := 0
+		for scanner.Scan() {
+			var event dto.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			require.Equal(t, "Событие", event.Title)
+			count++
+		}
+		require.Equal(t, 2, count)
+
+		// параметр format важнее заголовка Accept.
+		res, _ = export("?format=json", "application/x-ndjson")
+		require.Contains(t, res.Header.Get("Content-Type"), "application/json")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		res, body := export("?format=csv&from=вчера", "")
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		var resp ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Contains(t, resp.Errors, "format")
+		require.Contains(t, resp.Errors, "from")
+
+		res, _ = export("?from=2023-03-10T00:00:00Z&to=2023-03-01T00:00:00Z", "")
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
+}
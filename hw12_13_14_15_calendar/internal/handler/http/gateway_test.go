@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+)
+
+// gatewayEvent событие в ответе REST-фасада, имена полей совпадают с proto.
+type gatewayEvent struct {
+	ID           string
+	Title        string
+	Duration     string
+	NotifyStatus string
+	Owner        *struct {
+		Email string
+	}
+}
+
+func (es *EventsSuiteTest) TestGateway() {
+	code, body := es.doRequest(http.MethodPost, "/v2/events", []byte(`{
+		"Title": "Планерка",
+		"Date": "2023-03-06T09:00:00Z",
+		"Duration": "1800s"
+	}`))
+	es.Require().Equal(http.StatusOK, code, string(body))
+	var created gatewayEvent
+	es.Require().NoError(json.Unmarshal(body, &created))
+	es.Require().Equal("Планерка", created.Title)
+	es.Require().Equal("1800s", created.Duration)
+	es.Require().Equal("none", created.NotifyStatus)
+
+	es.Run("parity with v1", func() {
+		code, body := es.doRequest(http.MethodGet, "/v2/events/"+created.ID, nil)
+		es.Require().Equal(http.StatusOK, code)
+		var v2 gatewayEvent
+		es.Require().NoError(json.Unmarshal(body, &v2))
+
+		code, body = es.doRequest(http.MethodGet, "/events/"+created.ID, nil)
+		es.Require().Equal(http.StatusOK, code)
+		var v1 dto.Event
+		es.Require().NoError(json.Unmarshal(body, &v1))
+
+		es.Require().Equal(v1.ID, v2.ID)
+		es.Require().Equal(v1.Title, v2.Title)
+		es.Require().Equal(v1.NotifyStatus, v2.NotifyStatus)
+		es.Require().NotNil(v2.Owner)
+		es.Require().Equal(v1.Owner.Email, v2.Owner.Email)
+	})
+
+	es.Run("query parameters", func() {
+		query := url.Values{"Date": {"2023-03-06T12:00:00Z"}, "RangeType": {"RANGE_TYPE_DAY"}}
+		code, body := es.doRequest(http.MethodGet, "/v2/events/list?"+query.Encode(), nil)
+		es.Require().Equal(http.StatusOK, code, string(body))
+		var list struct {
+			List []gatewayEvent
+		}
+		es.Require().NoError(json.Unmarshal(body, &list))
+		es.Require().Len(list.List, 1)
+		es.Require().Equal(created.ID, list.List[0].ID)
+	})
+
+	es.Run("errors in rqres format", func() {
+		code, body := es.doRequest(http.MethodPost, "/v2/events", []byte(`{"Title": "", "Duration": "1800s"}`))
+		// ошибки полей передаются в деталях статуса и возвращаются так же, как в v1.
+		es.Require().Equal(http.StatusUnprocessableEntity, code)
+		resp := es.decodeResp(body)
+		es.Require().Equal("error", resp.Status)
+		es.Require().Contains(resp.Errors, "Title")
+
+		code, body = es.doRequest(http.MethodGet, "/v2/events/00000000-0000-0000-0000-000000000001", nil)
+		es.Require().Equal(http.StatusNotFound, code)
+		es.Require().Equal("error", es.decodeResp(body).Status)
+
+		code, _ = es.doRequest(http.MethodGet, "/v2/unknown", nil)
+		es.Require().Equal(http.StatusNotFound, code)
+	})
+
+	es.Run("support is not exposed", func() {
+		// внутренний API доступен только по gRPC, авторизованный пользователь его не вызовет.
+		code, _ := es.doRequest(http.MethodGet, "/v2/support/notifications/pending", nil)
+		es.Require().Equal(http.StatusNotFound, code)
+		code, _ = es.doRequest(http.MethodPost, "/v2/support/cleanup", []byte(`{"StoreTime": "1s"}`))
+		es.Require().Equal(http.StatusNotFound, code)
+	})
+
+	es.Run("auth required", func() {
+		res, err := http.Get(es.testServer.URL + "/v2/tags") //nolint:noctx // короткий запрос в тесте.
+		es.Require().NoError(err)
+		_ = res.Body.Close()
+		es.Require().Equal(http.StatusUnauthorized, res.StatusCode)
+	})
+}
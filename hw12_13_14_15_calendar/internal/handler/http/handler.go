@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"fmt"
 
 	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
@@ -13,18 +14,35 @@ type Handler struct {
 	logger   logger.Logger
 }
 
-func (h Handler) handleError(action string, err error) rqres.Response {
+func (h Handler) handleError(ctx context.Context, action string, err error) rqres.Response {
 	logErr := fmt.Errorf("%s - %w", action, err)
-	h.logger.Error(logErr.Error())
+	h.log(ctx).Error(logErr.Error())
 	return rqres.FromError(err)
 }
 
+// log логгер с полями запроса: идентификатором запроса и пользователем.
+func (h Handler) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
+
 type Handlers struct {
-	Events *Events
+	Events       *Events
+	Tags         *Tags
+	Resources    *Resources
+	Attachments  *Attachments
+	Availability *Availability
+	Digest       *Digest
+	Profile      *Profile
 }
 
 func NewHandlers(services *deps.Services, logger logger.Logger) *Handlers {
 	return &Handlers{
-		Events: &Events{&Handler{services: services, logger: logger}},
+		Events:       &Events{&Handler{services: services, logger: logger}},
+		Tags:         &Tags{&Handler{services: services, logger: logger}},
+		Resources:    &Resources{&Handler{services: services, logger: logger}},
+		Attachments:  &Attachments{&Handler{services: services, logger: logger}},
+		Availability: &Availability{&Handler{services: services, logger: logger}},
+		Digest:       &Digest{&Handler{services: services, logger: logger}},
+		Profile:      &Profile{&Handler{services: services, logger: logger}},
 	}
 }
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+func (es *EventsSuiteTest) TestIdempotency() {
+	body := []byte(`{
+		"title": "Созвон с мобильного",
+		"date": "2023-03-10T09:00:00Z",
+		"duration": "30m"
+	}`)
+	create := func(key string, body []byte) (int, ErrorResponseDTO) {
+		code, resp := es.doRequestHeader(http.MethodPost, "/events", body, http.Header{
+			IdempotencyKeyHeader: []string{key},
+		})
+		return code, es.decodeResp(resp)
+	}
+	eventID := func(resp ErrorResponseDTO) string {
+		var event dto.Event
+		es.Require().NoError(json.Unmarshal(resp.Data, &event))
+		return event.ID
+	}
+
+	code, resp := create("retry-1", body)
+	es.Require().Equal(http.StatusOK, code)
+	createdID := eventID(resp)
+
+	es.Run("retry returns stored event", func() {
+		code, resp := create("retry-1", body)
+		es.Require().Equal(http.StatusOK, code, resp.Message)
+		es.Require().Equal(createdID, eventID(resp))
+
+		code, raw := es.doRequest(http.MethodGet, "/events/list/month?date=2023-03-01T00:00:00Z", nil)
+		es.Require().Equal(http.StatusOK, code)
+		var list []dto.Event
+		es.Require().NoError(json.Unmarshal(raw, &list))
+		es.Require().Len(list, 1)
+	})
+
+	es.Run("key reused with other params", func() {
+		code, resp := create("retry-1", []byte(`{
+			"title": "Другое событие",
+			"date": "2023-03-11T09:00:00Z",
+			"duration": "30m"
+		}`))
+		es.Require().Equal(http.StatusBadRequest, code)
+		es.Require().Equal(model.ErrIdempotencyKeyReusedCode, resp.Code)
+	})
+
+	es.Run("without key", func() {
+		code, resp := create("", body)
+		es.Require().Equal(http.StatusBadRequest, code)
+		es.Require().Equal(model.ErrEventDateBusyCode, resp.Code)
+	})
+
+	es.Run("failed request is stored", func() {
+		code, _ := create("retry-2", body)
+		es.Require().Equal(http.StatusBadRequest, code)
+		// время освободилось, но повтор получает сохраненную ошибку без выполнения запроса.
+		code, raw := es.doRequest(http.MethodDelete, "/events/"+createdID, nil)
+		es.Require().Equal(http.StatusOK, code, string(raw))
+		code, resp := create("retry-2", body)
+		es.Require().Equal(http.StatusBadRequest, code)
+		es.Require().Equal(model.ErrEventDateBusyCode, resp.Code)
+
+		code, resp = create("retry-2", []byte(`{
+			"title": "Созвон с мобильного",
+			"date": "2023-03-12T09:00:00Z",
+			"duration": "30m"
+		}`))
+		es.Require().Equal(http.StatusBadRequest, code)
+		es.Require().Equal(model.ErrIdempotencyKeyReusedCode, resp.Code)
+	})
+
+	es.Run("concurrent duplicates", func() {
+		const n = 5
+		concurrentBody := []byte(`{
+			"title": "Параллельный повтор",
+			"date": "2023-03-13T09:00:00Z",
+			"duration": "30m"
+		}`)
+		var (
+			wg  sync.WaitGroup
+			ids = make([]string, n)
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				code, resp := create("retry-3", concurrentBody)
+				if code == http.StatusOK {
+					var event dto.Event
+					if json.Unmarshal(resp.Data, &event) == nil {
+						ids[i] = event.ID
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, id := range ids {
+			es.Require().NotEmpty(id)
+			es.Require().Equal(ids[0], id)
+		}
+	})
+
+	es.Run("gateway forwards key", func() {
+		gatewayBody := []byte(`{
+			"Title": "Через фасад",
+			"Date": "2023-03-14T09:00:00Z",
+			"Duration": "1800s"
+		}`)
+		header := http.Header{IdempotencyKeyHeader: []string{"retry-4"}}
+		code, first := es.doRequestHeader(http.MethodPost, "/v2/events", gatewayBody, header)
+		es.Require().Equal(http.StatusOK, code, string(first))
+		code, second := es.doRequestHeader(http.MethodPost, "/v2/events", gatewayBody, header)
+		es.Require().Equal(http.StatusOK, code, string(second))
+		var e1, e2 gatewayEvent
+		es.Require().NoError(json.Unmarshal(first, &e1))
+		es.Require().NoError(json.Unmarshal(second, &e2))
+		es.Require().Equal(e1.ID, e2.ID)
+	})
+}
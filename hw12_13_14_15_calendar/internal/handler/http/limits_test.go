@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := newTestServer(t, config.Server{RateLimit: ratelimit.Config{
+		PerUser: ratelimit.Budget{
+			Read:  ratelimit.Limit{Rate: 0.01, Burst: 2},
+			Write: ratelimit.Limit{Rate: 0.01, Burst: 1},
+		},
+		PerIP: ratelimit.Budget{
+			Read: ratelimit.Limit{Rate: 0.01, Burst: 4},
+		},
+	}})
+
+	t.Run("per user read and write", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			res, _ := apiRequest(t, server, http.MethodGet, "/tags", ValidUserEmail, nil)
+			require.Equal(t, http.StatusOK, res.StatusCode)
+		}
+		res, resp := apiRequest(t, server, http.MethodGet, "/tags", ValidUserEmail, nil)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "100", res.Header.Get("Retry-After"))
+		require.Equal(t, http.StatusTooManyRequests, resp.Code)
+		require.JSONEq(t, `{"retryAfter": 100}`, string(resp.Data))
+
+		// бюджет записи расходуется отдельно.
+		tag := []byte(`{"name": "Работа", "color": "#FF0000"}`)
+		res, _ = apiRequest(t, server, http.MethodPost, "/tags", ValidUserEmail, tag)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		tag = []byte(`{"name": "Дом", "color": "#00FF00"}`)
+		res, _ = apiRequest(t, server, http.MethodPost, "/tags", ValidUserEmail, tag)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	})
+
+	t.Run("per ip before auth", func(t *testing.T) {
+		// три запроса на чтение уже выполнены пользователем с этого адреса.
+		res, _ := apiRequest(t, server, http.MethodGet, "/tags", "", nil)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		res, _ = apiRequest(t, server, http.MethodGet, "/tags", "", nil)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.NotEmpty(t, res.Header.Get("Retry-After"))
+	})
+}
+
+func TestEventQuota(t *testing.T) {
+	server := newTestServer(t, config.Server{}, calendartest.WithEventQuota(2))
+	create := func(date string) (*http.Response, ErrorResponseDTO) {
+		return apiRequest(t, server, http.MethodPost, "/events", ValidUserEmail, []byte(`{
+			"title": "Событие",
+			"date": "`+date+`",
+			"duration": "30m"
+		}`))
+	}
+	res, _ := create("2023-03-01T09:00:00Z")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	res, first := create("2023-03-02T09:00:00Z")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, resp := create("2023-03-03T09:00:00Z")
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	require.Equal(t, model.ErrEventQuotaCode, resp.Code)
+
+	res, resp = apiRequest(t, server, http.MethodPost, "/events/batch", ValidUserEmail, []byte(`{
+		"items": [{"title": "Пакет", "date": "2023-03-04T09:00:00Z", "duration": "30m"}]
+	}`))
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	require.Equal(t, model.ErrEventQuotaCode, resp.Code)
+
+	// события в корзине учитываются в квоте.
+	var event struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(first.Data, &event))
+	res, _ = apiRequest(t, server, http.MethodDelete, "/events/"+event.ID, ValidUserEmail, nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	res, resp = create("2023-03-03T09:00:00Z")
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	require.Equal(t, model.ErrEventQuotaCode, resp.Code)
+}
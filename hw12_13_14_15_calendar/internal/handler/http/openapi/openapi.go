@@ -0,0 +1,41 @@
+// Package openapi спецификация REST API календаря и страница документации.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+const (
+	SpecPath = "/openapi.yaml"
+	DocsPath = "/docs"
+)
+
+// Spec спецификация OpenAPI 3, при изменении маршрутов REST-сервера ее нужно
+// обновлять - расхождение проверяет тест handler/http.
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+// SpecHandler отдает спецификацию.
+func SpecHandler() http.Handler {
+	return staticHandler("application/yaml; charset=utf-8", Spec)
+}
+
+// DocsHandler отдает страницу Swagger UI для спецификации по адресу SpecPath.
+func DocsHandler() http.Handler {
+	return staticHandler("text/html; charset=utf-8", docsPage)
+}
+
+func staticHandler(contentType string, content []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(content)
+		}
+	})
+}
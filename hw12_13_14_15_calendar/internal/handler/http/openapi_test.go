@@ -0,0 +1,214 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// Контрактный тест: маршруты сервера и DTO не должны расходиться со спецификацией.
+
+type specSchema struct {
+	Ref        string                 `yaml:"$ref"`
+	Properties map[string]*specSchema `yaml:"properties"`
+	AllOf      []*specSchema          `yaml:"allOf"`
+}
+
+type specDoc struct {
+	Paths      map[string]map[string]interface{} `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*specSchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// specOperations поддерживаемые методы, остальные ключи пути (parameters и т.п.) пропускаются.
+var specOperations = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "patch": true}
+
+// specDTOs соответствие схем спецификации типам DTO.
+var specDTOs = map[string]interface{}{
+	"User":                 dto.User{},
+	"Tag":                  dto.Tag{},
+	"TagCreate":            dto.TagCreate{},
+	"TagUpdate":            dto.TagUpdate{},
+	"Resource":             dto.Resource{},
+	"ResourceCreate":       dto.ResourceCreate{},
+	"ResourceUpdate":       dto.ResourceUpdate{},
+	"ResourceBooking":      dto.ResourceBooking{},
+	"Event":                dto.Event{},
+	"EventFound":           dto.EventFound{},
+	"EventCreate":          dto.EventCreate{},
+	"EventUpdate":          dto.EventUpdate{},
+	"Link":                 dto.Link{},
+	"Attachment":           dto.Attachment{},
+	"EventBatchCreate":     dto.EventBatchCreate{},
+	"EventBatchUpdateItem": dto.EventBatchUpdateItem{},
+	"EventBatchUpdate":     dto.EventBatchUpdate{},
+	"EventBatchDelete":     dto.EventBatchDelete{},
+	"BatchResult":          dto.BatchResult{},
+	"BatchReport":          dto.BatchReport{},
+	"WorkingHours":         dto.WorkingHours{},
+	"Availability":         dto.Availability{},
+	"OutOfOfficeCreate":    dto.OutOfOfficeCreate{},
+	"OutOfOffice":          dto.OutOfOffice{},
+	"Slot":                 dto.Slot{},
+	"Digest":               dto.Digest{},
+	"Profile":              dto.Profile{},
+}
+
+func loadSpec(t *testing.T) specDoc {
+	t.Helper()
+	var doc specDoc
+	require.NoError(t, yaml.Unmarshal(openapi.Spec, &doc))
+	return doc
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	doc := loadSpec(t)
+	registered := make(map[string]bool)
+	for _, route := range newRestServer(t, config.Server{}).Routes() {
+		// HEAD обслуживается вместе с GET и отдельно не описывается.
+		if route.Method == http.MethodHead {
+			continue
+		}
+		registered[route.Method+" "+route.Path] = true
+	}
+	documented := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method := range item {
+			if specOperations[method] {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+	require.NotEmpty(t, registered)
+	require.Equal(t, sortedKeys(registered), sortedKeys(documented))
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	doc := loadSpec(t)
+	schemas := doc.Components.Schemas
+
+	t.Run("refs resolve", func(t *testing.T) {
+		var root map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(openapi.Spec, &root))
+		refs := make(map[string]bool)
+		collectRefs(root, refs)
+		require.NotEmpty(t, refs)
+		for ref := range refs {
+			require.True(t, strings.HasPrefix(ref, "#/"), ref)
+			var node interface{} = root
+			for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+				m, ok := node.(map[string]interface{})
+				require.True(t, ok, ref)
+				node, ok = m[part]
+				require.True(t, ok, ref)
+			}
+		}
+	})
+
+	for name, value := range specDTOs {
+		name, value := name, value
+		t.Run(name, func(t *testing.T) {
+			schema, ok := schemas[name]
+			require.True(t, ok, "схема %s не описана", name)
+			documented := make(map[string]bool)
+			collectProperties(schema, schemas, documented)
+			fields := make(map[string]bool)
+			collectJSONFields(reflect.TypeOf(value), fields)
+			require.Equal(t, sortedKeys(fields), sortedKeys(documented))
+		})
+	}
+}
+
+func TestOpenAPIServe(t *testing.T) {
+	server := newTestServer(t, config.Server{})
+
+	for path, contentType := range map[string]string{
+		openapi.SpecPath: "application/yaml",
+		openapi.DocsPath: "text/html",
+	} {
+		// документация доступна без заголовка Authorization.
+		res, body := sendRequest(t, server, http.MethodGet, path, "", nil, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, path)
+		require.Contains(t, res.Header.Get("Content-Type"), contentType)
+		if path == openapi.SpecPath {
+			require.Equal(t, openapi.Spec, body)
+		} else {
+			require.Contains(t, string(body), openapi.SpecPath)
+		}
+	}
+
+	// остальные маршруты по-прежнему требуют авторизации.
+	res, _ := sendRequest(t, server, http.MethodGet, "/tags", "", nil, nil)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func collectRefs(node interface{}, refs map[string]bool) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				refs[ref] = true
+				continue
+			}
+			collectRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range value {
+			collectRefs(child, refs)
+		}
+	}
+}
+
+// collectProperties свойства схемы с учетом allOf и ссылок на другие схемы.
+func collectProperties(schema *specSchema, schemas map[string]*specSchema, props map[string]bool) {
+	if schema.Ref != "" {
+		if ref, ok := schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]; ok {
+			collectProperties(ref, schemas, props)
+		}
+		return
+	}
+	for name := range schema.Properties {
+		props[name] = true
+	}
+	for _, part := range schema.AllOf {
+		collectProperties(part, schemas, props)
+	}
+}
+
+// collectJSONFields имена полей в json, включая поля встроенных структур.
+func collectJSONFields(typ reflect.Type, fields map[string]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			collectJSONFields(field.Type, fields)
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
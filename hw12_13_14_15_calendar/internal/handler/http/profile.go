@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type Profile struct {
+	*Handler
+}
+
+func (p *Profile) Get(request *rs.Request) rs.Response {
+	const actionName = "получение профиля"
+	user, err := p.services.User.GetCurrent(request.Context())
+	if err != nil {
+		return p.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromUserModel(*user))
+}
+
+func (p *Profile) Update(request *rs.Request) rs.Response {
+	const actionName = "изменение профиля"
+	var input dto.Profile
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				p.log(request.Context()).Error("изменение профиля - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return p.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	user, err := p.services.User.GetCurrent(request.Context())
+	if err != nil {
+		return p.handleError(request.Context(), actionName, err)
+	}
+	if err = p.services.User.Update(request.Context(), *user, input.Model()); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			err = errx.InvalidNew("неверные данные", errs)
+		}
+		return p.handleError(request.Context(), actionName, err)
+	}
+	return rs.OK("профиль изменен", nil)
+}
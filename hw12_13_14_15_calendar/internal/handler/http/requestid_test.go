@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
+)
+
+func TestRequestID(t *testing.T) {
+	server := newTestServer(t, config.Server{})
+	do := func(method, resource, id, body string) (*http.Response, ErrorResponseDTO) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, method, server.URL+resource, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", ValidUserEmail)
+		if id != "" {
+			req.Header.Set(requestid.Header, id)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		var resp ErrorResponseDTO
+		_ = json.NewDecoder(res.Body).Decode(&resp)
+		return res, resp
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		res, resp := do(http.MethodPost, "/events", "req-42", `{"title": ""}`)
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		require.Equal(t, "req-42", res.Header.Get(requestid.Header))
+		require.Equal(t, "req-42", resp.RequestID)
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		res, resp := do(http.MethodGet, "/events/wrong", "", "")
+		id := res.Header.Get(requestid.Header)
+		require.True(t, requestid.Valid(id))
+		require.Equal(t, id, resp.RequestID)
+
+		// недопустимый идентификатор клиента заменяется.
+		res, _ = do(http.MethodGet, "/tags", "bad id", "")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NotEqual(t, "bad id", res.Header.Get(requestid.Header))
+		require.True(t, requestid.Valid(res.Header.Get(requestid.Header)))
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		res, resp := do(http.MethodGet, "/v2/events/wrong", "req-43", "")
+		require.GreaterOrEqual(t, res.StatusCode, http.StatusBadRequest)
+		require.Equal(t, "req-43", resp.RequestID)
+	})
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type Resources struct {
+	*Handler
+}
+
+func (r *Resources) GetList(request *rs.Request) rs.Response {
+	const actionName = "получение списка ресурсов"
+	search, err := dto.ParseResourceSearch(request.URL.Query())
+	if err != nil {
+		return r.handleError(request.Context(), actionName, err)
+	}
+	resources, err := r.services.Resource.GetList(request.Context(), search)
+	if err != nil {
+		return r.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromResourceSlice(resources))
+}
+
+func (r *Resources) GetByID(request *rs.Request) rs.Response {
+	const actionName = "получение ресурса по ID"
+	resource, err := r.resource(request.Context(), request)
+	if err != nil {
+		return r.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromResourceModel(*resource))
+}
+
+func (r *Resources) Create(request *rs.Request) rs.Response {
+	const actionName = "добавление ресурса"
+	var input dto.ResourceCreate
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				r.log(request.Context()).Error("добавление ресурса - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return r.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	resource, err := r.services.Resource.Add(request.Context(), input.Model())
+	if err != nil {
+		return r.handleError(request.Context(), actionName, err)
+	}
+	r.log(request.Context()).Info("ресурс добавлен: resourceID=%s", resource.ID.String())
+	return rs.OK("ресурс добавлен", dto.FromResourceModel(*resource))
+}
+
+func (r *Resources) Update(request *rs.Request) rs.Response {
+	const actionName = "изменение ресурса"
+	var input dto.ResourceUpdate
+	ctx := request.Context()
+	resource, err := r.resource(ctx, request)
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				r.log(ctx).Error("изменение ресурса - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return r.handleError(ctx, actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	inputUpdate, vErrs := input.Model()
+	if vErrs != nil {
+		return r.handleError(ctx, actionName, errx.InvalidNew("неверные данные", vErrs))
+	}
+	if err = r.services.Resource.Update(ctx, *resource, inputUpdate); err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	r.log(ctx).Info("ресурс изменен: resourceID=%s", resource.ID.String())
+	return rs.OK("ресурс изменен", nil)
+}
+
+func (r *Resources) Delete(request *rs.Request) rs.Response {
+	const actionName = "удаление ресурса"
+	ctx := request.Context()
+	resource, err := r.resource(ctx, request)
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	if err = r.services.Resource.Delete(ctx, *resource); err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	r.log(ctx).Info("ресурс удален: resourceID=%s", resource.ID.String())
+	return rs.OK("ресурс удален", dto.FromResourceModel(*resource))
+}
+
+// Bookings бронь ресурса в периоде from..to.
+func (r *Resources) Bookings(request *rs.Request) rs.Response {
+	const actionName = "получение брони ресурса"
+	ctx := request.Context()
+	resource, err := r.resource(ctx, request)
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	period, err := dto.ParsePeriod(request.URL.Query())
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	bookings, err := r.services.Resource.Bookings(ctx, *resource, period)
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	return rs.Data(dto.FromResourceBookingSlice(bookings))
+}
+
+// Available ресурсы, свободные на всем протяжении периода from..to.
+func (r *Resources) Available(request *rs.Request) rs.Response {
+	const actionName = "поиск свободных ресурсов"
+	ctx := request.Context()
+	search, err := dto.ParseResourceSearch(request.URL.Query())
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	period, err := dto.ParsePeriod(request.URL.Query())
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	resources, err := r.services.Resource.Available(ctx, search, period)
+	if err != nil {
+		return r.handleError(ctx, actionName, err)
+	}
+	return rs.Data(dto.FromResourceSlice(resources))
+}
+
+func (r *Resources) resource(ctx context.Context, request *rs.Request) (*model.Resource, error) {
+	resourceID, err := uuid.Parse(request.Param("resourceID"))
+	if err != nil {
+		return nil, fmt.Errorf("неверный resourceID: %w", err)
+	}
+	return r.services.Resource.GetByID(ctx, resourceID)
+}
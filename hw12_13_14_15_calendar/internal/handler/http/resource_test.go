@@ -0,0 +1,137 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+const otherUserEmail = "other@otus.ru"
+
+// getJSON GET-запрос, ответ которого содержит объект или список без обертки.
+func getJSON(t *testing.T, server *httptest.Server, resource, login string, target interface{}) int {
+	t.Helper()
+	res, body := sendRequest(t, server, http.MethodGet, resource, login, nil, nil)
+	if res.StatusCode == http.StatusOK {
+		require.NoError(t, json.Unmarshal(body, target))
+	}
+	return res.StatusCode
+}
+
+func TestResources(t *testing.T) {
+	server := newTestServer(t, config.Server{}, calendartest.WithUser("Other", otherUserEmail))
+	createResource := func(login, body string) dto.Resource {
+		res, resp := apiRequest(t, server, http.MethodPost, "/resources", login, []byte(body))
+		require.Equal(t, http.StatusOK, res.StatusCode, resp.Message)
+		var resource dto.Resource
+		require.NoError(t, json.Unmarshal(resp.Data, &resource))
+		return resource
+	}
+	room := createResource(ValidUserEmail,
+		`{"kind": "room", "name": "Переговорная 1", "capacity": 8, "location": "3 этаж"}`)
+	projector := createResource(otherUserEmail, `{"kind": "equipment", "name": "Проектор"}`)
+	require.Equal(t, "room", room.Kind)
+	require.Equal(t, 8, room.Capacity)
+
+	t.Run("manage", func(t *testing.T) {
+		res, resp := apiRequest(t, server, http.MethodPost, "/resources", otherUserEmail,
+			[]byte(`{"kind": "room", "name": "переговорная 1"}`))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrResourceNameBusyCode, resp.Code)
+		res, _ = apiRequest(t, server, http.MethodPost, "/resources", otherUserEmail,
+			[]byte(`{"kind": "car", "name": "Автомобиль"}`))
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+
+		// изменять ресурс может только ответственный.
+		res, resp = apiRequest(t, server, http.MethodPut, "/resources/"+room.ID, otherUserEmail,
+			[]byte(`{"capacity": 2}`))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrCalendarAccessCode, resp.Code)
+		res, _ = apiRequest(t, server, http.MethodPut, "/resources/"+room.ID, ValidUserEmail,
+			[]byte(`{"capacity": 10}`))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var list []dto.Resource
+		require.Equal(t, http.StatusOK,
+			getJSON(t, server, "/resources?kind=room&minCapacity=10", otherUserEmail, &list))
+		require.Len(t, list, 1)
+		require.Equal(t, room.ID, list[0].ID)
+		require.Equal(t, http.StatusOK, getJSON(t, server, "/resources", otherUserEmail, &list))
+		require.Len(t, list, 2)
+	})
+
+	t.Run("booking", func(t *testing.T) {
+		res, resp := apiRequest(t, server, http.MethodPost, "/events", ValidUserEmail, []byte(`{
+			"title": "Планирование", "date": "2023-04-12T10:00:00Z", "duration": "1h",
+			"resourceIds": ["`+room.ID+`", "`+projector.ID+`"]
+		}`))
+		require.Equal(t, http.StatusOK, res.StatusCode, resp.Message)
+		var event dto.Event
+		require.NoError(t, json.Unmarshal(resp.Data, &event))
+		require.Len(t, event.Resources, 2)
+
+		// другой пользователь не может занять ресурс в пересекающееся время.
+		res, resp = apiRequest(t, server, http.MethodPost, "/events", otherUserEmail, []byte(`{
+			"title": "Демо", "date": "2023-04-12T10:30:00Z", "duration": "1h", "resourceIds": ["`+room.ID+`"]
+		}`))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrEventResourceBusyCode, resp.Code)
+		require.Contains(t, resp.Message, room.Name)
+
+		res, resp = apiRequest(t, server, http.MethodPost, "/events", otherUserEmail, []byte(`{
+			"title": "Демо", "date": "2023-04-12T11:00:00Z", "duration": "1h", "resourceIds": ["`+room.ID+`"]
+		}`))
+		require.Equal(t, http.StatusOK, res.StatusCode, resp.Message)
+		var other dto.Event
+		require.NoError(t, json.Unmarshal(resp.Data, &other))
+
+		// перенос на занятое время отклоняется, изменение собственного события - нет.
+		res, resp = apiRequest(t, server, http.MethodPut, "/events/"+other.ID, otherUserEmail,
+			[]byte(`{"date": "2023-04-12T10:45:00Z"}`))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, model.ErrEventResourceBusyCode, resp.Code)
+		res, _ = apiRequest(t, server, http.MethodPut, "/events/"+event.ID, ValidUserEmail,
+			[]byte(`{"duration": "50m"}`))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var bookings []dto.ResourceBooking
+		period := "from=2023-04-12T09:00:00Z&to=2023-04-12T13:00:00Z"
+		require.Equal(t, http.StatusOK,
+			getJSON(t, server, "/resources/"+room.ID+"/bookings?"+period, otherUserEmail, &bookings))
+		require.Len(t, bookings, 2)
+		require.Equal(t, event.ID, bookings[0].EventID)
+		require.Equal(t, ValidUserEmail, bookings[0].BookedBy)
+		require.Equal(t, other.ID, bookings[1].EventID)
+		require.Equal(t, "Other", bookings[1].BookedBy)
+
+		var available []dto.Resource
+		require.Equal(t, http.StatusOK, getJSON(t, server,
+			"/resources/available?from=2023-04-12T10:00:00Z&to=2023-04-12T10:50:00Z", otherUserEmail, &available))
+		require.Empty(t, available)
+		require.Equal(t, http.StatusOK, getJSON(t, server,
+			"/resources/available?from=2023-04-12T10:50:00Z&to=2023-04-12T11:00:00Z", otherUserEmail, &available))
+		require.Len(t, available, 2)
+		require.Equal(t, http.StatusOK, getJSON(t, server,
+			"/resources/available?kind=equipment&from=2023-04-12T11:00:00Z&to=2023-04-12T12:00:00Z",
+			otherUserEmail, &available))
+		require.Len(t, available, 1)
+		require.Equal(t, projector.ID, available[0].ID)
+		res, _ = apiRequest(t, server, http.MethodGet,
+			"/resources/available?from=2023-04-12T12:00:00Z&to=2023-04-12T11:00:00Z", otherUserEmail, nil)
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+
+		// удаление ресурса снимает бронь.
+		res, _ = apiRequest(t, server, http.MethodDelete, "/resources/"+room.ID, ValidUserEmail, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, http.StatusOK, getJSON(t, server, "/events/"+event.ID, ValidUserEmail, &event))
+		require.Len(t, event.Resources, 1)
+		require.Equal(t, projector.ID, event.Resources[0].ID)
+	})
+}
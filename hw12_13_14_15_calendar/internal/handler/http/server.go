@@ -2,30 +2,85 @@ package http
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
 	deps "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/caldav"
+	grpchandler "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/grpc"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/openapi"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/closer"
-	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest"
 )
 
+const (
+	// exportTimeout предельное время выгрузки событий потоком.
+	exportTimeout = 5 * time.Minute
+	// transferTimeout предельное время загрузки и скачивания вложения.
+	transferTimeout = 5 * time.Minute
+)
+
 func NewHandledServer(
 	config config.Server, services *deps.Services, deps *deps.Deps,
 ) (*rest.Server, closer.CloseFunc) {
-	server := rest.NewServer(servers.NewConfig(
-		config.Host,
-		config.Port,
-		false,
-	), services.Auth, deps.Logger)
+	server := rest.NewServer(config.ServersConfig(), services.Auth, deps.Logger)
 
 	hs := NewHandlers(services, deps.Logger)
 
 	server.GET("/events/list/{rangeType}", hs.Events.GetListOnDate)
+	server.GET("/events/trash", hs.Events.GetTrash)
+	server.GET("/events/search", hs.Events.Search)
+	server.GET("/events/export", hs.Events.Export, rest.WithTimeout(exportTimeout))
+	server.POST("/events/batch", hs.Events.CreateBatch)
+	server.PUT("/events/batch", hs.Events.UpdateBatch)
+	server.POST("/events/batch/delete", hs.Events.DeleteBatch)
+	server.POST("/events/{eventID}/restore", hs.Events.Restore)
 	server.GET("/events/{eventID}", hs.Events.GetByID)
 	server.POST("/events", hs.Events.Create)
 	server.PUT("/events/{eventID}", hs.Events.Update)
 	server.DELETE("/events/{eventID}", hs.Events.Delete)
+	server.GET("/events/{eventID}/attachments", hs.Attachments.GetList)
+	server.POST("/events/{eventID}/attachments", hs.Attachments.Upload, rest.WithTimeout(transferTimeout))
+	server.GET(
+		"/events/{eventID}/attachments/{attachmentID}", hs.Attachments.Download, rest.WithTimeout(transferTimeout),
+	)
+	server.DELETE("/events/{eventID}/attachments/{attachmentID}", hs.Attachments.Delete)
+	server.GET("/tags", hs.Tags.GetList)
+	server.GET("/tags/{tagID}", hs.Tags.GetByID)
+	server.POST("/tags", hs.Tags.Create)
+	server.PUT("/tags/{tagID}", hs.Tags.Update)
+	server.DELETE("/tags/{tagID}", hs.Tags.Delete)
+	server.GET("/resources", hs.Resources.GetList)
+	server.GET("/resources/available", hs.Resources.Available)
+	server.GET("/resources/{resourceID}", hs.Resources.GetByID)
+	server.GET("/resources/{resourceID}/bookings", hs.Resources.Bookings)
+	server.POST("/resources", hs.Resources.Create)
+	server.PUT("/resources/{resourceID}", hs.Resources.Update)
+	server.DELETE("/resources/{resourceID}", hs.Resources.Delete)
+	server.GET("/availability", hs.Availability.Get)
+	server.PUT("/availability", hs.Availability.Update)
+	server.GET("/availability/ooo", hs.Availability.GetOutOfOffice)
+	server.POST("/availability/ooo", hs.Availability.AddOutOfOffice)
+	server.DELETE("/availability/ooo/{oooID}", hs.Availability.DeleteOutOfOffice)
+	server.GET("/availability/slots", hs.Availability.GetFreeSlots)
+	server.GET("/digest", hs.Digest.Get)
+	server.PUT("/digest", hs.Digest.Update)
+	server.GET("/profile", hs.Profile.Get)
+	server.PUT("/profile", hs.Profile.Update)
+
+	server.Public(openapi.SpecPath, openapi.SpecHandler())
+	server.Public(openapi.DocsPath, openapi.DocsHandler())
+
+	if gateway, err := grpchandler.NewGatewayHandler(services, deps.Logger); err != nil {
+		deps.Logger.Error("REST-фасад gRPC не подключен: %s", err.Error())
+	} else {
+		server.Mount(grpchandler.GatewayPrefix+"/", gateway)
+	}
+
+	// CalDAV-клиенты находят календарь по адресу /.well-known/caldav (RFC 6764).
+	server.Mount("/.well-known/caldav", http.RedirectHandler(caldav.BasePath, http.StatusMovedPermanently))
+	server.Mount(caldav.BasePath, caldav.NewHandler(services, deps.Logger))
 
 	return server, func(ctx context.Context) error {
 		return server.Stop(ctx)
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+)
+
+type Tags struct {
+	*Handler
+}
+
+func (t *Tags) GetList(request *rs.Request) rs.Response {
+	const actionName = "получение списка меток"
+	tags, err := t.services.TagCRUD.GetUserTags(request.Context())
+	if err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromTagSlice(tags))
+}
+
+func (t *Tags) GetByID(request *rs.Request) rs.Response {
+	const actionName = "получение метки по ID"
+	tagID, err := uuid.Parse(request.Param("tagID"))
+	if err != nil {
+		return t.handleError(request.Context(), actionName, fmt.Errorf("неверный tagID: %w", err))
+	}
+	tag, err := t.services.TagCRUD.GetByID(request.Context(), tagID)
+	if err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	return rs.Data(dto.FromTagModel(*tag))
+}
+
+func (t *Tags) Create(request *rs.Request) rs.Response {
+	const actionName = "добавление метки"
+	var input dto.TagCreate
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				t.log(request.Context()).Error("добавление метки - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return t.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	tag, err := t.services.TagCRUD.Add(request.Context(), input.Model())
+	if err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	t.log(request.Context()).Info("метка добавлена: tagID=%s", tag.ID.String())
+	return rs.OK("метка добавлена", dto.FromTagModel(*tag))
+}
+
+func (t *Tags) Update(request *rs.Request) rs.Response {
+	const actionName = "изменение метки"
+	var input dto.TagUpdate
+	tagID, err := uuid.Parse(request.Param("tagID"))
+	if err != nil {
+		return t.handleError(request.Context(), actionName, fmt.Errorf("неверный tagID: %w", err))
+	}
+	if request.ContentLength > 0 {
+		defer func() {
+			if err := request.Body.Close(); err != nil {
+				t.log(request.Context()).Error("изменение метки - request.Body.Close(): %s", err.Error())
+			}
+		}()
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			return t.handleError(request.Context(), actionName, fmt.Errorf("ошибка парсинга входных данных: %w", err))
+		}
+	}
+	ctx := request.Context()
+	tag, err := t.services.TagCRUD.GetByID(ctx, tagID)
+	if err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	if err = t.services.TagCRUD.Update(ctx, *tag, input.Model()); err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	t.log(request.Context()).Info("метка изменена: tagID=%s", tag.ID.String())
+	return rs.OK("метка изменена", nil)
+}
+
+func (t *Tags) Delete(request *rs.Request) rs.Response {
+	const actionName = "удаление метки"
+	tagID, err := uuid.Parse(request.Param("tagID"))
+	if err != nil {
+		return t.handleError(request.Context(), actionName, fmt.Errorf("неверный tagID: %w", err))
+	}
+	ctx := request.Context()
+	tag, err := t.services.TagCRUD.GetByID(ctx, tagID)
+	if err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	if err = t.services.TagCRUD.Delete(ctx, *tag); err != nil {
+		return t.handleError(request.Context(), actionName, err)
+	}
+	t.log(request.Context()).Info("метка удалена: tagID=%s", tag.ID.String())
+	return rs.OK("метка удалена", dto.FromTagModel(*tag))
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/config"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/app/deps/calendar/calendartest"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/handler/http/dto"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs/certstest"
+)
+
+const serviceAccount = "scheduler@otus.ru"
+
+func TestMutualTLS(t *testing.T) {
+	ca := certstest.NewCA(t)
+	certFile, keyFile := ca.Issue(t, "calendar", "127.0.0.1")
+	cfg := config.Server{
+		TLS: config.ServerTLS{
+			CertFile:        certFile,
+			KeyFile:         keyFile,
+			ClientCAFile:    ca.File,
+			ServiceAccounts: map[string]string{"scheduler": serviceAccount},
+		},
+	}
+	server := httptest.NewUnstartedServer(newRestServer(t, cfg,
+		calendartest.WithLogLevel(logger.LevelFatal), calendartest.WithUser(serviceAccount, serviceAccount)))
+	source, err := cfg.ServersConfig().GetTLS().Source(nil)
+	require.NoError(t, err)
+	server.TLS, err = source.ServerConfig("h2", "http/1.1")
+	require.NoError(t, err)
+	server.StartTLS()
+	defer server.Close()
+
+	// request запрос с сертификатом клиента name, пустое имя - только проверка сервера.
+	request := func(name, path string) (int, []byte) {
+		files := certs.Files{CA: ca.File}
+		if name != "" {
+			files.Cert, files.Key = ca.Issue(t, name)
+		}
+		source, err := certs.NewSource(files, 0, nil)
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: source.ClientConfig("")}}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", ValidUserEmail)
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, body
+	}
+	profile := func(name string) string {
+		code, body := request(name, "/profile")
+		require.Equal(t, http.StatusOK, code)
+		var user dto.User
+		require.NoError(t, json.Unmarshal(body, &user))
+		return user.Email
+	}
+
+	t.Run("user without certificate", func(t *testing.T) {
+		require.Equal(t, ValidUserEmail, profile(""))
+		code, _ := request("", "/tags")
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("service account", func(t *testing.T) {
+		// логин из заголовка не используется, если сертификат предъявлен.
+		require.Equal(t, serviceAccount, profile("scheduler"))
+	})
+
+	t.Run("unmapped certificate", func(t *testing.T) {
+		code, _ := request("intruder", "/profile")
+		require.Equal(t, http.StatusUnauthorized, code)
+	})
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+// Attachment вложение события: метаданные файла, содержимое хранится в хранилище файлов по BlobKey.
+type Attachment struct {
+	ID          uuid.UUID
+	EventID     uuid.UUID
+	FileName    string
+	ContentType string
+	Size        int64
+	BlobKey     string
+	CreatedAt   time.Time
+}
+
+// AttachmentCreate модель создания вложения, содержимое к этому моменту уже сохранено.
+type AttachmentCreate struct {
+	EventID     uuid.UUID
+	FileName    string
+	ContentType string
+	Size        int64
+	BlobKey     string
+}
+
+// Validate базовая валидация структуры.
+func (ac AttachmentCreate) Validate() error {
+	var errs errx.NamedErrors
+	if ac.EventID.ID() == 0 {
+		errs.Add(errx.NamedError{
+			Field: "EventID",
+			Err:   ErrAttachmentEventID,
+		})
+	}
+	if ac.FileName == "" {
+		errs.Add(errx.NamedError{
+			Field: "FileName",
+			Err:   ErrAttachmentEmptyName,
+		})
+	}
+	if ac.Size <= 0 {
+		errs.Add(errx.NamedError{
+			Field: "Size",
+			Err:   ErrAttachmentEmpty,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// AttachmentSearch модель поиска вложений.
+type AttachmentSearch struct {
+	ID       *uuid.UUID
+	EventID  *uuid.UUID
+	EventIDs []uuid.UUID
+}
+
+// AttachmentUpload загружаемый файл: имя и тип, заявленные клиентом, и содержимое.
+type AttachmentUpload struct {
+	FileName    string
+	ContentType string
+	Content     io.Reader
+}
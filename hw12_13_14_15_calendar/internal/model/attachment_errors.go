@@ -0,0 +1,19 @@
+package model
+
+import "errors"
+
+const (
+	ErrAttachmentTooLargeCode = 1017
+	ErrAttachmentTypeCode     = 1018
+	ErrAttachmentCountCode    = 1019
+)
+
+var (
+	ErrAttachmentEventID   = errors.New("не задан идентификатор события вложения")
+	ErrAttachmentEmptyName = errors.New("не задано имя файла")
+	ErrAttachmentEmpty     = errors.New("пустой файл")
+	ErrAttachmentTooLarge  = errors.New("превышен допустимый размер файла")
+	ErrAttachmentType      = errors.New("недопустимый тип файла")
+	ErrAttachmentCount     = errors.New("превышено допустимое количество вложений события")
+	ErrAttachmentNotFound  = errors.New("указанное вложение не найдено")
+)
@@ -0,0 +1,234 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var ErrorUnknownAvailabilityPolicy = errors.New("unknown availability policy")
+
+// AvailabilityPolicy реакция на событие вне рабочего времени владельца.
+type AvailabilityPolicy int
+
+const (
+	// AvailabilityIgnore рабочее время не проверяется (по умолчанию).
+	AvailabilityIgnore AvailabilityPolicy = iota
+	// AvailabilityWarn событие создается с предупреждением.
+	AvailabilityWarn
+	// AvailabilityReject событие отклоняется.
+	AvailabilityReject
+	AvailabilityError
+)
+
+func (ap AvailabilityPolicy) Valid() bool {
+	return ap < AvailabilityError
+}
+
+func (ap AvailabilityPolicy) String() string {
+	switch ap { //nolint:exhaustive // has def-value
+	case AvailabilityIgnore:
+		return "ignore"
+	case AvailabilityWarn:
+		return "warn"
+	case AvailabilityReject:
+		return "reject"
+	}
+	return ""
+}
+
+func ParseAvailabilityPolicy(policy string) (AvailabilityPolicy, error) {
+	switch policy {
+	case "ignore":
+		return AvailabilityIgnore, nil
+	case "warn":
+		return AvailabilityWarn, nil
+	case "reject":
+		return AvailabilityReject, nil
+	}
+	return AvailabilityError, ErrorUnknownAvailabilityPolicy
+}
+
+// WorkingHours рабочий интервал дня недели, Start и End - смещение от полуночи
+// в часовом поясе пользователя. В один день может быть несколько интервалов.
+type WorkingHours struct {
+	Weekday time.Weekday
+	Start   time.Duration
+	End     time.Duration
+}
+
+// Availability настройки доступности пользователя. Пустой Hours - рабочее время не ограничено.
+type Availability struct {
+	UserID   uuid.UUID
+	TimeZone string
+	Policy   AvailabilityPolicy
+	Hours    []WorkingHours
+}
+
+// DefaultAvailability настройки пользователя, который их не задавал.
+func DefaultAvailability(userID uuid.UUID) Availability {
+	return Availability{
+		UserID:   userID,
+		TimeZone: time.UTC.String(),
+		Policy:   AvailabilityIgnore,
+	}
+}
+
+// Location часовой пояс пользователя.
+func (a Availability) Location() *time.Location {
+	loc, err := time.LoadLocation(a.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Validate базовая валидация структуры.
+func (a Availability) Validate() error {
+	var errs errx.NamedErrors
+	if _, err := time.LoadLocation(a.TimeZone); err != nil || a.TimeZone == "" {
+		errs.Add(errx.NamedError{
+			Field: "TimeZone",
+			Err:   ErrAvailabilityTimeZone,
+		})
+	}
+	if !a.Policy.Valid() {
+		errs.Add(errx.NamedError{
+			Field: "Policy",
+			Err:   ErrAvailabilityPolicy,
+		})
+	}
+	for _, wh := range a.Hours {
+		if wh.Weekday < time.Sunday || wh.Weekday > time.Saturday ||
+			wh.Start < 0 || wh.End > 24*time.Hour || wh.Start >= wh.End {
+			errs.Add(errx.NamedError{
+				Field: "Hours",
+				Err:   ErrAvailabilityHours,
+			})
+			break
+		}
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// workingRanges рабочие интервалы дня day в часовом поясе пользователя.
+func (a Availability) workingRanges(day time.Time) []DateRange {
+	loc := a.Location()
+	day = day.In(loc)
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	if len(a.Hours) == 0 {
+		return []DateRange{DateRgnFromDates(midnight, midnight.AddDate(0, 0, 1))}
+	}
+	var ranges []DateRange
+	for _, wh := range a.Hours {
+		if wh.Weekday != midnight.Weekday() {
+			continue
+		}
+		ranges = append(ranges, DateRgnFromDates(midnight.Add(wh.Start), midnight.Add(wh.End)))
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].DateStart.Before(ranges[j].DateStart)
+	})
+	return ranges
+}
+
+// Covers находится ли интервал целиком в рабочем времени и вне периодов отсутствия.
+func (a Availability) Covers(dateRgn DateRange, absences []OutOfOffice) bool {
+	for _, ooo := range absences {
+		if ooo.Overlaps(dateRgn) {
+			return false
+		}
+	}
+	if len(a.Hours) == 0 {
+		return true
+	}
+	for _, working := range a.workingRanges(dateRgn.GetFrom()) {
+		if !dateRgn.GetFrom().Before(working.GetFrom()) && !dateRgn.GetTo().After(working.GetTo()) {
+			return true
+		}
+	}
+	return false
+}
+
+// FreeSlots свободные интервалы рабочего времени дня day за вычетом событий и периодов отсутствия.
+func (a Availability) FreeSlots(day time.Time, events []Event, absences []OutOfOffice) []DateRange {
+	busy := make([]DateRange, 0, len(events)+len(absences))
+	for _, event := range events {
+		busy = append(busy, DateRange{DateStart: event.Date, Duration: event.Duration})
+	}
+	for _, ooo := range absences {
+		busy = append(busy, DateRgnFromDates(ooo.DateFrom, ooo.DateTo))
+	}
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].DateStart.Before(busy[j].DateStart)
+	})
+	slots := make([]DateRange, 0)
+	for _, working := range a.workingRanges(day) {
+		from, to := working.GetFrom(), working.GetTo()
+		for _, rng := range busy {
+			if !rng.GetTo().After(from) || !rng.GetFrom().Before(to) {
+				continue
+			}
+			if rng.GetFrom().After(from) {
+				slots = append(slots, DateRgnFromDates(from, rng.GetFrom()))
+			}
+			if rng.GetTo().After(from) {
+				from = rng.GetTo()
+			}
+		}
+		if from.Before(to) {
+			slots = append(slots, DateRgnFromDates(from, to))
+		}
+	}
+	return slots
+}
+
+// OutOfOffice период отсутствия пользователя.
+type OutOfOffice struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	DateFrom time.Time
+	DateTo   time.Time
+	Reason   string
+}
+
+func (o OutOfOffice) Overlaps(dateRgn DateRange) bool {
+	return o.DateTo.After(dateRgn.GetFrom()) && o.DateFrom.Before(dateRgn.GetTo())
+}
+
+// OutOfOfficeCreate модель создания периода отсутствия.
+type OutOfOfficeCreate struct {
+	UserID   uuid.UUID
+	DateFrom time.Time
+	DateTo   time.Time
+	Reason   string
+}
+
+// Validate базовая валидация структуры.
+func (oc OutOfOfficeCreate) Validate() error {
+	var errs errx.NamedErrors
+	if oc.DateFrom.IsZero() || !oc.DateTo.After(oc.DateFrom) {
+		errs.Add(errx.NamedError{
+			Field: "DateTo",
+			Err:   ErrOutOfOfficeDates,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// OutOfOfficeSearch модель поиска периодов отсутствия.
+type OutOfOfficeSearch struct {
+	ID     *uuid.UUID
+	UserID *uuid.UUID
+	// DateRange периоды, пересекающиеся с интервалом.
+	DateRange *DateRange
+}
@@ -0,0 +1,11 @@
+package model
+
+import "errors"
+
+var (
+	ErrAvailabilityTimeZone = errors.New("неизвестный часовой пояс")
+	ErrAvailabilityPolicy   = errors.New("неизвестная политика проверки рабочего времени")
+	ErrAvailabilityHours    = errors.New("неверный рабочий интервал, ожидается 00:00 <= начало < конец <= 24:00")
+	ErrOutOfOfficeDates     = errors.New("неверный период отсутствия")
+	ErrOutOfOfficeNotFound  = errors.New("указанный период отсутствия не найден")
+)
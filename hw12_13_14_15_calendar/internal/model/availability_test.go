@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityCovers(t *testing.T) {
+	// вторник, 10:00-19:00 по Москве (UTC+3).
+	settings := Availability{
+		TimeZone: "Europe/Moscow",
+		Policy:   AvailabilityReject,
+		Hours: []WorkingHours{
+			{Weekday: time.Tuesday, Start: 10 * time.Hour, End: 19 * time.Hour},
+		},
+	}
+	require.NoError(t, settings.Validate())
+	day := time.Date(2023, 3, 7, 0, 0, 0, 0, settings.Location())
+	absences := []OutOfOffice{
+		{DateFrom: day.Add(12 * time.Hour), DateTo: day.Add(13 * time.Hour)},
+	}
+	testCases := []struct {
+		name     string
+		dateRgn  DateRange
+		expected bool
+	}{
+		{
+			name:     "inside hours",
+			dateRgn:  DateRange{DateStart: day.Add(10 * time.Hour), Duration: time.Hour},
+			expected: true,
+		}, {
+			name:     "inside hours in utc",
+			dateRgn:  DateRange{DateStart: day.Add(18 * time.Hour).UTC(), Duration: time.Hour},
+			expected: true,
+		}, {
+			name:     "ends after hours",
+			dateRgn:  DateRange{DateStart: day.Add(18 * time.Hour), Duration: 2 * time.Hour},
+			expected: false,
+		}, {
+			name:     "other weekday",
+			dateRgn:  DateRange{DateStart: day.Add(-12 * time.Hour), Duration: time.Hour},
+			expected: false,
+		}, {
+			name:     "out of office",
+			dateRgn:  DateRange{DateStart: day.Add(12*time.Hour + 30*time.Minute), Duration: time.Hour},
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, settings.Covers(tc.dateRgn, absences))
+		})
+	}
+}
+
+func TestAvailabilityFreeSlots(t *testing.T) {
+	day := time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Date: day.Add(8 * time.Hour), Duration: 2 * time.Hour},
+		{Date: day.Add(11 * time.Hour), Duration: 30 * time.Minute},
+		{Date: day.Add(11 * time.Hour), Duration: time.Hour},
+	}
+	absences := []OutOfOffice{
+		{DateFrom: day.Add(16 * time.Hour), DateTo: day.Add(48 * time.Hour)},
+	}
+
+	t.Run("without hours", func(t *testing.T) {
+		settings := DefaultAvailability(uuid.Nil)
+		slots := settings.FreeSlots(day, events, absences)
+		require.Equal(t, []DateRange{
+			DateRgnFromDates(day, day.Add(8*time.Hour)),
+			DateRgnFromDates(day.Add(10*time.Hour), day.Add(11*time.Hour)),
+			DateRgnFromDates(day.Add(12*time.Hour), day.Add(16*time.Hour)),
+		}, slots)
+	})
+
+	t.Run("with hours", func(t *testing.T) {
+		settings := Availability{
+			TimeZone: "UTC",
+			Hours: []WorkingHours{
+				{Weekday: time.Tuesday, Start: 14 * time.Hour, End: 18 * time.Hour},
+				{Weekday: time.Tuesday, Start: 9 * time.Hour, End: 13 * time.Hour},
+			},
+		}
+		slots := settings.FreeSlots(day, events, absences)
+		require.Equal(t, []DateRange{
+			DateRgnFromDates(day.Add(10*time.Hour), day.Add(11*time.Hour)),
+			DateRgnFromDates(day.Add(12*time.Hour), day.Add(13*time.Hour)),
+			DateRgnFromDates(day.Add(14*time.Hour), day.Add(16*time.Hour)),
+		}, slots)
+	})
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var ErrorUnknownDigestMode = errors.New("unknown digest mode")
+
+// DigestMode периодичность сводки событий.
+type DigestMode int
+
+const (
+	// DigestOff сводка не отправляется (по умолчанию).
+	DigestOff DigestMode = iota
+	// DigestDaily ежедневная сводка событий дня.
+	DigestDaily
+	// DigestWeekly еженедельная сводка событий на семь дней вперед.
+	DigestWeekly
+	DigestError
+)
+
+func (dm DigestMode) Valid() bool {
+	return dm < DigestError
+}
+
+func (dm DigestMode) String() string {
+	switch dm { //nolint:exhaustive // has def-value
+	case DigestOff:
+		return "off"
+	case DigestDaily:
+		return "daily"
+	case DigestWeekly:
+		return "weekly"
+	}
+	return ""
+}
+
+func ParseDigestMode(mode string) (DigestMode, error) {
+	switch mode {
+	case "off":
+		return DigestOff, nil
+	case "daily":
+		return DigestDaily, nil
+	case "weekly":
+		return DigestWeekly, nil
+	}
+	return DigestError, ErrorUnknownDigestMode
+}
+
+// DigestSettings настройки сводки событий пользователя. Время отправки - в часовом поясе
+// из настроек доступности пользователя.
+type DigestSettings struct {
+	UserID uuid.UUID
+	Mode   DigestMode
+	// SendAt смещение времени отправки от полуночи.
+	SendAt time.Duration
+	// Weekday день отправки еженедельной сводки.
+	Weekday time.Weekday
+	// LastSentAt запланированное время последней отправленной сводки.
+	LastSentAt time.Time
+}
+
+// DefaultDigestSettings настройки пользователя, который их не задавал.
+func DefaultDigestSettings(userID uuid.UUID) DigestSettings {
+	return DigestSettings{
+		UserID:  userID,
+		Mode:    DigestOff,
+		SendAt:  8 * time.Hour,
+		Weekday: time.Monday,
+	}
+}
+
+// Validate базовая валидация структуры.
+func (ds DigestSettings) Validate() error {
+	var errs errx.NamedErrors
+	if !ds.Mode.Valid() {
+		errs.Add(errx.NamedError{
+			Field: "Mode",
+			Err:   ErrDigestMode,
+		})
+	}
+	if ds.SendAt < 0 || ds.SendAt >= 24*time.Hour {
+		errs.Add(errx.NamedError{
+			Field: "SendAt",
+			Err:   ErrDigestSendAt,
+		})
+	}
+	if ds.Weekday < time.Sunday || ds.Weekday > time.Saturday {
+		errs.Add(errx.NamedError{
+			Field: "Weekday",
+			Err:   ErrDigestWeekday,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// Due время отправки и период сводки, если к моменту now она должна быть отправлена.
+// Сводка отправляется только в день отправки: пропущенная за прошлые дни сводка неактуальна.
+// Период начинается с полуночи дня отправки и длится день или неделю.
+func (ds DigestSettings) Due(now time.Time, loc *time.Location) (time.Time, DateRange, bool) {
+	if ds.Mode != DigestDaily && ds.Mode != DigestWeekly {
+		return time.Time{}, DateRange{}, false
+	}
+	local := now.In(loc)
+	if ds.Mode == DigestWeekly && local.Weekday() != ds.Weekday {
+		return time.Time{}, DateRange{}, false
+	}
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	sendAt := midnight.Add(ds.SendAt)
+	if now.Before(sendAt) || !ds.LastSentAt.Before(sendAt) {
+		return time.Time{}, DateRange{}, false
+	}
+	days := 1
+	if ds.Mode == DigestWeekly {
+		days = 7
+	}
+	return sendAt, DateRgnFromDates(midnight, midnight.AddDate(0, 0, days)), true
+}
+
+// MessageDigest признак сводки в очереди оповещений, сообщения без признака - оповещения о событиях.
+const MessageDigest = "digest"
+
+// Digest сводка событий пользователя за период, публикуется в очередь оповещений.
+type Digest struct {
+	Kind       string        `json:"kind"`       // Всегда MessageDigest.
+	Mode       string        `json:"mode"`       // daily или weekly.
+	DateStart  time.Time     `json:"dateStart"`  // Начало периода.
+	DateEnd    time.Time     `json:"dateEnd"`    // Окончание периода.
+	TimeZone   string        `json:"timeZone"`   // Часовой пояс пользователя.
+	NotifyUser NotifyUser    `json:"notifyUser"` // Пользователь, которому отправлять.
+	Events     []DigestEvent `json:"events"`     // События периода по возрастанию даты.
+	UserID     uuid.UUID     `json:"userId"`     // Владелец сводки, по нему подтверждается отправка.
+	SendAt     time.Time     `json:"sendAt"`     // Запланированное время отправки.
+}
+
+type DigestEvent struct {
+	ID       uuid.UUID     `json:"id"`
+	Title    string        `json:"title"`
+	Date     time.Time     `json:"date"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Location часовой пояс пользователя.
+func (d Digest) Location() *time.Location {
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,9 @@
+package model
+
+import "errors"
+
+var (
+	ErrDigestMode    = errors.New("неизвестная периодичность сводки")
+	ErrDigestSendAt  = errors.New("неверное время отправки сводки, ожидается 00:00 <= время < 24:00")
+	ErrDigestWeekday = errors.New("неверный день недели отправки сводки")
+)
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestSettingsDue(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	// вторник 2023-03-07, сводка в 08:30 по Москве.
+	sendAt := time.Date(2023, 3, 7, 8, 30, 0, 0, moscow)
+	midnight := time.Date(2023, 3, 7, 0, 0, 0, 0, moscow)
+	testCases := []struct {
+		name     string
+		settings DigestSettings
+		now      time.Time
+		expected *DateRange
+	}{
+		{
+			name:     "off",
+			settings: DigestSettings{Mode: DigestOff, SendAt: 8*time.Hour + 30*time.Minute},
+			now:      sendAt.Add(time.Minute),
+		}, {
+			name:     "daily before send time",
+			settings: DigestSettings{Mode: DigestDaily, SendAt: 8*time.Hour + 30*time.Minute},
+			now:      sendAt.Add(-time.Minute),
+		}, {
+			name:     "daily due",
+			settings: DigestSettings{Mode: DigestDaily, SendAt: 8*time.Hour + 30*time.Minute},
+			// 05:31 UTC = 08:31 MSK.
+			now:      sendAt.Add(time.Minute).UTC(),
+			expected: &DateRange{DateStart: midnight, Duration: 24 * time.Hour},
+		}, {
+			name: "daily already sent",
+			settings: DigestSettings{
+				Mode: DigestDaily, SendAt: 8*time.Hour + 30*time.Minute, LastSentAt: sendAt,
+			},
+			now: sendAt.Add(time.Hour),
+		}, {
+			name: "daily sent yesterday",
+			settings: DigestSettings{
+				Mode: DigestDaily, SendAt: 8*time.Hour + 30*time.Minute, LastSentAt: sendAt.AddDate(0, 0, -1),
+			},
+			now:      sendAt.Add(10 * time.Hour),
+			expected: &DateRange{DateStart: midnight, Duration: 24 * time.Hour},
+		}, {
+			name: "weekly other day",
+			settings: DigestSettings{
+				Mode: DigestWeekly, SendAt: 8*time.Hour + 30*time.Minute, Weekday: time.Monday,
+			},
+			now: sendAt.Add(time.Minute),
+		}, {
+			name: "weekly due",
+			settings: DigestSettings{
+				Mode: DigestWeekly, SendAt: 8*time.Hour + 30*time.Minute, Weekday: time.Tuesday,
+			},
+			now:      sendAt.Add(time.Minute),
+			expected: &DateRange{DateStart: midnight, Duration: 7 * 24 * time.Hour},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			at, period, ok := tc.settings.Due(tc.now, moscow)
+			if tc.expected == nil {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.True(t, sendAt.Equal(at), "send at %s", at)
+			require.True(t, tc.expected.GetFrom().Equal(period.GetFrom()))
+			require.True(t, tc.expected.GetTo().Equal(period.GetTo()))
+		})
+	}
+}
+
+func TestDigestSettingsValidate(t *testing.T) {
+	require.NoError(t, DefaultDigestSettings(uuid.New()).Validate())
+	err := DigestSettings{Mode: DigestError, SendAt: 24 * time.Hour, Weekday: 7}.Validate()
+	require.ErrorIs(t, err, ErrDigestMode)
+	require.ErrorIs(t, err, ErrDigestSendAt)
+	require.ErrorIs(t, err, ErrDigestWeekday)
+}
@@ -19,6 +19,17 @@ type Event struct {
 	NotifyStatus NotifyStatus
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	// DeletedAt дата помещения в корзину, nil - событие не удалено.
+	DeletedAt *time.Time
+	Tags      []Tag
+	// Links ссылки на видеовстречу, документы.
+	Links []EventLink
+	// Resources забронированные событием ресурсы: переговорные, оборудование.
+	Resources []Resource
+	// ICalUID идентификатор события, назначенный клиентом CalDAV, пусто - событие создано не через CalDAV.
+	ICalUID string
+	// Warnings предупреждения, возникшие при создании события, не сохраняются.
+	Warnings []string
 }
 
 // EventCreate модель создания события.
@@ -31,6 +42,17 @@ type EventCreate struct {
 	Description *string
 	// NotifyTerm время до начала события для оповещения, опционально.
 	NotifyTerm *time.Duration
+	// TagIDs метки события, опционально.
+	TagIDs []uuid.UUID
+	// Links ссылки события, опционально.
+	Links []EventLink
+	// ResourceIDs бронируемые ресурсы, опционально.
+	ResourceIDs []uuid.UUID
+	// ICalUID идентификатор события клиента CalDAV, опционально.
+	ICalUID string
+	// OwnerQuota максимальное количество событий владельца, включая корзину, проверяется
+	// при добавлении вместе с другими событиями владельца; 0 - без ограничений.
+	OwnerQuota int64
 }
 
 // Validate базовая валидация структуры.
@@ -66,6 +88,7 @@ func (ec EventCreate) Validate() error {
 			Err:   ErrEventWrongNotifyTerm,
 		})
 	}
+	errs = append(errs, ValidateLinks(ec.Links)...)
 	if errs.Empty() {
 		return nil
 	}
@@ -80,6 +103,12 @@ type EventUpdate struct {
 	Description  *string
 	NotifyTerm   *time.Duration
 	NotifyStatus *NotifyStatus
+	// TagIDs новый набор меток, nil - метки не меняются.
+	TagIDs *[]uuid.UUID
+	// Links новый набор ссылок, nil - ссылки не меняются.
+	Links *[]EventLink
+	// ResourceIDs новый набор бронируемых ресурсов, nil - бронь не меняется.
+	ResourceIDs *[]uuid.UUID
 }
 
 // Validate базовая валидация структуры.
@@ -109,6 +138,9 @@ func (ec EventUpdate) Validate() error {
 			Err:   ErrEventWrongNotifyTerm,
 		})
 	}
+	if ec.Links != nil {
+		errs = append(errs, ValidateLinks(*ec.Links)...)
+	}
 	if errs.Empty() {
 		return nil
 	}
@@ -128,6 +160,20 @@ type EventSearch struct {
 	DateLess *time.Time
 	// NeedNotifyTerm необходимые к оповещению на указанную дату.
 	NeedNotifyTerm *time.Time
+	// Trash учет событий в корзине, по умолчанию они исключаются.
+	Trash TrashFilter
+	// DeletedLess выбрать события, помещенные в корзину ранее указанной даты.
+	DeletedLess *time.Time
+	// Query текстовый запрос по заголовку и описанию, см. SearchTerms.
+	Query *string
+	// TagIDs события, отмеченные хотя бы одной из меток.
+	TagIDs []uuid.UUID
+	// IDs события с указанными идентификаторами.
+	IDs []uuid.UUID
+	// ResourceIDs события, бронирующие хотя бы один из ресурсов.
+	ResourceIDs []uuid.UUID
+	// ICalUID события с указанным идентификатором клиента CalDAV.
+	ICalUID *string
 }
 
 func EventSearchID(guid string) (EventSearch, error) {
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// EventBatchMaxSize максимальное количество операций в пакете.
+const EventBatchMaxSize = 100
+
+var ErrorUnknownBatchMode = errors.New("неизвестный режим выполнения пакета")
+
+// BatchMode режим выполнения пакета операций.
+type BatchMode int
+
+const (
+	// BatchAtomic пакет выполняется, только если все операции прошли проверку (по умолчанию).
+	BatchAtomic BatchMode = iota
+	// BatchBestEffort выполняются операции, прошедшие проверку, остальные отклоняются.
+	BatchBestEffort
+)
+
+func ParseBatchMode(sMode string) (BatchMode, error) {
+	switch sMode {
+	case "", "atomic":
+		return BatchAtomic, nil
+	case "bestEffort":
+		return BatchBestEffort, nil
+	}
+	return BatchAtomic, ErrorUnknownBatchMode
+}
+
+// EventBatchUpdate операция изменения события в пакете.
+type EventBatchUpdate struct {
+	ID    uuid.UUID
+	Input EventUpdate
+}
+
+// BatchResult результат операции пакета, порядок результатов соответствует порядку операций.
+type BatchResult struct {
+	EventID uuid.UUID
+	// Event созданное событие, только для пакета добавления.
+	Event *Event
+	// Err ошибка проверки операции в виде errx, nil - операция выполнена.
+	Err error
+}
+
+// BatchReport результат выполнения пакета.
+type BatchReport struct {
+	Results []BatchResult
+	// Applied количество выполненных операций.
+	Applied int
+}
@@ -21,15 +21,37 @@ const (
 	ErrEventOwnerIDCode      = 1003
 	ErrEventOwnerExistsCode  = 1004
 	ErrEventDateBusyCode     = 1005
+	ErrEventSearchQueryCode  = 1006
+	ErrEventBatchSizeCode    = 1008
+	ErrEventBatchOverlapCode = 1009
+	ErrEventBatchDuplCode    = 1010
+	ErrEventBatchRejectCode  = 1011
+	ErrEventOutOfHoursCode   = 1012
+	ErrEventQuotaCode        = 1016
+	ErrEventResourceBusyCode = 1020
 )
 
 var (
-	ErrCalendarAccess       = errors.New("нет доступа к календарю")
-	ErrCalendarDateRange    = errors.New("неверный интервал дат для получения событий")
-	ErrEventOwnerID         = errors.New("не задан идентификатор владельца")
-	ErrEventOwnerExists     = errors.New("указанный владелец не найден")
-	ErrEventDateBusy        = errors.New("указанная дата занята")
-	ErrEventNotFound        = errors.New("указанное событие не найдено")
-	ErrEventWrongNotifyTerm = errors.New("неверный интервал оповещения")
-	ErrEventNotFoundID      = errors.New("не найдено событие")
+	ErrCalendarAccess        = errors.New("нет доступа к календарю")
+	ErrCalendarDateRange     = errors.New("неверный интервал дат для получения событий")
+	ErrEventOwnerID          = errors.New("не задан идентификатор владельца")
+	ErrEventOwnerExists      = errors.New("указанный владелец не найден")
+	ErrEventDateBusy         = errors.New("указанная дата занята")
+	ErrEventNotFound         = errors.New("указанное событие не найдено")
+	ErrEventWrongNotifyTerm  = errors.New("неверный интервал оповещения")
+	ErrEventNotFoundID       = errors.New("не найдено событие")
+	ErrEventTrashNotFound    = errors.New("указанное событие в корзине не найдено")
+	ErrEventSearchQuery      = errors.New("пустой поисковый запрос")
+	ErrEventTagNotFound      = errors.New("метка не найдена")
+	ErrEventBatchSize        = errors.New("пакет должен содержать от 1 до 100 операций")
+	ErrEventBatchOverlap     = errors.New("дата пересекается с другим событием пакета")
+	ErrEventBatchDupl        = errors.New("событие уже участвует в пакете")
+	ErrEventBatchReject      = errors.New("операция отменена из-за ошибок в других операциях пакета")
+	ErrEventOutOfHours       = errors.New("событие вне рабочего времени владельца")
+	ErrEventQuota            = errors.New("превышено допустимое количество событий")
+	ErrEventLinksCount       = errors.New("слишком много ссылок события")
+	ErrEventLinkType         = errors.New("неизвестный тип ссылки")
+	ErrEventLinkURL          = errors.New("неверная ссылка, ожидается адрес http(s)")
+	ErrEventResourceNotFound = errors.New("ресурс не найден")
+	ErrEventResourceBusy     = errors.New("ресурс занят в это время")
 )
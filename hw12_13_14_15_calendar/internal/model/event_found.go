@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnippetStartSel, SnippetStopSel обрамление найденных слов во фрагменте текста.
+const (
+	SnippetStartSel = "<b>"
+	SnippetStopSel  = "</b>"
+)
+
+// EventFound результат полнотекстового поиска события.
+type EventFound struct {
+	Event Event
+	// Rank релевантность, чем больше, тем выше событие в результатах.
+	Rank float64
+	// Snippet фрагмент заголовка и описания с выделенными найденными словами.
+	Snippet string
+}
+
+// SearchTerms разбивает поисковый запрос на слова в нижнем регистре, каждое слово
+// ищется как префикс слов заголовка и описания. Знаки препинания отбрасываются.
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+		terms = append(terms, word)
+	}
+	return terms
+}
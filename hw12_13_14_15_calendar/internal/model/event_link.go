@@ -0,0 +1,94 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var ErrorUnknownLinkType = errors.New("unknown link type")
+
+// MaxEventLinks наибольшее количество ссылок события.
+const MaxEventLinks = 10
+
+// LinkType тип ссылки события.
+type LinkType int
+
+const (
+	// LinkVideo ссылка на видеовстречу.
+	LinkVideo LinkType = iota
+	// LinkDocument ссылка на документ.
+	LinkDocument
+	LinkError
+)
+
+func (lt LinkType) Valid() bool {
+	return lt < LinkError
+}
+
+func (lt LinkType) String() string {
+	switch lt { //nolint:exhaustive // has def-value
+	case LinkVideo:
+		return "video"
+	case LinkDocument:
+		return "document"
+	}
+	return ""
+}
+
+func ParseLinkType(linkType string) (LinkType, error) {
+	switch linkType {
+	case "video":
+		return LinkVideo, nil
+	case "document":
+		return LinkDocument, nil
+	}
+	return LinkError, ErrorUnknownLinkType
+}
+
+// EventLink типизированная ссылка события: видеовстреча, документ.
+type EventLink struct {
+	Type LinkType `json:"type"`
+	URL  string   `json:"url"`
+	// Title подпись ссылки, опционально.
+	Title string `json:"title,omitempty"`
+}
+
+// ValidateLinks проверка ссылок события, ошибки именуются Links[i].
+func ValidateLinks(links []EventLink) errx.NamedErrors {
+	var errs errx.NamedErrors
+	if len(links) > MaxEventLinks {
+		errs.Add(errx.NamedError{
+			Field: "Links",
+			Err:   ErrEventLinksCount,
+		})
+		return errs
+	}
+	for i, link := range links {
+		field := "Links[" + strconv.Itoa(i) + "]"
+		if !link.Type.Valid() {
+			errs.Add(errx.NamedError{
+				Field: field + ".Type",
+				Err:   ErrEventLinkType,
+			})
+		}
+		if !isWebURL(link.URL) {
+			errs.Add(errx.NamedError{
+				Field: field + ".URL",
+				Err:   ErrEventLinkURL,
+			})
+		}
+	}
+	return errs
+}
+
+// isWebURL абсолютный адрес http(s) с указанием хоста.
+func isWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -156,3 +156,21 @@ func TestEventUpdateValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateLinks(t *testing.T) {
+	require.Empty(t, ValidateLinks([]EventLink{
+		{Type: LinkVideo, URL: "https://meet.example.com/room"},
+		{Type: LinkDocument, URL: "http://docs.example.com/1", Title: "Повестка"},
+	}))
+	require.Equal(t, errx.NamedErrors{
+		{Field: "Links[0].Type", Err: ErrEventLinkType},
+		{Field: "Links[1].URL", Err: ErrEventLinkURL},
+		{Field: "Links[2].URL", Err: ErrEventLinkURL},
+	}, ValidateLinks([]EventLink{
+		{Type: LinkError, URL: "https://example.com"},
+		{Type: LinkDocument, URL: "javascript:alert(1)"},
+		{Type: LinkVideo, URL: "/relative"},
+	}))
+	links := make([]EventLink, MaxEventLinks+1)
+	require.Equal(t, errx.NamedErrors{{Field: "Links", Err: ErrEventLinksCount}}, ValidateLinks(links))
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyMaxLen максимальная длина ключа идемпотентности.
+const IdempotencyKeyMaxLen = 255
+
+// IdempotencyKey результат запроса на создание события, сохраненный по ключу идемпотентности.
+type IdempotencyKey struct {
+	Key    string
+	UserID uuid.UUID
+	// RequestHash хеш параметров запроса, повтор ключа с другими параметрами отклоняется.
+	RequestHash string
+	// Event созданное событие, nil - запрос еще выполняется или завершился ошибкой Failure.
+	Event *Event
+	// Failure ошибка запроса, повтор получает ее без выполнения запроса.
+	Failure   *IdempotencyFailure
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IdempotencyFailure ошибка клиента, сохраненная по ключу идемпотентности: вид и код ошибки
+// errx, сообщение и ошибки полей для ошибки валидации.
+type IdempotencyFailure struct {
+	Kind    byte
+	Code    int
+	Message string
+	Fields  []IdempotencyFieldError
+}
+
+type IdempotencyFieldError struct {
+	Field   string
+	Message string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "errors"
+
+const (
+	ErrIdempotencyKeyReusedCode     = 1013
+	ErrIdempotencyKeyInProgressCode = 1014
+	ErrIdempotencyKeyLengthCode     = 1015
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("ключ идемпотентности уже использован с другими параметрами")
+	ErrIdempotencyKeyInProgress = errors.New("запрос с этим ключом идемпотентности еще выполняется")
+	ErrIdempotencyKeyLength     = errors.New("слишком длинный ключ идемпотентности")
+)
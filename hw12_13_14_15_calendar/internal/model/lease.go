@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+// Lease аренда роли ведущего среди экземпляров сервиса, запущенных одновременно.
+// Аренда принадлежит Holder до ExpiresAt, затем ее может захватить любой экземпляр.
+type Lease struct {
+	Name      string
+	Holder    string
+	ExpiresAt time.Time
+	// OwnerID учетная запись, захватившая аренду: продлить и освободить аренду может только
+	// ее экземпляр, даже если Holder другой учетной записи совпадает.
+	OwnerID uuid.UUID
+}
+
+// LeaseAcquire модель захвата или продления аренды.
+type LeaseAcquire struct {
+	Name   string
+	Holder string
+	TTL    time.Duration
+}
+
+// Validate базовая валидация структуры.
+func (la LeaseAcquire) Validate() error {
+	var errs errx.NamedErrors
+	if la.Name == "" {
+		errs.Add(errx.NamedError{
+			Field: "Name",
+			Err:   ErrLeaseEmptyName,
+		})
+	}
+	if la.Holder == "" {
+		errs.Add(errx.NamedError{
+			Field: "Holder",
+			Err:   ErrLeaseEmptyHolder,
+		})
+	}
+	if la.TTL <= 0 {
+		errs.Add(errx.NamedError{
+			Field: "TTL",
+			Err:   ErrLeaseWrongTTL,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
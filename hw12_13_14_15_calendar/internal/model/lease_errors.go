@@ -0,0 +1,9 @@
+package model
+
+import "errors"
+
+var (
+	ErrLeaseEmptyName   = errors.New("не задано имя аренды")
+	ErrLeaseEmptyHolder = errors.New("не задан владелец аренды")
+	ErrLeaseWrongTTL    = errors.New("срок аренды должен быть положительным")
+)
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+var ErrorUnknownResourceKind = errors.New("unknown resource kind")
+
+// ResourceKind вид бронируемого ресурса.
+type ResourceKind int
+
+const (
+	// ResourceRoom переговорная комната.
+	ResourceRoom ResourceKind = iota
+	// ResourceEquipment оборудование: проектор, камера и т.п.
+	ResourceEquipment
+	ResourceError
+)
+
+func (rk ResourceKind) Valid() bool {
+	return rk < ResourceError
+}
+
+func (rk ResourceKind) String() string {
+	switch rk { //nolint:exhaustive // has def-value
+	case ResourceRoom:
+		return "room"
+	case ResourceEquipment:
+		return "equipment"
+	}
+	return ""
+}
+
+func ParseResourceKind(kind string) (ResourceKind, error) {
+	switch kind {
+	case "room":
+		return ResourceRoom, nil
+	case "equipment":
+		return ResourceEquipment, nil
+	}
+	return ResourceError, ErrorUnknownResourceKind
+}
+
+// Resource общий для всех пользователей ресурс, который бронируется событиями.
+// Изменять и удалять ресурс может только ответственный за него пользователь.
+type Resource struct {
+	ID        uuid.UUID
+	Kind      ResourceKind
+	Name      string
+	ManagerID uuid.UUID
+	// Capacity вместимость, 0 - не ограничена или не применима.
+	Capacity int
+	// Location расположение: здание, этаж, опционально.
+	Location string
+}
+
+// ResourceCreate модель создания ресурса.
+type ResourceCreate struct {
+	Kind      ResourceKind
+	Name      string
+	ManagerID uuid.UUID
+	Capacity  int
+	Location  string
+}
+
+// Validate базовая валидация структуры.
+func (rc ResourceCreate) Validate() error {
+	var errs errx.NamedErrors
+	if !rc.Kind.Valid() {
+		errs.Add(errx.NamedError{
+			Field: "Kind",
+			Err:   ErrResourceKind,
+		})
+	}
+	if rc.Name == "" {
+		errs.Add(errx.NamedError{
+			Field: "Name",
+			Err:   ErrResourceEmptyName,
+		})
+	}
+	if rc.ManagerID.ID() == 0 {
+		errs.Add(errx.NamedError{
+			Field: "ManagerID",
+			Err:   ErrResourceManagerID,
+		})
+	}
+	if rc.Capacity < 0 {
+		errs.Add(errx.NamedError{
+			Field: "Capacity",
+			Err:   ErrResourceCapacity,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// ResourceUpdate модель изменения ресурса, вид ресурса не меняется.
+type ResourceUpdate struct {
+	Name     *string
+	Capacity *int
+	Location *string
+	// ManagerID передача ответственности за ресурс другому пользователю.
+	ManagerID *uuid.UUID
+}
+
+// Validate базовая валидация структуры.
+func (ru ResourceUpdate) Validate() error {
+	var errs errx.NamedErrors
+	if ru.Name != nil && *ru.Name == "" {
+		errs.Add(errx.NamedError{
+			Field: "Name",
+			Err:   ErrResourceEmptyName,
+		})
+	}
+	if ru.Capacity != nil && *ru.Capacity < 0 {
+		errs.Add(errx.NamedError{
+			Field: "Capacity",
+			Err:   ErrResourceCapacity,
+		})
+	}
+	if ru.ManagerID != nil && ru.ManagerID.ID() == 0 {
+		errs.Add(errx.NamedError{
+			Field: "ManagerID",
+			Err:   ErrResourceManagerID,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// ResourceSearch модель поиска ресурсов.
+type ResourceSearch struct {
+	ID    *uuid.UUID
+	NotID *uuid.UUID
+	IDs   []uuid.UUID
+	Kind  *ResourceKind
+	Name  *string
+	// MinCapacity ресурсы вместимостью не меньше указанной.
+	MinCapacity *int
+}
+
+// ResourceBooking интервал, на который ресурс забронирован событием.
+type ResourceBooking struct {
+	ResourceID uuid.UUID
+	EventID    uuid.UUID
+	DateRange  DateRange
+	// BookedBy имя владельца события.
+	BookedBy string
+}
@@ -0,0 +1,17 @@
+package model
+
+import "errors"
+
+const (
+	ErrResourceNameBusyCode = 1021
+)
+
+var (
+	ErrResourceKind      = errors.New("неизвестный вид ресурса")
+	ErrResourceEmptyName = errors.New("не введено название ресурса")
+	ErrResourceManagerID = errors.New("не задан ответственный за ресурс")
+	ErrResourceCapacity  = errors.New("неверная вместимость ресурса")
+	ErrResourceNameBusy  = errors.New("ресурс с таким названием уже существует")
+	ErrResourceNotFound  = errors.New("указанный ресурс не найден")
+	ErrResourceManager   = errors.New("указанный ответственный не найден")
+)
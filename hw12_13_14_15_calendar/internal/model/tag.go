@@ -0,0 +1,89 @@
+package model
+
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+// tagColorRe цвет метки в формате #RRGGBB.
+var tagColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Tag пользовательская метка (категория) событий.
+type Tag struct {
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+	Name    string
+	Color   string
+}
+
+// TagCreate модель создания метки.
+type TagCreate struct {
+	OwnerID uuid.UUID
+	Name    string
+	Color   string
+}
+
+// Validate базовая валидация структуры.
+func (tc TagCreate) Validate() error {
+	var errs errx.NamedErrors
+	if tc.Name == "" {
+		errs.Add(errx.NamedError{
+			Field: "Name",
+			Err:   ErrTagEmptyName,
+		})
+	}
+	if !tagColorRe.MatchString(tc.Color) {
+		errs.Add(errx.NamedError{
+			Field: "Color",
+			Err:   ErrTagWrongColor,
+		})
+	}
+	if tc.OwnerID.ID() == 0 {
+		errs.Add(errx.NamedError{
+			Field: "OwnerID",
+			Err:   ErrTagOwnerID,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// TagUpdate модель изменения метки.
+type TagUpdate struct {
+	Name  *string
+	Color *string
+}
+
+// Validate базовая валидация структуры.
+func (tu TagUpdate) Validate() error {
+	var errs errx.NamedErrors
+	if tu.Name != nil && *tu.Name == "" {
+		errs.Add(errx.NamedError{
+			Field: "Name",
+			Err:   ErrTagEmptyName,
+		})
+	}
+	if tu.Color != nil && !tagColorRe.MatchString(*tu.Color) {
+		errs.Add(errx.NamedError{
+			Field: "Color",
+			Err:   ErrTagWrongColor,
+		})
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// TagSearch модель поиска меток.
+type TagSearch struct {
+	ID      *uuid.UUID
+	NotID   *uuid.UUID
+	IDs     []uuid.UUID
+	OwnerID *uuid.UUID
+	Name    *string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "errors"
+
+const (
+	ErrTagNameBusyCode = 1007
+)
+
+var (
+	ErrTagEmptyName  = errors.New("не введено название метки")
+	ErrTagWrongColor = errors.New("неверный цвет метки, ожидается #RRGGBB")
+	ErrTagOwnerID    = errors.New("не задан идентификатор владельца метки")
+	ErrTagNameBusy   = errors.New("метка с таким названием уже существует")
+	ErrTagNotFound   = errors.New("указанная метка не найдена")
+)
@@ -0,0 +1,13 @@
+package model
+
+// TrashFilter режим учета событий, помещенных в корзину, при поиске.
+type TrashFilter int
+
+const (
+	// TrashExclude события из корзины не выбираются (по умолчанию).
+	TrashExclude TrashFilter = iota
+	// TrashOnly выбираются только события из корзины.
+	TrashOnly
+	// TrashInclude выбираются все события.
+	TrashInclude
+)
@@ -2,6 +2,7 @@ package model
 
 import (
 	"net/mail"
+	"regexp"
 
 	"github.com/google/uuid"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
@@ -12,12 +13,23 @@ type User struct {
 	ID    uuid.UUID
 	Name  string
 	Email string
+	// Locale язык писем: ru, en, en-US; пустое значение - язык по умолчанию.
+	Locale string
+}
+
+// localeRe тег языка: код языка и необязательный регион.
+var localeRe = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// ValidLocale пустая строка допустима и означает язык по умолчанию.
+func ValidLocale(locale string) bool {
+	return locale == "" || localeRe.MatchString(locale)
 }
 
 // UserCreate модель создания пользователя.
 type UserCreate struct {
-	Name  string
-	Email string
+	Name   string
+	Email  string
+	Locale string
 }
 
 // Validate базовая валидация структуры.
@@ -36,6 +48,12 @@ func (uc UserCreate) Validate() error {
 			Err:   ErrUserWrongEmail,
 		})
 	}
+	if !ValidLocale(uc.Locale) {
+		errs.Add(errx.NamedError{
+			Field: "Locale",
+			Err:   ErrUserWrongLocale,
+		})
+	}
 	if errs.Empty() {
 		return nil
 	}
@@ -44,8 +62,9 @@ func (uc UserCreate) Validate() error {
 
 // UserUpdate модель изменения пользователя.
 type UserUpdate struct {
-	Name  *string
-	Email *string
+	Name   *string
+	Email  *string
+	Locale *string
 }
 
 // Validate базовая валидация структуры.
@@ -66,6 +85,12 @@ func (uu UserUpdate) Validate() error {
 			})
 		}
 	}
+	if uu.Locale != nil && !ValidLocale(*uu.Locale) {
+		errs.Add(errx.NamedError{
+			Field: "Locale",
+			Err:   ErrUserWrongLocale,
+		})
+	}
 	if errs.Empty() {
 		return nil
 	}
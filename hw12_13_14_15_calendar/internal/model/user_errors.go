@@ -2,10 +2,16 @@ package model
 
 import "errors"
 
+const (
+	ErrUserHasEventsCode = 1022
+)
+
 var (
 	ErrUserEmptyID        = errors.New("не задан ID пользователя")
 	ErrUserEmptyName      = errors.New("не введено имя пользователя")
 	ErrUserWrongEmail     = errors.New("неверный E-mail")
 	ErrUserDuplicateEmail = errors.New("пользователь с таким E-mail уже существует")
 	ErrUserNotFound       = errors.New("указанный пользователь не найден")
+	ErrUserWrongLocale    = errors.New("неверный язык, ожидается код языка с необязательным регионом: ru, en-US")
+	ErrUserHasEvents      = errors.New("у пользователя есть события, в том числе в корзине")
 )
@@ -38,11 +38,25 @@ func TestUserCreateValidate(t *testing.T) {
 					Err:   ErrUserWrongEmail,
 				},
 			},
+		}, {
+			name: "bad user create locale",
+			input: UserCreate{
+				Name:   "Test User",
+				Email:  "test@test.ru",
+				Locale: "en_us",
+			},
+			expected: []errx.NamedError{
+				{
+					Field: "Locale",
+					Err:   ErrUserWrongLocale,
+				},
+			},
 		}, {
 			name: "ok user create",
 			input: UserCreate{
-				Name:  "Test User",
-				Email: "test@test.ru",
+				Name:   "Test User",
+				Email:  "test@test.ru",
+				Locale: "en-US",
 			},
 			expected: nil,
 		},
@@ -70,6 +84,8 @@ func TestUserUpdateValidate(t *testing.T) {
 		emptyEmail string
 		wrongEmail = "erferf#2dfv"
 		okEmail    = "test@test.ru"
+		badLocale  = "English"
+		noLocale   string
 	)
 	testCases := []struct {
 		name     string
@@ -107,11 +123,23 @@ func TestUserUpdateValidate(t *testing.T) {
 					Err:   ErrUserWrongEmail,
 				},
 			},
+		}, {
+			name: "bad user update locale",
+			input: UserUpdate{
+				Locale: &badLocale,
+			},
+			expected: []errx.NamedError{
+				{
+					Field: "Locale",
+					Err:   ErrUserWrongLocale,
+				},
+			},
 		}, {
 			name: "ok user update",
 			input: UserUpdate{
-				Name:  &okName,
-				Email: &okEmail,
+				Name:   &okName,
+				Email:  &okEmail,
+				Locale: &noLocale,
 			},
 			expected: nil,
 		},
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type AttachmentRepo struct {
+	mu          sync.RWMutex
+	attachments []model.Attachment
+}
+
+func NewAttachmentRepo() repository.Attachment {
+	return &AttachmentRepo{}
+}
+
+func (ar *AttachmentRepo) Add(ctx context.Context, input model.AttachmentCreate) (*model.Attachment, error) {
+	attachment := model.Attachment{
+		ID:          uuid.New(),
+		EventID:     input.EventID,
+		FileName:    input.FileName,
+		ContentType: input.ContentType,
+		Size:        input.Size,
+		BlobKey:     input.BlobKey,
+		CreatedAt:   time.Now(),
+	}
+	ar.mu.Lock()
+	ar.attachments = append(ar.attachments, attachment)
+	ar.mu.Unlock()
+
+	return &attachment, nil
+}
+
+func (ar *AttachmentRepo) Delete(ctx context.Context, search model.AttachmentSearch) (int64, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	var n int64
+	result := make([]model.Attachment, 0)
+	for _, attachment := range ar.attachments {
+		if !ar.matchSearch(attachment, search) {
+			result = append(result, attachment)
+		} else {
+			n++
+		}
+	}
+	ar.attachments = result
+	return n, nil
+}
+
+// GetList вложения хранятся в порядке добавления.
+func (ar *AttachmentRepo) GetList(ctx context.Context, search model.AttachmentSearch) ([]model.Attachment, error) {
+	var filtered []model.Attachment
+	ar.mu.RLock()
+	for _, attachment := range ar.attachments {
+		if ar.matchSearch(attachment, search) {
+			filtered = append(filtered, attachment)
+		}
+	}
+	ar.mu.RUnlock()
+
+	return filtered, nil
+}
+
+func (ar *AttachmentRepo) matchSearch(attachment model.Attachment, search model.AttachmentSearch) bool {
+	if search.ID != nil && attachment.ID != *search.ID {
+		return false
+	}
+	if search.EventID != nil && attachment.EventID != *search.EventID {
+		return false
+	}
+	if search.EventIDs != nil && !containsID(search.EventIDs, attachment.EventID) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type AvailabilityRepo struct {
+	mu       sync.RWMutex
+	settings map[uuid.UUID]model.Availability
+	absences []model.OutOfOffice
+}
+
+func NewAvailabilityRepo() repository.Availability {
+	return &AvailabilityRepo{}
+}
+
+func (ar *AvailabilityRepo) Get(ctx context.Context, userID uuid.UUID) (*model.Availability, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+	settings, ok := ar.settings[userID]
+	if !ok {
+		return nil, nil
+	}
+	settings.Hours = append([]model.WorkingHours(nil), settings.Hours...)
+	return &settings, nil
+}
+
+func (ar *AvailabilityRepo) Save(ctx context.Context, input model.Availability) error {
+	input.Hours = append([]model.WorkingHours(nil), input.Hours...)
+	ar.mu.Lock()
+	if ar.settings == nil {
+		ar.settings = make(map[uuid.UUID]model.Availability)
+	}
+	ar.settings[input.UserID] = input
+	ar.mu.Unlock()
+	return nil
+}
+
+func (ar *AvailabilityRepo) AddOutOfOffice(
+	ctx context.Context,
+	input model.OutOfOfficeCreate,
+) (*model.OutOfOffice, error) {
+	ooo := model.OutOfOffice{
+		ID:       uuid.New(),
+		UserID:   input.UserID,
+		DateFrom: input.DateFrom,
+		DateTo:   input.DateTo,
+		Reason:   input.Reason,
+	}
+	ar.mu.Lock()
+	ar.absences = append(ar.absences, ooo)
+	ar.mu.Unlock()
+
+	return &ooo, nil
+}
+
+func (ar *AvailabilityRepo) DeleteOutOfOffice(ctx context.Context, search model.OutOfOfficeSearch) (int64, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	var n int64
+	result := make([]model.OutOfOffice, 0)
+	for _, ooo := range ar.absences {
+		if !ar.matchSearch(ooo, search) {
+			result = append(result, ooo)
+		} else {
+			n++
+		}
+	}
+	ar.absences = result
+	return n, nil
+}
+
+func (ar *AvailabilityRepo) GetOutOfOffice(
+	ctx context.Context,
+	search model.OutOfOfficeSearch,
+) ([]model.OutOfOffice, error) {
+	var filtered []model.OutOfOffice
+	ar.mu.RLock()
+	for _, ooo := range ar.absences {
+		if ar.matchSearch(ooo, search) {
+			filtered = append(filtered, ooo)
+		}
+	}
+	ar.mu.RUnlock()
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].DateFrom.Before(filtered[j].DateFrom)
+	})
+	return filtered, nil
+}
+
+func (ar *AvailabilityRepo) matchSearch(ooo model.OutOfOffice, search model.OutOfOfficeSearch) bool {
+	if search.ID != nil && ooo.ID != *search.ID {
+		return false
+	}
+	if search.UserID != nil && ooo.UserID != *search.UserID {
+		return false
+	}
+	if search.DateRange != nil && !ooo.Overlaps(*search.DateRange) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type DigestRepo struct {
+	mu       sync.RWMutex
+	settings map[uuid.UUID]model.DigestSettings
+}
+
+func NewDigestRepo() repository.Digest {
+	return &DigestRepo{}
+}
+
+func (dr *DigestRepo) Get(ctx context.Context, userID uuid.UUID) (*model.DigestSettings, error) {
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+	settings, ok := dr.settings[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &settings, nil
+}
+
+func (dr *DigestRepo) Save(ctx context.Context, input model.DigestSettings) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.settings == nil {
+		dr.settings = make(map[uuid.UUID]model.DigestSettings)
+	}
+	input.LastSentAt = dr.settings[input.UserID].LastSentAt
+	dr.settings[input.UserID] = input
+	return nil
+}
+
+func (dr *DigestRepo) GetEnabled(ctx context.Context) ([]model.DigestSettings, error) {
+	dr.mu.RLock()
+	enabled := make([]model.DigestSettings, 0)
+	for _, settings := range dr.settings {
+		if settings.Mode != model.DigestOff {
+			enabled = append(enabled, settings)
+		}
+	}
+	dr.mu.RUnlock()
+	sort.Slice(enabled, func(i, j int) bool {
+		return enabled[i].UserID.String() < enabled[j].UserID.String()
+	})
+	return enabled, nil
+}
+
+func (dr *DigestRepo) MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) (bool, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	settings, ok := dr.settings[userID]
+	if !ok || !settings.LastSentAt.Before(sentAt) {
+		return false, nil
+	}
+	settings.LastSentAt = sentAt
+	dr.settings[userID] = settings
+	return true, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+func TestDigestMemoryRepo(t *testing.T) {
+	digestRepo := DigestRepo{}
+	ctx := context.Background()
+	sendAt := time.Date(2023, 3, 29, 8, 0, 0, 0, time.UTC)
+	daily, off := uuid.New(), uuid.New()
+
+	settings, err := digestRepo.Get(ctx, daily)
+	require.NoError(t, err)
+	require.Nil(t, settings)
+	// настроек нет - отмечать нечего.
+	marked, err := digestRepo.MarkSent(ctx, daily, sendAt)
+	require.NoError(t, err)
+	require.False(t, marked)
+
+	require.NoError(t, digestRepo.Save(ctx, model.DigestSettings{UserID: daily, Mode: model.DigestDaily}))
+	require.NoError(t, digestRepo.Save(ctx, model.DigestSettings{UserID: off, Mode: model.DigestOff}))
+	enabled, err := digestRepo.GetEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	require.Equal(t, daily, enabled[0].UserID)
+
+	// сводку на одно время отмечает только первый вызов.
+	marked, err = digestRepo.MarkSent(ctx, daily, sendAt)
+	require.NoError(t, err)
+	require.True(t, marked)
+	marked, err = digestRepo.MarkSent(ctx, daily, sendAt)
+	require.NoError(t, err)
+	require.False(t, marked)
+
+	// изменение настроек не сбрасывает время отправки.
+	require.NoError(t, digestRepo.Save(ctx, model.DigestSettings{
+		UserID: daily, Mode: model.DigestWeekly, LastSentAt: time.Time{},
+	}))
+	settings, err = digestRepo.Get(ctx, daily)
+	require.NoError(t, err)
+	require.Equal(t, model.DigestWeekly, settings.Mode)
+	require.True(t, sendAt.Equal(settings.LastSentAt))
+}
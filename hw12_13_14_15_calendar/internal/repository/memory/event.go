@@ -2,6 +2,8 @@ package memory
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,47 +16,120 @@ import (
 type EventRepo struct {
 	mu     sync.RWMutex
 	events []model.Event
+	index  *textIndex
+	// tags источник названий и цветов меток, в событиях хранятся только ID меток.
+	tags repository.Tag
+	// resources источник данных ресурсов, в событиях хранятся только ID ресурсов.
+	resources repository.Resource
 }
 
-func NewEventRepo() repository.Event {
-	return &EventRepo{}
+func NewEventRepo(tags repository.Tag, resources repository.Resource) repository.Event {
+	er := &EventRepo{index: newTextIndex(), tags: tags, resources: resources}
+	if tagRepo, ok := tags.(*TagRepo); ok {
+		tagRepo.mu.Lock()
+		tagRepo.detach = er.detachTags
+		tagRepo.mu.Unlock()
+	}
+	return er
 }
 
 func (er *EventRepo) Add(ctx context.Context, input model.EventCreate) (*model.Event, error) {
-	event := model.Event{
-		ID:           uuid.New(),
-		Title:        input.Title,
-		Date:         input.Date,
-		Duration:     input.Duration,
-		NotifyStatus: model.NotifyStatusNone,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+	events, err := er.AddBatch(ctx, []model.EventCreate{input})
+	if err != nil {
+		return nil, err
 	}
-	if input.OwnerID.ID() > 0 {
-		event.Owner = &model.User{ID: input.OwnerID}
+	return &events[0], nil
+}
+
+func (er *EventRepo) AddBatch(ctx context.Context, inputs []model.EventCreate) ([]model.Event, error) {
+	events := make([]model.Event, len(inputs))
+	added := make(map[uuid.UUID]struct{}, len(inputs))
+	for i, input := range inputs {
+		events[i] = newEvent(input)
+		added[events[i].ID] = struct{}{}
 	}
-	if input.Description != nil {
-		event.Description = *input.Description
+	er.mu.Lock()
+	if err := er.checkQuota(inputs); err != nil {
+		er.mu.Unlock()
+		return nil, err
 	}
-	if input.NotifyTerm != nil {
-		event.NotifyTerm = *input.NotifyTerm
+	all := make([]model.Event, 0, len(er.events)+len(events))
+	all = append(append(all, er.events...), events...)
+	if err := er.checkResources(ctx, all, added); err != nil {
+		er.mu.Unlock()
+		return nil, err
+	}
+	er.events = all
+	for _, event := range events {
+		er.getIndex().put(event)
 	}
-	er.mu.Lock()
-	er.events = append(er.events, event)
 	er.mu.Unlock()
 
-	return &event, nil
+	return er.fillRelations(ctx, events)
 }
 
 func (er *EventRepo) Update(ctx context.Context, input model.EventUpdate, search model.EventSearch) (int64, error) {
 	er.mu.Lock()
 	defer er.mu.Unlock()
+	return er.commit(ctx, []model.EventBatchUpdate{{Input: input}}, []model.EventSearch{search})
+}
+
+func (er *EventRepo) UpdateBatch(ctx context.Context, items []model.EventBatchUpdate) (int64, error) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	searches := make([]model.EventSearch, len(items))
+	for i, item := range items {
+		eventID := item.ID
+		searches[i] = model.EventSearch{ID: &eventID}
+	}
+	return er.commit(ctx, items, searches)
+}
+
+// commit применяет изменения items[i].Input к событиям searches[i] все сразу или ни одного:
+// если у событий с новыми ресурсами или временем бронь пересекается с другими событиями,
+// события не меняются. Вызывается под блокировкой на запись.
+func (er *EventRepo) commit(
+	ctx context.Context,
+	items []model.EventBatchUpdate,
+	searches []model.EventSearch,
+) (int64, error) {
+	events := make([]model.Event, len(er.events))
+	copy(events, er.events)
 	var n int64
-	for i, event := range er.events {
-		if !er.matchSearch(event, search) {
+	changed := make(map[uuid.UUID]struct{})
+	rebooked := make(map[uuid.UUID]struct{})
+	for i, item := range items {
+		ids := er.update(events, item.Input, searches[i])
+		n += int64(len(ids))
+		rebook := item.Input.ResourceIDs != nil || item.Input.Date != nil || item.Input.Duration != nil
+		for _, id := range ids {
+			changed[id] = struct{}{}
+			if rebook {
+				rebooked[id] = struct{}{}
+			}
+		}
+	}
+	if err := er.checkResources(ctx, events, rebooked); err != nil {
+		return 0, err
+	}
+	er.events = events
+	for _, event := range events {
+		if _, ok := changed[event.ID]; ok {
+			er.getIndex().put(event)
+		}
+	}
+	return n, nil
+}
+
+// update изменяет события events, удовлетворяющие search, возвращает идентификаторы измененных событий.
+func (er *EventRepo) update(events []model.Event, input model.EventUpdate, search model.EventSearch) []uuid.UUID {
+	var ids []uuid.UUID
+	ranks := er.searchRanks(search)
+	for i, event := range events {
+		if !er.matchSearch(event, search, ranks) {
 			continue
 		}
-		n++
+		ids = append(ids, event.ID)
 		if input.Title != nil {
 			event.Title = *input.Title
 		}
@@ -73,10 +148,91 @@ func (er *EventRepo) Update(ctx context.Context, input model.EventUpdate, search
 		if input.NotifyStatus != nil {
 			event.NotifyStatus = *input.NotifyStatus
 		}
+		if input.TagIDs != nil {
+			event.Tags = tagRefs(*input.TagIDs)
+		}
+		if input.ResourceIDs != nil {
+			event.Resources = resourceRefs(*input.ResourceIDs)
+		}
+		if input.Links != nil {
+			event.Links = copyLinks(*input.Links)
+		}
 		event.UpdatedAt = time.Now()
-		er.events[i] = event
+		events[i] = event
 	}
-	return n, nil
+	return ids
+}
+
+// checkResources ошибка model.ErrEventResourceBusy, если события checked бронируют ресурс
+// на время, занятое другим событием из events; вызывается под блокировкой на запись.
+// checkQuota ошибка model.ErrEventQuota, если после добавления inputs событий владельца станет
+// больше его квоты, вызывается под блокировкой.
+func (er *EventRepo) checkQuota(inputs []model.EventCreate) error {
+	added := make(map[uuid.UUID]int64)
+	for _, input := range inputs {
+		if input.OwnerQuota > 0 {
+			added[input.OwnerID]++
+		}
+	}
+	for _, input := range inputs {
+		if input.OwnerQuota <= 0 {
+			continue
+		}
+		n := added[input.OwnerID]
+		for _, event := range er.events {
+			if event.Owner != nil && event.Owner.ID == input.OwnerID {
+				n++
+			}
+		}
+		if n > input.OwnerQuota {
+			return fmt.Errorf("%w: не более %d", model.ErrEventQuota, input.OwnerQuota)
+		}
+	}
+	return nil
+}
+
+func (er *EventRepo) checkResources(
+	ctx context.Context,
+	events []model.Event,
+	checked map[uuid.UUID]struct{},
+) error {
+	var busyIDs []uuid.UUID
+	for _, event := range events {
+		if _, ok := checked[event.ID]; !ok || event.DeletedAt != nil || len(event.Resources) == 0 {
+			continue
+		}
+		for _, other := range events {
+			overlaps := other.Date.Before(event.Date.Add(event.Duration)) &&
+				other.Date.Add(other.Duration).After(event.Date)
+			if other.ID == event.ID || other.DeletedAt != nil || !overlaps {
+				continue
+			}
+			for _, resource := range event.Resources {
+				if hasAnyResource(other, []uuid.UUID{resource.ID}) && !containsID(busyIDs, resource.ID) {
+					busyIDs = append(busyIDs, resource.ID)
+				}
+			}
+		}
+	}
+	if len(busyIDs) == 0 {
+		return nil
+	}
+	busy := make([]string, 0, len(busyIDs))
+	if er.resources != nil {
+		resources, err := er.resources.GetList(ctx, model.ResourceSearch{IDs: busyIDs})
+		if err != nil {
+			return err
+		}
+		for _, resource := range resources {
+			busy = append(busy, resource.Name)
+		}
+	} else {
+		for _, resourceID := range busyIDs {
+			busy = append(busy, resourceID.String())
+		}
+	}
+	sort.Strings(busy)
+	return fmt.Errorf("%w: %s", model.ErrEventResourceBusy, strings.Join(busy, ", "))
 }
 
 func (er *EventRepo) Delete(ctx context.Context, search model.EventSearch) (int64, error) {
@@ -84,30 +240,351 @@ func (er *EventRepo) Delete(ctx context.Context, search model.EventSearch) (int6
 	defer er.mu.Unlock()
 	var n int64
 	result := make([]model.Event, 0)
+	ranks := er.searchRanks(search)
 	for _, event := range er.events {
-		if !er.matchSearch(event, search) {
+		if !er.matchSearch(event, search, ranks) {
 			result = append(result, event)
+		} else {
+			er.getIndex().remove(event.ID)
+			n++
 		}
 	}
 	er.events = result
 	return n, nil
 }
 
+func (er *EventRepo) Trash(ctx context.Context, search model.EventSearch) (int64, error) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	var n int64
+	search.Trash = model.TrashExclude
+	now := time.Now()
+	ranks := er.searchRanks(search)
+	for i, event := range er.events {
+		if !er.matchSearch(event, search, ranks) {
+			continue
+		}
+		n++
+		deletedAt := now
+		er.events[i].DeletedAt = &deletedAt
+	}
+	return n, nil
+}
+
+func (er *EventRepo) Restore(ctx context.Context, search model.EventSearch) (int64, error) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	var n int64
+	search.Trash = model.TrashOnly
+	ranks := er.searchRanks(search)
+	for i, event := range er.events {
+		if !er.matchSearch(event, search, ranks) {
+			continue
+		}
+		n++
+		er.events[i].DeletedAt = nil
+	}
+	return n, nil
+}
+
 // GetList не учитываем пагинацию, сортировку.
 func (er *EventRepo) GetList(ctx context.Context, search model.EventSearch) ([]model.Event, error) {
 	var filtered []model.Event
 	er.mu.RLock()
+	ranks := er.searchRanks(search)
 	for _, event := range er.events {
-		if er.matchSearch(event, search) {
+		if er.matchSearch(event, search, ranks) {
 			filtered = append(filtered, event)
 		}
 	}
 	er.mu.RUnlock()
 
-	return filtered, nil
+	filtered, err := er.fillRelations(ctx, filtered)
+	if err != nil || (len(search.TagIDs) == 0 && len(search.ResourceIDs) == 0) {
+		return filtered, err
+	}
+	// метки и ресурсы могли быть удалены после установки, проверяем по актуальному набору.
+	result := filtered[:0]
+	for _, event := range filtered {
+		if len(search.TagIDs) > 0 && !hasAnyTag(event, search.TagIDs) {
+			continue
+		}
+		if len(search.ResourceIDs) > 0 && !hasAnyResource(event, search.ResourceIDs) {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (er *EventRepo) Count(ctx context.Context, search model.EventSearch) (int64, error) {
+	events, err := er.GetList(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(events)), nil
+}
+
+// Search полнотекстовый поиск, результаты упорядочены по убыванию релевантности.
+func (er *EventRepo) Search(ctx context.Context, search model.EventSearch) ([]model.EventFound, error) {
+	if search.Query == nil {
+		return nil, nil
+	}
+	terms := model.SearchTerms(*search.Query)
+	var found []model.EventFound
+	er.mu.RLock()
+	ranks := er.match(terms)
+	for _, event := range er.events {
+		if !er.matchSearch(event, search, ranks) {
+			continue
+		}
+		found = append(found, model.EventFound{
+			Event:   event,
+			Rank:    ranks[event.ID],
+			Snippet: buildSnippet(event, terms),
+		})
+	}
+	er.mu.RUnlock()
+
+	if len(found) > 0 {
+		events := make([]model.Event, len(found))
+		for i := range found {
+			events[i] = found[i].Event
+		}
+		events, err := er.fillRelations(ctx, events)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Event = events[i]
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Rank > found[j].Rank
+	})
+	return found, nil
 }
 
-func (er *EventRepo) matchSearch(event model.Event, search model.EventSearch) bool {
+// fillRelations заполняет метки и ресурсы событий.
+func (er *EventRepo) fillRelations(ctx context.Context, events []model.Event) ([]model.Event, error) {
+	events, err := er.fillTags(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	return er.fillResources(ctx, events)
+}
+
+// fillTags заменяет ссылки на метки полными данными меток, удаленные метки отбрасываются.
+func (er *EventRepo) fillTags(ctx context.Context, events []model.Event) ([]model.Event, error) {
+	if er.tags == nil {
+		return events, nil
+	}
+	var ids []uuid.UUID
+	for _, event := range events {
+		for _, tag := range event.Tags {
+			ids = append(ids, tag.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return events, nil
+	}
+	tags, err := er.tags.GetList(ctx, model.TagSearch{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	tagsMap := make(map[uuid.UUID]model.Tag, len(tags))
+	for _, tag := range tags {
+		tagsMap[tag.ID] = tag
+	}
+	for i, event := range events {
+		if len(event.Tags) == 0 {
+			continue
+		}
+		filled := make([]model.Tag, 0, len(event.Tags))
+		for _, ref := range event.Tags {
+			if tag, ok := tagsMap[ref.ID]; ok {
+				filled = append(filled, tag)
+			}
+		}
+		events[i].Tags = filled
+	}
+	return events, nil
+}
+
+// fillResources заменяет ссылки на ресурсы полными данными, удаленные ресурсы отбрасываются.
+func (er *EventRepo) fillResources(ctx context.Context, events []model.Event) ([]model.Event, error) {
+	if er.resources == nil {
+		return events, nil
+	}
+	var ids []uuid.UUID
+	for _, event := range events {
+		for _, resource := range event.Resources {
+			ids = append(ids, resource.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return events, nil
+	}
+	resources, err := er.resources.GetList(ctx, model.ResourceSearch{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	resourcesMap := make(map[uuid.UUID]model.Resource, len(resources))
+	for _, resource := range resources {
+		resourcesMap[resource.ID] = resource
+	}
+	for i, event := range events {
+		if len(event.Resources) == 0 {
+			continue
+		}
+		filled := make([]model.Resource, 0, len(event.Resources))
+		for _, ref := range event.Resources {
+			if resource, ok := resourcesMap[ref.ID]; ok {
+				filled = append(filled, resource)
+			}
+		}
+		events[i].Resources = filled
+	}
+	return events, nil
+}
+
+func newEvent(input model.EventCreate) model.Event {
+	event := model.Event{
+		ID:           uuid.New(),
+		Title:        input.Title,
+		Date:         input.Date,
+		Duration:     input.Duration,
+		NotifyStatus: model.NotifyStatusNone,
+		ICalUID:      input.ICalUID,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if input.OwnerID.ID() > 0 {
+		event.Owner = &model.User{ID: input.OwnerID}
+	}
+	if input.Description != nil {
+		event.Description = *input.Description
+	}
+	if input.NotifyTerm != nil {
+		event.NotifyTerm = *input.NotifyTerm
+	}
+	event.Tags = tagRefs(input.TagIDs)
+	event.Resources = resourceRefs(input.ResourceIDs)
+	event.Links = copyLinks(input.Links)
+	return event
+}
+
+// copyLinks хранимые ссылки не разделяют массив с входными данными.
+func copyLinks(links []model.EventLink) []model.EventLink {
+	if len(links) == 0 {
+		return nil
+	}
+	return append([]model.EventLink(nil), links...)
+}
+
+func hasAnyTag(event model.Event, tagIDs []uuid.UUID) bool {
+	for _, tag := range event.Tags {
+		if containsID(tagIDs, tag.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// detachTags снимает удаленные метки со всех событий, в том числе из корзины.
+func (er *EventRepo) detachTags(tagIDs []uuid.UUID) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	for i, event := range er.events {
+		if !hasAnyTag(event, tagIDs) {
+			continue
+		}
+		kept := make([]model.Tag, 0, len(event.Tags))
+		for _, tag := range event.Tags {
+			if !containsID(tagIDs, tag.ID) {
+				kept = append(kept, tag)
+			}
+		}
+		er.events[i].Tags = kept
+	}
+}
+
+func tagRefs(tagIDs []uuid.UUID) []model.Tag {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	refs := make([]model.Tag, len(tagIDs))
+	for i, tagID := range tagIDs {
+		refs[i] = model.Tag{ID: tagID}
+	}
+	return refs
+}
+
+func hasAnyResource(event model.Event, resourceIDs []uuid.UUID) bool {
+	for _, resource := range event.Resources {
+		if containsID(resourceIDs, resource.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceRefs(resourceIDs []uuid.UUID) []model.Resource {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+	refs := make([]model.Resource, len(resourceIDs))
+	for i, resourceID := range resourceIDs {
+		refs[i] = model.Resource{ID: resourceID}
+	}
+	return refs
+}
+
+// getIndex ленивая инициализация индекса для EventRepo{}, вызывается под блокировкой на запись.
+func (er *EventRepo) getIndex() *textIndex {
+	if er.index == nil {
+		er.index = newTextIndex()
+	}
+	return er.index
+}
+
+func (er *EventRepo) match(terms []string) map[uuid.UUID]float64 {
+	if er.index == nil {
+		return nil
+	}
+	return er.index.match(terms)
+}
+
+// searchRanks релевантность событий по search.Query, считается один раз на вызов и передается в matchSearch.
+func (er *EventRepo) searchRanks(search model.EventSearch) map[uuid.UUID]float64 {
+	if search.Query == nil {
+		return nil
+	}
+	return er.match(model.SearchTerms(*search.Query))
+}
+
+func (er *EventRepo) matchSearch(event model.Event, search model.EventSearch, ranks map[uuid.UUID]float64) bool {
+	if search.Query != nil {
+		if _, ok := ranks[event.ID]; !ok {
+			return false
+		}
+	}
+	switch search.Trash {
+	case model.TrashExclude:
+		if event.DeletedAt != nil {
+			return false
+		}
+	case model.TrashOnly:
+		if event.DeletedAt == nil {
+			return false
+		}
+	case model.TrashInclude:
+	}
+	if search.DeletedLess != nil {
+		if event.DeletedAt == nil || !event.DeletedAt.Before(*search.DeletedLess) {
+			return false
+		}
+	}
 	if search.ID != nil {
 		if strings.Compare(event.ID.String(), search.ID.String()) != 0 {
 			return false
@@ -118,11 +595,23 @@ func (er *EventRepo) matchSearch(event model.Event, search model.EventSearch) bo
 			return false
 		}
 	}
+	if search.IDs != nil && !containsID(search.IDs, event.ID) {
+		return false
+	}
 	if search.OwnerID != nil {
 		if strings.Compare(event.Owner.ID.String(), search.OwnerID.String()) != 0 {
 			return false
 		}
 	}
+	if search.ICalUID != nil && event.ICalUID != *search.ICalUID {
+		return false
+	}
+	if len(search.TagIDs) > 0 && !hasAnyTag(event, search.TagIDs) {
+		return false
+	}
+	if len(search.ResourceIDs) > 0 && !hasAnyResource(event, search.ResourceIDs) {
+		return false
+	}
 	if search.DateRange != nil {
 		evStart := event.Date
 		evEnd := event.Date
@@ -154,7 +643,7 @@ func (er *EventRepo) BlockEvents4Notify(ctx context.Context, now time.Time) ([]m
 	er.mu.Lock()
 	defer er.mu.Unlock()
 	for i, event := range er.events {
-		if er.matchSearch(event, search) {
+		if er.matchSearch(event, search, nil) {
 			er.events[i].NotifyStatus = model.NotifyStatusBlocked
 			filtered = append(filtered, event)
 		}
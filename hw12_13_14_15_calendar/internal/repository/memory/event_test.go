@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -98,4 +99,259 @@ func TestEventMemoryRepo(t *testing.T) {
 		})
 		require.Equal(t, 0, len(actual))
 	})
+
+	t.Run("trash test", func(t *testing.T) {
+		eventRepo := EventRepo{}
+		ctx := context.Background()
+		baseDate := time.Now()
+		userID, _ := uuid.Parse("ab8e3706-7ad8-11ed-95f7-d00d1b9e4cfe")
+
+		ids := make([]uuid.UUID, 3)
+		for i := range ids {
+			event, err := eventRepo.Add(ctx, model.EventCreate{
+				Title:    "title",
+				Date:     baseDate.Add(time.Duration(i+1) * time.Hour * 24),
+				Duration: time.Hour,
+				OwnerID:  userID,
+			})
+			require.NoError(t, err)
+			ids[i] = event.ID
+		}
+
+		n, err := eventRepo.Trash(ctx, model.EventSearch{ID: &ids[0]})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		// повторно в корзину не помещается.
+		n, _ = eventRepo.Trash(ctx, model.EventSearch{ID: &ids[0]})
+		require.Equal(t, int64(0), n)
+
+		actual, _ := eventRepo.GetList(ctx, model.EventSearch{})
+		require.Equal(t, 2, len(actual))
+
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{Trash: model.TrashOnly})
+		require.Equal(t, 1, len(actual))
+		require.Equal(t, ids[0], actual[0].ID)
+		require.NotNil(t, actual[0].DeletedAt)
+
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{Trash: model.TrashInclude})
+		require.Equal(t, 3, len(actual))
+
+		n, _ = eventRepo.Restore(ctx, model.EventSearch{ID: &ids[0]})
+		require.Equal(t, int64(1), n)
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{ID: &ids[0]})
+		require.Equal(t, 1, len(actual))
+		require.Nil(t, actual[0].DeletedAt)
+
+		_, _ = eventRepo.Trash(ctx, model.EventSearch{ID: &ids[1]})
+		deletedLess := time.Now().Add(time.Hour)
+		// удаление из корзины не затрагивает остальные события.
+		n, _ = eventRepo.Delete(ctx, model.EventSearch{Trash: model.TrashOnly, DeletedLess: &deletedLess})
+		require.Equal(t, int64(1), n)
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{Trash: model.TrashInclude})
+		require.Equal(t, 2, len(actual))
+	})
+
+	t.Run("search test", func(t *testing.T) {
+		eventRepo := EventRepo{}
+		ctx := context.Background()
+		baseDate := time.Now()
+		userID1, _ := uuid.Parse("ab8e3706-7ad8-11ed-95f7-d00d1b9e4cfe")
+		userID2, _ := uuid.Parse("90bdce82-7ad8-11ed-99c1-d00d1b9e4cfe")
+
+		inputs := []struct {
+			title       string
+			description string
+			ownerID     uuid.UUID
+		}{
+			{"Планерка команды", "Обсуждение релиза", userID1},
+			{"Релиз календаря", "Выкладка на прод, планерка после", userID1},
+			{"Обед", "", userID1},
+			{"Релиз", "", userID2},
+		}
+		ids := make([]uuid.UUID, len(inputs))
+		for i, input := range inputs {
+			description := input.description
+			event, err := eventRepo.Add(ctx, model.EventCreate{
+				Title:       input.title,
+				Date:        baseDate.Add(time.Duration(i+1) * time.Hour * 24),
+				Duration:    time.Hour,
+				OwnerID:     input.ownerID,
+				Description: &description,
+			})
+			require.NoError(t, err)
+			ids[i] = event.ID
+		}
+
+		query := "рели"
+		found, err := eventRepo.Search(ctx, model.EventSearch{OwnerID: &userID1, Query: &query})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(found))
+		// совпадение в заголовке важнее совпадения в описании.
+		require.Equal(t, ids[1], found[0].Event.ID)
+		require.Equal(t, ids[0], found[1].Event.ID)
+		require.Equal(t, "<b>Релиз</b> календаря Выкладка на прод, планерка после", found[0].Snippet)
+		require.Equal(t, "Планерка команды Обсуждение <b>релиза</b>", found[1].Snippet)
+
+		query = "Планерка, РЕЛИЗ!"
+		found, _ = eventRepo.Search(ctx, model.EventSearch{OwnerID: &userID1, Query: &query})
+		require.Equal(t, 2, len(found))
+
+		query = "обед релиз"
+		found, _ = eventRepo.Search(ctx, model.EventSearch{Query: &query})
+		require.Equal(t, 0, len(found))
+
+		title := "Ужин"
+		_, _ = eventRepo.Update(ctx, model.EventUpdate{Title: &title}, model.EventSearch{ID: &ids[2]})
+		query = "обе"
+		actual, _ := eventRepo.GetList(ctx, model.EventSearch{Query: &query})
+		require.Equal(t, 0, len(actual))
+		query = "ужин"
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{Query: &query})
+		require.Equal(t, 1, len(actual))
+
+		_, _ = eventRepo.Delete(ctx, model.EventSearch{ID: &ids[3]})
+		query = "релиз"
+		found, _ = eventRepo.Search(ctx, model.EventSearch{Query: &query})
+		require.Equal(t, 2, len(found))
+	})
+
+	t.Run("tags test", func(t *testing.T) {
+		tagRepo := NewTagRepo()
+		eventRepo := NewEventRepo(tagRepo, nil)
+		ctx := context.Background()
+		baseDate := time.Now()
+		userID, _ := uuid.Parse("ab8e3706-7ad8-11ed-95f7-d00d1b9e4cfe")
+
+		work, _ := tagRepo.Add(ctx, model.TagCreate{OwnerID: userID, Name: "Работа", Color: "#FF0000"})
+		home, _ := tagRepo.Add(ctx, model.TagCreate{OwnerID: userID, Name: "Дом", Color: "#00FF00"})
+
+		tagSets := [][]uuid.UUID{{work.ID}, {work.ID, home.ID}, nil}
+		ids := make([]uuid.UUID, len(tagSets))
+		for i, tagIDs := range tagSets {
+			event, err := eventRepo.Add(ctx, model.EventCreate{
+				Title:    "title",
+				Date:     baseDate.Add(time.Duration(i+1) * time.Hour * 24),
+				Duration: time.Hour,
+				OwnerID:  userID,
+				TagIDs:   tagIDs,
+			})
+			require.NoError(t, err)
+			ids[i] = event.ID
+		}
+
+		actual, _ := eventRepo.GetList(ctx, model.EventSearch{ID: &ids[1]})
+		require.Equal(t, []model.Tag{*work, *home}, actual[0].Tags)
+
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{TagIDs: []uuid.UUID{home.ID}})
+		require.Equal(t, 1, len(actual))
+
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{TagIDs: []uuid.UUID{work.ID, home.ID}})
+		require.Equal(t, 2, len(actual))
+
+		// пустой набор снимает все метки.
+		_, _ = eventRepo.Update(ctx, model.EventUpdate{TagIDs: &[]uuid.UUID{}}, model.EventSearch{ID: &ids[0]})
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{TagIDs: []uuid.UUID{work.ID}})
+		require.Equal(t, 1, len(actual))
+
+		// удаленная метка не возвращается и не участвует в фильтре.
+		_, _ = tagRepo.Delete(ctx, model.TagSearch{ID: &work.ID})
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{ID: &ids[1]})
+		require.Equal(t, []model.Tag{*home}, actual[0].Tags)
+		actual, _ = eventRepo.GetList(ctx, model.EventSearch{TagIDs: []uuid.UUID{work.ID}})
+		require.Equal(t, 0, len(actual))
+	})
+	t.Run("resource booking", func(t *testing.T) {
+		ctx := context.Background()
+		resourceRepo := NewResourceRepo()
+		eventRepo := NewEventRepo(nil, resourceRepo)
+		room, err := resourceRepo.Add(ctx, model.ResourceCreate{Kind: model.ResourceRoom, Name: "Переговорная"})
+		require.NoError(t, err)
+		date := time.Date(2023, 4, 24, 10, 0, 0, 0, time.UTC)
+
+		// из параллельных броней одного ресурса на пересекающееся время проходит одна.
+		const n = 10
+		errs := make(chan error, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := eventRepo.Add(ctx, model.EventCreate{
+					Title:       "Совещание",
+					Date:        date.Add(time.Duration(i) * time.Minute),
+					Duration:    time.Hour,
+					OwnerID:     uuid.New(),
+					ResourceIDs: []uuid.UUID{room.ID},
+				})
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		var booked int
+		for err := range errs {
+			if err == nil {
+				booked++
+				continue
+			}
+			require.ErrorIs(t, err, model.ErrEventResourceBusy)
+		}
+		require.Equal(t, 1, booked)
+
+		// перенос на занятое время отклоняет пакет целиком.
+		free, err := eventRepo.Add(ctx, model.EventCreate{
+			Title:       "Планерка",
+			Date:        date.Add(3 * time.Hour),
+			Duration:    time.Hour,
+			OwnerID:     uuid.New(),
+			ResourceIDs: []uuid.UUID{room.ID},
+		})
+		require.NoError(t, err)
+		title := "Планерка (перенос)"
+		_, err = eventRepo.UpdateBatch(ctx, []model.EventBatchUpdate{
+			{ID: free.ID, Input: model.EventUpdate{Title: &title}},
+			{ID: free.ID, Input: model.EventUpdate{Date: &date}},
+		})
+		require.ErrorIs(t, err, model.ErrEventResourceBusy)
+		actual, _ := eventRepo.GetList(ctx, model.EventSearch{ID: &free.ID})
+		require.Equal(t, "Планерка", actual[0].Title)
+	})
+
+	t.Run("owner quota", func(t *testing.T) {
+		ctx := context.Background()
+		eventRepo := NewEventRepo(nil, nil)
+		ownerID := uuid.New()
+		date := time.Date(2023, 4, 24, 10, 0, 0, 0, time.UTC)
+
+		// из параллельных добавлений проходят только умещающиеся в квоту.
+		const n, quota = 10, 3
+		errs := make(chan error, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := eventRepo.Add(ctx, model.EventCreate{
+					Title:      "Событие",
+					Date:       date.Add(time.Duration(i) * time.Hour),
+					Duration:   time.Hour,
+					OwnerID:    ownerID,
+					OwnerQuota: quota,
+				})
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		var added int
+		for err := range errs {
+			if err == nil {
+				added++
+				continue
+			}
+			require.ErrorIs(t, err, model.ErrEventQuota)
+		}
+		require.Equal(t, quota, added)
+	})
 }
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type idempotencyID struct {
+	userID uuid.UUID
+	key    string
+}
+
+type IdempotencyRepo struct {
+	mu   sync.RWMutex
+	keys map[idempotencyID]model.IdempotencyKey
+}
+
+func NewIdempotencyRepo() repository.Idempotency {
+	return &IdempotencyRepo{}
+}
+
+func (ir *IdempotencyRepo) Get(
+	ctx context.Context, userID uuid.UUID, key string, now time.Time,
+) (*model.IdempotencyKey, error) {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+	stored, ok := ir.keys[idempotencyID{userID: userID, key: key}]
+	if !ok || !stored.ExpiresAt.After(now) {
+		return nil, nil
+	}
+	stored.Event = copyEvent(stored.Event)
+	stored.Failure = copyFailure(stored.Failure)
+	return &stored, nil
+}
+
+func (ir *IdempotencyRepo) Reserve(ctx context.Context, input model.IdempotencyKey) (bool, error) {
+	id := idempotencyID{userID: input.UserID, key: input.Key}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if ir.keys == nil {
+		ir.keys = make(map[idempotencyID]model.IdempotencyKey)
+	}
+	if stored, ok := ir.keys[id]; ok && stored.ExpiresAt.After(input.CreatedAt) {
+		return false, nil
+	}
+	input.Event, input.Failure = nil, nil
+	ir.keys[id] = input
+	return true, nil
+}
+
+func (ir *IdempotencyRepo) Complete(ctx context.Context, userID uuid.UUID, key string, event model.Event) error {
+	id := idempotencyID{userID: userID, key: key}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if stored, ok := ir.keys[id]; ok {
+		stored.Event = copyEvent(&event)
+		ir.keys[id] = stored
+	}
+	return nil
+}
+
+func (ir *IdempotencyRepo) Fail(
+	ctx context.Context, userID uuid.UUID, key string, failure model.IdempotencyFailure,
+) error {
+	id := idempotencyID{userID: userID, key: key}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if stored, ok := ir.keys[id]; ok {
+		stored.Failure = copyFailure(&failure)
+		ir.keys[id] = stored
+	}
+	return nil
+}
+
+func (ir *IdempotencyRepo) Delete(ctx context.Context, userID uuid.UUID, key string) error {
+	ir.mu.Lock()
+	delete(ir.keys, idempotencyID{userID: userID, key: key})
+	ir.mu.Unlock()
+	return nil
+}
+
+func (ir *IdempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var n int64
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	for id, stored := range ir.keys {
+		if !stored.ExpiresAt.After(now) {
+			delete(ir.keys, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// copyEvent копия события, чтобы сохраненный результат не менялся вызывающим кодом.
+func copyEvent(event *model.Event) *model.Event {
+	if event == nil {
+		return nil
+	}
+	cp := *event
+	cp.Tags = append([]model.Tag(nil), event.Tags...)
+	cp.Warnings = append([]string(nil), event.Warnings...)
+	if event.Owner != nil {
+		owner := *event.Owner
+		cp.Owner = &owner
+	}
+	return &cp
+}
+
+func copyFailure(failure *model.IdempotencyFailure) *model.IdempotencyFailure {
+	if failure == nil {
+		return nil
+	}
+	cp := *failure
+	cp.Fields = append([]model.IdempotencyFieldError(nil), failure.Fields...)
+	return &cp
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+func TestIdempotencyMemoryRepo(t *testing.T) {
+	t.Run("complex test", func(t *testing.T) {
+		keysRepo := IdempotencyRepo{}
+		ctx := context.Background()
+		userID1, _ := uuid.Parse("ab8e3706-7ad8-11ed-95f7-d00d1b9e4cfe")
+		userID2, _ := uuid.Parse("90bdce82-7ad8-11ed-99c1-d00d1b9e4cfe")
+		now := time.Date(2023, 3, 10, 9, 0, 0, 0, time.UTC)
+
+		reserve := func(userID uuid.UUID, at time.Time) bool {
+			ok, err := keysRepo.Reserve(ctx, model.IdempotencyKey{
+				Key:         "key-1",
+				UserID:      userID,
+				RequestHash: "hash",
+				CreatedAt:   at,
+				ExpiresAt:   at.Add(time.Hour),
+			})
+			require.NoError(t, err)
+			return ok
+		}
+		require.True(t, reserve(userID1, now))
+		require.False(t, reserve(userID1, now.Add(time.Minute)))
+		// ключи разных пользователей не пересекаются.
+		require.True(t, reserve(userID2, now))
+
+		stored, err := keysRepo.Get(ctx, userID1, "key-1", now)
+		require.NoError(t, err)
+		require.Nil(t, stored.Event)
+
+		event := model.Event{ID: uuid.New(), Title: "Созвон", Warnings: []string{"вне рабочего времени"}}
+		require.NoError(t, keysRepo.Complete(ctx, userID1, "key-1", event))
+		event.Warnings[0] = "изменено"
+		stored, _ = keysRepo.Get(ctx, userID1, "key-1", now)
+		require.Equal(t, event.ID, stored.Event.ID)
+		require.Equal(t, []string{"вне рабочего времени"}, stored.Event.Warnings)
+
+		// истекший ключ не возвращается и может быть занят заново.
+		expired := now.Add(time.Hour)
+		stored, _ = keysRepo.Get(ctx, userID1, "key-1", expired)
+		require.Nil(t, stored)
+		require.True(t, reserve(userID1, expired))
+		stored, _ = keysRepo.Get(ctx, userID1, "key-1", expired)
+		require.Nil(t, stored.Event)
+
+		failure := model.IdempotencyFailure{Kind: 1, Code: 1001, Message: "время занято"}
+		require.NoError(t, keysRepo.Fail(ctx, userID1, "key-1", failure))
+		stored, _ = keysRepo.Get(ctx, userID1, "key-1", expired)
+		require.Nil(t, stored.Event)
+		require.Equal(t, failure, *stored.Failure)
+
+		require.NoError(t, keysRepo.Delete(ctx, userID1, "key-1"))
+		stored, _ = keysRepo.Get(ctx, userID1, "key-1", expired)
+		require.Nil(t, stored)
+
+		n, err := keysRepo.DeleteExpired(ctx, expired)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		require.Empty(t, keysRepo.keys)
+	})
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type LeaseRepo struct {
+	mu     sync.Mutex
+	leases map[string]model.Lease
+}
+
+func NewLeaseRepo() repository.Lease {
+	return &LeaseRepo{}
+}
+
+func (lr *LeaseRepo) Acquire(ctx context.Context, lease model.Lease, now time.Time) (*model.Lease, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	if lr.leases == nil {
+		lr.leases = make(map[string]model.Lease)
+	}
+	stored, ok := lr.leases[lease.Name]
+	if !ok || stored.Holder == lease.Holder && stored.OwnerID == lease.OwnerID || !stored.ExpiresAt.After(now) {
+		lr.leases[lease.Name] = lease
+		stored = lease
+	}
+	return &stored, nil
+}
+
+func (lr *LeaseRepo) Release(ctx context.Context, name, holder string, ownerID uuid.UUID) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	if stored, ok := lr.leases[name]; ok && stored.Holder == holder && stored.OwnerID == ownerID {
+		delete(lr.leases, name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+func TestLeaseMemoryRepo(t *testing.T) {
+	leaseRepo := LeaseRepo{}
+	ctx := context.Background()
+	now := time.Date(2023, 3, 22, 9, 0, 0, 0, time.UTC)
+	owner, intruder := uuid.New(), uuid.New()
+
+	acquireAs := func(ownerID uuid.UUID, holder string, at time.Time) string {
+		lease, err := leaseRepo.Acquire(ctx, model.Lease{
+			Name:      "scheduler",
+			Holder:    holder,
+			ExpiresAt: at.Add(time.Minute),
+			OwnerID:   ownerID,
+		}, at)
+		require.NoError(t, err)
+		return lease.Holder
+	}
+	acquire := func(holder string, at time.Time) string {
+		return acquireAs(owner, holder, at)
+	}
+	require.Equal(t, "first", acquire("first", now))
+	// аренда занята до истечения срока.
+	require.Equal(t, "first", acquire("second", now.Add(30*time.Second)))
+	// владелец продлевает аренду.
+	require.Equal(t, "first", acquire("first", now.Add(50*time.Second)))
+	require.Equal(t, "first", acquire("second", now.Add(90*time.Second)))
+	// истекшую аренду захватывает другой экземпляр.
+	require.Equal(t, "second", acquire("second", now.Add(2*time.Minute)))
+
+	// другая учетная запись с тем же holder аренду не продлевает и не освобождает.
+	require.Equal(t, "second", acquireAs(intruder, "second", now.Add(2*time.Minute)))
+	require.NoError(t, leaseRepo.Release(ctx, "scheduler", "second", intruder))
+	require.Equal(t, "second", acquire("first", now.Add(2*time.Minute)))
+
+	// чужую аренду освободить нельзя.
+	require.NoError(t, leaseRepo.Release(ctx, "scheduler", "first", owner))
+	require.Equal(t, "second", acquire("first", now.Add(2*time.Minute)))
+	require.NoError(t, leaseRepo.Release(ctx, "scheduler", "second", owner))
+	require.Equal(t, "first", acquire("first", now.Add(2*time.Minute)))
+}
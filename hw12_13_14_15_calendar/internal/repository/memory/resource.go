@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type ResourceRepo struct {
+	mu        sync.RWMutex
+	resources []model.Resource
+}
+
+func NewResourceRepo() repository.Resource {
+	return &ResourceRepo{}
+}
+
+func (rr *ResourceRepo) Add(ctx context.Context, input model.ResourceCreate) (*model.Resource, error) {
+	resource := model.Resource{
+		ID:        uuid.New(),
+		Kind:      input.Kind,
+		Name:      input.Name,
+		ManagerID: input.ManagerID,
+		Capacity:  input.Capacity,
+		Location:  input.Location,
+	}
+	rr.mu.Lock()
+	rr.resources = append(rr.resources, resource)
+	rr.mu.Unlock()
+
+	return &resource, nil
+}
+
+func (rr *ResourceRepo) Update(
+	ctx context.Context, input model.ResourceUpdate, search model.ResourceSearch,
+) (int64, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	var n int64
+	for i, resource := range rr.resources {
+		if !rr.matchSearch(resource, search) {
+			continue
+		}
+		n++
+		if input.Name != nil {
+			resource.Name = *input.Name
+		}
+		if input.Capacity != nil {
+			resource.Capacity = *input.Capacity
+		}
+		if input.Location != nil {
+			resource.Location = *input.Location
+		}
+		if input.ManagerID != nil {
+			resource.ManagerID = *input.ManagerID
+		}
+		rr.resources[i] = resource
+	}
+	return n, nil
+}
+
+func (rr *ResourceRepo) Delete(ctx context.Context, search model.ResourceSearch) (int64, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	var n int64
+	result := make([]model.Resource, 0)
+	for _, resource := range rr.resources {
+		if !rr.matchSearch(resource, search) {
+			result = append(result, resource)
+		} else {
+			n++
+		}
+	}
+	rr.resources = result
+	return n, nil
+}
+
+// GetList не учитываем пагинацию, сортировка по названию.
+func (rr *ResourceRepo) GetList(ctx context.Context, search model.ResourceSearch) ([]model.Resource, error) {
+	var filtered []model.Resource
+	rr.mu.RLock()
+	for _, resource := range rr.resources {
+		if rr.matchSearch(resource, search) {
+			filtered = append(filtered, resource)
+		}
+	}
+	rr.mu.RUnlock()
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Name < filtered[j].Name
+	})
+	return filtered, nil
+}
+
+func (rr *ResourceRepo) matchSearch(resource model.Resource, search model.ResourceSearch) bool {
+	if search.ID != nil && resource.ID != *search.ID {
+		return false
+	}
+	if search.NotID != nil && resource.ID == *search.NotID {
+		return false
+	}
+	if search.IDs != nil && !containsID(search.IDs, resource.ID) {
+		return false
+	}
+	if search.Kind != nil && resource.Kind != *search.Kind {
+		return false
+	}
+	if search.Name != nil && !strings.EqualFold(resource.Name, *search.Name) {
+		return false
+	}
+	if search.MinCapacity != nil && resource.Capacity < *search.MinCapacity {
+		return false
+	}
+	return true
+}
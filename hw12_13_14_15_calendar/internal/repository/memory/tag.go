@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type TagRepo struct {
+	mu   sync.RWMutex
+	tags []model.Tag
+	// detach снимает удаленные метки с событий, как каскад event_tags в pgsql.
+	detach func(tagIDs []uuid.UUID)
+}
+
+func NewTagRepo() repository.Tag {
+	return &TagRepo{}
+}
+
+func (tr *TagRepo) Add(ctx context.Context, input model.TagCreate) (*model.Tag, error) {
+	tag := model.Tag{
+		ID:      uuid.New(),
+		OwnerID: input.OwnerID,
+		Name:    input.Name,
+		Color:   input.Color,
+	}
+	tr.mu.Lock()
+	tr.tags = append(tr.tags, tag)
+	tr.mu.Unlock()
+
+	return &tag, nil
+}
+
+func (tr *TagRepo) Update(ctx context.Context, input model.TagUpdate, search model.TagSearch) (int64, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	var n int64
+	for i, tag := range tr.tags {
+		if !tr.matchSearch(tag, search) {
+			continue
+		}
+		n++
+		if input.Name != nil {
+			tag.Name = *input.Name
+		}
+		if input.Color != nil {
+			tag.Color = *input.Color
+		}
+		tr.tags[i] = tag
+	}
+	return n, nil
+}
+
+func (tr *TagRepo) Delete(ctx context.Context, search model.TagSearch) (int64, error) {
+	tr.mu.Lock()
+	var deleted []uuid.UUID
+	result := make([]model.Tag, 0)
+	for _, tag := range tr.tags {
+		if !tr.matchSearch(tag, search) {
+			result = append(result, tag)
+		} else {
+			deleted = append(deleted, tag.ID)
+		}
+	}
+	tr.tags = result
+	detach := tr.detach
+	tr.mu.Unlock()
+
+	// хук вызывается без блокировки меток, чтобы не зависеть от порядка блокировок с EventRepo.
+	if detach != nil && len(deleted) > 0 {
+		detach(deleted)
+	}
+	return int64(len(deleted)), nil
+}
+
+// GetList не учитываем пагинацию, сортировку.
+func (tr *TagRepo) GetList(ctx context.Context, search model.TagSearch) ([]model.Tag, error) {
+	var filtered []model.Tag
+	tr.mu.RLock()
+	for _, tag := range tr.tags {
+		if tr.matchSearch(tag, search) {
+			filtered = append(filtered, tag)
+		}
+	}
+	tr.mu.RUnlock()
+
+	return filtered, nil
+}
+
+func (tr *TagRepo) matchSearch(tag model.Tag, search model.TagSearch) bool {
+	if search.ID != nil {
+		if strings.Compare(tag.ID.String(), search.ID.String()) != 0 {
+			return false
+		}
+	}
+	if search.NotID != nil {
+		if strings.Compare(tag.ID.String(), search.NotID.String()) == 0 {
+			return false
+		}
+	}
+	if search.IDs != nil && !containsID(search.IDs, tag.ID) {
+		return false
+	}
+	if search.OwnerID != nil {
+		if strings.Compare(tag.OwnerID.String(), search.OwnerID.String()) != 0 {
+			return false
+		}
+	}
+	if search.Name != nil {
+		if !strings.EqualFold(tag.Name, *search.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
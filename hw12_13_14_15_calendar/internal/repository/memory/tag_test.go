@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+func TestTagMemoryRepo(t *testing.T) {
+	t.Run("complex test", func(t *testing.T) {
+		tagRepo := TagRepo{}
+		ctx := context.Background()
+		userID1, _ := uuid.Parse("ab8e3706-7ad8-11ed-95f7-d00d1b9e4cfe")
+		userID2, _ := uuid.Parse("90bdce82-7ad8-11ed-99c1-d00d1b9e4cfe")
+
+		tags := []model.Tag{
+			{OwnerID: userID1, Name: "Работа", Color: "#FF0000"},
+			{OwnerID: userID1, Name: "Дом", Color: "#00FF00"},
+			{OwnerID: userID2, Name: "Работа", Color: "#0000FF"},
+		}
+		for i, tag := range tags {
+			newTag, err := tagRepo.Add(ctx, model.TagCreate{
+				OwnerID: tag.OwnerID,
+				Name:    tag.Name,
+				Color:   tag.Color,
+			})
+			require.NoError(t, err)
+			tags[i].ID = newTag.ID
+		}
+
+		actual, _ := tagRepo.GetList(ctx, model.TagSearch{OwnerID: &userID1})
+		require.ElementsMatch(t, tags[0:2], actual)
+
+		name := "работа"
+		actual, _ = tagRepo.GetList(ctx, model.TagSearch{Name: &name})
+		require.Equal(t, 2, len(actual))
+
+		actual, _ = tagRepo.GetList(ctx, model.TagSearch{IDs: []uuid.UUID{tags[0].ID, tags[2].ID}})
+		require.ElementsMatch(t, []model.Tag{tags[0], tags[2]}, actual)
+
+		tags[1].Color = "#ABCDEF"
+		n, _ := tagRepo.Update(ctx, model.TagUpdate{Color: &tags[1].Color}, model.TagSearch{ID: &tags[1].ID})
+		require.Equal(t, int64(1), n)
+
+		n, _ = tagRepo.Delete(ctx, model.TagSearch{ID: &tags[0].ID})
+		require.Equal(t, int64(1), n)
+
+		actual, _ = tagRepo.GetList(ctx, model.TagSearch{})
+		require.ElementsMatch(t, tags[1:], actual)
+	})
+	t.Run("delete detaches tag from events", func(t *testing.T) {
+		tagRepo := NewTagRepo()
+		eventRepo := NewEventRepo(tagRepo, nil)
+		ctx := context.Background()
+		ownerID := uuid.New()
+
+		work, err := tagRepo.Add(ctx, model.TagCreate{OwnerID: ownerID, Name: "Работа", Color: "#FF0000"})
+		require.NoError(t, err)
+		home, err := tagRepo.Add(ctx, model.TagCreate{OwnerID: ownerID, Name: "Дом", Color: "#00FF00"})
+		require.NoError(t, err)
+		event, err := eventRepo.Add(ctx, model.EventCreate{
+			Title:    "Планерка",
+			Date:     time.Now(),
+			Duration: time.Hour,
+			OwnerID:  ownerID,
+			TagIDs:   []uuid.UUID{work.ID, home.ID},
+		})
+		require.NoError(t, err)
+
+		n, err := tagRepo.Delete(ctx, model.TagSearch{ID: &work.ID})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		// события с удаленной меткой больше не находятся по ней, в том числе при удалении.
+		search := model.EventSearch{TagIDs: []uuid.UUID{work.ID}, Trash: model.TrashInclude}
+		n, err = eventRepo.Trash(ctx, search)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), n)
+		n, err = eventRepo.Delete(ctx, search)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), n)
+
+		events, err := eventRepo.GetList(ctx, model.EventSearch{ID: &event.ID})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, []model.Tag{*home}, events[0].Tags)
+	})
+}
@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+)
+
+// Веса слов по аналогии с setweight A и B в pgsql.
+const (
+	titleWeight       = 1.0
+	descriptionWeight = 0.4
+	// snippetMaxWords максимальное количество слов во фрагменте.
+	snippetMaxWords = 35
+	// snippetLeadWords количество слов перед первым найденным.
+	snippetLeadWords = 5
+)
+
+// textIndex инвертированный индекс слов заголовка и описания событий.
+// Не потокобезопасен, синхронизация на стороне EventRepo.
+type textIndex struct {
+	// postings слово -> событие -> суммарный вес вхождений.
+	postings map[string]map[uuid.UUID]float64
+	// words отсортированный список слов для поиска по префиксу.
+	words []string
+	// docs слова, проиндексированные для события.
+	docs map[uuid.UUID][]string
+}
+
+func newTextIndex() *textIndex {
+	return &textIndex{
+		postings: make(map[string]map[uuid.UUID]float64),
+		docs:     make(map[uuid.UUID][]string),
+	}
+}
+
+// put индексирует событие, предыдущая версия удаляется из индекса.
+func (ti *textIndex) put(event model.Event) {
+	ti.remove(event.ID)
+	weights := make(map[string]float64)
+	for _, word := range splitWords(event.Title) {
+		weights[word] += titleWeight
+	}
+	for _, word := range splitWords(event.Description) {
+		weights[word] += descriptionWeight
+	}
+	words := make([]string, 0, len(weights))
+	for word, weight := range weights {
+		posting, ok := ti.postings[word]
+		if !ok {
+			posting = make(map[uuid.UUID]float64)
+			ti.postings[word] = posting
+			ti.insertWord(word)
+		}
+		posting[event.ID] = weight
+		words = append(words, word)
+	}
+	ti.docs[event.ID] = words
+}
+
+func (ti *textIndex) remove(eventID uuid.UUID) {
+	words, ok := ti.docs[eventID]
+	if !ok {
+		return
+	}
+	for _, word := range words {
+		posting := ti.postings[word]
+		delete(posting, eventID)
+		if len(posting) == 0 {
+			delete(ti.postings, word)
+			ti.deleteWord(word)
+		}
+	}
+	delete(ti.docs, eventID)
+}
+
+// match события, содержащие слова с каждым из префиксов terms, с рангом.
+func (ti *textIndex) match(terms []string) map[uuid.UUID]float64 {
+	if len(terms) == 0 {
+		return nil
+	}
+	var result map[uuid.UUID]float64
+	for _, term := range terms {
+		found := make(map[uuid.UUID]float64)
+		from := sort.SearchStrings(ti.words, term)
+		for i := from; i < len(ti.words) && strings.HasPrefix(ti.words[i], term); i++ {
+			for eventID, weight := range ti.postings[ti.words[i]] {
+				found[eventID] += weight
+			}
+		}
+		if result == nil {
+			result = found
+			continue
+		}
+		// событие должно содержать все слова запроса.
+		for eventID, rank := range result {
+			weight, ok := found[eventID]
+			if !ok {
+				delete(result, eventID)
+				continue
+			}
+			result[eventID] = rank + weight
+		}
+	}
+	return result
+}
+
+func (ti *textIndex) insertWord(word string) {
+	i := sort.SearchStrings(ti.words, word)
+	ti.words = append(ti.words, "")
+	copy(ti.words[i+1:], ti.words[i:])
+	ti.words[i] = word
+}
+
+func (ti *textIndex) deleteWord(word string) {
+	i := sort.SearchStrings(ti.words, word)
+	if i < len(ti.words) && ti.words[i] == word {
+		ti.words = append(ti.words[:i], ti.words[i+1:]...)
+	}
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isWordSeparator)
+}
+
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// buildSnippet фрагмент заголовка и описания с выделением слов, начинающихся с terms.
+func buildSnippet(event model.Event, terms []string) string {
+	text := event.Title
+	if event.Description != "" {
+		text += " " + event.Description
+	}
+	words := strings.Fields(text)
+	first := -1
+	marked := make([]string, len(words))
+	for i, word := range words {
+		marked[i] = word
+		if !matchWord(word, terms) {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		// знаки препинания вокруг слова оставляем вне выделения.
+		start := strings.IndexFunc(word, func(r rune) bool { return !isWordSeparator(r) })
+		end := strings.LastIndexFunc(word, func(r rune) bool { return !isWordSeparator(r) })
+		_, size := utf8.DecodeRuneInString(word[end:])
+		marked[i] = word[:start] + model.SnippetStartSel + word[start:end+size] + model.SnippetStopSel + word[end+size:]
+	}
+	from := 0
+	if first > snippetLeadWords {
+		from = first - snippetLeadWords
+	}
+	to := from + snippetMaxWords
+	if to > len(marked) {
+		to = len(marked)
+	}
+	return strings.Join(marked[from:to], " ")
+}
+
+func matchWord(word string, terms []string) bool {
+	for _, part := range splitWords(word) {
+		for _, term := range terms {
+			if strings.HasPrefix(part, term) {
+				return true
+			}
+		}
+	}
+	return false
+}
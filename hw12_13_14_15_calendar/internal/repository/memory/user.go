@@ -21,9 +21,10 @@ func NewUserRepo() repository.User {
 
 func (ur *UserRepo) Add(ctx context.Context, input model.UserCreate) (*model.User, error) {
 	user := model.User{
-		ID:    uuid.New(),
-		Name:  input.Name,
-		Email: input.Email,
+		ID:     uuid.New(),
+		Name:   input.Name,
+		Email:  input.Email,
+		Locale: input.Locale,
 	}
 	ur.mu.Lock()
 	ur.users = append(ur.users, user)
@@ -47,6 +48,9 @@ func (ur *UserRepo) Update(ctx context.Context, input model.UserUpdate, search m
 		if input.Email != nil {
 			user.Email = *input.Email
 		}
+		if input.Locale != nil {
+			user.Locale = *input.Locale
+		}
 		ur.users[i] = user
 	}
 	return n, nil
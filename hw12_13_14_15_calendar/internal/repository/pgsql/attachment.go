@@ -0,0 +1,96 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type AttachmentRepo struct {
+	pool *sql.DB
+}
+
+func NewAttachmentRepo(pool *sql.DB) repository.Attachment {
+	return &AttachmentRepo{pool: pool}
+}
+
+func (ar AttachmentRepo) Add(ctx context.Context, input model.AttachmentCreate) (*model.Attachment, error) {
+	guid := uuid.New()
+	stmt := sqlf.InsertInto("event_attachments").
+		Set("id", guid.String()).
+		Set("event_id", input.EventID.String()).
+		Set("file_name", input.FileName).
+		Set("content_type", input.ContentType).
+		Set("size", input.Size).
+		Set("blob_key", input.BlobKey)
+	_, err := stmt.ExecAndClose(ctx, ar.pool)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := ar.GetList(ctx, model.AttachmentSearch{ID: &guid})
+	if err != nil {
+		return nil, err
+	}
+	return &attachments[0], nil
+}
+
+func (ar AttachmentRepo) Delete(ctx context.Context, search model.AttachmentSearch) (int64, error) {
+	stmt := sqlf.DeleteFrom("event_attachments")
+	ar.applySearch(stmt, search)
+	res, err := stmt.ExecAndClose(ctx, ar.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetList не учитываем пагинацию, сортировка по дате добавления.
+func (ar AttachmentRepo) GetList(ctx context.Context, search model.AttachmentSearch) ([]model.Attachment, error) {
+	stmt := sqlf.From("event_attachments").
+		Select("id, event_id, file_name, content_type, size, blob_key, created_at")
+	ar.applySearch(stmt, search)
+	stmt.OrderBy("created_at", "id")
+	attachments := make([]model.Attachment, 0)
+	rows, err := ar.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var id, eventID string
+		attachment := model.Attachment{}
+		err = rows.Scan(
+			&id, &eventID, &attachment.FileName, &attachment.ContentType,
+			&attachment.Size, &attachment.BlobKey, &attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if attachment.ID, err = uuid.Parse(id); err != nil {
+			return nil, err
+		}
+		if attachment.EventID, err = uuid.Parse(eventID); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func (ar AttachmentRepo) applySearch(stmt *sqlf.Stmt, search model.AttachmentSearch) {
+	if search.ID != nil {
+		stmt.Where("event_attachments.id = ?", search.ID.String())
+	}
+	if search.EventID != nil {
+		stmt.Where("event_attachments.event_id = ?", search.EventID.String())
+	}
+	if search.EventIDs != nil {
+		stmt.Where("event_attachments.event_id = ANY(?::uuid[])", uuidArray(search.EventIDs))
+	}
+}
@@ -0,0 +1,163 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type AvailabilityRepo struct {
+	pool *sql.DB
+}
+
+func NewAvailabilityRepo(pool *sql.DB) repository.Availability {
+	return &AvailabilityRepo{pool: pool}
+}
+
+// workingHours формат хранения рабочего интервала в jsonb, смещения в секундах.
+type workingHours struct {
+	Weekday int   `json:"weekday"`
+	Start   int64 `json:"start"`
+	End     int64 `json:"end"`
+}
+
+func (ar AvailabilityRepo) Get(ctx context.Context, userID uuid.UUID) (*model.Availability, error) {
+	var (
+		policy, hoursJSON string
+		settings          = model.Availability{UserID: userID}
+	)
+	stmt := sqlf.From("user_availability").
+		Select("time_zone, policy, hours").
+		Where("user_id = ?", userID.String())
+	err := ar.pool.QueryRowContext(ctx, stmt.String(), stmt.Args()...).
+		Scan(&settings.TimeZone, &policy, &hoursJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if settings.Policy, err = model.ParseAvailabilityPolicy(policy); err != nil {
+		return nil, fmt.Errorf("error reading availability policy: %w", err)
+	}
+	var hours []workingHours
+	if err = json.Unmarshal([]byte(hoursJSON), &hours); err != nil {
+		return nil, fmt.Errorf("error reading working hours: %w", err)
+	}
+	for _, wh := range hours {
+		settings.Hours = append(settings.Hours, model.WorkingHours{
+			Weekday: time.Weekday(wh.Weekday),
+			Start:   time.Duration(wh.Start) * time.Second,
+			End:     time.Duration(wh.End) * time.Second,
+		})
+	}
+	return &settings, nil
+}
+
+func (ar AvailabilityRepo) Save(ctx context.Context, input model.Availability) error {
+	hours := make([]workingHours, len(input.Hours))
+	for i, wh := range input.Hours {
+		hours[i] = workingHours{
+			Weekday: int(wh.Weekday),
+			Start:   int64(wh.Start.Seconds()),
+			End:     int64(wh.End.Seconds()),
+		}
+	}
+	hoursJSON, err := json.Marshal(hours)
+	if err != nil {
+		return err
+	}
+	stmt := sqlf.InsertInto("user_availability").
+		Set("user_id", input.UserID.String()).
+		Set("time_zone", input.TimeZone).
+		Set("policy", input.Policy.String()).
+		Set("hours", string(hoursJSON)).
+		Clause("ON CONFLICT (user_id) DO UPDATE SET " +
+			"time_zone = EXCLUDED.time_zone, policy = EXCLUDED.policy, hours = EXCLUDED.hours")
+	_, err = stmt.ExecAndClose(ctx, ar.pool)
+	return err
+}
+
+func (ar AvailabilityRepo) AddOutOfOffice(
+	ctx context.Context,
+	input model.OutOfOfficeCreate,
+) (*model.OutOfOffice, error) {
+	guid := uuid.New()
+	stmt := sqlf.InsertInto("out_of_office").
+		Set("id", guid.String()).
+		Set("user_id", input.UserID.String()).
+		Set("date_from", input.DateFrom).
+		Set("date_to", input.DateTo).
+		Set("reason", input.Reason)
+	if _, err := stmt.ExecAndClose(ctx, ar.pool); err != nil {
+		return nil, err
+	}
+	absences, err := ar.GetOutOfOffice(ctx, model.OutOfOfficeSearch{ID: &guid})
+	if err != nil {
+		return nil, err
+	}
+	return &absences[0], nil
+}
+
+func (ar AvailabilityRepo) DeleteOutOfOffice(ctx context.Context, search model.OutOfOfficeSearch) (int64, error) {
+	stmt := sqlf.DeleteFrom("out_of_office")
+	ar.applySearch(stmt, search)
+	res, err := stmt.ExecAndClose(ctx, ar.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (ar AvailabilityRepo) GetOutOfOffice(
+	ctx context.Context,
+	search model.OutOfOfficeSearch,
+) ([]model.OutOfOffice, error) {
+	stmt := sqlf.From("out_of_office").Select("id, user_id, date_from, date_to, reason")
+	ar.applySearch(stmt, search)
+	stmt.OrderBy("date_from")
+	absences := make([]model.OutOfOffice, 0)
+	rows, err := ar.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var id, userID string
+		ooo := model.OutOfOffice{}
+		if err = rows.Scan(&id, &userID, &ooo.DateFrom, &ooo.DateTo, &ooo.Reason); err != nil {
+			return nil, err
+		}
+		if ooo.ID, err = uuid.Parse(id); err != nil {
+			return nil, err
+		}
+		if ooo.UserID, err = uuid.Parse(userID); err != nil {
+			return nil, err
+		}
+		absences = append(absences, ooo)
+	}
+	return absences, nil
+}
+
+func (ar AvailabilityRepo) applySearch(stmt *sqlf.Stmt, search model.OutOfOfficeSearch) {
+	if search.ID != nil {
+		stmt.Where("out_of_office.id = ?", search.ID.String())
+	}
+	if search.UserID != nil {
+		stmt.Where("out_of_office.user_id = ?", search.UserID.String())
+	}
+	if search.DateRange != nil {
+		stmt.Where("out_of_office.date_to > ?", search.DateRange.GetFrom()).
+			Where("out_of_office.date_from < ?", search.DateRange.GetTo())
+	}
+}
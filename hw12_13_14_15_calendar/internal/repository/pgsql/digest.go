@@ -0,0 +1,113 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type DigestRepo struct {
+	pool *sql.DB
+}
+
+func NewDigestRepo(pool *sql.DB) repository.Digest {
+	return &DigestRepo{pool: pool}
+}
+
+func (dr DigestRepo) Get(ctx context.Context, userID uuid.UUID) (*model.DigestSettings, error) {
+	stmt := sqlf.From("user_digest").
+		Select("user_id, mode, send_at, weekday, last_sent_at").
+		Where("user_id = ?", userID.String())
+	settings, err := dr.scan(dr.pool.QueryRowContext(ctx, stmt.String(), stmt.Args()...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (dr DigestRepo) Save(ctx context.Context, input model.DigestSettings) error {
+	stmt := sqlf.InsertInto("user_digest").
+		Set("user_id", input.UserID.String()).
+		Set("mode", input.Mode.String()).
+		Set("send_at", int64(input.SendAt.Seconds())).
+		Set("weekday", int(input.Weekday)).
+		Clause("ON CONFLICT (user_id) DO UPDATE SET " +
+			"mode = EXCLUDED.mode, send_at = EXCLUDED.send_at, weekday = EXCLUDED.weekday")
+	_, err := stmt.ExecAndClose(ctx, dr.pool)
+	return err
+}
+
+func (dr DigestRepo) GetEnabled(ctx context.Context) ([]model.DigestSettings, error) {
+	stmt := sqlf.From("user_digest").
+		Select("user_id, mode, send_at, weekday, last_sent_at").
+		Where("mode <> ?", model.DigestOff.String()).
+		OrderBy("user_id")
+	rows, err := dr.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	enabled := make([]model.DigestSettings, 0)
+	for rows.Next() {
+		settings, err := dr.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		enabled = append(enabled, *settings)
+	}
+	return enabled, rows.Err()
+}
+
+func (dr DigestRepo) MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) (bool, error) {
+	// условие на прежнее значение делает отметку атомарной: сводку отправит только один вызов.
+	stmt := sqlf.Update("user_digest").
+		Set("last_sent_at", sentAt).
+		Where("user_id = ?", userID.String()).
+		Where("(last_sent_at IS NULL OR last_sent_at < ?)", sentAt)
+	res, err := stmt.ExecAndClose(ctx, dr.pool)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (dr DigestRepo) scan(row interface{ Scan(...interface{}) error }) (*model.DigestSettings, error) {
+	var (
+		userID, mode string
+		sendAt       int64
+		weekday      int
+		lastSentAt   sql.NullTime
+	)
+	if err := row.Scan(&userID, &mode, &sendAt, &weekday, &lastSentAt); err != nil {
+		return nil, err
+	}
+	settings := model.DigestSettings{
+		SendAt:     time.Duration(sendAt) * time.Second,
+		Weekday:    time.Weekday(weekday),
+		LastSentAt: lastSentAt.Time,
+	}
+	var err error
+	if settings.UserID, err = uuid.Parse(userID); err != nil {
+		return nil, err
+	}
+	if settings.Mode, err = model.ParseDigestMode(mode); err != nil {
+		return nil, fmt.Errorf("error reading digest mode: %w", err)
+	}
+	return &settings, nil
+}
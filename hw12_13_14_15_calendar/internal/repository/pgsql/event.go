@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,13 @@ import (
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
 )
 
+// eventFields поля выборки события, порядок соответствует prepareModel.
+const eventFields = `id, title, date,
+	EXTRACT(EPOCH FROM duration)::int,
+	description,
+	EXTRACT(EPOCH FROM notify_term)::int,
+	notify_status, created_at, updated_at, deleted_at, links, ical_uid`
+
 type EventRepo struct {
 	pool *sql.DB
 }
@@ -22,6 +30,52 @@ func NewEventRepo(pool *sql.DB) repository.Event {
 }
 
 func (er EventRepo) Add(ctx context.Context, input model.EventCreate) (*model.Event, error) {
+	events, err := er.AddBatch(ctx, []model.EventCreate{input})
+	if err != nil {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+func (er EventRepo) AddBatch(ctx context.Context, inputs []model.EventCreate) ([]model.Event, error) {
+	tx, err := er.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err = er.checkQuota(ctx, tx, inputs); err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(inputs))
+	for i, input := range inputs {
+		if ids[i], err = er.addTx(ctx, tx, input); err != nil {
+			return nil, err
+		}
+	}
+	if err = er.checkResources(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	events, err := er.GetList(ctx, model.EventSearch{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	// порядок событий соответствует порядку inputs.
+	byID := make(map[uuid.UUID]model.Event, len(events))
+	for _, event := range events {
+		byID[event.ID] = event
+	}
+	for i, id := range ids {
+		events[i] = byID[id]
+	}
+	return events, nil
+}
+
+func (er EventRepo) addTx(ctx context.Context, tx *sql.Tx, input model.EventCreate) (uuid.UUID, error) {
 	guid := uuid.New()
 	stmt := sqlf.InsertInto("events").
 		Set("id", guid.String()).
@@ -29,6 +83,7 @@ func (er EventRepo) Add(ctx context.Context, input model.EventCreate) (*model.Ev
 		Set("date", input.Date).
 		Set("duration", fmt.Sprintf("%d seconds", int64(input.Duration.Seconds()))).
 		Set("notify_status", model.NotifyStatusNone.String())
+	defer stmt.Close()
 	if input.OwnerID.ID() > 0 {
 		stmt.Set("owner_id", input.OwnerID.String())
 	}
@@ -38,20 +93,69 @@ func (er EventRepo) Add(ctx context.Context, input model.EventCreate) (*model.Ev
 	if input.NotifyTerm != nil {
 		stmt.Set("notify_term", fmt.Sprintf("%d seconds", int64(input.NotifyTerm.Seconds())))
 	}
-	_, err := stmt.ExecAndClose(ctx, er.pool)
+	if len(input.Links) > 0 {
+		links, err := marshalLinks(input.Links)
+		if err != nil {
+			return guid, err
+		}
+		stmt.Set("links", links)
+	}
+	if input.ICalUID != "" {
+		stmt.Set("ical_uid", input.ICalUID)
+	}
+	if _, err := tx.ExecContext(ctx, stmt.String(), stmt.Args()...); err != nil {
+		return guid, err
+	}
+	if err := er.setTags(ctx, tx, []uuid.UUID{guid}, input.TagIDs); err != nil {
+		return guid, err
+	}
+	return guid, er.setResources(ctx, tx, []uuid.UUID{guid}, input.ResourceIDs)
+}
+
+func (er EventRepo) Update(ctx context.Context, input model.EventUpdate, search model.EventSearch) (int64, error) {
+	tx, err := er.pool.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	events, err := er.GetList(ctx, model.EventSearch{ID: &guid})
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	n, err := er.updateTx(ctx, tx, input, search)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return &events[0], nil
+	return n, tx.Commit()
 }
 
-func (er EventRepo) Update(ctx context.Context, input model.EventUpdate, search model.EventSearch) (int64, error) {
+func (er EventRepo) UpdateBatch(ctx context.Context, items []model.EventBatchUpdate) (int64, error) {
+	tx, err := er.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var total int64
+	for _, item := range items {
+		eventID := item.ID
+		n, err := er.updateTx(ctx, tx, item.Input, model.EventSearch{ID: &eventID})
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, tx.Commit()
+}
+
+func (er EventRepo) updateTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	input model.EventUpdate,
+	search model.EventSearch,
+) (int64, error) {
 	stmt := sqlf.Update("events").
 		Set("updated_at", time.Now())
+	defer stmt.Close()
 	er.applySearch(stmt, search)
 	if input.Title != nil {
 		stmt.Set("title", *input.Title)
@@ -71,11 +175,209 @@ func (er EventRepo) Update(ctx context.Context, input model.EventUpdate, search
 	if input.NotifyStatus != nil {
 		stmt.Set("notify_status", input.NotifyStatus.String())
 	}
-	res, err := stmt.ExecAndClose(ctx, er.pool)
+	if input.Links != nil {
+		if len(*input.Links) == 0 {
+			stmt.SetExpr("links", "NULL")
+		} else {
+			links, err := marshalLinks(*input.Links)
+			if err != nil {
+				return 0, err
+			}
+			stmt.Set("links", links)
+		}
+	}
+	// бронь ресурсов проверяется заново при изменении ресурсов или времени события.
+	rebook := input.ResourceIDs != nil || input.Date != nil || input.Duration != nil
+	if input.TagIDs == nil && !rebook {
+		res, err := tx.ExecContext(ctx, stmt.String(), stmt.Args()...)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+	// метки и ресурсы заменяются целиком, поэтому сначала определяем изменяемые события.
+	stmt.Returning("events.id")
+	rows, err := tx.QueryContext(ctx, stmt.String(), stmt.Args()...)
 	if err != nil {
 		return 0, err
 	}
-	return res.RowsAffected()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		guid, err := uuid.Parse(id)
+		if err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		ids = append(ids, guid)
+	}
+	if err = rows.Close(); err != nil {
+		return 0, err
+	}
+	if input.TagIDs != nil {
+		if err = er.setTags(ctx, tx, ids, *input.TagIDs); err != nil {
+			return 0, err
+		}
+	}
+	if input.ResourceIDs != nil {
+		if err = er.setResources(ctx, tx, ids, *input.ResourceIDs); err != nil {
+			return 0, err
+		}
+	}
+	if rebook {
+		if err = er.checkResources(ctx, tx, ids); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(ids)), nil
+}
+
+// setTags заменяет метки событий eventIDs на tagIDs.
+func (er EventRepo) setTags(ctx context.Context, tx *sql.Tx, eventIDs, tagIDs []uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "DELETE FROM event_tags WHERE event_id = ANY($1::uuid[])", uuidArray(eventIDs))
+	if err != nil {
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO event_tags (event_id, tag_id)
+			SELECT e, t FROM unnest($1::uuid[]) e, unnest($2::uuid[]) t ON CONFLICT DO NOTHING`,
+		uuidArray(eventIDs), uuidArray(tagIDs),
+	)
+	return err
+}
+
+// setResources заменяет бронь ресурсов событиями eventIDs на resourceIDs.
+func (er EventRepo) setResources(ctx context.Context, tx *sql.Tx, eventIDs, resourceIDs []uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "DELETE FROM event_resources WHERE event_id = ANY($1::uuid[])", uuidArray(eventIDs))
+	if err != nil {
+		return err
+	}
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO event_resources (event_id, resource_id)
+			SELECT e, r FROM unnest($1::uuid[]) e, unnest($2::uuid[]) r ON CONFLICT DO NOTHING`,
+		uuidArray(eventIDs), uuidArray(resourceIDs),
+	)
+	return err
+}
+
+// checkResources ошибка model.ErrEventResourceBusy, если события eventIDs бронируют ресурс
+// на время, занятое другим событием. Ресурсы событий блокируются до конца транзакции tx,
+// поэтому параллельная бронь тех же ресурсов дожидается ее завершения и видит записанные события.
+// checkQuota ошибка model.ErrEventQuota, если после добавления inputs событий владельца станет
+// больше его квоты. Строка владельца блокируется до конца транзакции, поэтому одновременные
+// добавления одного владельца проверяются по очереди.
+func (er EventRepo) checkQuota(ctx context.Context, tx *sql.Tx, inputs []model.EventCreate) error {
+	added := make(map[uuid.UUID]int64)
+	quotas := make(map[uuid.UUID]int64)
+	ownerIDs := make([]uuid.UUID, 0)
+	for _, input := range inputs {
+		if input.OwnerQuota <= 0 {
+			continue
+		}
+		if _, ok := quotas[input.OwnerID]; !ok {
+			ownerIDs = append(ownerIDs, input.OwnerID)
+		}
+		added[input.OwnerID]++
+		quotas[input.OwnerID] = input.OwnerQuota
+	}
+	if len(ownerIDs) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE`,
+		uuidArray(ownerIDs))
+	if err != nil {
+		return err
+	}
+	for _, ownerID := range ownerIDs {
+		var n int64
+		err = tx.QueryRowContext(ctx, `SELECT count(*) FROM events WHERE owner_id = $1`, ownerID.String()).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n+added[ownerID] > quotas[ownerID] {
+			return fmt.Errorf("%w: не более %d", model.ErrEventQuota, quotas[ownerID])
+		}
+	}
+	return nil
+}
+
+func (er EventRepo) checkResources(ctx context.Context, tx *sql.Tx, eventIDs []uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(
+		ctx,
+		`SELECT id FROM resources WHERE id IN (
+			SELECT resource_id FROM event_resources WHERE event_id = ANY($1::uuid[])
+		) ORDER BY id FOR UPDATE`,
+		uuidArray(eventIDs),
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT DISTINCT resources.name FROM event_resources booked
+			JOIN events ON events.id = booked.event_id
+			JOIN event_resources other ON other.resource_id = booked.resource_id AND other.event_id != booked.event_id
+			JOIN events others ON others.id = other.event_id
+			JOIN resources ON resources.id = booked.resource_id
+		WHERE booked.event_id = ANY($1::uuid[])
+			AND events.deleted_at IS NULL AND others.deleted_at IS NULL
+			AND others.date < events.date + events.duration
+			AND others.date + others.duration > events.date
+		ORDER BY resources.name`,
+		uuidArray(eventIDs),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	var busy []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return err
+		}
+		busy = append(busy, name)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if len(busy) > 0 {
+		return fmt.Errorf("%w: %s", model.ErrEventResourceBusy, strings.Join(busy, ", "))
+	}
+	return nil
+}
+
+// selectRelations выборка владельца, меток и ресурсов события, следуют за eventFields.
+func (er EventRepo) selectRelations(stmt *sqlf.Stmt) {
+	stmt.Select("(select row_to_json(users) from users where events.owner_id=users.id) as owner")
+	stmt.Select(`(select json_agg(tags ORDER BY tags.name) from tags
+		join event_tags on event_tags.tag_id=tags.id where event_tags.event_id=events.id) as tags`)
+	stmt.Select(`(select json_agg(resources ORDER BY resources.name) from resources
+		join event_resources on event_resources.resource_id=resources.id
+		where event_resources.event_id=events.id) as resources`)
 }
 
 func (er EventRepo) Delete(ctx context.Context, search model.EventSearch) (int64, error) {
@@ -88,17 +390,36 @@ func (er EventRepo) Delete(ctx context.Context, search model.EventSearch) (int64
 	return res.RowsAffected()
 }
 
+func (er EventRepo) Trash(ctx context.Context, search model.EventSearch) (int64, error) {
+	search.Trash = model.TrashExclude
+	stmt := sqlf.Update("events").
+		Set("deleted_at", time.Now())
+	er.applySearch(stmt, search)
+	res, err := stmt.ExecAndClose(ctx, er.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (er EventRepo) Restore(ctx context.Context, search model.EventSearch) (int64, error) {
+	search.Trash = model.TrashOnly
+	stmt := sqlf.Update("events").
+		SetExpr("deleted_at", "NULL")
+	er.applySearch(stmt, search)
+	res, err := stmt.ExecAndClose(ctx, er.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // GetList не учитываем пагинацию, сортировку.
 func (er EventRepo) GetList(ctx context.Context, search model.EventSearch) ([]model.Event, error) {
 	stmt := sqlf.From("events").
-		Select(`id, title, date, 
-			EXTRACT(EPOCH FROM duration)::int, 
-			description, 
-			EXTRACT(EPOCH FROM notify_term)::int, 
-			notify_status, created_at, updated_at`,
-		)
+		Select(eventFields)
 	er.applySearch(stmt, search)
-	stmt.Select("(select row_to_json(users) from users where events.owner_id=users.id) as owner")
+	er.selectRelations(stmt)
 	events := make([]model.Event, 0)
 	rows, err := er.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
 	if err != nil {
@@ -118,15 +439,80 @@ func (er EventRepo) GetList(ctx context.Context, search model.EventSearch) ([]mo
 	return events, nil
 }
 
-func (er EventRepo) prepareModel(row *sql.Rows) (model.Event, error) {
+func (er EventRepo) Count(ctx context.Context, search model.EventSearch) (int64, error) {
+	var n int64
+	stmt := sqlf.From("events").
+		Select("count(*)")
+	er.applySearch(stmt, search)
+	err := er.pool.QueryRowContext(ctx, stmt.String(), stmt.Args()...).Scan(&n)
+	return n, err
+}
+
+// Search полнотекстовый поиск по tsvector-колонке search_vector, см. миграцию add_events_search.
+func (er EventRepo) Search(ctx context.Context, search model.EventSearch) ([]model.EventFound, error) {
+	if search.Query == nil {
+		return nil, nil
+	}
+	tsQuery := er.tsQuery(*search.Query)
+	stmt := sqlf.From("events").
+		Select(eventFields)
+	er.applySearch(stmt, search)
+	er.selectRelations(stmt)
+	stmt.Select("ts_rank(events.search_vector, to_tsquery('simple', ?)) as rank", tsQuery)
+	stmt.Select(
+		"ts_headline('simple', events.title || ' ' || coalesce(events.description, ''), to_tsquery('simple', ?), ?)",
+		tsQuery,
+		fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15", model.SnippetStartSel, model.SnippetStopSel),
+	)
+	stmt.OrderBy("rank DESC")
+	found := make([]model.EventFound, 0)
+	rows, err := er.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var item model.EventFound
+		item.Event, err = er.prepareModel(rows, &item.Rank, &item.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// tsQuery запрос с поиском по префиксу каждого слова: "слово1:* & слово2:*".
+func (er EventRepo) tsQuery(query string) string {
+	terms := model.SearchTerms(query)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// prepareModel extra - дополнительные поля выборки, следующие за owner, tags и resources.
+func (er EventRepo) prepareModel(row *sql.Rows, extra ...interface{}) (model.Event, error) {
 	var (
 		id, description, userJSON, notifyStatus sql.NullString
+		tagsJSON, resourcesJSON, linksJSON      sql.NullString
+		icalUID                                 sql.NullString
 		duration, notifyTerm                    sql.NullInt64
+		deletedAt                               sql.NullTime
 		event                                   model.Event
 	)
-	if err := row.Scan(
+	dest := []interface{}{
 		&id, &event.Title, &event.Date, &duration, &description,
-		&notifyTerm, &notifyStatus, &event.CreatedAt, &event.UpdatedAt, &userJSON); err != nil {
+		&notifyTerm, &notifyStatus, &event.CreatedAt, &event.UpdatedAt, &deletedAt, &linksJSON, &icalUID,
+		&userJSON, &tagsJSON, &resourcesJSON,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		if err != nil {
 			return event, err
 		}
@@ -140,17 +526,19 @@ func (er EventRepo) prepareModel(row *sql.Rows) (model.Event, error) {
 	}
 	if userJSON.Valid {
 		var dtoUser struct {
-			ID    string `json:"id"`
-			Name  string `json:"name"`
-			Email string `json:"email"`
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			Email  string `json:"email"`
+			Locale string `json:"locale"`
 		}
 		err := json.Unmarshal([]byte(userJSON.String), &dtoUser)
 		if err != nil {
 			return event, fmt.Errorf("error reading event owner: %w", err)
 		}
 		event.Owner = &model.User{
-			Name:  dtoUser.Name,
-			Email: dtoUser.Email,
+			Name:   dtoUser.Name,
+			Email:  dtoUser.Email,
+			Locale: dtoUser.Locale,
 		}
 		guid, err := uuid.Parse(dtoUser.ID)
 		if err != nil {
@@ -158,6 +546,34 @@ func (er EventRepo) prepareModel(row *sql.Rows) (model.Event, error) {
 		}
 		event.Owner.ID = guid
 	}
+	if tagsJSON.Valid {
+		var dtoTags []struct {
+			ID      uuid.UUID `json:"id"`
+			OwnerID uuid.UUID `json:"owner_id"`
+			Name    string    `json:"name"`
+			Color   string    `json:"color"`
+		}
+		if err := json.Unmarshal([]byte(tagsJSON.String), &dtoTags); err != nil {
+			return event, fmt.Errorf("error reading event tags: %w", err)
+		}
+		for _, dtoTag := range dtoTags {
+			event.Tags = append(event.Tags, model.Tag(dtoTag))
+		}
+	}
+	if resourcesJSON.Valid {
+		resources, err := unmarshalResources(resourcesJSON.String)
+		if err != nil {
+			return event, fmt.Errorf("error reading event resources: %w", err)
+		}
+		event.Resources = resources
+	}
+	if linksJSON.Valid {
+		links, err := unmarshalLinks(linksJSON.String)
+		if err != nil {
+			return event, fmt.Errorf("error reading event links: %w", err)
+		}
+		event.Links = links
+	}
 	if duration.Valid {
 		event.Duration = time.Duration(duration.Int64) * time.Second
 	}
@@ -174,19 +590,62 @@ func (er EventRepo) prepareModel(row *sql.Rows) (model.Event, error) {
 		}
 		event.NotifyStatus = nf
 	}
+	if icalUID.Valid {
+		event.ICalUID = icalUID.String
+	}
+	if deletedAt.Valid {
+		val := deletedAt.Time
+		event.DeletedAt = &val
+	}
 	return event, nil
 }
 
 func (er EventRepo) applySearch(stmt *sqlf.Stmt, search model.EventSearch) {
+	switch search.Trash {
+	case model.TrashExclude:
+		stmt.Where("events.deleted_at IS NULL")
+	case model.TrashOnly:
+		stmt.Where("events.deleted_at IS NOT NULL")
+	case model.TrashInclude:
+	}
+	if search.DeletedLess != nil {
+		stmt.Where("events.deleted_at < ?", *search.DeletedLess)
+	}
+	if search.Query != nil {
+		if tsQuery := er.tsQuery(*search.Query); tsQuery != "" {
+			stmt.Where("events.search_vector @@ to_tsquery('simple', ?)", tsQuery)
+		} else {
+			stmt.Where("FALSE")
+		}
+	}
 	if search.ID != nil {
 		stmt.Where("events.id = ?", search.ID.String())
 	}
 	if search.NotID != nil {
 		stmt.Where("events.id != ?", search.NotID.String())
 	}
+	if search.IDs != nil {
+		stmt.Where("events.id = ANY(?::uuid[])", uuidArray(search.IDs))
+	}
 	if search.OwnerID != nil {
 		stmt.Where("events.owner_id = ?", search.OwnerID.String())
 	}
+	if search.ICalUID != nil {
+		stmt.Where("events.ical_uid = ?", *search.ICalUID)
+	}
+	if len(search.TagIDs) > 0 {
+		stmt.Where(
+			"EXISTS (SELECT 1 FROM event_tags WHERE event_tags.event_id = events.id AND event_tags.tag_id = ANY(?::uuid[]))",
+			uuidArray(search.TagIDs),
+		)
+	}
+	if len(search.ResourceIDs) > 0 {
+		stmt.Where(
+			`EXISTS (SELECT 1 FROM event_resources
+				WHERE event_resources.event_id = events.id AND event_resources.resource_id = ANY(?::uuid[]))`,
+			uuidArray(search.ResourceIDs),
+		)
+	}
 	if search.DateRange != nil {
 		if search.TacDuration {
 			stmt.Where("events.date + events.duration > ?", search.DateRange.GetFrom())
@@ -208,16 +667,11 @@ func (er EventRepo) applySearch(stmt *sqlf.Stmt, search model.EventSearch) {
 
 func (er EventRepo) BlockEvents4Notify(ctx context.Context, now time.Time) ([]model.Event, error) {
 	stmt := sqlf.From("events").
-		Select(`id, title, date, 
-			EXTRACT(EPOCH FROM duration)::int, 
-			description, 
-			EXTRACT(EPOCH FROM notify_term)::int, 
-			notify_status, created_at, updated_at`,
-		)
+		Select(eventFields)
 	er.applySearch(stmt, model.EventSearch{
 		NeedNotifyTerm: &now,
 	})
-	stmt.Select("(select row_to_json(users) from users where events.owner_id=users.id) as owner")
+	er.selectRelations(stmt)
 
 	events := make([]model.Event, 0)
 
@@ -253,3 +707,65 @@ func (er EventRepo) BlockEvents4Notify(ctx context.Context, now time.Time) ([]mo
 	}
 	return events, nil
 }
+
+// linkJSON хранимое представление ссылки события в колонке links.
+type linkJSON struct {
+	Type  string `json:"type"`
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+func marshalLinks(links []model.EventLink) (string, error) {
+	items := make([]linkJSON, len(links))
+	for i, link := range links {
+		items[i] = linkJSON{Type: link.Type.String(), URL: link.URL, Title: link.Title}
+	}
+	data, err := json.Marshal(items)
+	return string(data), err
+}
+
+func unmarshalLinks(data string) ([]model.EventLink, error) {
+	var items []linkJSON
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		return nil, err
+	}
+	links := make([]model.EventLink, 0, len(items))
+	for _, item := range items {
+		linkType, err := model.ParseLinkType(item.Type)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, model.EventLink{Type: linkType, URL: item.URL, Title: item.Title})
+	}
+	return links, nil
+}
+
+func unmarshalResources(data string) ([]model.Resource, error) {
+	var items []struct {
+		ID        uuid.UUID `json:"id"`
+		Kind      string    `json:"kind"`
+		Name      string    `json:"name"`
+		ManagerID uuid.UUID `json:"manager_id"`
+		Capacity  int       `json:"capacity"`
+		Location  string    `json:"location"`
+	}
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		return nil, err
+	}
+	resources := make([]model.Resource, len(items))
+	for i, item := range items {
+		kind, err := model.ParseResourceKind(item.Kind)
+		if err != nil {
+			return nil, err
+		}
+		resources[i] = model.Resource{
+			ID:        item.ID,
+			Kind:      kind,
+			Name:      item.Name,
+			ManagerID: item.ManagerID,
+			Capacity:  item.Capacity,
+			Location:  item.Location,
+		}
+	}
+	return resources, nil
+}
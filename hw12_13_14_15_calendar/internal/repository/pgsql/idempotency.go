@@ -0,0 +1,126 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type IdempotencyRepo struct {
+	pool *sql.DB
+}
+
+func NewIdempotencyRepo(pool *sql.DB) repository.Idempotency {
+	return &IdempotencyRepo{pool: pool}
+}
+
+func (ir IdempotencyRepo) Get(
+	ctx context.Context, userID uuid.UUID, key string, now time.Time,
+) (*model.IdempotencyKey, error) {
+	var (
+		response, failure sql.NullString
+		stored            = model.IdempotencyKey{Key: key, UserID: userID}
+	)
+	stmt := sqlf.From("idempotency_keys").
+		Select("request_hash, response, failure, created_at, expires_at").
+		Where("user_id = ?", userID.String()).
+		Where("key = ?", key).
+		Where("expires_at > ?", now)
+	err := ir.pool.QueryRowContext(ctx, stmt.String(), stmt.Args()...).
+		Scan(&stored.RequestHash, &response, &failure, &stored.CreatedAt, &stored.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.Valid {
+		stored.Event = &model.Event{}
+		if err = json.Unmarshal([]byte(response.String), stored.Event); err != nil {
+			return nil, fmt.Errorf("error reading idempotent response: %w", err)
+		}
+	}
+	if failure.Valid {
+		stored.Failure = &model.IdempotencyFailure{}
+		if err = json.Unmarshal([]byte(failure.String), stored.Failure); err != nil {
+			return nil, fmt.Errorf("error reading idempotent failure: %w", err)
+		}
+	}
+	return &stored, nil
+}
+
+func (ir IdempotencyRepo) Reserve(ctx context.Context, input model.IdempotencyKey) (bool, error) {
+	stmt := sqlf.InsertInto("idempotency_keys").
+		Set("user_id", input.UserID.String()).
+		Set("key", input.Key).
+		Set("request_hash", input.RequestHash).
+		Set("created_at", input.CreatedAt).
+		Set("expires_at", input.ExpiresAt).
+		Clause("ON CONFLICT (user_id, key) DO UPDATE SET " +
+			"request_hash = EXCLUDED.request_hash, response = NULL, failure = NULL, " +
+			"created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at " +
+			"WHERE idempotency_keys.expires_at <= EXCLUDED.created_at")
+	res, err := stmt.ExecAndClose(ctx, ir.pool)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (ir IdempotencyRepo) Complete(ctx context.Context, userID uuid.UUID, key string, event model.Event) error {
+	response, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	stmt := sqlf.Update("idempotency_keys").
+		Set("response", string(response)).
+		Where("user_id = ?", userID.String()).
+		Where("key = ?", key)
+	_, err = stmt.ExecAndClose(ctx, ir.pool)
+	return err
+}
+
+func (ir IdempotencyRepo) Fail(
+	ctx context.Context, userID uuid.UUID, key string, failure model.IdempotencyFailure,
+) error {
+	data, err := json.Marshal(failure)
+	if err != nil {
+		return err
+	}
+	stmt := sqlf.Update("idempotency_keys").
+		Set("failure", string(data)).
+		Where("user_id = ?", userID.String()).
+		Where("key = ?", key)
+	_, err = stmt.ExecAndClose(ctx, ir.pool)
+	return err
+}
+
+func (ir IdempotencyRepo) Delete(ctx context.Context, userID uuid.UUID, key string) error {
+	stmt := sqlf.DeleteFrom("idempotency_keys").
+		Where("user_id = ?", userID.String()).
+		Where("key = ?", key)
+	_, err := stmt.ExecAndClose(ctx, ir.pool)
+	return err
+}
+
+func (ir IdempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	stmt := sqlf.DeleteFrom("idempotency_keys").
+		Where("expires_at <= ?", now)
+	res, err := stmt.ExecAndClose(ctx, ir.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
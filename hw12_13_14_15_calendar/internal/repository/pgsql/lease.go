@@ -0,0 +1,58 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type LeaseRepo struct {
+	pool *sql.DB
+}
+
+func NewLeaseRepo(pool *sql.DB) repository.Lease {
+	return &LeaseRepo{pool: pool}
+}
+
+func (lr LeaseRepo) Acquire(ctx context.Context, lease model.Lease, now time.Time) (*model.Lease, error) {
+	// захват и чтение в одном запросе: при занятой аренде DO UPDATE не выполняется
+	// и RETURNING пуст, тогда возвращается текущий владелец.
+	const query = `WITH acquired AS (
+		INSERT INTO leases (name, holder, expires_at, owner_id) VALUES ($1, $2, $3, $5)
+		ON CONFLICT (name) DO UPDATE
+		SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at, owner_id = EXCLUDED.owner_id
+		WHERE leases.holder = EXCLUDED.holder AND leases.owner_id = EXCLUDED.owner_id
+			OR leases.expires_at <= $4
+		RETURNING name, holder, expires_at, owner_id
+	)
+	SELECT name, holder, expires_at, owner_id FROM acquired
+	UNION ALL
+	SELECT name, holder, expires_at, owner_id FROM leases
+	WHERE name = $1 AND NOT EXISTS (SELECT 1 FROM acquired)`
+	var current model.Lease
+	err := lr.pool.QueryRowContext(ctx, query, lease.Name, lease.Holder, lease.ExpiresAt, now, lease.OwnerID).
+		Scan(&current.Name, &current.Holder, &current.ExpiresAt, &current.OwnerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// чужая аренда освобождена между вставкой и чтением: не захвачена, повторится в следующий раз.
+		return &model.Lease{Name: lease.Name}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+func (lr LeaseRepo) Release(ctx context.Context, name, holder string, ownerID uuid.UUID) error {
+	stmt := sqlf.DeleteFrom("leases").
+		Where("name = ?", name).
+		Where("holder = ?", holder).
+		Where("owner_id = ?", ownerID)
+	_, err := stmt.ExecAndClose(ctx, lr.pool)
+	return err
+}
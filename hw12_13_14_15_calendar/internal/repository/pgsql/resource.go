@@ -0,0 +1,127 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type ResourceRepo struct {
+	pool *sql.DB
+}
+
+func NewResourceRepo(pool *sql.DB) repository.Resource {
+	return &ResourceRepo{pool: pool}
+}
+
+func (rr ResourceRepo) Add(ctx context.Context, input model.ResourceCreate) (*model.Resource, error) {
+	guid := uuid.New()
+	stmt := sqlf.InsertInto("resources").
+		Set("id", guid.String()).
+		Set("kind", input.Kind.String()).
+		Set("name", input.Name).
+		Set("manager_id", input.ManagerID.String()).
+		Set("capacity", input.Capacity).
+		Set("location", input.Location)
+	_, err := stmt.ExecAndClose(ctx, rr.pool)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := rr.GetList(ctx, model.ResourceSearch{ID: &guid})
+	if err != nil {
+		return nil, err
+	}
+	return &resources[0], nil
+}
+
+func (rr ResourceRepo) Update(
+	ctx context.Context, input model.ResourceUpdate, search model.ResourceSearch,
+) (int64, error) {
+	stmt := sqlf.Update("resources")
+	rr.applySearch(stmt, search)
+	if input.Name != nil {
+		stmt.Set("name", *input.Name)
+	}
+	if input.Capacity != nil {
+		stmt.Set("capacity", *input.Capacity)
+	}
+	if input.Location != nil {
+		stmt.Set("location", *input.Location)
+	}
+	if input.ManagerID != nil {
+		stmt.Set("manager_id", input.ManagerID.String())
+	}
+	res, err := stmt.ExecAndClose(ctx, rr.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (rr ResourceRepo) Delete(ctx context.Context, search model.ResourceSearch) (int64, error) {
+	stmt := sqlf.DeleteFrom("resources")
+	rr.applySearch(stmt, search)
+	res, err := stmt.ExecAndClose(ctx, rr.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetList не учитываем пагинацию, сортировка по названию.
+func (rr ResourceRepo) GetList(ctx context.Context, search model.ResourceSearch) ([]model.Resource, error) {
+	stmt := sqlf.From("resources").Select("id, kind, name, manager_id, capacity, location")
+	rr.applySearch(stmt, search)
+	stmt.OrderBy("name")
+	resources := make([]model.Resource, 0)
+	rows, err := rr.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var id, kind, managerID string
+		resource := model.Resource{}
+		if err = rows.Scan(&id, &kind, &resource.Name, &managerID, &resource.Capacity, &resource.Location); err != nil {
+			return nil, err
+		}
+		if resource.ID, err = uuid.Parse(id); err != nil {
+			return nil, err
+		}
+		if resource.Kind, err = model.ParseResourceKind(kind); err != nil {
+			return nil, err
+		}
+		if resource.ManagerID, err = uuid.Parse(managerID); err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func (rr ResourceRepo) applySearch(stmt *sqlf.Stmt, search model.ResourceSearch) {
+	if search.ID != nil {
+		stmt.Where("resources.id = ?", search.ID.String())
+	}
+	if search.NotID != nil {
+		stmt.Where("resources.id != ?", search.NotID.String())
+	}
+	if search.IDs != nil {
+		stmt.Where("resources.id = ANY(?::uuid[])", uuidArray(search.IDs))
+	}
+	if search.Kind != nil {
+		stmt.Where("resources.kind = ?", search.Kind.String())
+	}
+	if search.Name != nil {
+		stmt.Where("lower(resources.name) = lower(?)", *search.Name)
+	}
+	if search.MinCapacity != nil {
+		stmt.Where("resources.capacity >= ?", *search.MinCapacity)
+	}
+}
@@ -0,0 +1,121 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/leporo/sqlf"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+)
+
+type TagRepo struct {
+	pool *sql.DB
+}
+
+func NewTagRepo(pool *sql.DB) repository.Tag {
+	return &TagRepo{pool: pool}
+}
+
+func (tr TagRepo) Add(ctx context.Context, input model.TagCreate) (*model.Tag, error) {
+	guid := uuid.New()
+	stmt := sqlf.InsertInto("tags").
+		Set("id", guid.String()).
+		Set("owner_id", input.OwnerID.String()).
+		Set("name", input.Name).
+		Set("color", input.Color)
+	_, err := stmt.ExecAndClose(ctx, tr.pool)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := tr.GetList(ctx, model.TagSearch{ID: &guid})
+	if err != nil {
+		return nil, err
+	}
+	return &tags[0], nil
+}
+
+func (tr TagRepo) Update(ctx context.Context, input model.TagUpdate, search model.TagSearch) (int64, error) {
+	stmt := sqlf.Update("tags")
+	tr.applySearch(stmt, search)
+	if input.Name != nil {
+		stmt.Set("name", *input.Name)
+	}
+	if input.Color != nil {
+		stmt.Set("color", *input.Color)
+	}
+	res, err := stmt.ExecAndClose(ctx, tr.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (tr TagRepo) Delete(ctx context.Context, search model.TagSearch) (int64, error) {
+	stmt := sqlf.DeleteFrom("tags")
+	tr.applySearch(stmt, search)
+	res, err := stmt.ExecAndClose(ctx, tr.pool)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetList не учитываем пагинацию, сортировка по названию.
+func (tr TagRepo) GetList(ctx context.Context, search model.TagSearch) ([]model.Tag, error) {
+	stmt := sqlf.From("tags").Select("id, owner_id, name, color")
+	tr.applySearch(stmt, search)
+	stmt.OrderBy("name")
+	tags := make([]model.Tag, 0)
+	rows, err := tr.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var id, ownerID string
+		tag := model.Tag{}
+		if err = rows.Scan(&id, &ownerID, &tag.Name, &tag.Color); err != nil {
+			return nil, err
+		}
+		if tag.ID, err = uuid.Parse(id); err != nil {
+			return nil, err
+		}
+		if tag.OwnerID, err = uuid.Parse(ownerID); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (tr TagRepo) applySearch(stmt *sqlf.Stmt, search model.TagSearch) {
+	if search.ID != nil {
+		stmt.Where("tags.id = ?", search.ID.String())
+	}
+	if search.NotID != nil {
+		stmt.Where("tags.id != ?", search.NotID.String())
+	}
+	if search.IDs != nil {
+		stmt.Where("tags.id = ANY(?::uuid[])", uuidArray(search.IDs))
+	}
+	if search.OwnerID != nil {
+		stmt.Where("tags.owner_id = ?", search.OwnerID.String())
+	}
+	if search.Name != nil {
+		stmt.Where("lower(tags.name) = lower(?)", *search.Name)
+	}
+}
+
+// uuidArray литерал массива postgres для передачи списка идентификаторов одним параметром.
+func uuidArray(ids []uuid.UUID) string {
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = id.String()
+	}
+	return "{" + strings.Join(items, ",") + "}"
+}
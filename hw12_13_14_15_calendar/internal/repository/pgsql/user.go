@@ -23,7 +23,8 @@ func (ur UserRepo) Add(ctx context.Context, input model.UserCreate) (*model.User
 	stmt := sqlf.InsertInto("users").
 		Set("id", guid.String()).
 		Set("name", input.Name).
-		Set("email", input.Email)
+		Set("email", input.Email).
+		Set("locale", input.Locale)
 	err := stmt.QueryRowAndClose(ctx, ur.pool)
 	if err != nil {
 		return nil, err
@@ -44,6 +45,9 @@ func (ur UserRepo) Update(ctx context.Context, input model.UserUpdate, search mo
 	if input.Email != nil {
 		stmt.Set("email", *input.Email)
 	}
+	if input.Locale != nil {
+		stmt.Set("locale", *input.Locale)
+	}
 	res, err := stmt.ExecAndClose(ctx, ur.pool)
 	if err != nil {
 		return 0, err
@@ -63,7 +67,7 @@ func (ur UserRepo) Delete(ctx context.Context, search model.UserSearch) (int64,
 
 // GetList не учитываем пагинацию, сортировку.
 func (ur UserRepo) GetList(ctx context.Context, search model.UserSearch) ([]model.User, error) {
-	stmt := sqlf.From("users").Select("*")
+	stmt := sqlf.From("users").Select("id, name, email, locale")
 	ur.applySearch(stmt, search)
 	users := make([]model.User, 0)
 	rows, err := ur.pool.QueryContext(ctx, stmt.String(), stmt.Args()...)
@@ -85,12 +89,13 @@ func (ur UserRepo) GetList(ctx context.Context, search model.UserSearch) ([]mode
 
 func (ur UserRepo) prepareModel(row *sql.Rows) (model.User, error) {
 	var (
-		id    sql.NullString
-		name  sql.NullString
-		email sql.NullString
-		user  model.User
+		id     sql.NullString
+		name   sql.NullString
+		email  sql.NullString
+		locale sql.NullString
+		user   model.User
 	)
-	if err := row.Scan(&id, &name, &email); err != nil {
+	if err := row.Scan(&id, &name, &email, &locale); err != nil {
 		if err != nil {
 			return user, err
 		}
@@ -108,6 +113,9 @@ func (ur UserRepo) prepareModel(row *sql.Rows) (model.User, error) {
 	if email.Valid {
 		user.Email = email.String
 	}
+	if locale.Valid {
+		user.Locale = locale.String
+	}
 	return user, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
 )
 
@@ -14,8 +15,18 @@ type Event interface {
 	Add(context.Context, model.EventCreate) (*model.Event, error)
 	Update(context.Context, model.EventUpdate, model.EventSearch) (int64, error)
 	Delete(context.Context, model.EventSearch) (int64, error)
+	// AddBatch и UpdateBatch выполняют все операции атомарно.
+	AddBatch(context.Context, []model.EventCreate) ([]model.Event, error)
+	UpdateBatch(context.Context, []model.EventBatchUpdate) (int64, error)
+	// Trash помещает события в корзину, Restore возвращает их из корзины.
+	Trash(context.Context, model.EventSearch) (int64, error)
+	Restore(context.Context, model.EventSearch) (int64, error)
 	// GetList не учитываем пагинацию и сортировку.
 	GetList(context.Context, model.EventSearch) ([]model.Event, error)
+	Count(context.Context, model.EventSearch) (int64, error)
+	// Search полнотекстовый поиск по search.Query с учетом остальных условий,
+	// результаты упорядочены по убыванию релевантности.
+	Search(context.Context, model.EventSearch) ([]model.EventFound, error)
 	BlockEvents4Notify(context.Context, time.Time) ([]model.Event, error)
 }
 
@@ -27,3 +38,81 @@ type User interface {
 	// GetList не учитываем пагинацию и сортировку.
 	GetList(context.Context, model.UserSearch) ([]model.User, error)
 }
+
+// Tag репозиторий для управления метками событий.
+type Tag interface {
+	Add(context.Context, model.TagCreate) (*model.Tag, error)
+	Update(context.Context, model.TagUpdate, model.TagSearch) (int64, error)
+	Delete(context.Context, model.TagSearch) (int64, error)
+	// GetList не учитываем пагинацию и сортировку.
+	GetList(context.Context, model.TagSearch) ([]model.Tag, error)
+}
+
+// Resource репозиторий общих бронируемых ресурсов.
+type Resource interface {
+	Add(context.Context, model.ResourceCreate) (*model.Resource, error)
+	Update(context.Context, model.ResourceUpdate, model.ResourceSearch) (int64, error)
+	Delete(context.Context, model.ResourceSearch) (int64, error)
+	// GetList упорядочен по названию.
+	GetList(context.Context, model.ResourceSearch) ([]model.Resource, error)
+}
+
+// Attachment репозиторий метаданных вложений событий, содержимое хранится отдельно.
+type Attachment interface {
+	Add(context.Context, model.AttachmentCreate) (*model.Attachment, error)
+	Delete(context.Context, model.AttachmentSearch) (int64, error)
+	// GetList упорядочен по дате добавления.
+	GetList(context.Context, model.AttachmentSearch) ([]model.Attachment, error)
+}
+
+// Availability репозиторий настроек доступности пользователей.
+type Availability interface {
+	// Get возвращает nil, если пользователь настроек не задавал.
+	Get(context.Context, uuid.UUID) (*model.Availability, error)
+	// Save создает или заменяет настройки пользователя.
+	Save(context.Context, model.Availability) error
+	AddOutOfOffice(context.Context, model.OutOfOfficeCreate) (*model.OutOfOffice, error)
+	DeleteOutOfOffice(context.Context, model.OutOfOfficeSearch) (int64, error)
+	// GetOutOfOffice периоды отсутствия, упорядоченные по дате начала.
+	GetOutOfOffice(context.Context, model.OutOfOfficeSearch) ([]model.OutOfOffice, error)
+}
+
+// Idempotency репозиторий ключей идемпотентности, ключ уникален в пределах пользователя.
+type Idempotency interface {
+	// Get возвращает nil, если ключ не найден или истек к моменту now.
+	Get(ctx context.Context, userID uuid.UUID, key string, now time.Time) (*model.IdempotencyKey, error)
+	// Reserve сохраняет ключ без результата, истекший ключ заменяется.
+	// false - действующий ключ уже существует.
+	Reserve(context.Context, model.IdempotencyKey) (bool, error)
+	// Complete сохраняет результат запроса.
+	Complete(ctx context.Context, userID uuid.UUID, key string, event model.Event) error
+	// Fail сохраняет ошибку запроса.
+	Fail(ctx context.Context, userID uuid.UUID, key string, failure model.IdempotencyFailure) error
+	// Delete освобождает ключ, если запрос завершился внутренней ошибкой.
+	Delete(ctx context.Context, userID uuid.UUID, key string) error
+	// DeleteExpired удаляет ключи, истекшие к моменту now.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Lease репозиторий аренды ролей ведущего экземпляра.
+type Lease interface {
+	// Acquire захватывает аренду, если она свободна, истекла к моменту now или уже принадлежит
+	// lease.Holder той же учетной записи lease.OwnerID, и возвращает действующую аренду,
+	// в том числе чужую. Пустой владелец - аренда не захвачена и действующей нет.
+	Acquire(ctx context.Context, lease model.Lease, now time.Time) (*model.Lease, error)
+	// Release освобождает аренду, если она принадлежит holder учетной записи ownerID.
+	Release(ctx context.Context, name, holder string, ownerID uuid.UUID) error
+}
+
+// Digest репозиторий настроек сводки событий пользователей.
+type Digest interface {
+	// Get возвращает nil, если пользователь настроек не задавал.
+	Get(context.Context, uuid.UUID) (*model.DigestSettings, error)
+	// Save создает или заменяет настройки пользователя, время последней отправки не меняется.
+	Save(context.Context, model.DigestSettings) error
+	// GetEnabled настройки пользователей с включенной сводкой.
+	GetEnabled(context.Context) ([]model.DigestSettings, error)
+	// MarkSent отмечает отправку сводки, запланированной на sentAt; false - сводка
+	// на это или более позднее время уже отмечена.
+	MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) (bool, error)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+const (
+	// DefaultAttachmentSize размер файла по умолчанию, 10 Мб.
+	DefaultAttachmentSize = 10 << 20
+	// sniffLen столько байт анализирует http.DetectContentType.
+	sniffLen = 512
+	// maxFileName ограничение длины имени файла в байтах.
+	maxFileName = 255
+)
+
+// AttachmentLimits ограничения вложений.
+type AttachmentLimits struct {
+	// MaxSize размер файла в байтах, 0 - DefaultAttachmentSize.
+	MaxSize int64
+	// MaxCount количество вложений события, 0 - без ограничений.
+	MaxCount int
+	// AllowedTypes допустимые типы: image/png или image/*, пусто - любые.
+	AllowedTypes []string
+}
+
+type AttachmentService struct {
+	repo   repository.Attachment
+	blobs  blobstore.Store
+	log    logger.Logger
+	user   User
+	limits AttachmentLimits
+}
+
+func (as AttachmentService) Upload(
+	ctx context.Context, event model.Event, input model.AttachmentUpload,
+) (*model.Attachment, error) {
+	if _, err := authorizedUser(ctx, as.user, event.Owner); err != nil {
+		return nil, err
+	}
+	if as.limits.MaxCount > 0 {
+		attachments, err := as.repo.GetList(ctx, model.AttachmentSearch{EventID: &event.ID})
+		if err != nil {
+			return nil, errx.FatalNew(err)
+		}
+		if len(attachments) >= as.limits.MaxCount {
+			return nil, errx.LogicNew(model.ErrAttachmentCount, model.ErrAttachmentCountCode)
+		}
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(input.Content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, errx.FatalNew(err)
+	}
+	head = head[:n]
+	contentType, err := as.contentType(input.ContentType, head)
+	if err != nil {
+		return nil, err
+	}
+	// пустой файл отклоняется до записи в хранилище, итоговый размер известен после записи.
+	create := model.AttachmentCreate{
+		EventID:     event.ID,
+		FileName:    cleanFileName(input.FileName),
+		ContentType: contentType,
+		Size:        int64(n),
+		BlobKey:     event.ID.String() + "/" + uuid.New().String(),
+	}
+	if err = create.Validate(); err != nil {
+		return nil, invalidAttachment(err)
+	}
+	content := &sizeLimiter{
+		r:    io.MultiReader(bytes.NewReader(head), input.Content),
+		left: as.maxSize(),
+	}
+	if create.Size, err = as.blobs.Put(ctx, create.BlobKey, content); err != nil {
+		if errors.Is(err, model.ErrAttachmentTooLarge) {
+			return nil, errx.LogicNew(model.ErrAttachmentTooLarge, model.ErrAttachmentTooLargeCode)
+		}
+		return nil, errx.FatalNew(err)
+	}
+	attachment, err := as.repo.Add(ctx, create)
+	if err != nil {
+		as.deleteBlob(ctx, create.BlobKey)
+		return nil, errx.FatalNew(err)
+	}
+	return attachment, nil
+}
+
+func (as AttachmentService) GetList(ctx context.Context, event model.Event) ([]model.Attachment, error) {
+	if _, err := authorizedUser(ctx, as.user, event.Owner); err != nil {
+		return nil, err
+	}
+	attachments, err := as.repo.GetList(ctx, model.AttachmentSearch{EventID: &event.ID})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return attachments, nil
+}
+
+func (as AttachmentService) GetByID(
+	ctx context.Context, event model.Event, attachmentID uuid.UUID,
+) (*model.Attachment, error) {
+	if _, err := authorizedUser(ctx, as.user, event.Owner); err != nil {
+		return nil, err
+	}
+	attachments, err := as.repo.GetList(ctx, model.AttachmentSearch{ID: &attachmentID, EventID: &event.ID})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if len(attachments) == 0 {
+		return nil, errx.NotFoundNew(model.ErrAttachmentNotFound, map[string]uuid.UUID{
+			"attachmentId": attachmentID,
+		})
+	}
+	return &attachments[0], nil
+}
+
+func (as AttachmentService) Open(ctx context.Context, attachment model.Attachment) (io.ReadCloser, error) {
+	content, err := as.blobs.Open(ctx, attachment.BlobKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, errx.NotFoundNew(model.ErrAttachmentNotFound, map[string]uuid.UUID{
+				"attachmentId": attachment.ID,
+			})
+		}
+		return nil, errx.FatalNew(err)
+	}
+	return content, nil
+}
+
+func (as AttachmentService) Delete(ctx context.Context, event model.Event, attachment model.Attachment) error {
+	if _, err := authorizedUser(ctx, as.user, event.Owner); err != nil {
+		return err
+	}
+	if _, err := as.repo.Delete(ctx, model.AttachmentSearch{ID: &attachment.ID}); err != nil {
+		return errx.FatalNew(err)
+	}
+	as.deleteBlob(ctx, attachment.BlobKey)
+	return nil
+}
+
+// deleteBlob содержимое без метаданных недоступно, поэтому ошибка удаления только журналируется.
+func (as AttachmentService) deleteBlob(ctx context.Context, key string) {
+	if err := as.blobs.Delete(ctx, key); err != nil {
+		logger.FromContext(ctx, as.log).Error("ошибка удаления содержимого вложения %s: %s", key, err.Error())
+	}
+}
+
+func (as AttachmentService) maxSize() int64 {
+	if as.limits.MaxSize > 0 {
+		return as.limits.MaxSize
+	}
+	return DefaultAttachmentSize
+}
+
+// contentType тип файла: заявленный клиентом или, если он не указан, определенный по содержимому.
+// Заявленный тип отклоняется, если содержимое надежно опознается как другой тип: изображение
+// под видом документа, HTML под видом изображения и т.п.
+func (as AttachmentService) contentType(declared string, head []byte) (string, error) {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	contentType := "application/octet-stream"
+	if declared != "" {
+		mediaType, _, err := mime.ParseMediaType(declared)
+		if err != nil {
+			return "", errx.LogicNew(model.ErrAttachmentType, model.ErrAttachmentTypeCode)
+		}
+		contentType = mediaType
+	}
+	if contentType == "application/octet-stream" {
+		contentType = sniffed
+	}
+	if reliableType(sniffed) && sniffed != contentType {
+		return "", errx.LogicNew(model.ErrAttachmentType, model.ErrAttachmentTypeCode)
+	}
+	if !as.allowedType(contentType) {
+		return "", errx.LogicNew(model.ErrAttachmentType, model.ErrAttachmentTypeCode)
+	}
+	return contentType, nil
+}
+
+func (as AttachmentService) allowedType(contentType string) bool {
+	if len(as.limits.AllowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range as.limits.AllowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// reliableType типы, которые http.DetectContentType определяет по сигнатуре без ложных срабатываний.
+// Текст и архивы (в том числе документы office) опознаются неоднозначно и не сверяются.
+func reliableType(sniffed string) bool {
+	return sniffed == "text/html" || sniffed == "application/pdf" ||
+		strings.HasPrefix(sniffed, "image/") || strings.HasPrefix(sniffed, "audio/") ||
+		strings.HasPrefix(sniffed, "video/")
+}
+
+// cleanFileName имя файла без пути и управляющих символов, не длиннее maxFileName байт.
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "." || name == "/" || name == ".." {
+		return ""
+	}
+	for len(name) > maxFileName {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+func invalidAttachment(err error) error {
+	errs := errx.NamedErrors{}
+	if errors.As(err, &errs) {
+		return errx.InvalidNew("неверные параметры", errs)
+	}
+	return err
+}
+
+// sizeLimiter прерывает чтение ошибкой при превышении размера, чтобы хранилище не сохраняло файл.
+type sizeLimiter struct {
+	r    io.Reader
+	left int64
+}
+
+func (sl *sizeLimiter) Read(p []byte) (int, error) {
+	n, err := sl.r.Read(p)
+	sl.left -= int64(n)
+	if sl.left < 0 {
+		return n, model.ErrAttachmentTooLarge
+	}
+	return n, err
+}
+
+func NewAttachmentService(
+	repo repository.Attachment, blobs blobstore.Store, log logger.Logger, user User, limits AttachmentLimits,
+) Attachment {
+	return &AttachmentService{
+		repo:   repo,
+		blobs:  blobs,
+		log:    log,
+		user:   user,
+		limits: limits,
+	}
+}
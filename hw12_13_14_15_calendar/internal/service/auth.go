@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
 )
@@ -32,3 +34,20 @@ func (as AuthService) Authorize(ctx context.Context, email string) (*servers.Aut
 func NewAuthService(user User) servers.AuthService {
 	return &AuthService{user}
 }
+
+// authorizedUser текущий пользователь; если задан checkUser, текущий пользователь должен с ним совпадать.
+func authorizedUser(ctx context.Context, user User, checkUser *model.User) (*model.User, error) {
+	current, err := user.GetCurrent(ctx)
+	if err != nil {
+		// пользователь не авторизован.
+		nfErr := errx.NotFound{}
+		if errors.As(err, &nfErr) {
+			return nil, errx.LogicNew(model.ErrCalendarAccess, model.ErrCalendarAccessCode)
+		}
+		return nil, errx.FatalNew(err)
+	}
+	if checkUser != nil && strings.Compare(current.ID.String(), checkUser.ID.String()) != 0 {
+		return nil, errx.LogicNew(model.ErrCalendarAccess, model.ErrCalendarAccessCode)
+	}
+	return current, nil
+}
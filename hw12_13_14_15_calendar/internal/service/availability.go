@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type AvailabilityService struct {
+	repo   repository.Availability
+	events repository.Event
+	log    logger.Logger
+	user   User
+}
+
+func (as AvailabilityService) Get(ctx context.Context) (*model.Availability, error) {
+	user, err := authorizedUser(ctx, as.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	return userAvailability(ctx, as.repo, user.ID)
+}
+
+func (as AvailabilityService) Update(ctx context.Context, input model.Availability) error {
+	user, err := authorizedUser(ctx, as.user, nil)
+	if err != nil {
+		return err
+	}
+	input.UserID = user.ID
+	if err = input.Validate(); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			return errx.InvalidNew("неверные параметры", errs)
+		}
+		return err
+	}
+	if err = as.repo.Save(ctx, input); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+func (as AvailabilityService) GetOutOfOffice(ctx context.Context) ([]model.OutOfOffice, error) {
+	user, err := authorizedUser(ctx, as.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := as.repo.GetOutOfOffice(ctx, model.OutOfOfficeSearch{UserID: &user.ID})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return absences, nil
+}
+
+func (as AvailabilityService) AddOutOfOffice(
+	ctx context.Context,
+	input model.OutOfOfficeCreate,
+) (*model.OutOfOffice, error) {
+	user, err := authorizedUser(ctx, as.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	input.UserID = user.ID
+	if err = input.Validate(); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			return nil, errx.InvalidNew("неверные параметры", errs)
+		}
+		return nil, err
+	}
+	ooo, err := as.repo.AddOutOfOffice(ctx, input)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return ooo, nil
+}
+
+func (as AvailabilityService) DeleteOutOfOffice(ctx context.Context, oooID uuid.UUID) error {
+	user, err := authorizedUser(ctx, as.user, nil)
+	if err != nil {
+		return err
+	}
+	n, err := as.repo.DeleteOutOfOffice(ctx, model.OutOfOfficeSearch{ID: &oooID, UserID: &user.ID})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if n == 0 {
+		return errx.NotFoundNew(model.ErrOutOfOfficeNotFound, map[string]uuid.UUID{"oooId": oooID})
+	}
+	return nil
+}
+
+func (as AvailabilityService) FreeSlots(ctx context.Context, date time.Time) ([]model.DateRange, error) {
+	user, err := authorizedUser(ctx, as.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := userAvailability(ctx, as.repo, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// берется календарная дата, границы дня - в часовом поясе пользователя.
+	loc := settings.Location()
+	day := model.DateRgnFromDates(
+		time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
+		time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, loc),
+	)
+	events, err := as.events.GetList(ctx, model.EventSearch{
+		OwnerID:     &user.ID,
+		DateRange:   &day,
+		TacDuration: true,
+	})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	absences, err := as.repo.GetOutOfOffice(ctx, model.OutOfOfficeSearch{UserID: &user.ID, DateRange: &day})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return settings.FreeSlots(day.GetFrom(), events, absences), nil
+}
+
+// userAvailability настройки пользователя, для не задававших настройки - настройки по умолчанию.
+func userAvailability(ctx context.Context, repo repository.Availability, userID uuid.UUID) (*model.Availability, error) {
+	settings, err := repo.Get(ctx, userID)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if settings == nil {
+		def := model.DefaultAvailability(userID)
+		settings = &def
+	}
+	return settings, nil
+}
+
+// checkAvailability проверка интервала события по настройкам владельца: при политике
+// reject возвращается ошибка, при warn - текст предупреждения.
+func checkAvailability(
+	ctx context.Context,
+	repo repository.Availability,
+	ownerID uuid.UUID,
+	dateRgn model.DateRange,
+) (string, error) {
+	settings, err := userAvailability(ctx, repo, ownerID)
+	if err != nil {
+		return "", err
+	}
+	if settings.Policy == model.AvailabilityIgnore {
+		return "", nil
+	}
+	absences, err := repo.GetOutOfOffice(ctx, model.OutOfOfficeSearch{UserID: &ownerID, DateRange: &dateRgn})
+	if err != nil {
+		return "", errx.FatalNew(err)
+	}
+	if settings.Covers(dateRgn, absences) {
+		return "", nil
+	}
+	if settings.Policy == model.AvailabilityReject {
+		return "", errx.LogicNew(model.ErrEventOutOfHours, model.ErrEventOutOfHoursCode)
+	}
+	return model.ErrEventOutOfHours.Error(), nil
+}
+
+func NewAvailabilityService(
+	repo repository.Availability,
+	events repository.Event,
+	log logger.Logger,
+	user User,
+) Availability {
+	return &AvailabilityService{repo: repo, events: events, log: log, user: user}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type DigestService struct {
+	repo         repository.Digest
+	availability repository.Availability
+	events       repository.Event
+	log          logger.Logger
+	user         User
+	clock        clock.Clock
+}
+
+func (ds DigestService) Get(ctx context.Context) (*model.DigestSettings, error) {
+	user, err := authorizedUser(ctx, ds.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := ds.repo.Get(ctx, user.ID)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if settings == nil {
+		def := model.DefaultDigestSettings(user.ID)
+		settings = &def
+	}
+	return settings, nil
+}
+
+func (ds DigestService) Update(ctx context.Context, input model.DigestSettings) error {
+	user, err := authorizedUser(ctx, ds.user, nil)
+	if err != nil {
+		return err
+	}
+	input.UserID = user.ID
+	if err = input.Validate(); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			return errx.InvalidNew("неверные параметры", errs)
+		}
+		return err
+	}
+	if err = ds.repo.Save(ctx, input); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+func (ds DigestService) DueDigests(ctx context.Context) ([]model.Digest, error) {
+	enabled, err := ds.repo.GetEnabled(ctx)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	now := ds.clock.Now()
+	digests := make([]model.Digest, 0)
+	for _, settings := range enabled {
+		digest, err := ds.dueDigest(ctx, settings, now)
+		if err != nil {
+			// сводка пользователя будет сформирована при следующем вызове.
+			logger.FromContext(ctx, ds.log).Error("сводка пользователя %s: %s", settings.UserID.String(), err.Error())
+			continue
+		}
+		if digest != nil {
+			digests = append(digests, *digest)
+		}
+	}
+	return digests, nil
+}
+
+// dueDigest сводка пользователя, nil - время отправки не наступило или сводка уже подтверждена.
+// Сводка без событий тоже возвращается: ее отправку подтверждают без публикации.
+func (ds DigestService) dueDigest(
+	ctx context.Context,
+	settings model.DigestSettings,
+	now time.Time,
+) (*model.Digest, error) {
+	availability, err := userAvailability(ctx, ds.availability, settings.UserID)
+	if err != nil {
+		return nil, err
+	}
+	sendAt, period, ok := settings.Due(now, availability.Location())
+	if !ok {
+		return nil, nil
+	}
+	user, err := ds.user.GetByID(ctx, settings.UserID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := ds.events.GetList(ctx, model.EventSearch{
+		OwnerID:     &settings.UserID,
+		DateRange:   &period,
+		TacDuration: true,
+	})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+	digest := model.Digest{
+		Kind:       model.MessageDigest,
+		Mode:       settings.Mode.String(),
+		DateStart:  period.GetFrom(),
+		DateEnd:    period.GetTo(),
+		TimeZone:   availability.TimeZone,
+		NotifyUser: model.NotifyUser{Name: user.Name, Email: user.Email, Locale: user.Locale},
+		Events:     make([]model.DigestEvent, len(events)),
+		UserID:     settings.UserID,
+		SendAt:     sendAt,
+	}
+	for i, event := range events {
+		digest.Events[i] = model.DigestEvent{
+			ID:       event.ID,
+			Title:    event.Title,
+			Date:     event.Date,
+			Duration: event.Duration,
+		}
+	}
+	return &digest, nil
+}
+
+// MarkSent повторное подтверждение уже отмеченной сводки не считается ошибкой.
+func (ds DigestService) MarkSent(ctx context.Context, userID uuid.UUID, sendAt time.Time) error {
+	marked, err := ds.repo.MarkSent(ctx, userID, sendAt)
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if !marked {
+		logger.FromContext(ctx, ds.log).Warn("сводка пользователя %s на %s уже подтверждена",
+			userID.String(), sendAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func NewDigestService(
+	repo repository.Digest,
+	availability repository.Availability,
+	events repository.Event,
+	log logger.Logger,
+	user User,
+	clock clock.Clock,
+) Digest {
+	return &DigestService{
+		repo:         repo,
+		availability: availability,
+		events:       events,
+		log:          log,
+		user:         user,
+		clock:        clock,
+	}
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+/*
+Пакетные операции проверяются вместе: занятость дат определяется одним запросом
+по всему интервалу пакета, а пересечения ищутся и среди операций самого пакета.
+Прежний интервал изменяемого события свободен, только если его операция принята:
+отклоненная операция оставляет событие на месте. Поэтому изменения проверяются
+раундами: каждый раунд считает, что все еще не отклоненные операции выполняются,
+и повторяется, пока в нем отклоняется хотя бы одна операция.
+Бронь ресурсов проверяется для каждой операции отдельно: события пакета принадлежат
+одному владельцу и не пересекаются, поэтому внутри пакета ресурсы не конфликтуют.
+*/
+
+// batchSlot интервал, занимаемый событием после выполнения пакета.
+type batchSlot struct {
+	index int
+	start time.Time
+	end   time.Time
+}
+
+func (bs batchSlot) overlaps(start, end time.Time) bool {
+	return bs.end.After(start) && bs.start.Before(end)
+}
+
+// batchSlots занятые интервалы владельца: события из хранилища и принятые операции пакета.
+type batchSlots struct {
+	stored []model.Event
+	// skip события, прежние интервалы которых освобождаются операциями пакета.
+	skip     map[uuid.UUID]struct{}
+	accepted []batchSlot
+}
+
+// reset начало раунда проверки: интервалы событий moved освобождаются, принятых операций нет.
+func (bs *batchSlots) reset(moved map[uuid.UUID]struct{}) {
+	bs.skip = moved
+	bs.accepted = bs.accepted[:0]
+}
+
+// check ошибка, если интервал занят событием из хранилища или принятой операцией пакета.
+func (bs *batchSlots) check(start time.Time, duration time.Duration) error {
+	end := start.Add(duration)
+	for _, event := range bs.stored {
+		if _, ok := bs.skip[event.ID]; ok {
+			continue
+		}
+		if event.Date.Add(event.Duration).After(start) && event.Date.Before(end) {
+			return errx.LogicNew(model.ErrEventDateBusy, model.ErrEventDateBusyCode)
+		}
+	}
+	for _, slot := range bs.accepted {
+		if slot.overlaps(start, end) {
+			return errx.LogicNew(
+				fmt.Errorf("%w: операция %d", model.ErrEventBatchOverlap, slot.index),
+				model.ErrEventBatchOverlapCode,
+			)
+		}
+	}
+	return nil
+}
+
+// accept интервал операции index занят.
+func (bs *batchSlots) accept(index int, start time.Time, duration time.Duration) {
+	bs.accepted = append(bs.accepted, batchSlot{index: index, start: start, end: start.Add(duration)})
+}
+
+func (es EventCRUDService) AddBatch(
+	ctx context.Context,
+	inputs []model.EventCreate,
+	mode model.BatchMode,
+) (*model.BatchReport, error) {
+	user, err := es.getAuthorizedUser(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = es.checkBatchSize(len(inputs)); err != nil {
+		return nil, err
+	}
+	if err = es.checkQuota(ctx, user.ID, len(inputs)); err != nil {
+		return nil, err
+	}
+	report := &model.BatchReport{Results: make([]model.BatchResult, len(inputs))}
+	ranges := make([]model.DateRange, 0, len(inputs))
+	for i := range inputs {
+		inputs[i].OwnerID, inputs[i].OwnerQuota = user.ID, es.quota
+		ranges = append(ranges, model.DateRange{DateStart: inputs[i].Date, Duration: inputs[i].Duration})
+	}
+	slots, err := es.batchSlots(ctx, user.ID, ranges)
+	if err != nil {
+		return nil, err
+	}
+	warnings := make([]string, len(inputs))
+	for i, input := range inputs {
+		err = input.Validate()
+		if err == nil {
+			err = es.validateTags(ctx, user.ID, input.TagIDs)
+		}
+		if err == nil {
+			err = es.validateResources(ctx, input.ResourceIDs)
+		}
+		if err == nil {
+			err = slots.check(input.Date, input.Duration)
+		}
+		if err == nil {
+			err = es.checkResources(ctx, input.ResourceIDs, ranges[i], nil)
+		}
+		if err == nil {
+			warnings[i], err = checkAvailability(ctx, es.avail, user.ID, ranges[i])
+		}
+		if err == nil {
+			slots.accept(i, input.Date, input.Duration)
+		}
+		report.Results[i].Err = batchItemError(err)
+	}
+	valid := es.rejectBatch(report, mode)
+	if len(valid) == 0 {
+		return report, nil
+	}
+	accepted := make([]model.EventCreate, len(valid))
+	for i, index := range valid {
+		accepted[i] = inputs[index]
+	}
+	events, err := es.repo.AddBatch(ctx, accepted)
+	if err != nil {
+		return nil, writeError(err)
+	}
+	for i, index := range valid {
+		event := events[i]
+		if warnings[index] != "" {
+			event.Warnings = append(event.Warnings, warnings[index])
+		}
+		report.Results[index].EventID = event.ID
+		report.Results[index].Event = &event
+	}
+	report.Applied = len(valid)
+	return report, nil
+}
+
+func (es EventCRUDService) UpdateBatch(
+	ctx context.Context,
+	items []model.EventBatchUpdate,
+	mode model.BatchMode,
+) (*model.BatchReport, error) {
+	user, err := es.getAuthorizedUser(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = es.checkBatchSize(len(items)); err != nil {
+		return nil, err
+	}
+	report := &model.BatchReport{Results: make([]model.BatchResult, len(items))}
+	events, err := es.batchEvents(ctx, user.ID, items, report)
+	if err != nil {
+		return nil, err
+	}
+	// после изменения событие займет новый интервал.
+	ranges := make(map[int]model.DateRange, len(events))
+	for i, event := range events {
+		dateRgn := model.DateRange{DateStart: event.Date, Duration: event.Duration}
+		if items[i].Input.Date != nil {
+			dateRgn.DateStart = *items[i].Input.Date
+		}
+		if items[i].Input.Duration != nil {
+			dateRgn.Duration = *items[i].Input.Duration
+		}
+		ranges[i] = dateRgn
+	}
+	rangeList := make([]model.DateRange, 0, len(ranges))
+	for _, dateRgn := range ranges {
+		rangeList = append(rangeList, dateRgn)
+	}
+	slots, err := es.batchSlots(ctx, user.ID, rangeList)
+	if err != nil {
+		return nil, err
+	}
+	// pending операции, прошедшие проверку входных данных и еще не отклоненные.
+	pending := make(map[int]struct{}, len(events))
+	for i, event := range events {
+		if err = es.validateBatchInput(ctx, event, items[i].Input); err != nil {
+			report.Results[i].Err = batchItemError(err)
+			continue
+		}
+		pending[i] = struct{}{}
+	}
+	for rejected := true; rejected; {
+		rejected = false
+		moved := make(map[uuid.UUID]struct{}, len(pending))
+		for i := range pending {
+			moved[events[i].ID] = struct{}{}
+		}
+		slots.reset(moved)
+		for i := range items {
+			if _, ok := pending[i]; !ok {
+				continue
+			}
+			if err = es.checkBatchUpdate(ctx, slots, events[i], ranges[i], items[i].Input); err != nil {
+				report.Results[i].Err = batchItemError(err)
+				delete(pending, i)
+				rejected = true
+				continue
+			}
+			slots.accept(i, ranges[i].DateStart, ranges[i].Duration)
+		}
+	}
+	valid := es.rejectBatch(report, mode)
+	if len(valid) == 0 {
+		return report, nil
+	}
+	accepted := make([]model.EventBatchUpdate, len(valid))
+	for i, index := range valid {
+		accepted[i] = items[index]
+	}
+	if _, err = es.repo.UpdateBatch(ctx, accepted); err != nil {
+		return nil, writeError(err)
+	}
+	report.Applied = len(valid)
+	return report, nil
+}
+
+// validateBatchInput проверка операции изменения, не зависящая от занятости интервалов.
+func (es EventCRUDService) validateBatchInput(ctx context.Context, event model.Event, input model.EventUpdate) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.TagIDs != nil {
+		if err := es.validateTags(ctx, event.Owner.ID, *input.TagIDs); err != nil {
+			return err
+		}
+	}
+	if input.ResourceIDs != nil {
+		return es.validateResources(ctx, *input.ResourceIDs)
+	}
+	return nil
+}
+
+// checkBatchUpdate занятость интервала dateRgn, который событие займет после изменения.
+func (es EventCRUDService) checkBatchUpdate(
+	ctx context.Context,
+	slots *batchSlots,
+	event model.Event,
+	dateRgn model.DateRange,
+	input model.EventUpdate,
+) error {
+	if err := slots.check(dateRgn.DateStart, dateRgn.Duration); err != nil {
+		return err
+	}
+	if input.Date == nil && input.Duration == nil && input.ResourceIDs == nil {
+		return nil
+	}
+	resourceIDs := eventResourceIDs(event)
+	if input.ResourceIDs != nil {
+		resourceIDs = *input.ResourceIDs
+	}
+	// бронь событий, освобождающих прежние интервалы, не учитывается.
+	if err := es.checkResources(ctx, resourceIDs, dateRgn, slots.skip); err != nil {
+		return err
+	}
+	if input.Date == nil && input.Duration == nil {
+		return nil
+	}
+	_, err := checkAvailability(ctx, es.avail, event.Owner.ID, dateRgn)
+	return err
+}
+
+// DeleteBatch события помещаются в корзину.
+func (es EventCRUDService) DeleteBatch(
+	ctx context.Context,
+	eventIDs []uuid.UUID,
+	mode model.BatchMode,
+) (*model.BatchReport, error) {
+	user, err := es.getAuthorizedUser(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = es.checkBatchSize(len(eventIDs)); err != nil {
+		return nil, err
+	}
+	items := make([]model.EventBatchUpdate, len(eventIDs))
+	for i, eventID := range eventIDs {
+		items[i].ID = eventID
+	}
+	report := &model.BatchReport{Results: make([]model.BatchResult, len(eventIDs))}
+	if _, err = es.batchEvents(ctx, user.ID, items, report); err != nil {
+		return nil, err
+	}
+	valid := es.rejectBatch(report, mode)
+	if len(valid) == 0 {
+		return report, nil
+	}
+	accepted := make([]uuid.UUID, len(valid))
+	for i, index := range valid {
+		accepted[i] = eventIDs[index]
+	}
+	if _, err = es.repo.Trash(ctx, model.EventSearch{IDs: accepted}); err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	report.Applied = len(valid)
+	return report, nil
+}
+
+func (es EventCRUDService) checkBatchSize(size int) error {
+	if size == 0 || size > model.EventBatchMaxSize {
+		return errx.LogicNew(model.ErrEventBatchSize, model.ErrEventBatchSizeCode)
+	}
+	return nil
+}
+
+// batchEvents изменяемые события текущего пользователя по индексам операций, ошибки
+// поиска, доступа и повторов записываются в report.
+func (es EventCRUDService) batchEvents(
+	ctx context.Context,
+	userID uuid.UUID,
+	items []model.EventBatchUpdate,
+	report *model.BatchReport,
+) (map[int]model.Event, error) {
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	found, err := es.repo.GetList(ctx, model.EventSearch{IDs: ids})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	byID := make(map[uuid.UUID]model.Event, len(found))
+	for _, event := range found {
+		byID[event.ID] = event
+	}
+	events := make(map[int]model.Event, len(items))
+	seen := make(map[uuid.UUID]struct{}, len(items))
+	for i, item := range items {
+		report.Results[i].EventID = item.ID
+		event, ok := byID[item.ID]
+		switch {
+		case !ok:
+			report.Results[i].Err = errx.NotFoundNew(model.ErrEventNotFound, map[string]uuid.UUID{
+				"eventId": item.ID,
+			})
+		case event.Owner == nil || event.Owner.ID != userID:
+			report.Results[i].Err = errx.LogicNew(model.ErrCalendarAccess, model.ErrCalendarAccessCode)
+		default:
+			if _, ok = seen[item.ID]; ok {
+				report.Results[i].Err = errx.LogicNew(model.ErrEventBatchDupl, model.ErrEventBatchDuplCode)
+				continue
+			}
+			seen[item.ID] = struct{}{}
+			events[i] = event
+		}
+	}
+	return events, nil
+}
+
+// batchSlots занятые интервалы владельца, пересекающиеся с общим интервалом ranges.
+func (es EventCRUDService) batchSlots(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	ranges []model.DateRange,
+) (*batchSlots, error) {
+	slots := &batchSlots{skip: make(map[uuid.UUID]struct{})}
+	var from, to time.Time
+	for _, dateRgn := range ranges {
+		if dateRgn.Duration <= 0 {
+			continue
+		}
+		if from.IsZero() || dateRgn.GetFrom().Before(from) {
+			from = dateRgn.GetFrom()
+		}
+		if dateRgn.GetTo().After(to) {
+			to = dateRgn.GetTo()
+		}
+	}
+	if from.IsZero() {
+		return slots, nil
+	}
+	events, err := es.repo.GetList(ctx, model.EventSearch{
+		OwnerID:     &ownerID,
+		DateRange:   &model.DateRange{DateStart: from, Duration: to.Sub(from)},
+		TacDuration: true,
+	})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	slots.stored = events
+	return slots, nil
+}
+
+// rejectBatch индексы операций к выполнению; в атомарном режиме при наличии ошибок
+// отклоняются все операции.
+func (es EventCRUDService) rejectBatch(report *model.BatchReport, mode model.BatchMode) []int {
+	valid := make([]int, 0, len(report.Results))
+	for i, result := range report.Results {
+		if result.Err == nil {
+			valid = append(valid, i)
+		}
+	}
+	if mode == model.BatchAtomic && len(valid) < len(report.Results) {
+		for _, index := range valid {
+			report.Results[index].Err = errx.LogicNew(model.ErrEventBatchReject, model.ErrEventBatchRejectCode)
+		}
+		return nil
+	}
+	return valid
+}
+
+// batchItemError ошибки валидации приводятся к errx.Invalid, как и для одиночных операций.
+func batchItemError(err error) error {
+	errs := errx.NamedErrors{}
+	if errors.As(err, &errs) {
+		return errx.InvalidNew("неверные параметры", errs)
+	}
+	return err
+}
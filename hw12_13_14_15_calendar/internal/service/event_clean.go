@@ -5,31 +5,130 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
 )
 
 type EventCleanService struct {
-	repo  repository.Event
-	log   logger.Logger
-	clock clock.Clock
+	repo        repository.Event
+	attachments repository.Attachment
+	blobs       blobstore.Store
+	keys        repository.Idempotency
+	log         logger.Logger
+	clock       clock.Clock
 }
 
 func (ec EventCleanService) CleanupOldEvents(ctx context.Context, timeLive time.Duration) (int64, error) {
 	dateLess := ec.clock.Now().Add(timeLive * -1)
-	n, err := ec.repo.Delete(ctx, model.EventSearch{DateLess: &dateLess})
+	n, err := ec.repo.Trash(ctx, model.EventSearch{DateLess: &dateLess})
 	if err != nil {
 		return 0, errx.FatalNew(err)
 	}
 	return n, nil
 }
 
-func NewEventCleanService(repo repository.Event, log logger.Logger, clock clock.Clock) EventClean {
+// PurgeTrash вместе с событиями удаляются их вложения: метаданные и содержимое в хранилище файлов.
+func (ec EventCleanService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	deletedLess := ec.clock.Now().Add(retention * -1)
+	search := model.EventSearch{
+		Trash:       model.TrashOnly,
+		DeletedLess: &deletedLess,
+	}
+	events, err := ec.repo.GetList(ctx, search)
+	if err != nil {
+		return 0, errx.FatalNew(err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	ids := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	// в БД метаданные вложений удаляются каскадно, поэтому выбираются до удаления событий.
+	attachments, err := ec.attachments.GetList(ctx, model.AttachmentSearch{EventIDs: ids})
+	if err != nil {
+		return 0, errx.FatalNew(err)
+	}
+	search.IDs = ids
+	n, err := ec.repo.Delete(ctx, search)
+	if err != nil {
+		return 0, errx.FatalNew(err)
+	}
+	ec.purgeAttachments(ctx, ids, attachments)
+	return n, nil
+}
+
+// purgeAttachments удаляет вложения удаленных событий. Событие, восстановленное из корзины
+// между выборкой и удалением, осталось в хранилище - его вложения сохраняются.
+// Ошибки только журналируются: события уже удалены, а вложения без событий недоступны.
+func (ec EventCleanService) purgeAttachments(ctx context.Context, ids []uuid.UUID, attachments []model.Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+	remained, err := ec.repo.GetList(ctx, model.EventSearch{IDs: ids, Trash: model.TrashInclude})
+	if err != nil {
+		logger.FromContext(ctx, ec.log).Error("ошибка удаления вложений событий: %s", err.Error())
+		return
+	}
+	deleted := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !eventListed(remained, id) {
+			deleted = append(deleted, id)
+		}
+	}
+	if _, err = ec.attachments.Delete(ctx, model.AttachmentSearch{EventIDs: deleted}); err != nil {
+		logger.FromContext(ctx, ec.log).Error("ошибка удаления вложений событий: %s", err.Error())
+		return
+	}
+	for _, attachment := range attachments {
+		if !eventListed(remained, attachment.EventID) {
+			ec.deleteBlob(ctx, attachment.BlobKey)
+		}
+	}
+}
+
+func (ec EventCleanService) deleteBlob(ctx context.Context, key string) {
+	if err := ec.blobs.Delete(ctx, key); err != nil {
+		logger.FromContext(ctx, ec.log).Error("ошибка удаления содержимого вложения %s: %s", key, err.Error())
+	}
+}
+
+func eventListed(events []model.Event, id uuid.UUID) bool {
+	for _, event := range events {
+		if event.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (ec EventCleanService) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	n, err := ec.keys.DeleteExpired(ctx, ec.clock.Now())
+	if err != nil {
+		return 0, errx.FatalNew(err)
+	}
+	return n, nil
+}
+
+func NewEventCleanService(
+	repo repository.Event,
+	attachments repository.Attachment,
+	blobs blobstore.Store,
+	keys repository.Idempotency,
+	log logger.Logger,
+	clock clock.Clock,
+) EventClean {
 	return &EventCleanService{
-		repo:  repo,
-		log:   log,
-		clock: clock,
+		repo:        repo,
+		attachments: attachments,
+		blobs:       blobs,
+		keys:        keys,
+		log:         log,
+		clock:       clock,
 	}
 }
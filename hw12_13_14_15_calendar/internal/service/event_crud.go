@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -14,9 +15,14 @@ import (
 )
 
 type EventCRUDService struct {
-	repo repository.Event
-	log  logger.Logger
-	user User
+	repo      repository.Event
+	tags      repository.Tag
+	resources repository.Resource
+	avail     repository.Availability
+	log       logger.Logger
+	user      User
+	// quota максимальное количество хранимых событий пользователя, включая корзину; 0 - без ограничений.
+	quota int64
 }
 
 func (es EventCRUDService) validateAdd(ctx context.Context, input model.EventCreate) error {
@@ -30,12 +36,19 @@ func (es EventCRUDService) validateAdd(ctx context.Context, input model.EventCre
 	if user == nil {
 		return errx.LogicNew(model.ErrEventOwnerExists, model.ErrEventOwnerExistsCode)
 	}
+	if err = es.validateTags(ctx, input.OwnerID, input.TagIDs); err != nil {
+		return err
+	}
+	if err = es.validateResources(ctx, input.ResourceIDs); err != nil {
+		return err
+	}
+	dateRgn := model.DateRange{
+		DateStart: input.Date,
+		Duration:  input.Duration,
+	}
 	events, err := es.repo.GetList(ctx, model.EventSearch{
-		OwnerID: &input.OwnerID,
-		DateRange: &model.DateRange{
-			DateStart: input.Date,
-			Duration:  input.Duration,
-		},
+		OwnerID:     &input.OwnerID,
+		DateRange:   &dateRgn,
 		TacDuration: true,
 	})
 	if err != nil {
@@ -44,7 +57,7 @@ func (es EventCRUDService) validateAdd(ctx context.Context, input model.EventCre
 	if len(events) > 0 {
 		return errx.LogicNew(model.ErrEventDateBusy, model.ErrEventDateBusyCode)
 	}
-	return nil
+	return es.checkResources(ctx, input.ResourceIDs, dateRgn, nil)
 }
 
 func (es EventCRUDService) Add(ctx context.Context, input model.EventCreate) (*model.Event, error) {
@@ -52,7 +65,10 @@ func (es EventCRUDService) Add(ctx context.Context, input model.EventCreate) (*m
 	if err != nil {
 		return nil, err
 	}
-	input.OwnerID = user.ID
+	input.OwnerID, input.OwnerQuota = user.ID, es.quota
+	if err = es.checkQuota(ctx, user.ID, 1); err != nil {
+		return nil, err
+	}
 	if err = es.validateAdd(ctx, input); err != nil {
 		errs := errx.NamedErrors{}
 		if errors.As(err, &errs) {
@@ -60,9 +76,19 @@ func (es EventCRUDService) Add(ctx context.Context, input model.EventCreate) (*m
 		}
 		return nil, err
 	}
+	warning, err := checkAvailability(ctx, es.avail, input.OwnerID, model.DateRange{
+		DateStart: input.Date,
+		Duration:  input.Duration,
+	})
+	if err != nil {
+		return nil, err
+	}
 	event, err := es.repo.Add(ctx, input)
 	if err != nil {
-		return nil, errx.FatalNew(err)
+		return nil, writeError(err)
+	}
+	if warning != "" {
+		event.Warnings = append(event.Warnings, warning)
 	}
 	return event, nil
 }
@@ -71,7 +97,19 @@ func (es EventCRUDService) validateUpdate(ctx context.Context, event model.Event
 	if err := input.Validate(); err != nil {
 		return err
 	}
-	if input.Date == nil && input.Duration == nil {
+	if input.TagIDs != nil {
+		if err := es.validateTags(ctx, event.Owner.ID, *input.TagIDs); err != nil {
+			return err
+		}
+	}
+	resourceIDs := eventResourceIDs(event)
+	if input.ResourceIDs != nil {
+		if err := es.validateResources(ctx, *input.ResourceIDs); err != nil {
+			return err
+		}
+		resourceIDs = *input.ResourceIDs
+	}
+	if input.Date == nil && input.Duration == nil && input.ResourceIDs == nil {
 		return nil
 	}
 	dateRgn := model.DateRange{
@@ -84,6 +122,11 @@ func (es EventCRUDService) validateUpdate(ctx context.Context, event model.Event
 	if input.Duration != nil {
 		dateRgn.Duration = *input.Duration
 	}
+	skip := map[uuid.UUID]struct{}{event.ID: {}}
+	if input.Date == nil && input.Duration == nil {
+		// меняется только бронь ресурсов.
+		return es.checkResources(ctx, resourceIDs, dateRgn, skip)
+	}
 	search := model.EventSearch{
 		OwnerID:     &event.Owner.ID,
 		NotID:       &event.ID,
@@ -97,7 +140,12 @@ func (es EventCRUDService) validateUpdate(ctx context.Context, event model.Event
 	if len(events) > 0 {
 		return errx.LogicNew(model.ErrEventDateBusy, model.ErrEventDateBusyCode)
 	}
-	return nil
+	if err = es.checkResources(ctx, resourceIDs, dateRgn, skip); err != nil {
+		return err
+	}
+	// при изменении даты предупреждение не возвращается, учитывается только запрет.
+	_, err = checkAvailability(ctx, es.avail, event.Owner.ID, dateRgn)
+	return err
 }
 
 func (es EventCRUDService) Update(ctx context.Context, event model.Event, input model.EventUpdate) error {
@@ -113,7 +161,7 @@ func (es EventCRUDService) Update(ctx context.Context, event model.Event, input
 		return err
 	}
 	if _, err = es.repo.Update(ctx, input, model.EventSearch{ID: &event.ID}); err != nil {
-		return errx.FatalNew(err)
+		return writeError(err)
 	}
 	return nil
 }
@@ -122,6 +170,7 @@ func (es EventCRUDService) GetUserEventsOn(
 	ctx context.Context,
 	date time.Time,
 	kind model.RangeKind,
+	tagIDs []uuid.UUID,
 ) ([]model.Event, error) {
 	user, err := es.getAuthorizedUser(ctx, nil)
 	if err != nil {
@@ -134,6 +183,7 @@ func (es EventCRUDService) GetUserEventsOn(
 	return es.GetEvents(ctx, model.EventSearch{
 		OwnerID:   &user.ID,
 		DateRange: &dateRgn,
+		TagIDs:    tagIDs,
 	})
 }
 
@@ -146,18 +196,87 @@ func (es EventCRUDService) GetEvents(ctx context.Context, search model.EventSear
 	return events, nil
 }
 
+func (es EventCRUDService) Search(ctx context.Context, query string) ([]model.EventFound, error) {
+	user, err := es.getAuthorizedUser(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(model.SearchTerms(query)) == 0 {
+		return nil, errx.LogicNew(model.ErrEventSearchQuery, model.ErrEventSearchQueryCode)
+	}
+	found, err := es.repo.Search(ctx, model.EventSearch{
+		OwnerID: &user.ID,
+		Query:   &query,
+	})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return found, nil
+}
+
 func (es EventCRUDService) Delete(ctx context.Context, event model.Event) error {
 	_, err := es.getAuthorizedUser(ctx, event.Owner)
 	if err != nil {
 		return err
 	}
-	if _, err = es.repo.Delete(ctx, model.EventSearch{ID: &event.ID}); err != nil {
+	// события не удаляются, а помещаются в корзину.
+	if _, err = es.repo.Trash(ctx, model.EventSearch{ID: &event.ID}); err != nil {
 		// неустранимая пользователем ошибка.
 		return errx.FatalNew(err)
 	}
 	return nil
 }
 
+func (es EventCRUDService) GetTrash(ctx context.Context) ([]model.Event, error) {
+	user, err := es.getAuthorizedUser(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return es.GetEvents(ctx, model.EventSearch{
+		OwnerID: &user.ID,
+		Trash:   model.TrashOnly,
+	})
+}
+
+func (es EventCRUDService) Restore(ctx context.Context, eventID uuid.UUID) error {
+	event, err := es.getOne(ctx, model.EventSearch{ID: &eventID, Trash: model.TrashOnly})
+	if err != nil {
+		nfErr := errx.NotFound{}
+		if errors.As(err, &nfErr) {
+			return errx.NotFoundNew(model.ErrEventTrashNotFound, map[string]uuid.UUID{
+				"eventId": eventID,
+			})
+		}
+		return err
+	}
+	if _, err = es.getAuthorizedUser(ctx, event.Owner); err != nil {
+		return err
+	}
+	// за время нахождения в корзине дата события или его ресурсы могли быть заняты.
+	dateRgn := model.DateRange{
+		DateStart: event.Date,
+		Duration:  event.Duration,
+	}
+	events, err := es.repo.GetList(ctx, model.EventSearch{
+		OwnerID:     &event.Owner.ID,
+		DateRange:   &dateRgn,
+		TacDuration: true,
+	})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if len(events) > 0 {
+		return errx.LogicNew(model.ErrEventDateBusy, model.ErrEventDateBusyCode)
+	}
+	if err = es.checkResources(ctx, eventResourceIDs(*event), dateRgn, nil); err != nil {
+		return err
+	}
+	if _, err = es.repo.Restore(ctx, model.EventSearch{ID: &event.ID}); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
 func (es EventCRUDService) GetByID(ctx context.Context, eventID uuid.UUID) (*model.Event, error) {
 	event, err := es.getOne(ctx, model.EventSearch{ID: &eventID})
 	if err == nil {
@@ -186,26 +305,155 @@ func (es EventCRUDService) getOne(ctx context.Context, search model.EventSearch)
 }
 
 // getAuthorizedUser получить текущего пользователя.
+// checkQuota ошибка, если после добавления add событий будет превышена квота пользователя.
+// Проверка предварительная: окончательно квоту проверяет репозиторий при добавлении.
+func (es EventCRUDService) checkQuota(ctx context.Context, ownerID uuid.UUID, add int) error {
+	if es.quota <= 0 {
+		return nil
+	}
+	n, err := es.repo.Count(ctx, model.EventSearch{OwnerID: &ownerID, Trash: model.TrashInclude})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if n+int64(add) > es.quota {
+		return errx.LogicNew(
+			fmt.Errorf("%w: не более %d", model.ErrEventQuota, es.quota),
+			model.ErrEventQuotaCode,
+		)
+	}
+	return nil
+}
+
 func (es EventCRUDService) getAuthorizedUser(ctx context.Context, checkUser *model.User) (*model.User, error) {
-	user, err := es.user.GetCurrent(ctx)
+	return authorizedUser(ctx, es.user, checkUser)
+}
+
+// validateTags все метки должны принадлежать владельцу события.
+func (es EventCRUDService) validateTags(ctx context.Context, ownerID uuid.UUID, tagIDs []uuid.UUID) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	tags, err := es.tags.GetList(ctx, model.TagSearch{OwnerID: &ownerID, IDs: tagIDs})
 	if err != nil {
-		// пользователь не авторизован.
-		nfErr := errx.NotFound{}
-		if errors.As(err, &nfErr) {
-			return nil, errx.LogicNew(model.ErrCalendarAccess, model.ErrCalendarAccessCode)
+		return errx.FatalNew(err)
+	}
+	found := make(map[uuid.UUID]struct{}, len(tags))
+	for _, tag := range tags {
+		found[tag.ID] = struct{}{}
+	}
+	for _, tagID := range tagIDs {
+		if _, ok := found[tagID]; !ok {
+			return errx.NamedErrors{{Field: "TagIDs", Err: model.ErrEventTagNotFound}}
 		}
-		return nil, errx.FatalNew(err)
 	}
-	if checkUser != nil && strings.Compare(user.ID.String(), checkUser.ID.String()) != 0 {
-		return nil, errx.LogicNew(model.ErrCalendarAccess, model.ErrCalendarAccessCode)
+	return nil
+}
+
+// validateResources все бронируемые ресурсы должны существовать.
+func (es EventCRUDService) validateResources(ctx context.Context, resourceIDs []uuid.UUID) error {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+	resources, err := es.resources.GetList(ctx, model.ResourceSearch{IDs: resourceIDs})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	found := make(map[uuid.UUID]struct{}, len(resources))
+	for _, resource := range resources {
+		found[resource.ID] = struct{}{}
+	}
+	for _, resourceID := range resourceIDs {
+		if _, ok := found[resourceID]; !ok {
+			return errx.NamedErrors{{Field: "ResourceIDs", Err: model.ErrEventResourceNotFound}}
+		}
+	}
+	return nil
+}
+
+// checkResources ошибка, если какой-либо из ресурсов забронирован на пересекающийся
+// интервал событием любого пользователя; события skip не учитываются.
+func (es EventCRUDService) checkResources(
+	ctx context.Context,
+	resourceIDs []uuid.UUID,
+	dateRgn model.DateRange,
+	skip map[uuid.UUID]struct{},
+) error {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+	events, err := es.repo.GetList(ctx, model.EventSearch{
+		ResourceIDs: resourceIDs,
+		DateRange:   &dateRgn,
+		TacDuration: true,
+	})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	wanted := make(map[uuid.UUID]struct{}, len(resourceIDs))
+	for _, resourceID := range resourceIDs {
+		wanted[resourceID] = struct{}{}
+	}
+	var busy []string
+	for _, event := range events {
+		if _, ok := skip[event.ID]; ok {
+			continue
+		}
+		for _, resource := range event.Resources {
+			if _, ok := wanted[resource.ID]; ok {
+				busy = append(busy, resource.Name)
+				delete(wanted, resource.ID)
+			}
+		}
+	}
+	if len(busy) == 0 {
+		return nil
+	}
+	return errx.LogicNew(
+		fmt.Errorf("%w: %s", model.ErrEventResourceBusy, strings.Join(busy, ", ")),
+		model.ErrEventResourceBusyCode,
+	)
+}
+
+// writeError ошибка записи событий в хранилище. Хранилище повторяет проверку брони ресурсов
+// и квоты при записи, и ресурс или квота, занятые параллельным запросом после checkResources
+// или checkQuota, - логическая ошибка.
+func writeError(err error) error {
+	if errors.Is(err, model.ErrEventResourceBusy) {
+		return errx.LogicNew(err, model.ErrEventResourceBusyCode)
+	}
+	if errors.Is(err, model.ErrEventQuota) {
+		return errx.LogicNew(err, model.ErrEventQuotaCode)
+	}
+	return errx.FatalNew(err)
+}
+
+func eventResourceIDs(event model.Event) []uuid.UUID {
+	if len(event.Resources) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(event.Resources))
+	for i, resource := range event.Resources {
+		ids[i] = resource.ID
 	}
-	return user, nil
+	return ids
 }
 
-func NewEventCRUDService(repo repository.Event, log logger.Logger, user User) EventCRUD {
+func NewEventCRUDService(
+	repo repository.Event,
+	tags repository.Tag,
+	resources repository.Resource,
+	avail repository.Availability,
+	log logger.Logger,
+	user User,
+	quota int64,
+) EventCRUD {
 	return &EventCRUDService{
-		repo: repo,
-		log:  log,
-		user: user,
+		repo:      repo,
+		tags:      tags,
+		resources: resources,
+		avail:     avail,
+		log:       log,
+		user:      user,
+		quota:     quota,
 	}
 }
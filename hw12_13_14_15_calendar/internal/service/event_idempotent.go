@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+// DefIdempotencyTTL срок хранения ключей идемпотентности по умолчанию.
+const DefIdempotencyTTL = 24 * time.Hour
+
+type EventIdempotentService struct {
+	crud  EventCRUD
+	keys  repository.Idempotency
+	user  User
+	log   logger.Logger
+	clock clock.Clock
+	ttl   time.Duration
+	locks *keyLocks
+}
+
+func (ei EventIdempotentService) Add(ctx context.Context, key string, input model.EventCreate) (*model.Event, error) {
+	if key == "" {
+		return ei.crud.Add(ctx, input)
+	}
+	if len(key) > model.IdempotencyKeyMaxLen {
+		return nil, errx.LogicNew(model.ErrIdempotencyKeyLength, model.ErrIdempotencyKeyLengthCode)
+	}
+	user, err := authorizedUser(ctx, ei.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := requestHash(input)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	// одновременные повторы в рамках экземпляра сервиса ждут завершения первого запроса.
+	unlock := ei.locks.lock(user.ID.String() + ":" + key)
+	defer unlock()
+
+	now := ei.clock.Now()
+	stored, err := ei.keys.Get(ctx, user.ID, key, now)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if stored != nil {
+		return replay(*stored, hash)
+	}
+	reserved, err := ei.keys.Reserve(ctx, model.IdempotencyKey{
+		Key:         key,
+		UserID:      user.ID,
+		RequestHash: hash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ei.ttl),
+	})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if !reserved {
+		// ключ занят запросом, выполняющимся в другом экземпляре сервиса.
+		return ei.replayReserved(ctx, user.ID, key, hash)
+	}
+	event, err := ei.crud.Add(ctx, input)
+	if err != nil {
+		ei.fail(ctx, user.ID, key, err)
+		return nil, err
+	}
+	if err = ei.keys.Complete(ctx, user.ID, key, *event); err != nil {
+		// событие уже создано, повтор запроса получит ошибку ErrIdempotencyKeyInProgress.
+		logger.FromContext(ctx, ei.log).Error("ошибка сохранения результата по ключу идемпотентности: %s", err.Error())
+	}
+	return event, nil
+}
+
+// fail сохраняет ошибку клиента по ключу, и повтор получает ее без выполнения запроса.
+// После внутренней ошибки ключ освобождается: повтор выполнит запрос заново.
+func (ei EventIdempotentService) fail(ctx context.Context, userID uuid.UUID, key string, err error) {
+	if failure, ok := idempotencyFailure(err); ok {
+		saveErr := ei.keys.Fail(ctx, userID, key, failure)
+		if saveErr == nil {
+			return
+		}
+		logger.FromContext(ctx, ei.log).Error("ошибка сохранения ошибки по ключу идемпотентности: %s", saveErr.Error())
+	}
+	if delErr := ei.keys.Delete(ctx, userID, key); delErr != nil {
+		logger.FromContext(ctx, ei.log).Error("ошибка освобождения ключа идемпотентности: %s", delErr.Error())
+	}
+}
+
+func (ei EventIdempotentService) replayReserved(
+	ctx context.Context, userID uuid.UUID, key, hash string,
+) (*model.Event, error) {
+	stored, err := ei.keys.Get(ctx, userID, key, ei.clock.Now())
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if stored == nil {
+		return nil, errx.LogicNew(model.ErrIdempotencyKeyInProgress, model.ErrIdempotencyKeyInProgressCode)
+	}
+	return replay(*stored, hash)
+}
+
+// replay результат ранее выполненного запроса.
+func replay(stored model.IdempotencyKey, hash string) (*model.Event, error) {
+	if stored.RequestHash != hash {
+		return nil, errx.LogicNew(model.ErrIdempotencyKeyReused, model.ErrIdempotencyKeyReusedCode)
+	}
+	if stored.Failure != nil {
+		return nil, failureError(*stored.Failure)
+	}
+	if stored.Event == nil {
+		return nil, errx.LogicNew(model.ErrIdempotencyKeyInProgress, model.ErrIdempotencyKeyInProgressCode)
+	}
+	return stored.Event, nil
+}
+
+// idempotencyFailure ошибка клиента для сохранения по ключу, false - внутренняя ошибка.
+func idempotencyFailure(err error) (model.IdempotencyFailure, bool) {
+	var (
+		invalid  errx.Invalid
+		logic    errx.Logic
+		notFound errx.NotFound
+		base     errx.Base
+	)
+	switch {
+	case errors.As(err, &invalid):
+		failure := model.IdempotencyFailure{Kind: errx.TypeInvalid, Message: invalid.Title()}
+		for _, fieldErr := range invalid.Errors() {
+			failure.Fields = append(failure.Fields, model.IdempotencyFieldError{
+				Field:   fieldErr.Field,
+				Message: fieldErr.Err.Error(),
+			})
+		}
+		return failure, true
+	case errors.As(err, &logic):
+		return model.IdempotencyFailure{Kind: errx.TypeLogic, Code: logic.Code(), Message: logic.Error()}, true
+	case errors.As(err, &notFound):
+		return model.IdempotencyFailure{Kind: errx.TypeNotFound, Message: notFound.Error()}, true
+	case errors.As(err, &base) && base.Kind() == errx.TypePerms:
+		return model.IdempotencyFailure{Kind: errx.TypePerms, Message: base.Error()}, true
+	}
+	return model.IdempotencyFailure{}, false
+}
+
+// failureError ошибка errx, сохраненная idempotencyFailure.
+func failureError(failure model.IdempotencyFailure) error {
+	err := errors.New(failure.Message)
+	switch failure.Kind {
+	case errx.TypeInvalid:
+		var errs errx.NamedErrors
+		for _, fieldErr := range failure.Fields {
+			errs.Add(errx.NamedError{Field: fieldErr.Field, Err: errors.New(fieldErr.Message)})
+		}
+		return errx.InvalidNew(failure.Message, errs)
+	case errx.TypeLogic:
+		return errx.LogicNew(err, failure.Code)
+	case errx.TypeNotFound:
+		return errx.NotFoundNew(err, nil)
+	default:
+		return errx.PermsNew(err)
+	}
+}
+
+func requestHash(input model.EventCreate) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("error hashing request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// keyLocks мьютексы по ключу, освобожденный мьютекс удаляется.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (kl *keyLocks) lock(key string) func() {
+	kl.mu.Lock()
+	if kl.locks == nil {
+		kl.locks = make(map[string]*keyLock)
+	}
+	l, ok := kl.locks[key]
+	if !ok {
+		l = &keyLock{}
+		kl.locks[key] = l
+	}
+	l.refs++
+	kl.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		kl.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(kl.locks, key)
+		}
+		kl.mu.Unlock()
+	}
+}
+
+func NewEventIdempotentService(
+	crud EventCRUD,
+	keys repository.Idempotency,
+	user User,
+	log logger.Logger,
+	clock clock.Clock,
+	ttl time.Duration,
+) EventIdempotent {
+	if ttl <= 0 {
+		ttl = DefIdempotencyTTL
+	}
+	return &EventIdempotentService{
+		crud:  crud,
+		keys:  keys,
+		user:  user,
+		log:   log,
+		clock: clock,
+		ttl:   ttl,
+		locks: &keyLocks{},
+	}
+}
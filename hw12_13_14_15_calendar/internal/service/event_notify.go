@@ -29,12 +29,26 @@ func (en EventNotifyService) GetNotifications(ctx context.Context) ([]model.Noti
 	if err != nil {
 		return nil, errx.FatalNew(err)
 	}
+	return toNotifications(events), nil
+}
+
+func (en EventNotifyService) PendingNotifications(ctx context.Context) ([]model.Notification, error) {
+	now := en.clock.Now()
+	events, err := en.repo.GetList(ctx, model.EventSearch{NeedNotifyTerm: &now})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return toNotifications(events), nil
+}
+
+func toNotifications(events []model.Event) []model.Notification {
 	result := make([]model.Notification, len(events))
 	for i, event := range events {
 		user := model.NotifyUser{}
 		if event.Owner != nil {
 			user.Name = event.Owner.Name
 			user.Email = event.Owner.Email
+			user.Locale = event.Owner.Locale
 		}
 		result[i] = model.Notification{
 			EventID:       event.ID,
@@ -42,9 +56,10 @@ func (en EventNotifyService) GetNotifications(ctx context.Context) ([]model.Noti
 			EventDate:     event.Date,
 			EventDuration: event.Duration,
 			NotifyUser:    user,
+			EventLinks:    event.Links,
 		}
 	}
-	return result, nil
+	return result
 }
 
 func (en EventNotifyService) MarkEventNotified(ctx context.Context, eventID uuid.UUID) error {
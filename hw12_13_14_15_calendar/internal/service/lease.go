@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benbjohnson/clock"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type LeaseService struct {
+	repo  repository.Lease
+	log   logger.Logger
+	user  User
+	clock clock.Clock
+}
+
+func (ls LeaseService) Acquire(ctx context.Context, input model.LeaseAcquire) (*model.Lease, error) {
+	if err := input.Validate(); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			return nil, errx.InvalidNew("неверные параметры", errs)
+		}
+		return nil, err
+	}
+	user, err := authorizedUser(ctx, ls.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	now := ls.clock.Now()
+	lease, err := ls.repo.Acquire(ctx, model.Lease{
+		Name:      input.Name,
+		Holder:    input.Holder,
+		ExpiresAt: now.Add(input.TTL),
+		OwnerID:   user.ID,
+	}, now)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return lease, nil
+}
+
+func (ls LeaseService) Release(ctx context.Context, name, holder string) error {
+	user, err := authorizedUser(ctx, ls.user, nil)
+	if err != nil {
+		return err
+	}
+	if err = ls.repo.Release(ctx, name, holder, user.ID); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+func NewLeaseService(repo repository.Lease, log logger.Logger, user User, clock clock.Clock) Lease {
+	return &LeaseService{repo: repo, log: log, user: user, clock: clock}
+}
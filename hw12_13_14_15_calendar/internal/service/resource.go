@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type ResourceService struct {
+	repo   repository.Resource
+	events repository.Event
+	log    logger.Logger
+	user   User
+}
+
+// validateName название ресурса уникально среди всех ресурсов.
+func (rs ResourceService) validateName(ctx context.Context, name string, notID *uuid.UUID) error {
+	resources, err := rs.repo.GetList(ctx, model.ResourceSearch{Name: &name, NotID: notID})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if len(resources) > 0 {
+		return errx.LogicNew(model.ErrResourceNameBusy, model.ErrResourceNameBusyCode)
+	}
+	return nil
+}
+
+func (rs ResourceService) Add(ctx context.Context, input model.ResourceCreate) (*model.Resource, error) {
+	user, err := authorizedUser(ctx, rs.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	input.ManagerID = user.ID
+	if err = input.Validate(); err != nil {
+		return nil, invalidResource(err)
+	}
+	if err = rs.validateName(ctx, input.Name, nil); err != nil {
+		return nil, err
+	}
+	resource, err := rs.repo.Add(ctx, input)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return resource, nil
+}
+
+func (rs ResourceService) Update(ctx context.Context, resource model.Resource, input model.ResourceUpdate) error {
+	if _, err := authorizedUser(ctx, rs.user, &model.User{ID: resource.ManagerID}); err != nil {
+		return err
+	}
+	if err := input.Validate(); err != nil {
+		return invalidResource(err)
+	}
+	if input == (model.ResourceUpdate{}) {
+		return nil
+	}
+	if input.Name != nil {
+		if err := rs.validateName(ctx, *input.Name, &resource.ID); err != nil {
+			return err
+		}
+	}
+	if input.ManagerID != nil {
+		if _, err := rs.user.GetByID(ctx, *input.ManagerID); err != nil {
+			nfErr := errx.NotFound{}
+			if errors.As(err, &nfErr) {
+				return invalidResource(errx.NamedErrors{{Field: "ManagerID", Err: model.ErrResourceManager}})
+			}
+			return err
+		}
+	}
+	if _, err := rs.repo.Update(ctx, input, model.ResourceSearch{ID: &resource.ID}); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+// Delete бронь ресурса снимается со всех событий.
+func (rs ResourceService) Delete(ctx context.Context, resource model.Resource) error {
+	if _, err := authorizedUser(ctx, rs.user, &model.User{ID: resource.ManagerID}); err != nil {
+		return err
+	}
+	if _, err := rs.repo.Delete(ctx, model.ResourceSearch{ID: &resource.ID}); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+func (rs ResourceService) GetList(ctx context.Context, search model.ResourceSearch) ([]model.Resource, error) {
+	if _, err := authorizedUser(ctx, rs.user, nil); err != nil {
+		return nil, err
+	}
+	resources, err := rs.repo.GetList(ctx, search)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return resources, nil
+}
+
+func (rs ResourceService) GetByID(ctx context.Context, resourceID uuid.UUID) (*model.Resource, error) {
+	resources, err := rs.GetList(ctx, model.ResourceSearch{ID: &resourceID})
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, errx.NotFoundNew(model.ErrResourceNotFound, map[string]uuid.UUID{
+			"resourceId": resourceID,
+		})
+	}
+	return &resources[0], nil
+}
+
+// Bookings бронь упорядочена по дате начала; бронирующие события принадлежат разным
+// пользователям, поэтому их заголовки не раскрываются.
+func (rs ResourceService) Bookings(
+	ctx context.Context, resource model.Resource, dateRgn model.DateRange,
+) ([]model.ResourceBooking, error) {
+	if _, err := authorizedUser(ctx, rs.user, nil); err != nil {
+		return nil, err
+	}
+	events, err := rs.busyEvents(ctx, []uuid.UUID{resource.ID}, dateRgn)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+	names := make(map[uuid.UUID]string)
+	bookings := make([]model.ResourceBooking, len(events))
+	for i, event := range events {
+		bookings[i] = model.ResourceBooking{
+			ResourceID: resource.ID,
+			EventID:    event.ID,
+			DateRange:  model.DateRange{DateStart: event.Date, Duration: event.Duration},
+		}
+		if event.Owner != nil {
+			bookings[i].BookedBy = rs.ownerName(ctx, *event.Owner, names)
+		}
+	}
+	return bookings, nil
+}
+
+// ownerName хранилище в памяти заполняет у владельца события только ID, имя запрашивается отдельно.
+func (rs ResourceService) ownerName(ctx context.Context, owner model.User, names map[uuid.UUID]string) string {
+	if owner.Name != "" {
+		return owner.Name
+	}
+	if name, ok := names[owner.ID]; ok {
+		return name
+	}
+	if user, err := rs.user.GetByID(ctx, owner.ID); err == nil {
+		names[owner.ID] = user.Name
+	} else {
+		names[owner.ID] = ""
+	}
+	return names[owner.ID]
+}
+
+func (rs ResourceService) Available(
+	ctx context.Context, search model.ResourceSearch, dateRgn model.DateRange,
+) ([]model.Resource, error) {
+	resources, err := rs.GetList(ctx, search)
+	if err != nil || len(resources) == 0 {
+		return resources, err
+	}
+	ids := make([]uuid.UUID, len(resources))
+	for i, resource := range resources {
+		ids[i] = resource.ID
+	}
+	events, err := rs.busyEvents(ctx, ids, dateRgn)
+	if err != nil {
+		return nil, err
+	}
+	busy := make(map[uuid.UUID]struct{})
+	for _, event := range events {
+		for _, resource := range event.Resources {
+			busy[resource.ID] = struct{}{}
+		}
+	}
+	available := make([]model.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if _, ok := busy[resource.ID]; !ok {
+			available = append(available, resource)
+		}
+	}
+	return available, nil
+}
+
+// busyEvents события, бронирующие ресурсы в пересекающийся с периодом интервал.
+func (rs ResourceService) busyEvents(
+	ctx context.Context, resourceIDs []uuid.UUID, dateRgn model.DateRange,
+) ([]model.Event, error) {
+	if !dateRgn.Valid() || dateRgn.Duration < 0 {
+		return nil, errx.LogicNew(model.ErrCalendarDateRange, model.ErrCalendarDateRangeCode)
+	}
+	events, err := rs.events.GetList(ctx, model.EventSearch{
+		ResourceIDs: resourceIDs,
+		DateRange:   &dateRgn,
+		TacDuration: true,
+	})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return events, nil
+}
+
+func invalidResource(err error) error {
+	errs := errx.NamedErrors{}
+	if errors.As(err, &errs) {
+		return errx.InvalidNew("неверные параметры", errs)
+	}
+	return err
+}
+
+func NewResourceService(
+	repo repository.Resource, events repository.Event, log logger.Logger, user User,
+) Resource {
+	return &ResourceService{
+		repo:   repo,
+		events: events,
+		log:    log,
+		user:   user,
+	}
+}
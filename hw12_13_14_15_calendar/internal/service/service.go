@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,9 +15,68 @@ type EventCRUD interface {
 	Add(context.Context, model.EventCreate) (*model.Event, error)
 	Update(context.Context, model.Event, model.EventUpdate) error
 	Delete(context.Context, model.Event) error
-	GetUserEventsOn(context.Context, time.Time, model.RangeKind) ([]model.Event, error)
+	// GetUserEventsOn события текущего пользователя за период, при непустом списке меток -
+	// только отмеченные хотя бы одной из них.
+	GetUserEventsOn(context.Context, time.Time, model.RangeKind, []uuid.UUID) ([]model.Event, error)
 	GetEvents(context.Context, model.EventSearch) ([]model.Event, error)
 	GetByID(context.Context, uuid.UUID) (*model.Event, error)
+	// GetTrash события текущего пользователя, помещенные в корзину.
+	GetTrash(context.Context) ([]model.Event, error)
+	Restore(context.Context, uuid.UUID) error
+	// Search полнотекстовый поиск по событиям текущего пользователя.
+	Search(context.Context, string) ([]model.EventFound, error)
+	// AddBatch, UpdateBatch, DeleteBatch пакетные операции, ошибки проверки отдельных
+	// операций возвращаются в model.BatchReport, ошибка - только для пакета целиком.
+	AddBatch(context.Context, []model.EventCreate, model.BatchMode) (*model.BatchReport, error)
+	UpdateBatch(context.Context, []model.EventBatchUpdate, model.BatchMode) (*model.BatchReport, error)
+	DeleteBatch(context.Context, []uuid.UUID, model.BatchMode) (*model.BatchReport, error)
+}
+
+// TagCRUD сервис управления метками событий текущего пользователя.
+type TagCRUD interface {
+	Add(context.Context, model.TagCreate) (*model.Tag, error)
+	Update(context.Context, model.Tag, model.TagUpdate) error
+	Delete(context.Context, model.Tag) error
+	GetUserTags(context.Context) ([]model.Tag, error)
+	GetByID(context.Context, uuid.UUID) (*model.Tag, error)
+}
+
+// Resource общие для всех пользователей бронируемые ресурсы: переговорные, оборудование.
+type Resource interface {
+	// Add текущий пользователь становится ответственным за ресурс.
+	Add(context.Context, model.ResourceCreate) (*model.Resource, error)
+	// Update, Delete доступны только ответственному за ресурс.
+	Update(context.Context, model.Resource, model.ResourceUpdate) error
+	Delete(context.Context, model.Resource) error
+	GetList(context.Context, model.ResourceSearch) ([]model.Resource, error)
+	GetByID(context.Context, uuid.UUID) (*model.Resource, error)
+	// Bookings бронь ресурса событиями, пересекающимися с периодом.
+	Bookings(context.Context, model.Resource, model.DateRange) ([]model.ResourceBooking, error)
+	// Available подходящие под условия поиска ресурсы, свободные на всем протяжении периода.
+	Available(context.Context, model.ResourceSearch, model.DateRange) ([]model.Resource, error)
+}
+
+// Attachment вложения событий текущего пользователя.
+type Attachment interface {
+	// Upload проверяет размер и тип файла, сохраняет содержимое в хранилище файлов.
+	Upload(context.Context, model.Event, model.AttachmentUpload) (*model.Attachment, error)
+	GetList(context.Context, model.Event) ([]model.Attachment, error)
+	GetByID(context.Context, model.Event, uuid.UUID) (*model.Attachment, error)
+	// Open содержимое вложения, полученного GetByID; закрывает вызывающий.
+	Open(context.Context, model.Attachment) (io.ReadCloser, error)
+	Delete(context.Context, model.Event, model.Attachment) error
+}
+
+// Availability настройки рабочего времени и периоды отсутствия текущего пользователя.
+type Availability interface {
+	// Get для не задававшего настройки пользователя возвращаются настройки по умолчанию.
+	Get(context.Context) (*model.Availability, error)
+	Update(context.Context, model.Availability) error
+	GetOutOfOffice(context.Context) ([]model.OutOfOffice, error)
+	AddOutOfOffice(context.Context, model.OutOfOfficeCreate) (*model.OutOfOffice, error)
+	DeleteOutOfOffice(context.Context, uuid.UUID) error
+	// FreeSlots свободные интервалы рабочего времени за календарный день в часовом поясе пользователя.
+	FreeSlots(context.Context, time.Time) ([]model.DateRange, error)
 }
 
 // User работы с пользователями.
@@ -34,10 +94,46 @@ type User interface {
 // EventNotify сервис управления оповещениями.
 type EventNotify interface {
 	GetNotifications(context.Context) ([]model.Notification, error)
+	// PendingNotifications оповещения, которые будут отправлены, без изменения статуса событий.
+	PendingNotifications(context.Context) ([]model.Notification, error)
 	MarkEventNotified(context.Context, uuid.UUID) error
 }
 
 // EventClean удаление устаревших объектов календаря.
 type EventClean interface {
+	// CleanupOldEvents помещает в корзину события старше указанного срока.
 	CleanupOldEvents(context.Context, time.Duration) (int64, error)
+	// PurgeTrash окончательно удаляет события, находящиеся в корзине дольше указанного срока.
+	PurgeTrash(context.Context, time.Duration) (int64, error)
+	// PurgeIdempotencyKeys удаляет истекшие ключи идемпотентности.
+	PurgeIdempotencyKeys(context.Context) (int64, error)
+}
+
+// EventIdempotent создание событий с ключом идемпотентности: повтор запроса с тем же ключом
+// в течение срока хранения возвращает ранее созданное событие, а не создает новое.
+type EventIdempotent interface {
+	// Add при пустом ключе равносилен EventCRUD.Add.
+	Add(ctx context.Context, key string, input model.EventCreate) (*model.Event, error)
+}
+
+// Lease аренда роли ведущего для сервисов, запущенных в нескольких экземплярах.
+type Lease interface {
+	// Acquire захватывает или продлевает аренду от имени текущей учетной записи и возвращает
+	// действующую, владелец отличается от запрошенного, если аренду держит другой экземпляр.
+	Acquire(context.Context, model.LeaseAcquire) (*model.Lease, error)
+	// Release освобождает аренду до истечения срока, чужая аренда, в том числе захваченная
+	// другой учетной записью, не меняется.
+	Release(ctx context.Context, name, holder string) error
+}
+
+// Digest сводка событий: настройки текущего пользователя и формирование сводок к отправке.
+type Digest interface {
+	// Get для не задававшего настройки пользователя сводка отключена.
+	Get(context.Context) (*model.DigestSettings, error)
+	Update(context.Context, model.DigestSettings) error
+	// DueDigests сводки пользователей, время отправки которых наступило. Сводка возвращается,
+	// пока ее отправка не подтверждена через MarkSent.
+	DueDigests(context.Context) ([]model.Digest, error)
+	// MarkSent подтверждение отправки сводки пользователя, запланированной на sendAt.
+	MarkSent(ctx context.Context, userID uuid.UUID, sendAt time.Time) error
 }
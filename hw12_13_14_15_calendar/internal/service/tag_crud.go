@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+type TagCRUDService struct {
+	repo repository.Tag
+	log  logger.Logger
+	user User
+}
+
+// validateName название метки уникально в пределах владельца.
+func (ts TagCRUDService) validateName(ctx context.Context, ownerID uuid.UUID, name string, notID *uuid.UUID) error {
+	tags, err := ts.repo.GetList(ctx, model.TagSearch{
+		OwnerID: &ownerID,
+		Name:    &name,
+		NotID:   notID,
+	})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if len(tags) > 0 {
+		return errx.LogicNew(model.ErrTagNameBusy, model.ErrTagNameBusyCode)
+	}
+	return nil
+}
+
+func (ts TagCRUDService) Add(ctx context.Context, input model.TagCreate) (*model.Tag, error) {
+	user, err := authorizedUser(ctx, ts.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	input.OwnerID = user.ID
+	if err = input.Validate(); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			return nil, errx.InvalidNew("неверные параметры", errs)
+		}
+		return nil, err
+	}
+	if err = ts.validateName(ctx, user.ID, input.Name, nil); err != nil {
+		return nil, err
+	}
+	tag, err := ts.repo.Add(ctx, input)
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return tag, nil
+}
+
+func (ts TagCRUDService) Update(ctx context.Context, tag model.Tag, input model.TagUpdate) error {
+	if _, err := authorizedUser(ctx, ts.user, &model.User{ID: tag.OwnerID}); err != nil {
+		return err
+	}
+	if err := input.Validate(); err != nil {
+		errs := errx.NamedErrors{}
+		if errors.As(err, &errs) {
+			return errx.InvalidNew("неверные параметры", errs)
+		}
+		return err
+	}
+	if input.Name != nil {
+		if err := ts.validateName(ctx, tag.OwnerID, *input.Name, &tag.ID); err != nil {
+			return err
+		}
+	}
+	if _, err := ts.repo.Update(ctx, input, model.TagSearch{ID: &tag.ID}); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+// Delete метка снимается со всех событий.
+func (ts TagCRUDService) Delete(ctx context.Context, tag model.Tag) error {
+	if _, err := authorizedUser(ctx, ts.user, &model.User{ID: tag.OwnerID}); err != nil {
+		return err
+	}
+	if _, err := ts.repo.Delete(ctx, model.TagSearch{ID: &tag.ID}); err != nil {
+		return errx.FatalNew(err)
+	}
+	return nil
+}
+
+func (ts TagCRUDService) GetUserTags(ctx context.Context) ([]model.Tag, error) {
+	user, err := authorizedUser(ctx, ts.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := ts.repo.GetList(ctx, model.TagSearch{OwnerID: &user.ID})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	return tags, nil
+}
+
+// GetByID чужие метки не отдаются.
+func (ts TagCRUDService) GetByID(ctx context.Context, tagID uuid.UUID) (*model.Tag, error) {
+	user, err := authorizedUser(ctx, ts.user, nil)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := ts.repo.GetList(ctx, model.TagSearch{ID: &tagID, OwnerID: &user.ID})
+	if err != nil {
+		return nil, errx.FatalNew(err)
+	}
+	if len(tags) == 0 {
+		return nil, errx.NotFoundNew(model.ErrTagNotFound, map[string]uuid.UUID{
+			"tagId": tagID,
+		})
+	}
+	return &tags[0], nil
+}
+
+func NewTagCRUDService(repo repository.Tag, log logger.Logger, user User) TagCRUD {
+	return &TagCRUDService{
+		repo: repo,
+		log:  log,
+		user: user,
+	}
+}
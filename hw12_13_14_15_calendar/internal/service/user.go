@@ -12,8 +12,9 @@ import (
 )
 
 type UserService struct {
-	repo repository.User
-	log  logger.Logger
+	repo   repository.User
+	events repository.Event
+	log    logger.Logger
 }
 
 func (us UserService) validateAdd(ctx context.Context, input model.UserCreate) error {
@@ -66,8 +67,17 @@ func (us UserService) Update(ctx context.Context, user model.User, input model.U
 	return err
 }
 
+// Delete пользователь с событиями не удаляется, даже если все они в корзине: события удаляются
+// только очисткой корзины, в pgsql это же обеспечивает ON DELETE RESTRICT у events.owner_id.
 func (us UserService) Delete(ctx context.Context, user model.User) error {
-	_, err := us.repo.Delete(ctx, model.UserSearch{ID: &user.ID})
+	n, err := us.events.Count(ctx, model.EventSearch{OwnerID: &user.ID, Trash: model.TrashInclude})
+	if err != nil {
+		return errx.FatalNew(err)
+	}
+	if n > 0 {
+		return errx.LogicNew(model.ErrUserHasEvents, model.ErrUserHasEventsCode)
+	}
+	_, err = us.repo.Delete(ctx, model.UserSearch{ID: &user.ID})
 	return err
 }
 
@@ -110,9 +120,10 @@ func (us UserService) GetCurrent(ctx context.Context) (*model.User, error) {
 	return us.GetByID(ctx, userID)
 }
 
-func NewUserService(repo repository.User, logger logger.Logger) User {
+func NewUserService(repo repository.User, events repository.Event, logger logger.Logger) User {
 	return &UserService{
-		repo: repo,
-		log:  logger,
+		repo:   repo,
+		events: events,
+		log:    logger,
 	}
 }
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/model"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/internal/repository/memory"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+func TestUserDelete(t *testing.T) {
+	ctx := context.Background()
+	userRepo := memory.NewUserRepo()
+	eventRepo := memory.NewEventRepo(nil, nil)
+	users := NewUserService(userRepo, eventRepo, nil)
+
+	user, err := users.Add(ctx, model.UserCreate{Name: "Test", Email: "test@otus.ru"})
+	require.NoError(t, err)
+	event, err := eventRepo.Add(ctx, model.EventCreate{
+		Title:    "Планерка",
+		Date:     time.Now(),
+		Duration: time.Hour,
+		OwnerID:  user.ID,
+	})
+	require.NoError(t, err)
+	_, err = eventRepo.Trash(ctx, model.EventSearch{ID: &event.ID})
+	require.NoError(t, err)
+
+	// событие в корзине удаляется только ее очисткой, владелец остается.
+	err = users.Delete(ctx, *user)
+	var logic errx.Logic
+	require.True(t, errors.As(err, &logic), err)
+	require.Equal(t, model.ErrUserHasEventsCode, logic.Code())
+	_, err = users.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+
+	_, err = eventRepo.Delete(ctx, model.EventSearch{ID: &event.ID, Trash: model.TrashInclude})
+	require.NoError(t, err)
+	require.NoError(t, users.Delete(ctx, *user))
+	_, err = users.GetByID(ctx, user.ID)
+	require.Error(t, err)
+}
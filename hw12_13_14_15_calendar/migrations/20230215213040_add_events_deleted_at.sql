@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.events ADD COLUMN deleted_at timestamp with time zone;
+CREATE INDEX IF NOT EXISTS events_deleted_at_idx ON public.events (deleted_at);
+-- события удаляются только через корзину, пользователя с событиями удалить нельзя.
+ALTER TABLE IF EXISTS public.events DROP CONSTRAINT IF EXISTS owner_id_fkey;
+ALTER TABLE IF EXISTS public.events ADD CONSTRAINT owner_id_fkey FOREIGN KEY (owner_id)
+    REFERENCES public.users(id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.events DROP CONSTRAINT IF EXISTS owner_id_fkey;
+ALTER TABLE IF EXISTS public.events ADD CONSTRAINT owner_id_fkey FOREIGN KEY (owner_id)
+    REFERENCES public.users(id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE;
+DROP INDEX IF EXISTS public.events_deleted_at_idx;
+ALTER TABLE IF EXISTS public.events DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.tags (
+    id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    name character varying(50) NOT NULL,
+    color character(7) NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT owner_id_fkey FOREIGN KEY (owner_id)
+        REFERENCES public.users(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS tags_owner_name_key ON public.tags (owner_id, lower(name));
+CREATE TABLE public.event_tags (
+    event_id uuid NOT NULL,
+    tag_id uuid NOT NULL,
+    PRIMARY KEY (event_id, tag_id),
+    CONSTRAINT event_id_fkey FOREIGN KEY (event_id)
+        REFERENCES public.events(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE,
+    CONSTRAINT tag_id_fkey FOREIGN KEY (tag_id)
+        REFERENCES public.tags(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS event_tags_tag_id_idx ON public.event_tags (tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.event_tags;
+DROP TABLE IF EXISTS public.tags;
+-- +goose StatementEnd
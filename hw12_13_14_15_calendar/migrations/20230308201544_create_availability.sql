@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$ BEGIN
+    CREATE TYPE public.availability_policy as ENUM ('ignore', 'warn', 'reject');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+CREATE TABLE public.user_availability (
+    user_id uuid NOT NULL,
+    time_zone character varying(64) NOT NULL DEFAULT 'UTC',
+    policy public.availability_policy NOT NULL DEFAULT 'ignore'::availability_policy,
+    hours jsonb NOT NULL DEFAULT '[]'::jsonb,
+    PRIMARY KEY (user_id),
+    CONSTRAINT user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES public.users(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE TABLE public.out_of_office (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    date_from timestamp with time zone NOT NULL,
+    date_to timestamp with time zone NOT NULL,
+    reason character varying(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    CONSTRAINT user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES public.users(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS out_of_office_user_id_idx ON public.out_of_office (user_id, date_from);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.out_of_office;
+DROP TABLE IF EXISTS public.user_availability;
+DROP TYPE IF EXISTS public.availability_policy;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.idempotency_keys (
+    user_id uuid NOT NULL,
+    key character varying(255) NOT NULL,
+    request_hash character(64) NOT NULL,
+    response jsonb,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, key),
+    CONSTRAINT user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES public.users(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON public.idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.idempotency_keys;
+-- +goose StatementEnd
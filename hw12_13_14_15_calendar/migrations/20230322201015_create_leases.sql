@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.leases (
+    name character varying(255) NOT NULL,
+    holder character varying(255) NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.leases;
+-- +goose StatementEnd
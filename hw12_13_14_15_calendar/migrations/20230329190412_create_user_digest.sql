@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$ BEGIN
+    CREATE TYPE public.digest_mode as ENUM ('off', 'daily', 'weekly');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+CREATE TABLE public.user_digest (
+    user_id uuid NOT NULL,
+    mode public.digest_mode NOT NULL DEFAULT 'off'::digest_mode,
+    send_at integer NOT NULL DEFAULT 28800,
+    weekday smallint NOT NULL DEFAULT 1,
+    last_sent_at timestamp with time zone,
+    PRIMARY KEY (user_id),
+    CONSTRAINT user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES public.users(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS user_digest_mode_idx ON public.user_digest (mode) WHERE mode <> 'off';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.user_digest;
+DROP TYPE IF EXISTS public.digest_mode;
+-- +goose StatementEnd
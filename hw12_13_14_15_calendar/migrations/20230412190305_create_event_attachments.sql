@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.events ADD COLUMN IF NOT EXISTS links jsonb;
+CREATE TABLE public.event_attachments (
+    id uuid NOT NULL,
+    event_id uuid NOT NULL,
+    file_name character varying(255) NOT NULL,
+    content_type character varying(255) NOT NULL,
+    size bigint NOT NULL,
+    blob_key character varying(255) NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (id),
+    CONSTRAINT event_id_fkey FOREIGN KEY (event_id)
+        REFERENCES public.events(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS event_attachments_event_id_idx ON public.event_attachments (event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.event_attachments;
+ALTER TABLE public.events DROP COLUMN IF EXISTS links;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.resources (
+    id uuid NOT NULL,
+    kind character varying(20) NOT NULL,
+    name character varying(100) NOT NULL,
+    manager_id uuid NOT NULL,
+    capacity integer NOT NULL DEFAULT 0,
+    location character varying(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    CONSTRAINT resources_capacity_check CHECK (capacity >= 0),
+    CONSTRAINT manager_id_fkey FOREIGN KEY (manager_id)
+        REFERENCES public.users(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE RESTRICT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS resources_name_key ON public.resources (lower(name));
+CREATE TABLE public.event_resources (
+    event_id uuid NOT NULL,
+    resource_id uuid NOT NULL,
+    PRIMARY KEY (event_id, resource_id),
+    CONSTRAINT event_id_fkey FOREIGN KEY (event_id)
+        REFERENCES public.events(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE,
+    CONSTRAINT resource_id_fkey FOREIGN KEY (resource_id)
+        REFERENCES public.resources(id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS event_resources_resource_id_idx ON public.event_resources (resource_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.event_resources;
+DROP TABLE IF EXISTS public.resources;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.events ADD COLUMN IF NOT EXISTS ical_uid character varying(255);
+CREATE INDEX IF NOT EXISTS events_owner_ical_uid_idx ON public.events (owner_id, ical_uid)
+    WHERE ical_uid IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS public.events_owner_ical_uid_idx;
+ALTER TABLE public.events DROP COLUMN IF EXISTS ical_uid;
+-- +goose StatementEnd
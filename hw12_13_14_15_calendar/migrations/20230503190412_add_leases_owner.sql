@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- аренды живут не дольше TTL, выборы ведущего после обновления начинаются заново.
+DELETE FROM public.leases;
+ALTER TABLE public.leases ADD COLUMN IF NOT EXISTS owner_id uuid NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.leases DROP COLUMN IF EXISTS owner_id;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.idempotency_keys ADD COLUMN IF NOT EXISTS failure jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.idempotency_keys DROP COLUMN IF EXISTS failure;
+-- +goose StatementEnd
@@ -0,0 +1,7 @@
+// Package migrations миграции схемы PostgreSQL в формате goose, встроенные в исполняемые файлы.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedded(t *testing.T) {
+	onDisk, err := filepath.Glob("*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, onDisk)
+	embedded, err := fs.Glob(FS, "*.sql")
+	require.NoError(t, err)
+	require.Equal(t, onDisk, embedded)
+
+	for _, name := range embedded {
+		want, err := os.ReadFile(name)
+		require.NoError(t, err)
+		got, err := FS.ReadFile(name)
+		require.NoError(t, err)
+		require.Equal(t, want, got, name)
+	}
+}
@@ -0,0 +1,34 @@
+// Package blobstore хранилище содержимого файлов по ключу, метаданные хранятся отдельно.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store хранилище: локальный диск, S3 и т.п. Ключ - путь из сегментов через "/".
+type Store interface {
+	// Put сохраняет содержимое r целиком, при ошибке чтения r ничего не сохраняется.
+	// Возвращает количество сохраненных байт.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open содержимое по ключу, ErrNotFound - ключ не найден.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаление по ключу, отсутствие ключа ошибкой не считается.
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey ключ не пуст, относителен и не содержит переходов "..".
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	return path.Clean(key) == key && key != "." && !strings.HasPrefix(key, "../") && key != ".."
+}
@@ -0,0 +1,100 @@
+// Package local хранилище файлов в каталоге локального диска.
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore"
+)
+
+type Store struct {
+	root string
+}
+
+// NewStore хранилище в каталоге root, каталог создается при отсутствии.
+func NewStore(root string) (blobstore.Store, error) {
+	if root == "" {
+		return nil, errors.New("blob store directory is not set")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("can't create blob store directory: %w", err)
+	}
+	return &Store{root: root}, nil
+}
+
+// Put содержимое записывается во временный файл и переименовывается после успешной записи,
+// поэтому читатели не видят частично записанных файлов.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	fileName, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	dir := filepath.Dir(fileName)
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(tmp, contextReader{ctx: ctx, r: r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fileName)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *Store) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	fileName, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", blobstore.ErrNotFound, key)
+	}
+	return file, err
+}
+
+func (s *Store) Delete(_ context.Context, key string) error {
+	fileName, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(fileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Store) path(key string) (string, error) {
+	if !blobstore.ValidKey(key) {
+		return "", fmt.Errorf("%w: '%s'", blobstore.ErrInvalidKey, key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// contextReader прерывает запись при отмене контекста.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
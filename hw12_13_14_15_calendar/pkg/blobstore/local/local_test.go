@@ -0,0 +1,66 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore/local"
+)
+
+// failReader отдает часть данных и завершается ошибкой.
+type failReader struct {
+	data io.Reader
+}
+
+func (fr failReader) Read(p []byte) (int, error) {
+	n, err := fr.data.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := local.NewStore(root)
+	require.NoError(t, err)
+
+	n, err := store.Put(ctx, "event/file", strings.NewReader("content"))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), n)
+	r, err := store.Open(ctx, "event/file")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "content", string(data))
+
+	// прерванная запись не заменяет содержимое и не оставляет временных файлов.
+	_, err = store.Put(ctx, "event/file", failReader{data: strings.NewReader("partial")})
+	require.Error(t, err)
+	r, err = store.Open(ctx, "event/file")
+	require.NoError(t, err)
+	data, _ = io.ReadAll(r)
+	_ = r.Close()
+	require.Equal(t, "content", string(data))
+	entries, err := os.ReadDir(root + "/event")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete(ctx, "event/file"))
+	require.NoError(t, store.Delete(ctx, "event/file"))
+	_, err = store.Open(ctx, "event/file")
+	require.ErrorIs(t, err, blobstore.ErrNotFound)
+
+	for _, key := range []string{"", "../file", "event/../../file", "/etc/passwd", "event/"} {
+		_, err = store.Put(ctx, key, strings.NewReader("x"))
+		require.ErrorIs(t, err, blobstore.ErrInvalidKey, key)
+	}
+}
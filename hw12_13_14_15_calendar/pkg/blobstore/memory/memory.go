@@ -0,0 +1,55 @@
+// Package memory хранилище файлов в памяти для хранилища memory и тестов.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/blobstore"
+)
+
+type Store struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func NewStore() blobstore.Store {
+	return &Store{blobs: make(map[string][]byte)}
+}
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if !blobstore.ValidKey(key) {
+		return 0, fmt.Errorf("%w: '%s'", blobstore.ErrInvalidKey, key)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.blobs[key] = data
+	s.mu.Unlock()
+	return int64(len(data)), nil
+}
+
+func (s *Store) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	data, ok := s.blobs[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", blobstore.ErrNotFound, key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *Store) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.blobs, key)
+	s.mu.Unlock()
+	return nil
+}
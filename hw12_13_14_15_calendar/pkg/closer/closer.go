@@ -4,54 +4,220 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
 )
 
-var ErrShutdownCanceled = errors.New("shutdown cancelled")
+var (
+	ErrShutdownCanceled = errors.New("shutdown cancelled")
+	ErrCloseTimeout     = errors.New("close timeout exceeded")
+	ErrDependencyCycle  = errors.New("dependency cycle, closed in reverse registration order")
+)
 
 // CloseFunc функция для завершения сервиса.
 type CloseFunc func(ctx context.Context) error
 
+// Option настройка порядка и времени завершения при регистрации.
+type Option func(*entry)
+
+// DependsOn сервис использует сервисы names и завершается раньше них независимо от порядка регистрации.
+// Незарегистрированные имена не учитываются.
+func DependsOn(names ...string) Option {
+	return func(e *entry) {
+		e.dependsOn = append(e.dependsOn, names...)
+	}
+}
+
+// Group сервисы одной группы завершаются параллельно, когда завершены все зависящие от них.
+func Group(name string) Option {
+	return func(e *entry) {
+		e.group = name
+	}
+}
+
+// Timeout предельное время завершения сервиса, но не дольше оставшегося времени общего контекста.
+func Timeout(timeout time.Duration) Option {
+	return func(e *entry) {
+		e.timeout = timeout
+	}
+}
+
+type entry struct {
+	name      string
+	closeFunc CloseFunc
+	dependsOn []string
+	group     string
+	timeout   time.Duration
+}
+
+// Closer завершение сервисов в порядке, обратном регистрации (LIFO), с учетом DependsOn и Group.
 type Closer struct {
-	mu        sync.Mutex
-	closeFunc map[string]CloseFunc
+	mu      sync.Mutex
+	entries []*entry
 }
 
 func NewCloser() *Closer {
-	return &Closer{closeFunc: make(map[string]CloseFunc)}
+	return &Closer{}
 }
 
-func (c *Closer) Register(name string, closeFunc CloseFunc) {
+// Register регистрация функции завершения. Повторная регистрация имени заменяет функцию и настройки,
+// сохраняя место в порядке завершения.
+func (c *Closer) Register(name string, closeFunc CloseFunc, opts ...Option) {
 	if closeFunc == nil {
 		return
 	}
+	e := &entry{name: name, closeFunc: closeFunc}
+	for _, opt := range opts {
+		opt(e)
+	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.closeFunc[name] = closeFunc
+	for i, registered := range c.entries {
+		if registered.name == name {
+			c.entries[i] = e
+			return
+		}
+	}
+	c.entries = append(c.entries, e)
 }
 
-func (c *Closer) Close(ctx context.Context, logger logger.Logger) {
-	complete := make(chan struct{}, 1)
+// Close завершение всех сервисов по этапам plan. Сервис, не завершившийся за свое время, пропускается
+// с ErrCloseTimeout; по истечении ctx оставшиеся не завершаются и отмечаются ErrShutdownCanceled.
+// Ошибки возвращаются одним списком errx.NamedErrors по именам сервисов.
+func (c *Closer) Close(ctx context.Context, logger logger.Logger) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	go func() {
-		defer close(complete)
-		for name, closer := range c.closeFunc {
-			logger.Info("%s: closing", name)
-			if err := closer(ctx); err != nil {
-				logger.Error("error closing %s: %s", name, err.Error())
+	stages, err := plan(c.entries)
+	var errs errx.NamedErrors
+	if err != nil {
+		logger.Error("closer: %s", err.Error())
+	}
+	for i, stage := range stages {
+		if ctx.Err() != nil {
+			for _, rest := range stages[i:] {
+				for _, e := range rest {
+					errs.Add(errx.NamedError{Field: e.name, Err: ErrShutdownCanceled})
+				}
 			}
-			logger.Info("%s: closed successfully", name)
+			logger.Error("closer exited by timeout")
+			break
 		}
-	}()
+		for _, stageErr := range closeStage(ctx, stage, logger) {
+			errs.Add(stageErr)
+		}
+	}
+	c.entries = nil
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
 
+// closeStage параллельное завершение сервисов этапа.
+func closeStage(ctx context.Context, stage []*entry, logger logger.Logger) errx.NamedErrors {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs errx.NamedErrors
+	)
+	for _, e := range stage {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			logger.Info("%s: closing", e.name)
+			if err := closeOne(ctx, e); err != nil {
+				logger.Error("error closing %s: %s", e.name, err.Error())
+				mu.Lock()
+				errs.Add(errx.NamedError{Field: e.name, Err: err})
+				mu.Unlock()
+				return
+			}
+			logger.Info("%s: closed successfully", e.name)
+		}(e)
+	}
+	wg.Wait()
+	return errs
+}
+
+// closeOne вызов функции завершения с ожиданием не дольше таймаута сервиса: зависшая функция
+// продолжает выполняться в фоне, но не задерживает завершение остальных.
+func closeOne(ctx context.Context, e *entry) error {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- e.closeFunc(ctx)
+	}()
 	select {
-	case <-complete:
-		break
+	case err := <-done:
+		return err
 	case <-ctx.Done():
-		close(complete)
-		logger.Error("closer exited by timeout")
+		return ErrCloseTimeout
+	}
+}
+
+// plan этапы завершения: на каждом этапе - последний зарегистрированный из сервисов, от которых
+// больше никто не зависит, вместе с готовыми сервисами его группы. При цикле зависимостей
+// оставшиеся сервисы завершаются по одному в обратном порядке регистрации.
+func plan(entries []*entry) ([][]*entry, error) {
+	index := make(map[string]int, len(entries))
+	for i, e := range entries {
+		index[e.name] = i
+	}
+	// dependents[i] - сколько незавершенных сервисов используют i.
+	dependents := make([]int, len(entries))
+	for _, e := range entries {
+		for _, name := range e.dependsOn {
+			if j, ok := index[name]; ok {
+				dependents[j]++
+			}
+		}
+	}
+	closed := make([]bool, len(entries))
+	stages := make([][]*entry, 0, len(entries))
+	markClosed := func(stage []*entry) {
+		for _, e := range stage {
+			closed[index[e.name]] = true
+			for _, name := range e.dependsOn {
+				if j, ok := index[name]; ok {
+					dependents[j]--
+				}
+			}
+		}
+	}
+	for remaining := len(entries); remaining > 0; {
+		next := -1
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !closed[i] && dependents[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			for i := len(entries) - 1; i >= 0; i-- {
+				if !closed[i] {
+					stages = append(stages, []*entry{entries[i]})
+				}
+			}
+			return stages, ErrDependencyCycle
+		}
+		stage := []*entry{entries[next]}
+		if group := entries[next].group; group != "" {
+			for i := next - 1; i >= 0; i-- {
+				if !closed[i] && dependents[i] == 0 && entries[i].group == group {
+					stage = append(stage, entries[i])
+				}
+			}
+		}
+		markClosed(stage)
+		stages = append(stages, stage)
+		remaining -= len(stage)
 	}
+	return stages, nil
 }
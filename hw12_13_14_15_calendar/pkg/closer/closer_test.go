@@ -0,0 +1,140 @@
+package closer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+func newLogger(t *testing.T) logger.Logger {
+	t.Helper()
+	logs, err := logger.NewLogrus(logger.Config{Level: logger.LevelError})
+	require.NoError(t, err)
+	return logs
+}
+
+// recorder записывает порядок завершения сервисов.
+type recorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *recorder) closeFunc(name string, err error) CloseFunc {
+	return func(context.Context) error {
+		r.mu.Lock()
+		r.order = append(r.order, name)
+		r.mu.Unlock()
+		return err
+	}
+}
+
+func TestCloseOrder(t *testing.T) {
+	t.Run("lifo", func(t *testing.T) {
+		var rec recorder
+		c := NewCloser()
+		c.Register("DB", rec.closeFunc("DB", nil))
+		c.Register("Queue", rec.closeFunc("Queue", nil))
+		c.Register("Server", rec.closeFunc("Server", nil))
+		// повторная регистрация сохраняет место.
+		c.Register("Queue", rec.closeFunc("Queue v2", nil))
+		require.NoError(t, c.Close(context.Background(), newLogger(t)))
+		require.Equal(t, []string{"Server", "Queue v2", "DB"}, rec.order)
+
+		// повторный вызов ничего не закрывает.
+		require.NoError(t, c.Close(context.Background(), newLogger(t)))
+		require.Len(t, rec.order, 3)
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		var rec recorder
+		c := NewCloser()
+		c.Register("Server", rec.closeFunc("Server", nil), DependsOn("DB", "Unknown"))
+		c.Register("DB", rec.closeFunc("DB", nil))
+		c.Register("Cache", rec.closeFunc("Cache", nil))
+		require.NoError(t, c.Close(context.Background(), newLogger(t)))
+		require.Equal(t, []string{"Cache", "Server", "DB"}, rec.order)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		var rec recorder
+		c := NewCloser()
+		c.Register("A", rec.closeFunc("A", nil), DependsOn("B"))
+		c.Register("B", rec.closeFunc("B", nil), DependsOn("A"))
+		c.Register("C", rec.closeFunc("C", nil))
+		require.NoError(t, c.Close(context.Background(), newLogger(t)))
+		require.Equal(t, []string{"C", "B", "A"}, rec.order)
+	})
+}
+
+func TestCloseGroup(t *testing.T) {
+	// члены группы завершаются одновременно: каждый ждет начала завершения другого.
+	started := map[string]chan struct{}{"REST": make(chan struct{}), "GRPC": make(chan struct{})}
+	waitOther := func(self, other string) CloseFunc {
+		return func(ctx context.Context) error {
+			close(started[self])
+			select {
+			case <-started[other]:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	var rec recorder
+	c := NewCloser()
+	c.Register("DB", rec.closeFunc("DB", nil))
+	c.Register("REST", waitOther("REST", "GRPC"), Group("servers"), DependsOn("DB"))
+	c.Register("GRPC", waitOther("GRPC", "REST"), Group("servers"), DependsOn("DB"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, c.Close(ctx, newLogger(t)))
+	require.Equal(t, []string{"DB"}, rec.order)
+}
+
+func TestCloseErrors(t *testing.T) {
+	errFailed := errors.New("failed")
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	t.Run("aggregated", func(t *testing.T) {
+		var rec recorder
+		c := NewCloser()
+		c.Register("DB", rec.closeFunc("DB", errFailed))
+		c.Register("Hanging", hang, Timeout(10*time.Millisecond))
+		c.Register("Server", rec.closeFunc("Server", nil))
+
+		err := c.Close(context.Background(), newLogger(t))
+		var errs errx.NamedErrors
+		require.True(t, errors.As(err, &errs))
+		require.Equal(t, errx.NamedErrors{
+			{Field: "Hanging", Err: ErrCloseTimeout},
+			{Field: "DB", Err: errFailed},
+		}, errs)
+		// зависший сервис не задерживает завершение остальных.
+		require.Equal(t, []string{"Server", "DB"}, rec.order)
+	})
+
+	t.Run("overall timeout", func(t *testing.T) {
+		var rec recorder
+		c := NewCloser()
+		c.Register("DB", rec.closeFunc("DB", nil))
+		c.Register("Hanging", hang)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := c.Close(ctx, newLogger(t))
+		require.ErrorIs(t, err, ErrCloseTimeout)
+		require.ErrorIs(t, err, ErrShutdownCanceled)
+		require.Empty(t, rec.order)
+	})
+}
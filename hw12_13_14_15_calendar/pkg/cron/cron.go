@@ -0,0 +1,336 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+)
+
+var (
+	ErrJobName       = errors.New("empty job name")
+	ErrJobExists     = errors.New("job already exists")
+	ErrJobNotFound   = errors.New("job not found")
+	ErrJobSchedule   = errors.New("empty job schedule")
+	ErrJobFunc       = errors.New("empty job function")
+	ErrUnknownPolicy = errors.New("unknown overlap policy, expected skip or wait")
+)
+
+// Policy поведение при наступлении запуска, пока предыдущий еще выполняется.
+type Policy int
+
+const (
+	// PolicySkip запуск пропускается.
+	PolicySkip Policy = iota
+	// PolicyWait запуск выполняется сразу после завершения предыдущего,
+	// несколько отложенных запусков объединяются в один.
+	PolicyWait
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch s {
+	case "", "skip":
+		return PolicySkip, nil
+	case "wait":
+		return PolicyWait, nil
+	}
+	return PolicySkip, fmt.Errorf("'%s': %w", s, ErrUnknownPolicy)
+}
+
+// Job описание задачи.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(context.Context)
+	// Timeout предельное время выполнения, по истечении отменяется контекст Run, 0 - без ограничения.
+	Timeout time.Duration
+	// Jitter случайная задержка запуска в пределах значения, разносит запуски экземпляров.
+	Jitter time.Duration
+	Policy Policy
+	// CatchUp выполнить запуск, пропущенный пока сервис был остановлен, сразу при старте.
+	// Несколько пропущенных запусков объединяются в один.
+	CatchUp bool
+}
+
+type job struct {
+	spec    Job
+	state   JobState
+	pending bool
+	// changed новое расписание для запущенного цикла.
+	changed chan struct{}
+}
+
+// Scheduler запуск задач по расписанию: каждая задача в своем цикле, задачи не пересекаются
+// сами с собой, время последнего запуска сохраняется в Store.
+type Scheduler struct {
+	store  Store
+	logger logger.Logger
+	guard  func() bool
+
+	mu   sync.Mutex
+	jobs []*job
+	wg   sync.WaitGroup
+	// saveMu упорядочивает запись состояния.
+	saveMu sync.Mutex
+}
+
+// New планировщик, store == nil - состояние не сохраняется.
+func New(store Store, logger logger.Logger) *Scheduler {
+	return &Scheduler{store: store, logger: logger}
+}
+
+// SetGuard условие выполнения запусков, например роль ведущего экземпляра. Запуск при false
+// пропускается и не считается выполненным.
+func (s *Scheduler) SetGuard(guard func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guard = guard
+}
+
+// Add регистрация задачи до запуска планировщика.
+func (s *Scheduler) Add(spec Job) error {
+	if err := validate(spec); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.find(spec.Name) != nil {
+		return fmt.Errorf("'%s': %w", spec.Name, ErrJobExists)
+	}
+	s.jobs = append(s.jobs, &job{
+		spec:    spec,
+		state:   JobState{Name: spec.Name, Schedule: spec.Schedule.String()},
+		changed: make(chan struct{}, 1),
+	})
+	return nil
+}
+
+// Update замена расписания и параметров запуска задачи, новое расписание отсчитывается
+// от момента замены. При пустой spec.Run функция задачи не меняется.
+func (s *Scheduler) Update(spec Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j := s.find(spec.Name)
+	if j == nil {
+		return fmt.Errorf("'%s': %w", spec.Name, ErrJobNotFound)
+	}
+	if spec.Run == nil {
+		spec.Run = j.spec.Run
+	}
+	if err := validate(spec); err != nil {
+		return err
+	}
+	if spec.Schedule.String() == j.spec.Schedule.String() {
+		j.spec = spec
+		return nil
+	}
+	j.spec = spec
+	j.state.Schedule = spec.Schedule.String()
+	select {
+	case j.changed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Status состояние задач в порядке регистрации.
+func (s *Scheduler) Status() []JobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states := make([]JobState, len(s.jobs))
+	for i, j := range s.jobs {
+		states[i] = j.state
+	}
+	return states
+}
+
+// Start запуск задач до завершения ctx. Время последнего запуска берется из Store.
+func (s *Scheduler) Start(ctx context.Context) error {
+	var saved []JobState
+	if s.store != nil {
+		var err error
+		if saved, err = s.store.Load(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		i := sort.Search(len(saved), func(i int) bool { return saved[i].Name >= j.spec.Name })
+		if i < len(saved) && saved[i].Name == j.spec.Name {
+			j.state.LastRun = saved[i].LastRun
+			j.state.LastDuration = saved[i].LastDuration
+			j.state.Runs = saved[i].Runs
+			j.state.Skipped = saved[i].Skipped
+		}
+		s.wg.Add(1)
+		go s.loop(ctx, j, j.first(time.Now()))
+	}
+	return nil
+}
+
+// Stop ожидание завершения выполняемых задач после отмены контекста Start.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// first время первого запуска: следующее после последнего сохраненного или текущего момента.
+// Пропущенный за время остановки запуск выполняется сразу, если задан CatchUp.
+func (j *job) first(now time.Time) time.Time {
+	if j.state.LastRun.IsZero() {
+		return j.spec.Schedule.Next(now)
+	}
+	next := j.spec.Schedule.Next(j.state.LastRun)
+	if !next.Before(now) {
+		return next
+	}
+	if j.spec.CatchUp {
+		return now
+	}
+	return j.spec.Schedule.Next(now)
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job, next time.Time) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		j.state.NextRun = next
+		spec := j.spec
+		s.mu.Unlock()
+		s.save()
+		if next.IsZero() {
+			s.logger.Warn("job %s: no more runs for schedule '%s'", spec.Name, spec.Schedule.String())
+			return
+		}
+		timer := time.NewTimer(time.Until(next) + jitter(spec.Jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-j.changed:
+			timer.Stop()
+			s.mu.Lock()
+			next = j.spec.Schedule.Next(time.Now())
+			s.mu.Unlock()
+			continue
+		case <-timer.C:
+		}
+		s.trigger(ctx, j)
+		// следующий запуск отсчитывается от запланированного, а не фактического времени;
+		// запуски, пропущенные из-за долгой задержки, не выполняются.
+		now := time.Now()
+		if next = spec.Schedule.Next(next); next.Before(now) {
+			next = spec.Schedule.Next(now)
+		}
+	}
+}
+
+func (s *Scheduler) trigger(ctx context.Context, j *job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.guard != nil && !s.guard() {
+		s.logger.Debug("job %s: run skipped by guard", j.spec.Name)
+		return
+	}
+	if j.state.Running {
+		if j.spec.Policy == PolicyWait {
+			j.pending = true
+			s.logger.Info("job %s: previous run is in progress, run postponed", j.spec.Name)
+			return
+		}
+		j.state.Skipped++
+		s.logger.Warn("job %s: previous run is in progress, run skipped", j.spec.Name)
+		return
+	}
+	j.state.Running = true
+	s.wg.Add(1)
+	go s.run(ctx, j)
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		spec := j.spec
+		s.mu.Unlock()
+
+		started := time.Now()
+		runCtx, cancel := ctx, context.CancelFunc(func() {})
+		if spec.Timeout > 0 {
+			runCtx, cancel = context.WithTimeout(ctx, spec.Timeout)
+		}
+		spec.Run(runCtx)
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			s.logger.Warn("job %s: timeout %s exceeded", spec.Name, spec.Timeout)
+		}
+		cancel()
+
+		s.mu.Lock()
+		j.state.LastRun = started
+		j.state.LastDuration = time.Since(started)
+		j.state.Runs++
+		again := j.pending && ctx.Err() == nil
+		j.pending = false
+		j.state.Running = again
+		s.mu.Unlock()
+		s.save()
+		if !again {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) save() {
+	if s.store == nil {
+		return
+	}
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	if err := s.store.Save(s.Status()); err != nil {
+		s.logger.Error("error saving job state: %s", err.Error())
+	}
+}
+
+// find поиск задачи, вызывается под блокировкой.
+func (s *Scheduler) find(name string) *job {
+	for _, j := range s.jobs {
+		if j.spec.Name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+func validate(spec Job) error {
+	switch {
+	case spec.Name == "":
+		return ErrJobName
+	case spec.Schedule == nil:
+		return fmt.Errorf("'%s': %w", spec.Name, ErrJobSchedule)
+	case spec.Run == nil:
+		return fmt.Errorf("'%s': %w", spec.Name, ErrJobFunc)
+	}
+	return nil
+}
+
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(limit))) //nolint:gosec // задержка не требует криптостойкости.
+}
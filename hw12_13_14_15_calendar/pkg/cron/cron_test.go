@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+)
+
+func newLogger(t *testing.T) logger.Logger {
+	t.Helper()
+	logs, err := logger.NewLogrus(logger.Config{Level: logger.LevelInfo})
+	require.NoError(t, err)
+	return logs
+}
+
+// counter задача, считающая запуски, каждый запуск длится duration или до отмены контекста.
+type counter struct {
+	runs      int32
+	cancelled int32
+	duration  time.Duration
+}
+
+func (c *counter) run(ctx context.Context) {
+	atomic.AddInt32(&c.runs, 1)
+	select {
+	case <-time.After(c.duration):
+	case <-ctx.Done():
+		atomic.AddInt32(&c.cancelled, 1)
+	}
+}
+
+func (c *counter) count() int32 {
+	return atomic.LoadInt32(&c.runs)
+}
+
+func TestSchedulerPolicies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	skipped := &counter{duration: 120 * time.Millisecond}
+	waited := &counter{duration: 120 * time.Millisecond}
+	timed := &counter{duration: time.Hour}
+	s := New(nil, newLogger(t))
+	require.NoError(t, s.Add(Job{Name: "skip", Schedule: Every(50 * time.Millisecond), Run: skipped.run}))
+	require.NoError(t, s.Add(Job{
+		Name: "wait", Schedule: Every(50 * time.Millisecond), Run: waited.run, Policy: PolicyWait,
+	}))
+	require.NoError(t, s.Add(Job{
+		Name: "timeout", Schedule: Every(50 * time.Millisecond), Run: timed.run, Timeout: 20 * time.Millisecond,
+	}))
+	require.ErrorIs(t, s.Add(Job{Name: "skip", Schedule: Every(time.Second), Run: skipped.run}), ErrJobExists)
+	require.NoError(t, s.Start(ctx))
+
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	require.NoError(t, s.Stop(context.Background()))
+
+	states := s.Status()
+	require.Len(t, states, 3)
+	// запуски не пересекаются: пропущенные запуски учтены в Skipped.
+	require.Equal(t, int64(skipped.count()), states[0].Runs)
+	require.Positive(t, states[0].Skipped)
+	// отложенные запуски выполняются подряд, без пропусков.
+	require.Equal(t, int64(waited.count()), states[1].Runs)
+	require.Zero(t, states[1].Skipped)
+	require.GreaterOrEqual(t, waited.count(), int32(3))
+	// каждый запуск прерван по таймауту.
+	require.GreaterOrEqual(t, timed.count(), int32(5))
+	require.Equal(t, atomic.LoadInt32(&timed.cancelled), timed.count())
+	for _, state := range states {
+		require.False(t, state.Running)
+		require.False(t, state.LastRun.IsZero())
+	}
+}
+
+func TestSchedulerCatchUp(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	// задача выполнялась два часа назад, часовой запуск пропущен.
+	lastRun := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	require.NoError(t, store.Save([]JobState{
+		{Name: "catch-up", LastRun: lastRun, Runs: 7},
+		{Name: "no-catch-up", LastRun: lastRun},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	caught, missed := &counter{}, &counter{}
+	s := New(store, newLogger(t))
+	require.NoError(t, s.Add(Job{Name: "catch-up", Schedule: Every(time.Hour), Run: caught.run, CatchUp: true}))
+	require.NoError(t, s.Add(Job{Name: "no-catch-up", Schedule: Every(time.Hour), Run: missed.run}))
+	require.NoError(t, s.Start(ctx))
+
+	require.Eventually(t, func() bool {
+		return caught.count() == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return s.Status()[0].Runs == 8
+	}, time.Second, 10*time.Millisecond)
+	require.Zero(t, missed.count())
+	cancel()
+	require.NoError(t, s.Stop(context.Background()))
+
+	// состояние сохранено: время запуска и следующий запуск по расписанию.
+	states, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	require.True(t, states[0].LastRun.After(lastRun))
+	require.Equal(t, "@every 1h0m0s", states[0].Schedule)
+	require.WithinDuration(t, states[0].LastRun.Add(time.Hour), states[0].NextRun, time.Second)
+	require.WithinDuration(t, time.Now().Add(time.Hour), states[1].NextRun, time.Second)
+
+	var out strings.Builder
+	require.NoError(t, WriteStatus(&out, states))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasPrefix(lines[1], "catch-up"))
+	require.Contains(t, lines[1], " 8 ")
+	require.True(t, strings.HasPrefix(lines[2], "no-catch-up"))
+}
+
+func TestSchedulerGuardAndUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var allowed int32
+	job := &counter{}
+	s := New(nil, newLogger(t))
+	s.SetGuard(func() bool {
+		return atomic.LoadInt32(&allowed) == 1
+	})
+	require.NoError(t, s.Add(Job{Name: "job", Schedule: Every(time.Hour), Run: job.run}))
+	require.ErrorIs(t, s.Update(Job{Name: "unknown", Schedule: Every(time.Hour)}), ErrJobNotFound)
+	require.NoError(t, s.Start(ctx))
+
+	// новое расписание действует сразу, функция задачи сохраняется.
+	require.NoError(t, s.Update(Job{Name: "job", Schedule: Every(10 * time.Millisecond)}))
+	time.Sleep(100 * time.Millisecond)
+	require.Zero(t, job.count())
+	require.True(t, s.Status()[0].LastRun.IsZero())
+
+	atomic.StoreInt32(&allowed, 1)
+	require.Eventually(t, func() bool {
+		return job.count() >= 3
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "@every 10ms", s.Status()[0].Schedule)
+	cancel()
+	require.NoError(t, s.Stop(context.Background()))
+}
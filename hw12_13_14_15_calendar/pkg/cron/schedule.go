@@ -0,0 +1,223 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrScheduleFields = errors.New("cron expression must have 5 fields: minute hour day-of-month month day-of-week")
+	ErrScheduleValue  = errors.New("wrong cron field value")
+	ErrEveryPeriod    = errors.New("period must be positive")
+)
+
+// Schedule расписание запусков задачи.
+type Schedule interface {
+	// Next время первого запуска строго после t, нулевое время - запусков больше не будет.
+	Next(t time.Time) time.Time
+	String() string
+}
+
+// Every запуск с постоянным периодом, отсчитываемым от предыдущего запуска.
+type Every time.Duration
+
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+func (e Every) String() string {
+	return "@every " + time.Duration(e).String()
+}
+
+// field допустимый диапазон поля cron-выражения и имена значений.
+type field struct {
+	name      string
+	low, high int
+	names     map[string]int
+}
+
+var fields = []field{
+	{name: "minute", low: 0, high: 59},
+	{name: "hour", low: 0, high: 23},
+	{name: "day-of-month", low: 1, high: 31},
+	{name: "month", low: 1, high: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// 7 - тоже воскресенье.
+	{name: "day-of-week", low: 0, high: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule расписание по cron-выражению с точностью до минуты, значения полей - битовые маски.
+type cronSchedule struct {
+	spec                          string
+	minute, hour, dom, month, dow uint64
+	// domAny, dowAny поле начинается с "*": если ограничены оба поля дня,
+	// подходит день, совпадающий с любым из них.
+	domAny, dowAny bool
+	loc            *time.Location
+}
+
+// Parse разбор расписания: cron-выражение из 5 полей, макросы @daily, @hourly и т.п.
+// или @every <период> в формате time.ParseDuration. Время cron-выражения - в часовом поясе loc.
+func Parse(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		period, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", spec, err)
+		}
+		if period <= 0 {
+			return nil, fmt.Errorf("'%s': %w", spec, ErrEveryPeriod)
+		}
+		return Every(period), nil
+	}
+	expr := spec
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		expr = macro
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("'%s': %w", spec, ErrScheduleFields)
+	}
+	masks := make([]uint64, len(fields))
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", spec, err)
+		}
+		masks[i] = mask
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	dow := masks[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+	return &cronSchedule{
+		spec:   spec,
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    dow,
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+		loc:    loc,
+	}, nil
+}
+
+// parseField разбор поля: список через запятую из *, значений, диапазонов a-b и шагов */n, a-b/n.
+func parseField(part string, f field) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(part, ",") {
+		rng, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s '%s': %w", f.name, item, ErrScheduleValue)
+			}
+			rng, step = item[:i], n
+		}
+		from, to := f.low, f.high
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if from, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// a/n - от a до конца диапазона.
+				to = f.high
+			}
+		}
+		if from > to {
+			return 0, fmt.Errorf("%s '%s': %w", f.name, item, ErrScheduleValue)
+		}
+		for v := from; v <= to; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.low || v > f.high {
+		return 0, fmt.Errorf("%s '%s': %w", f.name, s, ErrScheduleValue)
+	}
+	return v, nil
+}
+
+func (cs *cronSchedule) String() string {
+	return cs.spec
+}
+
+// Next подбирает время последовательно по месяцу, дню, часу и минуте: при переходе
+// на следующее значение поля младшие поля сбрасываются. Поиск ограничен пятью годами,
+// этого достаточно для любого выражения, включая 29 февраля.
+func (cs *cronSchedule) Next(t time.Time) time.Time {
+	origLoc := t.Location()
+	t = t.In(cs.loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, cs.loc).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if cs.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, cs.loc)
+			continue
+		}
+		if !cs.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, cs.loc)
+			continue
+		}
+		if cs.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, cs.loc)
+			if !next.After(t) {
+				// переход на зимнее время: час повторяется, берем следующий.
+				next = t.Add(time.Hour).Truncate(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if cs.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t.In(origLoc)
+	}
+	return time.Time{}
+}
+
+func (cs *cronSchedule) dayMatches(t time.Time) bool {
+	dom := cs.dom&(1<<uint(t.Day())) != 0
+	dow := cs.dow&(1<<uint(t.Weekday())) != 0
+	if cs.domAny || cs.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,115 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2023, 3, 22, 14, 35, 20, 0, moscow) // среда
+	testCases := []struct {
+		spec     string
+		expected []time.Time
+	}{
+		{
+			spec: "0 3 * * *",
+			expected: []time.Time{
+				time.Date(2023, 3, 23, 3, 0, 0, 0, moscow),
+				time.Date(2023, 3, 24, 3, 0, 0, 0, moscow),
+			},
+		},
+		{
+			spec: "*/20 14-15 * * *",
+			expected: []time.Time{
+				time.Date(2023, 3, 22, 14, 40, 0, 0, moscow),
+				time.Date(2023, 3, 22, 15, 0, 0, 0, moscow),
+				time.Date(2023, 3, 22, 15, 20, 0, 0, moscow),
+				time.Date(2023, 3, 22, 15, 40, 0, 0, moscow),
+				time.Date(2023, 3, 23, 14, 0, 0, 0, moscow),
+			},
+		},
+		{
+			spec: "30 9 * * mon-fri",
+			expected: []time.Time{
+				time.Date(2023, 3, 23, 9, 30, 0, 0, moscow),
+				time.Date(2023, 3, 24, 9, 30, 0, 0, moscow),
+				time.Date(2023, 3, 27, 9, 30, 0, 0, moscow),
+			},
+		},
+		{
+			// ограничены оба поля дня: подходит 1-е число или воскресенье.
+			spec: "0 0 1 * 7",
+			expected: []time.Time{
+				time.Date(2023, 3, 26, 0, 0, 0, 0, moscow),
+				time.Date(2023, 4, 1, 0, 0, 0, 0, moscow),
+				time.Date(2023, 4, 2, 0, 0, 0, 0, moscow),
+			},
+		},
+		{
+			spec: "0 12 29 feb *",
+			expected: []time.Time{
+				time.Date(2024, 2, 29, 12, 0, 0, 0, moscow),
+				time.Date(2028, 2, 29, 12, 0, 0, 0, moscow),
+			},
+		},
+		{
+			spec: "@monthly",
+			expected: []time.Time{
+				time.Date(2023, 4, 1, 0, 0, 0, 0, moscow),
+				time.Date(2023, 5, 1, 0, 0, 0, 0, moscow),
+			},
+		},
+		{
+			spec: "@every 90m",
+			expected: []time.Time{
+				from.Add(90 * time.Minute),
+				from.Add(180 * time.Minute),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.spec, func(t *testing.T) {
+			schedule, err := Parse(tc.spec, moscow)
+			require.NoError(t, err)
+			if _, ok := schedule.(Every); !ok {
+				require.Equal(t, tc.spec, schedule.String())
+			}
+			next := from
+			for _, expected := range tc.expected {
+				next = schedule.Next(next)
+				require.True(t, expected.Equal(next), "expected %s, got %s", expected, next)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		spec string
+		err  error
+	}{
+		{spec: "0 3 * *", err: ErrScheduleFields},
+		{spec: "60 * * * *", err: ErrScheduleValue},
+		{spec: "* 5-1 * * *", err: ErrScheduleValue},
+		{spec: "*/0 * * * *", err: ErrScheduleValue},
+		{spec: "* * * foo *", err: ErrScheduleValue},
+		{spec: "@every -1m", err: ErrEveryPeriod},
+	}
+	for _, tc := range testCases {
+		_, err := Parse(tc.spec, time.UTC)
+		require.True(t, errors.Is(err, tc.err), "%s: %v", tc.spec, err)
+	}
+}
+
+func TestNextLocation(t *testing.T) {
+	schedule, err := Parse("0 3 * * *", time.FixedZone("MSK", 3*60*60))
+	require.NoError(t, err)
+	// 00:00 UTC = 03:00 MSK, результат в часовом поясе аргумента.
+	next := schedule.Next(time.Date(2023, 3, 22, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2023, 3, 23, 0, 0, 0, 0, time.UTC), next)
+}
@@ -0,0 +1,98 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// JobState состояние задачи, сохраняется между перезапусками для догоняющего запуска
+// и просмотра расписания.
+type JobState struct {
+	Name         string        `json:"name"`
+	Schedule     string        `json:"schedule"`
+	LastRun      time.Time     `json:"lastRun"`
+	LastDuration time.Duration `json:"lastDuration"`
+	NextRun      time.Time     `json:"nextRun"`
+	Running      bool          `json:"running"`
+	Runs         int64         `json:"runs"`
+	// Skipped запуски, пропущенные из-за незавершенного предыдущего.
+	Skipped int64 `json:"skipped"`
+}
+
+// Store хранилище состояния задач.
+type Store interface {
+	// Load состояние задач, упорядоченное по имени, пустое хранилище - не ошибка.
+	Load() ([]JobState, error)
+	Save([]JobState) error
+}
+
+const stateFileMode = 0o644
+
+// FileStore состояние задач в json-файле.
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) Store {
+	return &FileStore{path: path}
+}
+
+func (fst *FileStore) Load() ([]JobState, error) {
+	data, err := os.ReadFile(fst.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var states []JobState
+	if err = json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("error reading job state from '%s': %w", fst.path, err)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states, nil
+}
+
+// Save запись через временный файл, чтобы прерванная запись не испортила состояние.
+func (fst *FileStore) Save(states []JobState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := fst.path + ".tmp"
+	if err = os.WriteFile(tmp, data, stateFileMode); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fst.path)
+}
+
+// WriteStatus таблица задач с временем последнего и следующего запуска.
+func WriteStatus(out io.Writer, states []JobState) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tLAST RUN\tDURATION\tNEXT RUN\tRUNS\tSKIPPED")
+	for _, state := range states {
+		lastRun, duration := "never", "-"
+		if !state.LastRun.IsZero() {
+			lastRun = state.LastRun.Local().Format("2006-01-02 15:04:05")
+			duration = state.LastDuration.Round(time.Millisecond).String()
+		}
+		if state.Running {
+			duration = "running"
+		}
+		nextRun := "-"
+		if !state.NextRun.IsZero() {
+			nextRun = state.NextRun.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n",
+			state.Name, state.Schedule, lastRun, duration, nextRun, state.Runs, state.Skipped)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// Поля записей, относящиеся к запросу.
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+)
+
+type ctxKey struct{}
+
+// fieldSet поля запроса. Общий для всех производных контекстов, чтобы поле, добавленное
+// во внутреннем обработчике (например, пользователь после авторизации), попало и в запись
+// внешнего middleware о завершении запроса.
+type fieldSet struct {
+	mu     sync.RWMutex
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewContext контекст запроса с пустым набором полей логгера.
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &fieldSet{values: make(map[string]interface{})})
+}
+
+// WithField добавляет поле к набору полей контекста. Если контекст создан не NewContext,
+// возвращается производный контекст с новым набором.
+func WithField(ctx context.Context, key string, value interface{}) context.Context {
+	fields, ok := ctx.Value(ctxKey{}).(*fieldSet)
+	if !ok {
+		ctx = NewContext(ctx)
+		fields = ctx.Value(ctxKey{}).(*fieldSet)
+	}
+	fields.mu.Lock()
+	if _, exists := fields.values[key]; !exists {
+		fields.keys = append(fields.keys, key)
+	}
+	fields.values[key] = value
+	fields.mu.Unlock()
+	return ctx
+}
+
+// Field значение поля контекста.
+func Field(ctx context.Context, key string) (interface{}, bool) {
+	fields, ok := ctx.Value(ctxKey{}).(*fieldSet)
+	if !ok {
+		return nil, false
+	}
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+	value, ok := fields.values[key]
+	return value, ok
+}
+
+// FromContext логгер запроса: l с полями контекста в порядке добавления.
+func FromContext(ctx context.Context, l Logger) Logger {
+	fields, ok := ctx.Value(ctxKey{}).(*fieldSet)
+	if !ok {
+		return l
+	}
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+	for _, key := range fields.keys {
+		l = l.With(key, fields.values[key])
+	}
+	return l
+}
@@ -5,8 +5,10 @@ import (
 )
 
 var (
-	ErrorUnknownLevel = errors.New("unknown log level")
-	ErrorOpenLogFile  = errors.New("error open log file")
+	ErrorUnknownLevel   = errors.New("unknown log level")
+	ErrorOpenLogFile    = errors.New("error open log file")
+	ErrorUnknownFormat  = errors.New("unknown log format, expected text or json")
+	ErrorUnknownBackend = errors.New("unknown logger backend, expected logrus or slog")
 )
 
 type Level int
@@ -28,6 +30,11 @@ type Logger interface {
 	Error(string, ...interface{})
 	Fatal(string, ...interface{})
 	Debug(string, ...interface{})
+	// SetLevel смена уровня логирования без пересоздания логгера.
+	SetLevel(Level) error
+	// With логгер, добавляющий поле key=value к каждой записи, исходный логгер не меняется.
+	// Уровень логирования у производных логгеров общий с исходным.
+	With(key string, value interface{}) Logger
 }
 
 func (l Level) String() string {
@@ -70,9 +77,64 @@ func ParseLevel(level string) (Level, error) {
 	return l, nil
 }
 
+// Format формат записей лога.
+type Format int
+
+const (
+	// FormatText строка "[INFO]: время - сообщение key=value" (по умолчанию).
+	FormatText Format = iota
+	// FormatJSON объект json на строку.
+	FormatJSON
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch format {
+	case "", "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return FormatText, ErrorUnknownFormat
+}
+
+// Backend реализация логгера.
+type Backend int
+
+const (
+	// BackendLogrus github.com/sirupsen/logrus (по умолчанию).
+	BackendLogrus Backend = iota
+	// BackendSlog log/slog стандартной библиотеки.
+	BackendSlog
+)
+
+func ParseBackend(backend string) (Backend, error) {
+	switch backend {
+	case "", "logrus":
+		return BackendLogrus, nil
+	case "slog":
+		return BackendSlog, nil
+	}
+	return BackendLogrus, ErrorUnknownBackend
+}
+
 // Config для всех логгеров.
 type Config struct {
 	Level     Level
 	FileName  string
 	IsTesting bool
+	Backend   Backend
+	Format    Format
+	// Rotation ротация файла FileName, нулевое значение - без ротации.
+	Rotation Rotation
+}
+
+// New логгер выбранной в cfg.Backend реализации.
+func New(cfg Config) (Logger, error) {
+	switch cfg.Backend {
+	case BackendLogrus:
+		return NewLogrus(cfg)
+	case BackendSlog:
+		return NewSlog(cfg)
+	}
+	return nil, ErrorUnknownBackend
 }
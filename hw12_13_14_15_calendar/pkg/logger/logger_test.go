@@ -2,10 +2,15 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -104,4 +109,212 @@ func TestLogrusAdapter(t *testing.T) {
 		require.Contains(t, logged, "fatal")
 		require.Contains(t, buf.String(), "exit")
 	})
+	t.Run("set level", func(t *testing.T) {
+		levelFile, err := os.CreateTemp("", "log.")
+		require.NoError(t, err)
+		defer levelFile.Close()
+		defer os.Remove(levelFile.Name())
+
+		logger, err := NewLogrus(Config{
+			Level:     LevelError,
+			FileName:  levelFile.Name(),
+			IsTesting: true,
+		})
+		require.NoError(t, err)
+		logger.Info("before")
+		require.ErrorIs(t, logger.SetLevel(555), ErrorUnknownLevel)
+		require.NoError(t, logger.SetLevel(LevelInfo))
+		logger.Info("after")
+
+		s, err := os.ReadFile(levelFile.Name())
+		require.NoError(t, err)
+		require.NotContains(t, string(s), "before")
+		require.Contains(t, string(s), "after")
+	})
+}
+
+func TestParseFormatBackend(t *testing.T) {
+	format, err := ParseFormat("")
+	require.NoError(t, err)
+	require.Equal(t, FormatText, format)
+	format, err = ParseFormat("json")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSON, format)
+	_, err = ParseFormat("xml")
+	require.ErrorIs(t, err, ErrorUnknownFormat)
+
+	backend, err := ParseBackend("")
+	require.NoError(t, err)
+	require.Equal(t, BackendLogrus, backend)
+	backend, err = ParseBackend("slog")
+	require.NoError(t, err)
+	require.Equal(t, BackendSlog, backend)
+	_, err = ParseBackend("zap")
+	require.ErrorIs(t, err, ErrorUnknownBackend)
+}
+
+// newFileLogger логгер backend в формате format, пишущий во временный файл, и функция чтения файла.
+func newFileLogger(t *testing.T, backend Backend, format Format) (Logger, func() string) {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(Config{
+		Level:     LevelInfo,
+		FileName:  fileName,
+		IsTesting: true,
+		Backend:   backend,
+		Format:    format,
+	})
+	require.NoError(t, err)
+	return l, func() string {
+		s, err := os.ReadFile(fileName)
+		require.NoError(t, err)
+		return string(s)
+	}
+}
+
+func TestLoggerFields(t *testing.T) {
+	for name, backend := range map[string]Backend{"logrus": BackendLogrus, "slog": BackendSlog} {
+		backend := backend
+		t.Run(name+" text", func(t *testing.T) {
+			l, logged := newFileLogger(t, backend, FormatText)
+			l.With("user_id", "u1").With("n", 2).Info("hello %s", "world")
+			l.Info("plain")
+
+			lines := strings.Split(strings.TrimSpace(logged()), "\n")
+			require.Len(t, lines, 2)
+			require.Contains(t, lines[0], "hello world")
+			require.Contains(t, lines[0], "user_id=u1")
+			require.Contains(t, lines[0], "n=2")
+			require.NotContains(t, lines[1], "user_id")
+		})
+		t.Run(name+" json", func(t *testing.T) {
+			l, logged := newFileLogger(t, backend, FormatJSON)
+			child := l.With("user_id", "u1")
+			require.NoError(t, l.SetLevel(LevelError))
+			// уровень общий у исходного и производного логгеров.
+			child.Info("skipped")
+			child.Error("failed: %d", 42)
+
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(logged()), &record))
+			require.Equal(t, "failed: 42", record["msg"])
+			require.Equal(t, "u1", record["user_id"])
+			require.Contains(t, []interface{}{"error", "ERROR"}, record["level"])
+		})
+	}
+}
+
+func TestSlogFatal(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	l, logged := newFileLogger(t, BackendSlog, FormatText)
+	require.NoError(t, l.SetLevel(LevelFatal))
+	l.Error("error")
+	l.Fatal("fatal")
+	require.NotContains(t, logged(), "msg=error")
+	require.Contains(t, logged(), "level=FATAL")
+	require.Contains(t, buf.String(), "exit(1)")
+}
+
+func TestContextFields(t *testing.T) {
+	l, logged := newFileLogger(t, BackendLogrus, FormatText)
+
+	ctx := NewContext(context.Background())
+	// поле, добавленное во вложенном контексте, видно во внешнем.
+	inner := WithField(context.WithValue(ctx, struct{}{}, 1), FieldUserID, "u1")
+	WithField(inner, FieldRequestID, "r1")
+	value, ok := Field(ctx, FieldUserID)
+	require.True(t, ok)
+	require.Equal(t, "u1", value)
+
+	FromContext(ctx, l).Info("request")
+	require.Contains(t, logged(), "request_id=r1 user_id=u1")
+
+	// контекст без набора полей.
+	require.Equal(t, l, FromContext(context.Background(), l))
+	_, ok = Field(WithField(context.Background(), "k", "v"), "k")
+	require.True(t, ok)
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "app.log")
+	now := time.Date(2023, 4, 6, 10, 0, 0, 0, time.UTC)
+	backups := func() []string {
+		matches, err := filepath.Glob(fileName + ".*")
+		require.NoError(t, err)
+		return matches
+	}
+
+	t.Run("size", func(t *testing.T) {
+		rf, err := NewRotatingFile(fileName, Rotation{MaxSize: 10, MaxBackups: 2})
+		require.NoError(t, err)
+		defer rf.Close()
+		rf.now = func() time.Time { return now }
+		for i := 0; i < 4; i++ {
+			now = now.Add(time.Second)
+			_, err = rf.Write([]byte("12345678\n"))
+			require.NoError(t, err)
+		}
+		// 4 записи по 9 байт: 3 ротации, хранятся 2 последних.
+		require.Len(t, backups(), 2)
+		s, err := os.ReadFile(fileName)
+		require.NoError(t, err)
+		require.Equal(t, "12345678\n", string(s))
+	})
+
+	t.Run("interval and age", func(t *testing.T) {
+		rf, err := NewRotatingFile(fileName, Rotation{Interval: 24 * time.Hour, MaxAge: 12 * time.Hour})
+		require.NoError(t, err)
+		defer rf.Close()
+		rf.now = func() time.Time { return now }
+		rf.openedAt = now
+
+		_, err = rf.Write([]byte("day 1\n"))
+		require.NoError(t, err)
+		require.Len(t, backups(), 2)
+
+		now = now.Add(24 * time.Hour)
+		_, err = rf.Write([]byte("day 2\n"))
+		require.NoError(t, err)
+		// файлы, ротированные сутки назад, старше MaxAge и удалены.
+		require.Len(t, backups(), 1)
+
+		_, err = rf.Write([]byte("day 2 again\n"))
+		require.NoError(t, err)
+		require.Len(t, backups(), 1)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "app.log")
+		rf, err := NewRotatingFile(fileName, Rotation{MaxSize: 10})
+		require.NoError(t, err)
+		defer rf.Close()
+		rf.now = func() time.Time { return now }
+		_, err = rf.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+
+		// имя копии занято непустым каталогом: переименование не удается, запись идет в прежний файл.
+		backup := fileName + "." + now.UTC().Format(backupLayout)
+		require.NoError(t, os.MkdirAll(filepath.Join(backup, "busy"), 0o755))
+		n, err := rf.Write([]byte("failed\n"))
+		require.Error(t, err)
+		require.Equal(t, len("failed\n"), n)
+		s, err := os.ReadFile(fileName)
+		require.NoError(t, err)
+		require.Equal(t, "12345678\nfailed\n", string(s))
+
+		// ротация повторяется при следующей записи.
+		require.NoError(t, os.RemoveAll(backup))
+		_, err = rf.Write([]byte("rotated\n"))
+		require.NoError(t, err)
+		s, err = os.ReadFile(fileName)
+		require.NoError(t, err)
+		require.Equal(t, "rotated\n", string(s))
+		s, err = os.ReadFile(backup)
+		require.NoError(t, err)
+		require.Equal(t, "12345678\nfailed\n", string(s))
+	})
 }
@@ -1,16 +1,19 @@
 package logger
 
 import (
-	"io"
+	"bytes"
+	"fmt"
 	"log"
-	"os"
+	"sort"
 
 	"github.com/sirupsen/logrus"
 	easy "github.com/t-tomalak/logrus-easy-formatter"
 )
 
+const timestampFormat = "2006-01-02 15:04:05"
+
 type Logrus struct {
-	logger *logrus.Logger
+	entry *logrus.Entry
 }
 
 func NewLogrus(cfg Config) (Logger, error) {
@@ -18,61 +21,100 @@ func NewLogrus(cfg Config) (Logger, error) {
 	if !cfg.Level.Valid() {
 		return nil, ErrorUnknownLevel
 	}
-	var level logrus.Level
-	switch cfg.Level {
-	case LevelNone, LevelOut:
-		level = logrus.PanicLevel
-	case LevelInfo:
-		level = logrus.InfoLevel
-	case LevelWarn:
-		level = logrus.WarnLevel
-	case LevelError:
-		level = logrus.ErrorLevel
-	case LevelFatal:
-		level = logrus.FatalLevel
-	case LevelDebug:
-		level = logrus.DebugLevel
+	output, err := openOutput(cfg)
+	if err != nil {
+		return nil, err
 	}
-	if len(cfg.FileName) > 0 {
-		f, err := os.OpenFile(cfg.FileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
-		if err != nil {
-			return nil, ErrorOpenLogFile
-		}
-		if !cfg.IsTesting {
-			inst.SetOutput(io.MultiWriter(f, os.Stdout))
-		} else {
-			inst.SetOutput(f)
-		}
+	inst.SetOutput(output)
+	inst.SetLevel(logrusLevel(cfg.Level))
+	switch cfg.Format {
+	case FormatText:
+		inst.SetFormatter(&textFormatter{easy: easy.Formatter{
+			TimestampFormat: timestampFormat,
+			LogFormat:       "[%lvl%]: %time% - %msg%",
+		}})
+	case FormatJSON:
+		inst.SetFormatter(&logrus.JSONFormatter{TimestampFormat: timestampFormat})
+	default:
+		return nil, ErrorUnknownFormat
 	}
-	inst.SetLevel(level)
-	inst.SetFormatter(&easy.Formatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-		LogFormat:       "[%lvl%]: %time% - %msg%\n",
-	})
 	if cfg.IsTesting {
 		inst.ExitFunc = func(i int) {
 			log.Printf("logrus fatal exit(%d)\n", i)
 		}
 	}
-	return &Logrus{logger: inst}, nil
+	return &Logrus{entry: logrus.NewEntry(inst)}, nil
+}
+
+// textFormatter прежний формат easy.Formatter с полями key=value в конце строки.
+type textFormatter struct {
+	easy easy.Formatter
+}
+
+func (f *textFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	line, err := f.easy.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	buff := bytes.NewBuffer(line)
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(buff, " %s=%v", key, entry.Data[key])
+	}
+	buff.WriteByte('\n')
+	return buff.Bytes(), nil
+}
+
+func logrusLevel(level Level) logrus.Level {
+	switch level {
+	case LevelInfo:
+		return logrus.InfoLevel
+	case LevelWarn:
+		return logrus.WarnLevel
+	case LevelError:
+		return logrus.ErrorLevel
+	case LevelFatal:
+		return logrus.FatalLevel
+	case LevelDebug:
+		return logrus.DebugLevel
+	case LevelNone, LevelOut:
+	}
+	return logrus.PanicLevel
+}
+
+func (l Logrus) SetLevel(level Level) error {
+	if !level.Valid() {
+		return ErrorUnknownLevel
+	}
+	// logrus.Logger.SetLevel атомарен, смена безопасна при параллельной записи.
+	l.entry.Logger.SetLevel(logrusLevel(level))
+	return nil
+}
+
+func (l Logrus) With(key string, value interface{}) Logger {
+	return &Logrus{entry: l.entry.WithField(key, value)}
 }
 
 func (l Logrus) Debug(msg string, args ...interface{}) {
-	l.logger.Debugf(msg, args...)
+	l.entry.Debugf(msg, args...)
 }
 
 func (l Logrus) Info(msg string, args ...interface{}) {
-	l.logger.Infof(msg, args...)
+	l.entry.Infof(msg, args...)
 }
 
 func (l Logrus) Warn(msg string, args ...interface{}) {
-	l.logger.Warnf(msg, args...)
+	l.entry.Warnf(msg, args...)
 }
 
 func (l Logrus) Error(msg string, args ...interface{}) {
-	l.logger.Errorf(msg, args...)
+	l.entry.Errorf(msg, args...)
 }
 
 func (l Logrus) Fatal(msg string, args ...interface{}) {
-	l.logger.Fatalf(msg, args...)
+	l.entry.Fatalf(msg, args...)
 }
@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupLayout суффикс имени файла ротированного лога: app.log.20230406-150405.000.
+const backupLayout = "20060102-150405.000"
+
+// Rotation правила ротации файла лога, нулевые значения полей отключают соответствующее правило.
+type Rotation struct {
+	// MaxSize размер файла в байтах, при превышении которого он ротируется.
+	MaxSize int64
+	// Interval период ротации: 24h - файл на каждые сутки (UTC).
+	Interval time.Duration
+	// MaxAge срок хранения ротированных файлов.
+	MaxAge time.Duration
+	// MaxBackups количество хранимых ротированных файлов.
+	MaxBackups int
+}
+
+func (r Rotation) Enabled() bool {
+	return r.MaxSize > 0 || r.Interval > 0
+}
+
+// RotatingFile файл лога с ротацией по размеру и времени, безопасен для параллельной записи.
+// Ротированный файл переименовывается в <имя>.<время ротации>, лишние и устаревшие удаляются.
+type RotatingFile struct {
+	fileName string
+	rotation Rotation
+	now      func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func NewRotatingFile(fileName string, rotation Rotation) (*RotatingFile, error) {
+	rf := &RotatingFile{fileName: fileName, rotation: rotation, now: time.Now}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// open открывает файл на дозапись, время открытия существующего файла - время его изменения,
+// чтобы после перезапуска файл прошлых суток был ротирован при первой записи. При ошибке
+// текущий файл не меняется.
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	rf.file, rf.size, rf.openedAt = file, stat.Size(), rf.now()
+	if rf.size > 0 {
+		rf.openedAt = stat.ModTime()
+	}
+	return nil
+}
+
+// Write при ошибке ротации запись продолжается в текущий файл, ошибка возвращается вместе
+// с записанным, и ротация повторяется при следующей записи.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if rf.needRotate(int64(len(p))) {
+		rotateErr = rf.rotate()
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+func (rf *RotatingFile) needRotate(size int64) bool {
+	if rf.size == 0 {
+		return false
+	}
+	if rf.rotation.MaxSize > 0 && rf.size+size > rf.rotation.MaxSize {
+		return true
+	}
+	if interval := rf.rotation.Interval; interval > 0 {
+		next := rf.openedAt.Truncate(interval).Add(interval)
+		return !rf.now().Before(next)
+	}
+	return false
+}
+
+// rotate текущий файл закрывается только после открытия нового, поэтому при ошибке
+// переименования или открытия запись продолжается в прежний файл.
+func (rf *RotatingFile) rotate() error {
+	old := rf.file
+	backup := fmt.Sprintf("%s.%s", rf.fileName, rf.now().UTC().Format(backupLayout))
+	if err := os.Rename(rf.fileName, backup); err != nil {
+		return fmt.Errorf("log rotation: %w", err)
+	}
+	if err := rf.open(); err != nil {
+		// открытый файл прежний, возвращаем ему имя, чтобы запись шла в файл лога, а не в копию.
+		if renameErr := os.Rename(backup, rf.fileName); renameErr != nil {
+			return fmt.Errorf("log rotation: %w, writing to %s", err, backup)
+		}
+		return fmt.Errorf("log rotation: %w", err)
+	}
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("log rotation: %w", err)
+	}
+	return rf.cleanup()
+}
+
+// cleanup удаление ротированных файлов сверх MaxBackups и старше MaxAge.
+func (rf *RotatingFile) cleanup() error {
+	if rf.rotation.MaxBackups <= 0 && rf.rotation.MaxAge <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(rf.fileName + ".*")
+	if err != nil {
+		return err
+	}
+	type backup struct {
+		name string
+		time time.Time
+	}
+	backups := make([]backup, 0, len(matches))
+	for _, name := range matches {
+		rotatedAt, err := time.Parse(backupLayout, strings.TrimPrefix(name, rf.fileName+"."))
+		if err != nil {
+			// чужой файл с похожим именем.
+			continue
+		}
+		backups = append(backups, backup{name: name, time: rotatedAt})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	now := rf.now()
+	for i, b := range backups {
+		tooMany := rf.rotation.MaxBackups > 0 && i >= rf.rotation.MaxBackups
+		tooOld := rf.rotation.MaxAge > 0 && now.Sub(b.time) > rf.rotation.MaxAge
+		if tooMany || tooOld {
+			if err = os.Remove(b.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+// openOutput вывод логгера: файл cfg.FileName (с ротацией, если задана) и stdout, без файла - stderr.
+func openOutput(cfg Config) (io.Writer, error) {
+	if len(cfg.FileName) == 0 {
+		return os.Stderr, nil
+	}
+	var (
+		file io.Writer
+		err  error
+	)
+	if cfg.Rotation.Enabled() {
+		file, err = NewRotatingFile(cfg.FileName, cfg.Rotation)
+	} else {
+		file, err = os.OpenFile(cfg.FileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	}
+	if err != nil {
+		return nil, ErrorOpenLogFile
+	}
+	if cfg.IsTesting {
+		return file, nil
+	}
+	// stdout первым: MultiWriter прекращает запись на первой ошибке, например ротации файла.
+	return io.MultiWriter(os.Stdout, file), nil
+}
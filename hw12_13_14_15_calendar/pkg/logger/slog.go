@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+)
+
+// levelFatal уровень slog для Fatal: в slog уровня выше Error нет.
+const levelFatal = slog.LevelError + 4
+
+type Slog struct {
+	logger *slog.Logger
+	level  *slog.LevelVar
+	exit   func(int)
+}
+
+func NewSlog(cfg Config) (Logger, error) {
+	if !cfg.Level.Valid() {
+		return nil, ErrorUnknownLevel
+	}
+	output, err := openOutput(cfg)
+	if err != nil {
+		return nil, err
+	}
+	level := new(slog.LevelVar)
+	level.Set(slogLevel(cfg.Level))
+	options := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return attr
+			}
+			switch attr.Key {
+			case slog.TimeKey:
+				attr.Value = slog.StringValue(attr.Value.Time().Format(timestampFormat))
+			case slog.LevelKey:
+				if attr.Value.Any() == levelFatal {
+					attr.Value = slog.StringValue("FATAL")
+				}
+			}
+			return attr
+		},
+	}
+	var handler slog.Handler
+	switch cfg.Format {
+	case FormatText:
+		handler = slog.NewTextHandler(output, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(output, options)
+	default:
+		return nil, ErrorUnknownFormat
+	}
+	exit := os.Exit
+	if cfg.IsTesting {
+		exit = func(i int) {
+			log.Printf("slog fatal exit(%d)\n", i)
+		}
+	}
+	return &Slog{logger: slog.New(handler), level: level, exit: exit}, nil
+}
+
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	case LevelFatal:
+		return levelFatal
+	case LevelInfo, LevelNone, LevelOut:
+	}
+	return slog.LevelInfo
+}
+
+func (l Slog) SetLevel(level Level) error {
+	if !level.Valid() {
+		return ErrorUnknownLevel
+	}
+	// slog.LevelVar атомарен, уровень общий с производными логгерами.
+	l.level.Set(slogLevel(level))
+	return nil
+}
+
+func (l Slog) With(key string, value interface{}) Logger {
+	return &Slog{logger: l.logger.With(key, value), level: l.level, exit: l.exit}
+}
+
+func (l Slog) log(level slog.Level, msg string, args []interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	l.logger.Log(ctx, level, msg)
+}
+
+func (l Slog) Debug(msg string, args ...interface{}) {
+	l.log(slog.LevelDebug, msg, args)
+}
+
+func (l Slog) Info(msg string, args ...interface{}) {
+	l.log(slog.LevelInfo, msg, args)
+}
+
+func (l Slog) Warn(msg string, args ...interface{}) {
+	l.log(slog.LevelWarn, msg, args)
+}
+
+func (l Slog) Error(msg string, args ...interface{}) {
+	l.log(slog.LevelError, msg, args)
+}
+
+func (l Slog) Fatal(msg string, args ...interface{}) {
+	l.log(levelFatal, msg, args)
+	l.exit(1)
+}
@@ -0,0 +1,125 @@
+package mailer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format правила форматирования дат и продолжительностей для языка.
+type Format struct {
+	Date     string // формат даты для time.Format
+	Time     string // формат времени для time.Format
+	Weekdays [7]string
+	// Day, Hour, Minute единицы продолжительности: "1 ч 30 мин", "1h 30m".
+	Day, Hour, Minute string
+	// UnitSep разделитель числа и единицы.
+	UnitSep string
+}
+
+// formats известные языки, регион ищется по базовому языку: en-US - en.
+var formats = map[string]Format{
+	"ru": {
+		Date: "02.01.2006",
+		Time: "15:04",
+		Weekdays: [7]string{
+			"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота",
+		},
+		Day: "д", Hour: "ч", Minute: "мин", UnitSep: " ",
+	},
+	"en": {
+		Date: "Jan 2, 2006",
+		Time: "3:04 PM",
+		Weekdays: [7]string{
+			"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+		},
+		Day: "d", Hour: "h", Minute: "m",
+	},
+	"en-GB": {
+		Date: "2 Jan 2006",
+		Time: "15:04",
+		Weekdays: [7]string{
+			"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+		},
+		Day: "d", Hour: "h", Minute: "m",
+	},
+}
+
+// FormatFor правила языка locale, для неизвестного языка - английские.
+func FormatFor(locale string) Format {
+	for _, candidate := range FallbackChain(locale, "") {
+		if format, ok := formats[candidate]; ok {
+			return format
+		}
+	}
+	return formats["en"]
+}
+
+// FallbackChain порядок поиска шаблона: язык с регионом, базовый язык, язык по умолчанию.
+func FallbackChain(locale, defaultLocale string) []string {
+	chain := make([]string, 0, 3)
+	add := func(candidate string) {
+		if candidate == "" {
+			return
+		}
+		for _, c := range chain {
+			if c == candidate {
+				return
+			}
+		}
+		chain = append(chain, candidate)
+	}
+	add(locale)
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		add(locale[:i])
+	}
+	add(defaultLocale)
+	if i := strings.IndexByte(defaultLocale, '-'); i > 0 {
+		add(defaultLocale[:i])
+	}
+	return chain
+}
+
+// Funcs функции шаблонов языка: date, time, datetime, weekday, duration и sameDay.
+// Время выводится в часовом поясе значения, перевод в пояс получателя - забота отправителя.
+func (f Format) Funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"date": func(t time.Time) string {
+			return t.Format(f.Date)
+		},
+		"time": func(t time.Time) string {
+			return t.Format(f.Time)
+		},
+		"datetime": func(t time.Time) string {
+			return t.Format(f.Date + " " + f.Time)
+		},
+		"weekday": func(t time.Time) string {
+			return f.Weekdays[t.Weekday()]
+		},
+		"duration": f.Duration,
+		"sameDay": func(a, b time.Time) bool {
+			ay, am, ad := a.Date()
+			by, bm, bd := b.Date()
+			return ay == by && am == bm && ad == bd
+		},
+	}
+}
+
+// Duration продолжительность с точностью до минуты: "1 д 2 ч", "45 мин".
+func (f Format) Duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	parts := make([]string, 0, 3)
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d%s%s", days, f.UnitSep, f.Day))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d%s%s", hours, f.UnitSep, f.Hour))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d%s%s", minutes, f.UnitSep, f.Minute))
+	}
+	return strings.Join(parts, " ")
+}
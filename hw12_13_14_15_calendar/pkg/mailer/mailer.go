@@ -3,8 +3,9 @@ package mailer
 import (
 	"bytes"
 	"fmt"
-	"html/template"
-	"os"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
 	"strings"
 )
 
@@ -15,38 +16,23 @@ type Mail struct {
 	Cc      []string
 	Bcc     []string
 	Subject string
-	Data    interface{}
+	// Locale язык получателя, шаблон выбирается по Registry.Render.
+	Locale string
+	Data   interface{}
 }
 
 type Mailer interface {
 	SendMail(tplName string, mail Mail) error
 }
 
-func BuildBody(tplPath, tplName string, data interface{}) (string, error) {
-	fileName := fmt.Sprintf("%s/%s.mail.tmpl", tplPath, tplName)
-	stats, err := os.Stat(fileName)
-	if err != nil {
-		return "", fmt.Errorf("template file for %s not found", tplName)
+// BuildMessage письмо целиком: заголовки и тело. Тема из шаблона заменяет mail.Subject,
+// при наличии обоих вариантов тело собирается как multipart/alternative.
+func BuildMessage(mail Mail, msg Message) (string, error) {
+	if msg.Subject != "" {
+		mail.Subject = msg.Subject
 	}
-	if stats.IsDir() || !stats.Mode().IsRegular() {
-		return "", fmt.Errorf("%s is not regular file", tplName)
-	}
-	tmpl, err := template.ParseFiles(fileName)
-	if err != nil {
-		return "", fmt.Errorf("error parsing template file %s: %w", fileName, err)
-	}
-	buff := new(bytes.Buffer)
-	err = tmpl.Execute(buff, data)
-	if err != nil {
-		return "", fmt.Errorf("error executing template %s: %w", fileName, err)
-	}
-	return buff.String(), nil
-}
-
-func BuildHeaders(mail Mail) string {
 	sb := strings.Builder{}
 	sb.WriteString("MIME-version: 1.0\r\n")
-	sb.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
 	sb.WriteString(fmt.Sprintf("From: %s\r\n", mail.Sender))
 	if len(mail.To) > 0 {
 		sb.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(mail.To, ";")))
@@ -57,7 +43,45 @@ func BuildHeaders(mail Mail) string {
 	if len(mail.Bcc) > 0 {
 		sb.WriteString(fmt.Sprintf("Bcc: %s\r\n", strings.Join(mail.Bcc, ";")))
 	}
-	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", mail.Subject))
+	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", mail.Subject)))
 
-	return sb.String()
+	switch {
+	case msg.Text != "" && msg.HTML != "":
+		body, boundary, err := buildAlternative(msg)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", boundary))
+		sb.WriteString(body)
+	case msg.HTML != "":
+		sb.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n")
+		sb.WriteString(msg.HTML)
+	default:
+		sb.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+		sb.WriteString(msg.Text)
+	}
+	return sb.String(), nil
+}
+
+func buildAlternative(msg Message) (string, string, error) {
+	buff := new(bytes.Buffer)
+	w := multipart.NewWriter(buff)
+	// по RFC 2046 предпочтительный вариант - последний.
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=\"UTF-8\"", msg.Text},
+		{"text/html; charset=\"UTF-8\"", msg.HTML},
+	}
+	for _, part := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return "", "", err
+		}
+		if _, err = pw.Write([]byte(part.body)); err != nil {
+			return "", "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", "", err
+	}
+	return buff.String(), w.Boundary(), nil
 }
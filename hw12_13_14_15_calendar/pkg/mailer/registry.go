@@ -0,0 +1,217 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+var (
+	ErrNoDefaultLocale  = errors.New("no templates for default locale")
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrTemplateNoBody   = errors.New("template has neither text nor html variant")
+)
+
+// Варианты шаблона письма: тема, текст и html, достаточно одного из двух последних.
+const (
+	subjectSuffix = ".subject.tmpl"
+	textSuffix    = ".txt.tmpl"
+	htmlSuffix    = ".html.tmpl"
+)
+
+// Message письмо по шаблону, пустые поля - вариант не задан.
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type templateSet struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Registry шаблоны писем по языкам, разбираются при создании и Reload, а не при каждой отправке.
+// Раскладка файлов: <path>/<locale>/<name>.subject.tmpl, <name>.txt.tmpl, <name>.html.tmpl,
+// например ru/events/notify.txt.tmpl для шаблона events/notify.
+type Registry struct {
+	path          string
+	defaultLocale string
+
+	mu      sync.RWMutex
+	locales map[string]map[string]*templateSet
+}
+
+func NewRegistry(path, defaultLocale string) (*Registry, error) {
+	r := &Registry{path: path, defaultLocale: defaultLocale}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload перечитывает шаблоны, при ошибке остаются прежние.
+func (r *Registry) Reload() error {
+	locales, err := load(r.path)
+	if err != nil {
+		return err
+	}
+	if _, ok := locales[r.defaultLocale]; !ok {
+		return fmt.Errorf("'%s' in %s: %w", r.defaultLocale, r.path, ErrNoDefaultLocale)
+	}
+	r.mu.Lock()
+	r.locales = locales
+	r.mu.Unlock()
+	return nil
+}
+
+// Locales языки, для которых есть шаблоны.
+func (r *Registry) Locales() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]string, 0, len(r.locales))
+	for locale := range r.locales {
+		result = append(result, locale)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Render письмо по шаблону name на языке locale, шаблон ищется по цепочке FallbackChain.
+func (r *Registry) Render(name, locale string, data interface{}) (Message, error) {
+	set := r.lookup(name, locale)
+	if set == nil {
+		return Message{}, fmt.Errorf("'%s' for locale '%s': %w", name, locale, ErrTemplateNotFound)
+	}
+	var (
+		msg Message
+		err error
+	)
+	if set.subject != nil {
+		if msg.Subject, err = execute(set.subject, data); err != nil {
+			return Message{}, err
+		}
+		msg.Subject = strings.TrimSpace(msg.Subject)
+	}
+	if set.text != nil {
+		if msg.Text, err = execute(set.text, data); err != nil {
+			return Message{}, err
+		}
+	}
+	if set.html != nil {
+		if msg.HTML, err = execute(set.html, data); err != nil {
+			return Message{}, err
+		}
+	}
+	return msg, nil
+}
+
+func (r *Registry) lookup(name, locale string) *templateSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, candidate := range FallbackChain(locale, r.defaultLocale) {
+		if set, ok := r.locales[candidate][name]; ok {
+			return set
+		}
+	}
+	return nil
+}
+
+// executor общий интерфейс text/template и html/template.
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+	Name() string
+}
+
+func execute(tmpl executor, data interface{}) (string, error) {
+	buff := new(bytes.Buffer)
+	if err := tmpl.Execute(buff, data); err != nil {
+		return "", fmt.Errorf("error executing template %s: %w", tmpl.Name(), err)
+	}
+	return buff.String(), nil
+}
+
+// load разбор всех шаблонов каталога path, подкаталоги первого уровня - языки.
+func load(path string) (map[string]map[string]*templateSet, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading templates: %w", err)
+	}
+	locales := make(map[string]map[string]*templateSet)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		sets, err := loadLocale(filepath.Join(path, locale), FormatFor(locale).Funcs())
+		if err != nil {
+			return nil, err
+		}
+		locales[locale] = sets
+	}
+	return locales, nil
+}
+
+func loadLocale(dir string, funcs map[string]interface{}) (map[string]*templateSet, error) {
+	sets := make(map[string]*templateSet)
+	err := filepath.WalkDir(dir, func(fileName string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, fileName)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, suffix := range []string{subjectSuffix, textSuffix, htmlSuffix} {
+			if !strings.HasSuffix(rel, suffix) {
+				continue
+			}
+			name := strings.TrimSuffix(rel, suffix)
+			if sets[name] == nil {
+				sets[name] = &templateSet{}
+			}
+			return parse(sets[name], suffix, fileName, funcs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, set := range sets {
+		if set.text == nil && set.html == nil {
+			return nil, fmt.Errorf("%s/%s: %w", dir, name, ErrTemplateNoBody)
+		}
+	}
+	return sets, nil
+}
+
+func parse(set *templateSet, suffix, fileName string, funcs map[string]interface{}) error {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	base := filepath.Base(fileName)
+	switch suffix {
+	case htmlSuffix:
+		set.html, err = htmltemplate.New(base).Funcs(funcs).Parse(string(content))
+	case textSuffix:
+		set.text, err = texttemplate.New(base).Funcs(funcs).Parse(string(content))
+	default:
+		set.subject, err = texttemplate.New(base).Funcs(funcs).Parse(string(content))
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing template file %s: %w", fileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package mailer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplates раскладывает файлы шаблонов files (путь относительно корня - содержимое) во временный каталог.
+func writeTemplates(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		fileName := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fileName), 0o755))
+		require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	require.Equal(t, []string{"en-US", "en", "ru"}, FallbackChain("en-US", "ru"))
+	require.Equal(t, []string{"ru"}, FallbackChain("", "ru"))
+	require.Equal(t, []string{"ru"}, FallbackChain("ru", "ru"))
+	require.Equal(t, []string{"de", "en-GB", "en"}, FallbackChain("de", "en-GB"))
+}
+
+func TestFormatFuncs(t *testing.T) {
+	date := time.Date(2023, 4, 5, 14, 30, 0, 0, time.UTC)
+
+	ru := FormatFor("ru").Funcs()
+	require.Equal(t, "05.04.2023 14:30", ru["datetime"].(func(time.Time) string)(date))
+	require.Equal(t, "среда", ru["weekday"].(func(time.Time) string)(date))
+	require.Equal(t, "1 ч 30 мин", FormatFor("ru").Duration(90*time.Minute))
+
+	en := FormatFor("en-US").Funcs()
+	require.Equal(t, "Apr 5, 2023 2:30 PM", en["datetime"].(func(time.Time) string)(date))
+	require.Equal(t, "1d 2h", FormatFor("en-US").Duration(26*time.Hour))
+	require.Equal(t, "0m", FormatFor("en").Duration(0))
+
+	require.Equal(t, "5 Apr 2023", FormatFor("en-GB").Funcs()["date"].(func(time.Time) string)(date))
+	require.Equal(t, FormatFor("en"), FormatFor("de"))
+}
+
+func TestRegistryRender(t *testing.T) {
+	root := t.TempDir()
+	writeTemplates(t, root, map[string]string{
+		"ru/events/notify.subject.tmpl": "Событие {{.Title }}\n",
+		"ru/events/notify.txt.tmpl":     "{{.Title }} {{datetime .Start }}",
+		"ru/events/notify.html.tmpl":    "<b>{{.Title }}</b> {{duration .Duration }}",
+		"en/events/notify.txt.tmpl":     "{{.Title }} {{datetime .Start }}",
+		"en-GB/other.txt.tmpl":          "other",
+	})
+	registry, err := NewRegistry(root, "ru")
+	require.NoError(t, err)
+	require.Equal(t, []string{"en", "en-GB", "ru"}, registry.Locales())
+
+	data := map[string]interface{}{
+		"Title":    "<Встреча>",
+		"Start":    time.Date(2023, 4, 5, 14, 30, 0, 0, time.UTC),
+		"Duration": 90 * time.Minute,
+	}
+
+	msg, err := registry.Render("events/notify", "ru", data)
+	require.NoError(t, err)
+	require.Equal(t, "Событие <Встреча>", msg.Subject)
+	require.Equal(t, "<Встреча> 05.04.2023 14:30", msg.Text)
+	// html-вариант экранируется.
+	require.Equal(t, "<b>&lt;Встреча&gt;</b> 1 ч 30 мин", msg.HTML)
+
+	// en-GB -> en: шаблон английский, без темы и html.
+	msg, err = registry.Render("events/notify", "en-GB", data)
+	require.NoError(t, err)
+	require.Equal(t, Message{Text: "<Встреча> Apr 5, 2023 2:30 PM"}, msg)
+
+	// неизвестный язык -> язык по умолчанию.
+	msg, err = registry.Render("events/notify", "de", data)
+	require.NoError(t, err)
+	require.Equal(t, "Событие <Встреча>", msg.Subject)
+
+	_, err = registry.Render("events/digest", "ru", data)
+	require.True(t, errors.Is(err, ErrTemplateNotFound))
+}
+
+func TestRegistryErrors(t *testing.T) {
+	root := t.TempDir()
+	writeTemplates(t, root, map[string]string{"en/notify.txt.tmpl": "text"})
+	_, err := NewRegistry(root, "ru")
+	require.True(t, errors.Is(err, ErrNoDefaultLocale))
+
+	writeTemplates(t, root, map[string]string{"ru/notify.subject.tmpl": "subject"})
+	_, err = NewRegistry(root, "ru")
+	require.True(t, errors.Is(err, ErrTemplateNoBody))
+
+	writeTemplates(t, root, map[string]string{"ru/notify.txt.tmpl": "{{.Title"})
+	_, err = NewRegistry(root, "ru")
+	require.Error(t, err)
+}
+
+func TestRegistryReload(t *testing.T) {
+	root := t.TempDir()
+	writeTemplates(t, root, map[string]string{"ru/notify.txt.tmpl": "v1"})
+	registry, err := NewRegistry(root, "ru")
+	require.NoError(t, err)
+
+	writeTemplates(t, root, map[string]string{"ru/notify.txt.tmpl": "v2"})
+	require.NoError(t, registry.Reload())
+	msg, err := registry.Render("notify", "ru", nil)
+	require.NoError(t, err)
+	require.Equal(t, "v2", msg.Text)
+
+	// ошибочный шаблон не заменяет загруженные.
+	writeTemplates(t, root, map[string]string{"ru/notify.txt.tmpl": "{{end }}"})
+	require.Error(t, registry.Reload())
+	msg, err = registry.Render("notify", "ru", nil)
+	require.NoError(t, err)
+	require.Equal(t, "v2", msg.Text)
+}
+
+func TestBuildMessage(t *testing.T) {
+	mail := Mail{Sender: "support@otus.ru", To: []string{"user@otus.ru"}, Subject: "default"}
+
+	content, err := BuildMessage(mail, Message{Text: "text"})
+	require.NoError(t, err)
+	require.Contains(t, content, "Subject: default\r\n")
+	require.Contains(t, content, "Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\ntext")
+
+	content, err = BuildMessage(mail, Message{Subject: "Тема", Text: "text", HTML: "<b>html</b>"})
+	require.NoError(t, err)
+	require.Contains(t, content, "Subject: =?UTF-8?q?")
+	require.Contains(t, content, "Content-Type: multipart/alternative; boundary=")
+	textAt, htmlAt := strings.Index(content, "\r\n\r\ntext"), strings.Index(content, "<b>html</b>")
+	require.True(t, textAt > 0 && htmlAt > textAt, "text part must precede html part")
+}
+
+// TestRepositoryTemplates шаблоны писем приложения разбираются и выполняются на всех языках.
+func TestRepositoryTemplates(t *testing.T) {
+	registry, err := NewRegistry("../../templates/mail", "ru")
+	require.NoError(t, err)
+	start := time.Date(2023, 4, 5, 14, 30, 0, 0, time.UTC)
+	event := map[string]interface{}{
+		"Title": "Встреча", "EventID": "id", "Start": start, "End": start.Add(time.Hour), "Duration": time.Hour,
+	}
+	data := map[string]map[string]interface{}{
+		"events/notify": {
+			"UserName": "user", "EventTitle": "Встреча", "EventID": "id", "SenderEmail": "support@otus.ru",
+			"Start": start, "End": start.Add(time.Hour), "Duration": time.Hour,
+			"Links": []map[string]interface{}{{"Video": true, "URL": "https://meet.example.com/room", "Title": ""}},
+		},
+		"events/digest": {
+			"UserName": "user", "Weekly": true, "DateStart": start, "DateEnd": start.AddDate(0, 0, 6),
+			"TimeZone": "UTC", "Events": []map[string]interface{}{event}, "SenderEmail": "support@otus.ru",
+		},
+	}
+	for _, locale := range registry.Locales() {
+		for name, values := range data {
+			msg, err := registry.Render(name, locale, values)
+			require.NoError(t, err, "%s/%s", locale, name)
+			require.NotEmpty(t, msg.Subject, "%s/%s", locale, name)
+			require.Contains(t, msg.Text, "Встреча", "%s/%s", locale, name)
+			require.Contains(t, msg.HTML, "Встреча", "%s/%s", locale, name)
+			if name == "events/notify" {
+				require.Contains(t, msg.Text, "https://meet.example.com/room", locale)
+				require.Contains(t, msg.HTML, `href="https://meet.example.com/room"`, locale)
+			}
+		}
+	}
+}
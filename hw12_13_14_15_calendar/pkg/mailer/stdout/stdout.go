@@ -11,7 +11,7 @@ import (
 var ErrEmptyRecipient = errors.New("empty recipient list")
 
 type Config struct {
-	TmplPath    string
+	Templates   *mailer.Registry
 	DefaultFrom string
 }
 
@@ -27,13 +27,19 @@ func (ml *Mailer) SendMail(tplName string, mail mailer.Mail) error {
 	if len(mail.To) == 0 {
 		return ErrEmptyRecipient
 	}
-	body, err := mailer.BuildBody(ml.config.TmplPath, tplName, mail.Data)
+	msg, err := ml.config.Templates.Render(tplName, mail.Locale, mail.Data)
+	if err != nil {
+		return err
+	}
+	if mail.Sender == "" {
+		mail.Sender = ml.config.DefaultFrom
+	}
+	content, err := mailer.BuildMessage(mail, msg)
 	if err != nil {
 		return err
 	}
-	headers := mailer.BuildHeaders(mail)
 
-	_, err = fmt.Fprintf(os.Stdout, "%s\r\n%s\n\n", headers, body)
+	_, err = fmt.Fprintf(os.Stdout, "%s\n\n", content)
 
 	return err
 }
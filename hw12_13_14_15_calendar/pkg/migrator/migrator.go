@@ -0,0 +1,199 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+var ErrSchemaBehind = errors.New("database schema is behind migrations")
+
+// advisoryLockID ключ pg_advisory_lock, общий для всех экземпляров сервиса.
+const advisoryLockID int64 = 2023031519
+
+// Migrator миграции схемы PostgreSQL в формате goose: файлы NNN_name.sql с разделами
+// "-- +goose Up" и "-- +goose Down", примененные версии хранятся в таблице goose_db_version.
+type Migrator interface {
+	// Up применяет все новые миграции.
+	Up() error
+	// UpLocked Up под advisory lock: экземпляры, запущенные одновременно, применяют миграции
+	// по очереди, следующие находят схему уже обновленной.
+	UpLocked(ctx context.Context) error
+	// Down откатывает последнюю примененную миграцию.
+	Down() error
+	Status(ctx context.Context) ([]Migration, error)
+	// Version текущая версия схемы и версия последней известной миграции.
+	Version() (current, latest int64, err error)
+}
+
+type Migration struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// gooseMu goose хранит файловую систему, диалект и логгер в глобальных переменных.
+var gooseMu sync.Mutex
+
+type GooseMigrator struct {
+	db   *sql.DB
+	fsys fs.FS
+	out  io.Writer
+}
+
+// New миграции из корня fsys, ход выполнения выводится в out.
+func New(db *sql.DB, fsys fs.FS, out io.Writer) Migrator {
+	return &GooseMigrator{db: db, fsys: fsys, out: out}
+}
+
+func (gm GooseMigrator) Up() error {
+	return gm.with(func() error {
+		return goose.Up(gm.db, ".")
+	})
+}
+
+func (gm GooseMigrator) UpLocked(ctx context.Context) error {
+	// блокировка сессионная, поэтому держим для нее отдельное соединение пула.
+	conn, err := gm.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockID); err != nil {
+		return fmt.Errorf("can't acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockID)
+	}()
+	return gm.Up()
+}
+
+func (gm GooseMigrator) Down() error {
+	return gm.with(func() error {
+		return goose.Down(gm.db, ".")
+	})
+}
+
+func (gm GooseMigrator) Version() (current, latest int64, err error) {
+	err = gm.with(func() error {
+		migrations, err := goose.CollectMigrations(".", 0, goose.MaxVersion)
+		if err != nil {
+			return err
+		}
+		if len(migrations) > 0 {
+			latest = migrations[len(migrations)-1].Version
+		}
+		current, err = goose.GetDBVersion(gm.db)
+		return err
+	})
+	return current, latest, err
+}
+
+func (gm GooseMigrator) Status(ctx context.Context) ([]Migration, error) {
+	var result []Migration
+	err := gm.with(func() error {
+		migrations, err := goose.CollectMigrations(".", 0, goose.MaxVersion)
+		if err != nil {
+			return err
+		}
+		if _, err = goose.EnsureDBVersion(gm.db); err != nil {
+			return err
+		}
+		applied, err := gm.appliedAt(ctx)
+		if err != nil {
+			return err
+		}
+		result = make([]Migration, len(migrations))
+		for i, migration := range migrations {
+			result[i] = Migration{Version: migration.Version, Name: migration.Source}
+			if tstamp, ok := applied[migration.Version]; ok {
+				result[i].AppliedAt = &tstamp
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// appliedAt время применения версий, действующих сейчас: последняя запись версии - откат или применение.
+func (gm GooseMigrator) appliedAt(ctx context.Context) (map[int64]time.Time, error) {
+	query := fmt.Sprintf( //nolint:gosec // имя таблицы задается goose
+		"SELECT version_id, is_applied, tstamp FROM %s ORDER BY id DESC", goose.TableName(),
+	)
+	rows, err := gm.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	seen := make(map[int64]bool)
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			isApplied bool
+			tstamp    time.Time
+		)
+		if err = rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied {
+			applied[version] = tstamp
+		}
+	}
+	return applied, rows.Err()
+}
+
+func (gm GooseMigrator) with(fn func() error) error {
+	gooseMu.Lock()
+	defer gooseMu.Unlock()
+	if err := goose.SetDialect("postgres"); err != nil {
+		return err
+	}
+	goose.SetBaseFS(gm.fsys)
+	goose.SetLogger(log.New(gm.out, "", 0))
+	defer func() {
+		goose.SetBaseFS(nil)
+		goose.SetLogger(log.New(io.Discard, "", 0))
+	}()
+	return fn()
+}
+
+// CheckVersion ошибка ErrSchemaBehind, если применены не все известные миграции.
+// Схема новее миграций допустима: так работает предыдущая версия сервиса во время обновления.
+func CheckVersion(m Migrator) error {
+	current, latest, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if current < latest {
+		return fmt.Errorf("%w: version %d, latest migration %d", ErrSchemaBehind, current, latest)
+	}
+	return nil
+}
+
+// WriteStatus вывод состояния миграций таблицей.
+func WriteStatus(out io.Writer, migrations []Migration) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tAPPLIED\tNAME")
+	for _, migration := range migrations {
+		applied := "pending"
+		if migration.AppliedAt != nil {
+			applied = migration.AppliedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", migration.Version, applied, migration.Name)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,58 @@
+package migrator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMigrator struct {
+	Migrator
+	current, latest int64
+	err             error
+}
+
+func (fm fakeMigrator) Version() (int64, int64, error) {
+	return fm.current, fm.latest, fm.err
+}
+
+func TestCheckVersion(t *testing.T) {
+	errDB := errors.New("connection refused")
+	testCases := []struct {
+		name     string
+		migrator fakeMigrator
+		err      error
+	}{
+		{name: "up to date", migrator: fakeMigrator{current: 3, latest: 3}},
+		{name: "schema ahead", migrator: fakeMigrator{current: 4, latest: 3}},
+		{name: "empty database", migrator: fakeMigrator{current: 0, latest: 3}, err: ErrSchemaBehind},
+		{name: "behind", migrator: fakeMigrator{current: 2, latest: 3}, err: ErrSchemaBehind},
+		{name: "db error", migrator: fakeMigrator{err: errDB}, err: errDB},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckVersion(tc.migrator)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestWriteStatus(t *testing.T) {
+	applied := time.Date(2023, 3, 15, 19, 30, 0, 0, time.Local)
+	var buf bytes.Buffer
+	require.NoError(t, WriteStatus(&buf, []Migration{
+		{Version: 1, Name: "1_create_users.sql", AppliedAt: &applied},
+		{Version: 2, Name: "2_create_events.sql"},
+	}))
+	require.Equal(t, "VERSION  APPLIED           NAME\n"+
+		"1        2023-03-15 19:30  1_create_users.sql\n"+
+		"2        pending           2_create_events.sql\n", buf.String())
+}
@@ -3,23 +3,40 @@ package queue
 import (
 	"context"
 	"encoding/json"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
 )
 
 // Producer отправка сообщений в очередь.
 type Producer interface {
-	Produce(message Message) error
+	// Produce отправка сообщения, идентификатор запроса из ctx передается в заголовке сообщения.
+	Produce(ctx context.Context, message Message) error
 }
 
 type Consumer interface {
 	Consume(ctx context.Context, queue string) (<-chan Message, error)
 }
 
-type Message []byte
+// Message сообщение очереди: тело в JSON и строковые заголовки.
+type Message struct {
+	Body    []byte
+	Headers map[string]string
+}
 
 func EncMessage(object interface{}) (Message, error) {
-	return json.Marshal(object)
+	body, err := json.Marshal(object)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Body: body}, nil
 }
 
 func (m Message) Decode(object interface{}) error {
-	return json.Unmarshal(m, object)
+	return json.Unmarshal(m.Body, object)
+}
+
+// Context контекст обработки сообщения с идентификатором запроса отправителя,
+// для сообщения без идентификатора создается новый.
+func (m Message) Context(ctx context.Context) context.Context {
+	return requestid.NewContext(ctx, requestid.Accept(m.Headers[requestid.MetaKey]))
 }
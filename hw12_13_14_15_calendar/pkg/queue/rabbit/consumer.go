@@ -41,7 +41,7 @@ func (c *Consumer) Consume(ctx context.Context, queueName string) (<-chan queue.
 				if err = del.Ack(false); err != nil {
 					c.logger.Error("error sending ack: %s", err.Error())
 				}
-				message := queue.Message(del.Body)
+				message := queue.Message{Body: del.Body, Headers: stringHeaders(del.Headers)}
 				select {
 				case <-ctx.Done():
 					return
@@ -60,3 +60,17 @@ func (c *Consumer) Consume(ctx context.Context, queueName string) (<-chan queue.
 	}()
 	return messages, nil
 }
+
+// stringHeaders строковые заголовки сообщения, заголовки других типов не передаются.
+func stringHeaders(table amqp.Table) map[string]string {
+	if len(table) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(table))
+	for key, value := range table {
+		if s, ok := value.(string); ok {
+			headers[key] = s
+		}
+	}
+	return headers
+}
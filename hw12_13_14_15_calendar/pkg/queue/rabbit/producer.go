@@ -1,10 +1,12 @@
 package rabbit
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/streadway/amqp"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/queue"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
 )
 
 // Producer отправка сообщений в очередь.
@@ -19,7 +21,7 @@ func NewProducer(conn *MQConnection) (*Producer, error) {
 	return &Producer{MQConnection: conn}, nil
 }
 
-func (p *Producer) Produce(message queue.Message) error {
+func (p *Producer) Produce(ctx context.Context, message queue.Message) error {
 	ch, err := p.conn.Channel()
 	defer func() {
 		if err := ch.Close(); err != nil {
@@ -29,6 +31,13 @@ func (p *Producer) Produce(message queue.Message) error {
 	if err != nil {
 		return fmt.Errorf("can't get channel from AMQP connection: %w", err)
 	}
+	headers := make(amqp.Table, len(message.Headers)+1)
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		headers[requestid.MetaKey] = id
+	}
 	err = ch.Publish(
 		p.config.ExchangeName, // exchange
 		p.config.BindingKey,   // routing key
@@ -37,7 +46,8 @@ func (p *Producer) Produce(message queue.Message) error {
 		amqp.Publishing{
 			DeliveryMode: amqp.Persistent,
 			ContentType:  "application/json; charset=utf-8",
-			Body:         message,
+			Headers:      headers,
+			Body:         message.Body,
 		})
 	if err != nil {
 		return fmt.Errorf("can't publish message into RabbitMQ: %w", err)
@@ -0,0 +1,64 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+)
+
+/*
+Идентификатор запроса связывает записи журналов всех сервисов, участвовавших в обработке:
+REST-сервер принимает его из заголовка X-Request-ID или создает, передает в gRPC-метаданных
+и заголовках сообщений AMQP и возвращает клиенту в заголовке ответа и в ответах с ошибкой.
+*/
+
+const (
+	// Header заголовок HTTP-запроса и ответа.
+	Header = "X-Request-ID"
+	// MetaKey ключ метаданных gRPC и заголовков сообщений AMQP.
+	MetaKey = "x-request-id"
+	// MaxLength предельная длина принимаемого идентификатора.
+	MaxLength = 128
+)
+
+type ctxKey struct{}
+
+// New новый идентификатор.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid идентификатор клиента принимается, если он не пустой, не длиннее MaxLength и состоит
+// из печатных символов ASCII без пробелов: значение попадает в журналы и заголовки ответов.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Accept идентификатор клиента, если он допустим, иначе новый.
+func Accept(id string) string {
+	if Valid(id) {
+		return id
+	}
+	return New()
+}
+
+// NewContext контекст с идентификатором запроса, идентификатор добавляется и в поля логгера.
+func NewContext(ctx context.Context, id string) context.Context {
+	ctx = logger.WithField(ctx, logger.FieldRequestID, id)
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext идентификатор запроса контекста, пустая строка - если не задан.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+)
+
+func TestValid(t *testing.T) {
+	require.True(t, Valid("req-42"))
+	require.True(t, Valid(New()))
+	require.True(t, Valid(strings.Repeat("a", MaxLength)))
+	require.False(t, Valid(""))
+	require.False(t, Valid(strings.Repeat("a", MaxLength+1)))
+	require.False(t, Valid("bad id"))
+	require.False(t, Valid("line\nbreak"))
+	require.False(t, Valid("идентификатор"))
+
+	require.Equal(t, "req-42", Accept("req-42"))
+	require.NotEqual(t, "bad id", Accept("bad id"))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	require.Empty(t, FromContext(ctx))
+
+	ctx = NewContext(logger.NewContext(ctx), "req-42")
+	require.Equal(t, "req-42", FromContext(ctx))
+	value, ok := logger.Field(ctx, logger.FieldRequestID)
+	require.True(t, ok)
+	require.Equal(t, "req-42", value)
+}
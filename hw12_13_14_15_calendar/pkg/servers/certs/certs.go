@@ -0,0 +1,227 @@
+// Package certs сертификаты TLS из файлов PEM, перечитываемые при изменении файлов без перезапуска.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+)
+
+// DefaultCheckInterval как часто проверяются изменения файлов.
+const DefaultCheckInterval = 10 * time.Second
+
+var (
+	ErrNoCertificate = errors.New("certificate file is not set")
+	ErrNoKey         = errors.New("key file is not set")
+	ErrCAEmpty       = errors.New("no certificates found in CA file")
+)
+
+// Files файлы PEM: сертификат со своим ключом и корневые сертификаты для проверки другой стороны.
+// Пустые значения допустимы: без сертификата клиент не предъявляет его серверу,
+// без CA сервер не проверяет клиентов, а клиент проверяет сервер по системным корневым.
+type Files struct {
+	Cert string
+	Key  string
+	CA   string
+}
+
+func (f Files) list() []string {
+	var files []string
+	for _, file := range []string{f.Cert, f.Key, f.CA} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// fileStamp признак изменения файла.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Source текущие сертификат и корневые сертификаты. Изменения файлов проверяются при установке
+// соединений не чаще раза в interval, при ошибке чтения остаются прежние сертификаты.
+type Source struct {
+	files    Files
+	interval time.Duration
+	logger   logger.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	stamps  map[string]fileStamp
+	checked time.Time
+}
+
+// NewSource чтение сертификатов, interval <= 0 - проверка при каждом соединении,
+// logger nil - перечитывание не журналируется.
+func NewSource(files Files, interval time.Duration, logger logger.Logger) (*Source, error) {
+	if files.Cert != "" && files.Key == "" {
+		return nil, ErrNoKey
+	}
+	if files.Key != "" && files.Cert == "" {
+		return nil, ErrNoCertificate
+	}
+	s := &Source{files: files, interval: interval, logger: logger}
+	s.stamps = s.stat()
+	cert, pool, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	s.cert, s.pool, s.checked = cert, pool, time.Now()
+	return s, nil
+}
+
+// ServerConfig настройки TLS сервера: сертификат обязателен, сертификаты клиентов проверяются,
+// если заданы корневые, и только если клиент их предъявил - требовать ли их, решает сервер.
+func (s *Source) ServerConfig(nextProtos ...string) (*tls.Config, error) {
+	if s.files.Cert == "" {
+		return nil, ErrNoCertificate
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := s.current()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if pool != nil {
+				config.ClientCAs = pool
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+			return config, nil
+		},
+	}, nil
+}
+
+// ClientConfig настройки TLS клиента. Корневые сертификаты читаются только при создании,
+// так как проверка сервера использует их напрямую; сертификат клиента перечитывается.
+func (s *Source) ClientConfig(serverName string) *tls.Config {
+	_, pool := s.current()
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := s.current()
+			if cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+	}
+}
+
+// current сертификаты после проверки изменений файлов.
+func (s *Source) current() (*tls.Certificate, *x509.CertPool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now := time.Now(); now.Sub(s.checked) >= s.interval {
+		s.checked = now
+		s.reload()
+	}
+	return s.cert, s.pool
+}
+
+func (s *Source) reload() {
+	stamps := s.stat()
+	if sameStamps(s.stamps, stamps) {
+		return
+	}
+	cert, pool, err := load(s.files)
+	if err != nil {
+		// файлы могут обновляться не одновременно, попытка повторится при следующей проверке.
+		if s.logger != nil {
+			s.logger.Error("certificates reload failed, previous ones are used: %s", err.Error())
+		}
+		return
+	}
+	s.cert, s.pool, s.stamps = cert, pool, stamps
+	if s.logger != nil {
+		s.logger.Info("certificates reloaded: %v", s.files.list())
+	}
+}
+
+func (s *Source) stat() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, file := range s.files.list() {
+		if info, err := os.Stat(file); err == nil {
+			stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, stamp := range a {
+		if other, ok := b[file]; !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
+
+func load(files Files) (*tls.Certificate, *x509.CertPool, error) {
+	var (
+		cert *tls.Certificate
+		pool *x509.CertPool
+	)
+	if files.Cert != "" {
+		pair, err := tls.LoadX509KeyPair(files.Cert, files.Key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't load certificate '%s': %w", files.Cert, err)
+		}
+		cert = &pair
+	}
+	if files.CA != "" {
+		data, err := os.ReadFile(files.CA)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, nil, fmt.Errorf("%w '%s'", ErrCAEmpty, files.CA)
+		}
+	}
+	return cert, pool, nil
+}
+
+// Identities идентификаторы владельца сертификата в порядке приоритета: URI (SPIFFE),
+// DNS-имена, адреса e-mail и Common Name.
+func Identities(cert *x509.Certificate) []string {
+	if cert == nil {
+		return nil
+	}
+	var ids []string
+	for _, uri := range cert.URIs {
+		ids = append(ids, uri.String())
+	}
+	ids = append(ids, cert.DNSNames...)
+	ids = append(ids, cert.EmailAddresses...)
+	if cert.Subject.CommonName != "" {
+		ids = append(ids, cert.Subject.CommonName)
+	}
+	return ids
+}
+
+// PeerIdentities идентификаторы проверенного сертификата клиента, пусто - сертификата нет.
+func PeerIdentities(state *tls.ConnectionState) []string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return Identities(state.VerifiedChains[0][0])
+}
@@ -0,0 +1,102 @@
+package certs_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs/certstest"
+)
+
+func leaf(t *testing.T, cert *tls.Certificate) *x509.Certificate {
+	t.Helper()
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestSource(t *testing.T) {
+	ca := certstest.NewCA(t)
+	certFile, keyFile := ca.Issue(t, "calendar", "127.0.0.1")
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := certs.NewSource(certs.Files{Cert: certFile}, 0, nil)
+		require.ErrorIs(t, err, certs.ErrNoKey)
+		_, err = certs.NewSource(certs.Files{Cert: certFile, Key: ca.File}, 0, nil)
+		require.Error(t, err)
+		_, err = certs.NewSource(certs.Files{CA: keyFile}, 0, nil)
+		require.ErrorIs(t, err, certs.ErrCAEmpty)
+
+		source, err := certs.NewSource(certs.Files{CA: ca.File}, 0, nil)
+		require.NoError(t, err)
+		_, err = source.ServerConfig()
+		require.ErrorIs(t, err, certs.ErrNoCertificate)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		source, err := certs.NewSource(certs.Files{Cert: certFile, Key: keyFile, CA: ca.File}, 0, nil)
+		require.NoError(t, err)
+		config, err := source.ServerConfig("h2")
+		require.NoError(t, err)
+		forClient, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		require.Equal(t, tls.VerifyClientCertIfGiven, forClient.ClientAuth)
+		require.Equal(t, []string{"h2"}, forClient.NextProtos)
+		first := leaf(t, &forClient.Certificates[0])
+
+		// сертификат перевыпущен: новое время изменения или размер файлов.
+		time.Sleep(10 * time.Millisecond)
+		ca.Issue(t, "calendar", "127.0.0.1", "calendar.local")
+		forClient, err = config.GetConfigForClient(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		second := leaf(t, &forClient.Certificates[0])
+		require.NotEqual(t, first.SerialNumber, second.SerialNumber)
+		require.Equal(t, []string{"calendar.local"}, second.DNSNames)
+
+		// испорченный файл не заменяет действующий сертификат.
+		require.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0o600))
+		forClient, err = config.GetConfigForClient(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		require.Equal(t, second.SerialNumber, leaf(t, &forClient.Certificates[0]).SerialNumber)
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		certFile, keyFile := ca.Issue(t, "sender")
+		source, err := certs.NewSource(certs.Files{Cert: certFile, Key: keyFile}, time.Hour, nil)
+		require.NoError(t, err)
+		client := source.ClientConfig("calendar")
+		require.Equal(t, "calendar", client.ServerName)
+		first, err := client.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+		ca.Issue(t, "sender")
+		second, err := client.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+		require.Equal(t, leaf(t, first).SerialNumber, leaf(t, second).SerialNumber)
+	})
+}
+
+func TestIdentities(t *testing.T) {
+	ca := certstest.NewCA(t)
+	certFile, _ := ca.Issue(t, "scheduler", "scheduler.internal")
+	data, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	block, _ := pem.Decode(data)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	cert.URIs = []*url.URL{{Scheme: "spiffe", Host: "otus", Path: "/scheduler"}}
+	cert.EmailAddresses = []string{"scheduler@otus.ru"}
+	require.Equal(t, []string{
+		"spiffe://otus/scheduler", "scheduler.internal", "scheduler@otus.ru", "scheduler",
+	}, certs.Identities(cert))
+
+	require.Empty(t, certs.PeerIdentities(nil))
+	require.Empty(t, certs.PeerIdentities(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}))
+	state := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	require.Equal(t, certs.Identities(cert), certs.PeerIdentities(state))
+}
@@ -1,16 +1,121 @@
 package servers
 
+import (
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/certs"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
+)
+
 const (
 	defaultHost = "localhost"
 	defaultPort = 8080
+
+	defaultReadTimeout    = 10 * time.Second
+	defaultWriteTimeout   = 30 * time.Second
+	defaultHandlerTimeout = 30 * time.Second
 )
 
 type CtxKey struct{}
 
 type Config struct {
-	host  string
-	port  int
-	debug bool
+	host string
+	port int
+	// rateLimit лимиты частоты запросов, по умолчанию не ограничены.
+	rateLimit ratelimit.Config
+	timeouts  Timeouts
+	cors      CORS
+	tls       TLS
+}
+
+// Timeouts предельное время обработки запросов, 0 - значение по умолчанию.
+type Timeouts struct {
+	// Read чтение запроса вместе с телом.
+	Read time.Duration
+	// Write запись ответа, отсчитывается от окончания чтения заголовков запроса.
+	Write time.Duration
+	// Handler выполнение обработчика, отдельные маршруты могут задавать свое.
+	Handler time.Duration
+}
+
+// CORS кросс-доменные запросы из браузера, без разрешенных источников не обслуживаются.
+type CORS struct {
+	// AllowedOrigins разрешенные источники, "*" - любой.
+	AllowedOrigins []string
+	// AllowedHeaders заголовки запроса сверх стандартных, "*" - любые запрошенные.
+	AllowedHeaders []string
+	// AllowCredentials передача cookie и заголовка Authorization, с источником "*" в ответе
+	// указывается источник запроса.
+	AllowCredentials bool
+	// MaxAge срок кэширования ответа на предварительный запрос, 0 - не кэшируется.
+	MaxAge time.Duration
+}
+
+func (c CORS) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// TLS шифрование соединений, без файла сертификата сервер принимает соединения без TLS.
+type TLS struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile корневые сертификаты для проверки сертификатов клиентов (mTLS).
+	ClientCAFile string
+	// ReloadInterval как часто проверяются изменения файлов, 0 - certs.DefaultCheckInterval.
+	ReloadInterval time.Duration
+	// ServiceAccounts логины учетных записей по идентификаторам сертификатов клиентов,
+	// см. certs.Identities. Клиент с сертификатом вне списка не авторизуется.
+	ServiceAccounts map[string]string
+	// CertRequired полные имена методов gRPC или их префиксы (/events.Support/), для REST -
+	// префиксы путей (/admin/), вызываемые только с сертификатом клиента.
+	CertRequired []string
+}
+
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Source сертификаты сервера, перечитываемые при изменении файлов.
+func (t TLS) Source(logger logger.Logger) (*certs.Source, error) {
+	interval := t.ReloadInterval
+	if interval <= 0 {
+		interval = certs.DefaultCheckInterval
+	}
+	return certs.NewSource(certs.Files{Cert: t.CertFile, Key: t.KeyFile, CA: t.ClientCAFile}, interval, logger)
+}
+
+// ServiceAccount логин учетной записи, сопоставленной сертификату клиента с идентификаторами ids.
+func (t TLS) ServiceAccount(ids []string) (string, bool) {
+	for _, id := range ids {
+		if login, ok := t.ServiceAccounts[id]; ok {
+			return login, true
+		}
+	}
+	return "", false
+}
+
+// IsCertRequired нужен ли сертификат клиента для вызова метода или пути method.
+func (t TLS) IsCertRequired(method string) bool {
+	for _, prefix := range t.CertRequired {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PeerAccount логин по сертификату клиента соединения: ok - сертификат предъявлен,
+// пустой логин - он не сопоставлен учетной записи.
+func (t TLS) PeerAccount(state *tls.ConnectionState) (login string, ok bool) {
+	ids := certs.PeerIdentities(state)
+	if len(ids) == 0 {
+		return "", false
+	}
+	login, _ = t.ServiceAccount(ids)
+	return login, true
 }
 
 func (cfg Config) GetHost() string {
@@ -27,14 +132,60 @@ func (cfg Config) GetPort() int {
 	return defaultPort
 }
 
-func (cfg Config) IsDebug() bool {
-	return cfg.debug
+func (cfg Config) GetRateLimit() ratelimit.Config {
+	return cfg.rateLimit
+}
+
+// WithRateLimit копия конфигурации с лимитами частоты запросов.
+func (cfg Config) WithRateLimit(rateLimit ratelimit.Config) Config {
+	cfg.rateLimit = rateLimit
+	return cfg
+}
+
+// GetTimeouts предельное время с подставленными значениями по умолчанию.
+func (cfg Config) GetTimeouts() Timeouts {
+	timeouts := cfg.timeouts
+	if timeouts.Read <= 0 {
+		timeouts.Read = defaultReadTimeout
+	}
+	if timeouts.Write <= 0 {
+		timeouts.Write = defaultWriteTimeout
+	}
+	if timeouts.Handler <= 0 {
+		timeouts.Handler = defaultHandlerTimeout
+	}
+	return timeouts
+}
+
+// WithTimeouts копия конфигурации с предельным временем обработки запросов.
+func (cfg Config) WithTimeouts(timeouts Timeouts) Config {
+	cfg.timeouts = timeouts
+	return cfg
+}
+
+func (cfg Config) GetCORS() CORS {
+	return cfg.cors
+}
+
+// WithCORS копия конфигурации с разрешенными кросс-доменными запросами.
+func (cfg Config) WithCORS(cors CORS) Config {
+	cfg.cors = cors
+	return cfg
+}
+
+func (cfg Config) GetTLS() TLS {
+	return cfg.tls
+}
+
+// WithTLS копия конфигурации с шифрованием соединений.
+func (cfg Config) WithTLS(tls TLS) Config {
+	cfg.tls = tls
+	return cfg
 }
 
-func NewConfig(host string, port int, debug bool) Config {
+func NewConfig(host string, port int) Config {
 	return Config{
-		host:  host,
-		port:  port,
-		debug: debug,
+		host: host,
+		port: port,
 	}
 }
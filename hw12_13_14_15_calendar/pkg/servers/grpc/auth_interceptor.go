@@ -2,20 +2,27 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type AuthInterceptor struct {
 	authService servers.AuthService
+	tls         servers.TLS
 }
 
-func NewAuthInterceptor(authService servers.AuthService) *AuthInterceptor {
-	return &AuthInterceptor{authService}
+// NewAuthInterceptor клиент с проверенным сертификатом авторизуется учетной записью,
+// сопоставленной сертификату, остальные - по логину из метаданных authorization.
+func NewAuthInterceptor(authService servers.AuthService, tls servers.TLS) *AuthInterceptor {
+	return &AuthInterceptor{authService: authService, tls: tls}
 }
 
 func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
@@ -25,10 +32,11 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		user, err := i.authorize(ctx)
+		user, err := i.authorize(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
+		ctx = logger.WithField(ctx, logger.FieldUserID, user.ID)
 		ctx = context.WithValue(ctx, servers.CtxKey{}, map[string]string{
 			"id":    user.ID,
 			"name":  user.Name,
@@ -38,7 +46,16 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	}
 }
 
-func (i *AuthInterceptor) authorize(ctx context.Context) (*servers.AuthUser, error) {
+func (i *AuthInterceptor) authorize(ctx context.Context, method string) (*servers.AuthUser, error) {
+	if login, ok := i.tls.PeerAccount(peerTLS(ctx)); ok {
+		if login == "" {
+			return nil, status.Errorf(codes.Unauthenticated, "client certificate is not mapped to a service account")
+		}
+		return i.authorizeLogin(ctx, login)
+	}
+	if i.tls.IsCertRequired(method) {
+		return nil, status.Errorf(codes.Unauthenticated, "client certificate is required")
+	}
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Errorf(codes.Unauthenticated, "metadata is not provided")
@@ -47,7 +64,10 @@ func (i *AuthInterceptor) authorize(ctx context.Context) (*servers.AuthUser, err
 	if len(values) == 0 {
 		return nil, status.Errorf(codes.Unauthenticated, "authorization token is not provided")
 	}
-	userEmail := values[0]
+	return i.authorizeLogin(ctx, values[0])
+}
+
+func (i *AuthInterceptor) authorizeLogin(ctx context.Context, userEmail string) (*servers.AuthUser, error) {
 	user, err := i.authService.Authorize(ctx, userEmail)
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "error authorize: %v", err)
@@ -57,3 +77,16 @@ func (i *AuthInterceptor) authorize(ctx context.Context) (*servers.AuthUser, err
 	}
 	return user, nil
 }
+
+// peerTLS состояние TLS-соединения клиента, nil - соединение без TLS.
+func peerTLS(ctx context.Context) *tls.ConnectionState {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	return &info.State
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorsClientInterceptor клиентский перехватчик: статус ошибки ответа переводится в ошибку
+// errx по деталям статуса, клиент проверяет тип ошибки и код бизнес-логики через errors.As.
+// ResourceExhausted возвращается без изменений, чтобы клиент мог прочитать RetryInfo.
+func ErrorsClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+		st, ok := status.FromError(err)
+		if !ok || st.Code() == codes.ResourceExhausted {
+			return err
+		}
+		return rqres.ToError(st)
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/grpc/rqres"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type LoggerInterceptor struct {
@@ -28,12 +31,22 @@ func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
 	) (interface{}, error) {
 		timeStart := time.Now()
 		meta, ok := metadata.FromIncomingContext(ctx)
-		resp, err := handler(ctx, req)
-		ua := ""
+		var id, ua string
 		if ok {
+			if values := meta.Get(requestid.MetaKey); len(values) > 0 {
+				id = values[0]
+			}
 			ua = strings.Join(meta.Get("user-agent"), " ")
 		}
-		i.logger.Info(
+		id = requestid.Accept(id)
+		ctx = requestid.NewContext(logger.NewContext(ctx), id)
+		// вне gRPC-сервера (вызов обработчика напрямую) заголовок ответа не передается.
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetaKey, id))
+		resp, err := handler(ctx, req)
+		if st, isStatus := status.FromError(err); err != nil && isStatus {
+			err = rqres.WithRequestID(st, id).Err()
+		}
+		logger.FromContext(ctx, i.logger).Info(
 			fmt.Sprintf(
 				"Method: %s\tDuration: %s\tError: %v\tUser-Agent: \"%s\"", info.FullMethod, time.Since(timeStart).String(), err, ua,
 			),
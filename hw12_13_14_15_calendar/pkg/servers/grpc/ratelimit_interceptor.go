@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// readPrefixes префиксы имен методов, не изменяющих данные.
+var readPrefixes = []string{"Get", "List", "Search"}
+
+// RateLimitInterceptor ограничение частоты запросов: Unary по адресу клиента ставится до
+// авторизации, UnaryUser по пользователю - после нее.
+type RateLimitInterceptor struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimitInterceptor(limiter *ratelimit.Limiter) *RateLimitInterceptor {
+	return &RateLimitInterceptor{limiter: limiter}
+}
+
+func (i *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if ok, wait := i.limiter.AllowIP(peerIP(ctx), methodKind(info.FullMethod)); !ok {
+			return nil, resourceExhausted(ctx, wait)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (i *RateLimitInterceptor) UnaryUser() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		user, _ := ctx.Value(servers.CtxKey{}).(map[string]string)
+		if userID := user["id"]; userID != "" {
+			if ok, wait := i.limiter.AllowUser(userID, methodKind(info.FullMethod)); !ok {
+				return nil, resourceExhausted(ctx, wait)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// resourceExhausted время ожидания передается в заголовке retry-after и в деталях RetryInfo.
+func resourceExhausted(ctx context.Context, wait time.Duration) error {
+	retryAfter := ratelimit.RetryAfter(wait)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	st := status.Newf(codes.ResourceExhausted, "слишком много запросов, повторите через %d с", retryAfter)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Duration(retryAfter) * time.Second),
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func methodKind(fullMethod string) ratelimit.Kind {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return ratelimit.Read
+		}
+	}
+	return ratelimit.Write
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDClientInterceptor клиентский перехватчик: идентификатор запроса из контекста вызова
+// передается в метаданных, чтобы записи журнала сервера относились к тому же запросу.
+// Без идентификатора в контексте его создает сервер.
+func RequestIDClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if id := requestid.FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, requestid.MetaKey, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -2,33 +2,214 @@ package rqres
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
 )
 
 /*
-Преобразование err->*status.Status именно в указанном виде применяться не будет, сделано для упращения обработки.
+Статус ошибки дополняется деталями google.rpc: ErrorInfo с типом ошибки (Reason) и кодом
+ошибки бизнес-логики в Metadata, BadRequest с ошибками полей и ResourceInfo с параметрами
+поиска ненайденного объекта. По деталям ToError восстанавливает исходную ошибку errx.
 */
 
+// ErrorDomain домен ошибок в ErrorInfo.
+const ErrorDomain = "calendar.otusgo-hw"
+
+// Типы ошибок в ErrorInfo.Reason.
+const (
+	ReasonLogic    = "LOGIC"
+	ReasonInvalid  = "INVALID"
+	ReasonNotFound = "NOT_FOUND"
+	ReasonPerms    = "PERMISSION_DENIED"
+	ReasonFatal    = "INTERNAL"
+)
+
+// Ключи ErrorInfo.Metadata.
+const (
+	MetaCode      = "code"
+	MetaTitle     = "title"
+	MetaRequestID = "requestId"
+)
+
 func FromError(err error) *status.Status {
 	logErr := errx.Logic{}
 	if errors.As(err, &logErr) {
-		return status.Newf(codes.InvalidArgument, "[%d] %s", logErr.Code(), logErr.Error())
+		st := status.Newf(codes.InvalidArgument, "[%d] %s", logErr.Code(), logErr.Error())
+		return withDetails(st, errorInfo(ReasonLogic, map[string]string{MetaCode: strconv.Itoa(logErr.Code())}))
+	}
+	invErr := errx.Invalid{}
+	if errors.As(err, &invErr) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(invErr.Errors()))
+		for i, namedErr := range invErr.Errors() {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: namedErr.Field, Description: namedErr.Err.Error()}
+		}
+		return withDetails(
+			status.New(codes.InvalidArgument, err.Error()),
+			errorInfo(ReasonInvalid, map[string]string{MetaTitle: invErr.Title()}),
+			&errdetails.BadRequest{FieldViolations: violations},
+		)
 	}
 	nfErr := errx.NotFound{}
 	if errors.As(err, &nfErr) {
-		return status.New(codes.NotFound, nfErr.Error())
+		details := []protoiface.MessageV1{errorInfo(ReasonNotFound, nil)}
+		for _, resource := range resourceInfos(nfErr) {
+			details = append(details, resource)
+		}
+		return withDetails(status.New(codes.NotFound, nfErr.Error()), details...)
 	}
 	base := errx.Base{}
 	if errors.As(err, &base) {
 		switch base.Kind() {
 		case errx.TypePerms:
-			return status.New(codes.PermissionDenied, base.Error())
+			return withDetails(status.New(codes.PermissionDenied, base.Error()), errorInfo(ReasonPerms, nil))
 		case errx.TypeFatal:
-			return status.New(codes.Internal, base.Error())
+			return withDetails(status.New(codes.Internal, base.Error()), errorInfo(ReasonFatal, nil))
+		}
+	}
+	st := status.New(codes.InvalidArgument, err.Error())
+	return withDetails(st, errorInfo(ReasonLogic, map[string]string{MetaCode: strconv.Itoa(http.StatusBadRequest)}))
+}
+
+func errorInfo(reason string, metadata map[string]string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain, Metadata: metadata}
+}
+
+// resourceInfos параметры поиска ненайденного объекта, например {"eventId": ...}.
+func resourceInfos(nfErr errx.NotFound) []*errdetails.ResourceInfo {
+	params := reflect.ValueOf(nfErr.Params)
+	if params.Kind() != reflect.Map {
+		return nil
+	}
+	infos := make([]*errdetails.ResourceInfo, 0, params.Len())
+	for _, key := range params.MapKeys() {
+		infos = append(infos, &errdetails.ResourceInfo{
+			ResourceType: fmt.Sprint(key.Interface()),
+			ResourceName: fmt.Sprint(params.MapIndex(key).Interface()),
+			Description:  nfErr.Error(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ResourceType < infos[j].ResourceType
+	})
+	return infos
+}
+
+// withDetails статус с деталями, если детали добавить не удалось - исходный статус.
+func withDetails(st *status.Status, details ...protoiface.MessageV1) *status.Status {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// WithRequestID статус с идентификатором запроса в ErrorInfo.Metadata, статус без ErrorInfo
+// домена ErrorDomain возвращается без изменений.
+func WithRequestID(st *status.Status, id string) *status.Status {
+	details := make([]protoiface.MessageV1, 0, len(st.Details()))
+	found := false
+	for _, detail := range st.Details() {
+		message, ok := detail.(protoiface.MessageV1)
+		if !ok {
+			return st
+		}
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			metadata := make(map[string]string, len(info.GetMetadata())+1)
+			for key, value := range info.GetMetadata() {
+				metadata[key] = value
+			}
+			metadata[MetaRequestID] = id
+			message = errorInfo(info.GetReason(), metadata)
+			found = true
+		}
+		details = append(details, message)
+	}
+	if !found {
+		return st
+	}
+	return withDetails(status.New(st.Code(), st.Message()), details...)
+}
+
+var logicMessageRe = regexp.MustCompile(`^\[(\d+)\] (.*)$`)
+
+// ToError обратное преобразование статуса в ошибку errx: по деталям ErrorInfo,
+// для статусов без них (например, от grpc-gateway) - по коду.
+func ToError(st *status.Status) error {
+	if st.Code() == codes.OK {
+		return nil
+	}
+	var (
+		info      *errdetails.ErrorInfo
+		badReq    *errdetails.BadRequest
+		resources []*errdetails.ResourceInfo
+	)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == ErrorDomain {
+				info = d
+			}
+		case *errdetails.BadRequest:
+			badReq = d
+		case *errdetails.ResourceInfo:
+			resources = append(resources, d)
+		}
+	}
+	if info == nil {
+		return codeToError(st)
+	}
+	err := errors.New(st.Message())
+	switch info.GetReason() {
+	case ReasonLogic:
+		code, _ := strconv.Atoi(info.GetMetadata()[MetaCode])
+		if matches := logicMessageRe.FindStringSubmatch(st.Message()); matches != nil {
+			err = errors.New(matches[2])
+		}
+		return errx.LogicNew(err, code)
+	case ReasonInvalid:
+		var namedErrs errx.NamedErrors
+		for _, violation := range badReq.GetFieldViolations() {
+			namedErrs.Add(errx.NamedError{Field: violation.GetField(), Err: errors.New(violation.GetDescription())})
+		}
+		return errx.InvalidNew(info.GetMetadata()[MetaTitle], namedErrs)
+	case ReasonNotFound:
+		var params map[string]string
+		if len(resources) > 0 {
+			params = make(map[string]string, len(resources))
+			for _, resource := range resources {
+				params[resource.GetResourceType()] = resource.GetResourceName()
+			}
+		}
+		return errx.NotFoundNew(err, params)
+	case ReasonPerms:
+		return errx.PermsNew(err)
+	}
+	return errx.FatalNew(err)
+}
+
+func codeToError(st *status.Status) error {
+	err := errors.New(st.Message())
+	switch st.Code() { //nolint:exhaustive // остальные коды - внутренние ошибки.
+	case codes.InvalidArgument:
+		if matches := logicMessageRe.FindStringSubmatch(st.Message()); matches != nil {
+			code, _ := strconv.Atoi(matches[1])
+			return errx.LogicNew(errors.New(matches[2]), code)
 		}
+		return errx.LogicNew(err, http.StatusBadRequest)
+	case codes.NotFound, codes.Unimplemented:
+		return errx.NotFoundNew(err, nil)
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return errx.PermsNew(err)
 	}
-	return status.New(codes.InvalidArgument, err.Error())
+	return errx.FatalNew(err)
 }
@@ -1,13 +1,17 @@
 package grpc
 
 import (
+	"crypto/tls"
 	"errors"
 	"net"
 	"strconv"
 
+	"github.com/benbjohnson/clock"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type RegisterHandlerFunc func(s *grpc.Server)
@@ -17,14 +21,37 @@ type Server struct {
 	config      servers.Config
 	Logger      logger.Logger
 	AuthService servers.AuthService
+	// tlsConfig настройки TLS с перечитываемыми сертификатами, готовятся при запуске.
+	tlsConfig *tls.Config
 }
 
 func NewServer(config servers.Config, authSrv servers.AuthService, logger logger.Logger) *Server {
-	unaryChain := grpc.ChainUnaryInterceptor(
-		NewLoggerInterceptor(logger).Unary(),
-		NewAuthInterceptor(authSrv).Unary(),
-	)
-	return &Server{Server: grpc.NewServer(unaryChain), config: config, Logger: logger, AuthService: authSrv}
+	s := &Server{config: config, Logger: logger, AuthService: authSrv}
+	var limits *RateLimitInterceptor
+	if rateLimit := config.GetRateLimit(); rateLimit.Enabled() {
+		limits = NewRateLimitInterceptor(ratelimit.New(rateLimit, clock.New()))
+	}
+	interceptors := []grpc.UnaryServerInterceptor{NewLoggerInterceptor(logger).Unary()}
+	if limits != nil {
+		interceptors = append(interceptors, limits.Unary())
+	}
+	interceptors = append(interceptors, NewAuthInterceptor(authSrv, config.GetTLS()).Unary())
+	if limits != nil {
+		interceptors = append(interceptors, limits.UnaryUser())
+	}
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+	if config.GetTLS().Enabled() {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			GetConfigForClient: s.configForClient,
+		})))
+	}
+	s.Server = grpc.NewServer(opts...)
+	return s
+}
+
+func (s *Server) configForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	return s.tlsConfig.GetConfigForClient(hello)
 }
 
 func (s *Server) RegisterHandler(handlerFunc RegisterHandlerFunc) {
@@ -40,7 +67,23 @@ func (s *Server) Start() error {
 	if err != nil {
 		return err
 	}
-	err = s.Server.Serve(socket)
+	return s.Serve(socket)
+}
+
+// Serve обработка соединений уже открытого сокета, адрес из настроек не используется.
+func (s *Server) Serve(socket net.Listener) error {
+	if tlsCfg := s.config.GetTLS(); tlsCfg.Enabled() {
+		source, err := tlsCfg.Source(s.Logger)
+		if err != nil {
+			_ = socket.Close()
+			return err
+		}
+		if s.tlsConfig, err = source.ServerConfig("h2"); err != nil {
+			_ = socket.Close()
+			return err
+		}
+	}
+	err := s.Server.Serve(socket)
 	if err == nil || errors.Is(err, grpc.ErrServerStopped) {
 		return nil
 	}
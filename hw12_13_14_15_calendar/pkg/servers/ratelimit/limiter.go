@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// gcInterval период удаления заполнившихся корзин: такая корзина равносильна новой.
+const gcInterval = time.Minute
+
+// Kind вид запроса, для чтения и записи ведутся отдельные корзины.
+type Kind int
+
+const (
+	Read Kind = iota
+	Write
+)
+
+// Limit параметры корзины: Rate - пополнение, запросов в секунду, Burst - емкость.
+// Rate <= 0 - без ограничений.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst < 1 {
+		return 1
+	}
+	return float64(l.Burst)
+}
+
+// Budget лимиты для запросов на чтение и запись.
+type Budget struct {
+	Read  Limit `json:"read"`
+	Write Limit `json:"write"`
+}
+
+func (b Budget) limit(kind Kind) Limit {
+	if kind == Write {
+		return b.Write
+	}
+	return b.Read
+}
+
+// Config лимиты для авторизованного пользователя и для адреса клиента.
+type Config struct {
+	PerUser Budget `json:"perUser"`
+	PerIP   Budget `json:"perIp"`
+}
+
+func (c Config) Enabled() bool {
+	return c.PerUser.Read.Enabled() || c.PerUser.Write.Enabled() ||
+		c.PerIP.Read.Enabled() || c.PerIP.Write.Enabled()
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// refill пополнение корзины к моменту now.
+func (b *bucket) refill(now time.Time) float64 {
+	return math.Min(b.limit.capacity(), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+}
+
+// Limiter ограничение частоты запросов алгоритмом token bucket, корзина на каждый ключ.
+type Limiter struct {
+	config Config
+	clock  clock.Clock
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	lastGC  time.Time
+}
+
+func New(config Config, clk clock.Clock) *Limiter {
+	return &Limiter{config: config, clock: clk, buckets: make(map[string]*bucket)}
+}
+
+// AllowIP расходует токен корзины адреса клиента, при отказе возвращает время ожидания.
+func (l *Limiter) AllowIP(ip string, kind Kind) (bool, time.Duration) {
+	return l.allow("ip", ip, kind, l.config.PerIP.limit(kind))
+}
+
+// AllowUser расходует токен корзины пользователя, при отказе возвращает время ожидания.
+func (l *Limiter) AllowUser(userID string, kind Kind) (bool, time.Duration) {
+	return l.allow("user", userID, kind, l.config.PerUser.limit(kind))
+}
+
+func (l *Limiter) allow(scope, key string, kind Kind, limit Limit) (bool, time.Duration) {
+	if !limit.Enabled() {
+		return true, 0
+	}
+	now := l.clock.Now()
+	id := scope + ":" + key
+	if kind == Write {
+		id += ":w"
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.gc(now)
+
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{limit: limit, tokens: limit.capacity(), last: now}
+		l.buckets[id] = b
+	}
+	b.tokens = b.refill(now)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait
+}
+
+func (l *Limiter) gc(now time.Time) {
+	if now.Sub(l.lastGC) < gcInterval {
+		return
+	}
+	l.lastGC = now
+	for id, b := range l.buckets {
+		if b.refill(now) >= b.limit.capacity() {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+// RetryAfter время ожидания в целых секундах для заголовка Retry-After.
+func RetryAfter(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	clk := clock.NewMock()
+	limiter := New(Config{
+		PerUser: Budget{
+			Read:  Limit{Rate: 1, Burst: 3},
+			Write: Limit{Rate: 0.5, Burst: 1},
+		},
+	}, clk)
+
+	t.Run("burst and refill", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			ok, _ := limiter.AllowUser("u1", Read)
+			require.True(t, ok)
+		}
+		ok, wait := limiter.AllowUser("u1", Read)
+		require.False(t, ok)
+		require.Equal(t, time.Second, wait)
+		require.Equal(t, 1, RetryAfter(wait))
+
+		clk.Add(time.Second)
+		ok, _ = limiter.AllowUser("u1", Read)
+		require.True(t, ok)
+	})
+
+	t.Run("separate budgets and keys", func(t *testing.T) {
+		ok, _ := limiter.AllowUser("u1", Write)
+		require.True(t, ok)
+		ok, wait := limiter.AllowUser("u1", Write)
+		require.False(t, ok)
+		require.Equal(t, 2*time.Second, wait)
+
+		ok, _ = limiter.AllowUser("u2", Write)
+		require.True(t, ok)
+		// лимиты по адресу не заданы.
+		for i := 0; i < 10; i++ {
+			ok, _ = limiter.AllowIP("127.0.0.1", Write)
+			require.True(t, ok)
+		}
+	})
+
+	t.Run("full buckets removed", func(t *testing.T) {
+		require.NotEmpty(t, limiter.buckets)
+		clk.Add(gcInterval)
+		ok, _ := limiter.AllowUser("u3", Read)
+		require.True(t, ok)
+		require.Len(t, limiter.buckets, 1)
+	})
+
+	require.False(t, Config{}.Enabled())
+	require.True(t, Config{PerIP: Budget{Write: Limit{Rate: 1}}}.Enabled())
+}
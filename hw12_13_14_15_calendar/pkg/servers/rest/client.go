@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
 )
 
@@ -121,6 +122,9 @@ func (ac *Client) doQuery(ctx context.Context, method, resource string, params i
 	}
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Accept", "application/json")
+	if id := requestid.FromContext(ctx); id != "" {
+		request.Header.Set(requestid.Header, id)
+	}
 
 	if err = ac.authorize(request); err != nil {
 		return nil, err
@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// minCompressSize ответы известной длины меньше этого размера не сжимаются.
+const minCompressSize = 1024
+
+type encoder struct {
+	name      string
+	newWriter func(w io.Writer) io.WriteCloser
+}
+
+// encoders поддерживаемые кодировки в порядке предпочтения сервера.
+var encoders = []encoder{
+	{name: "br", newWriter: func(w io.Writer) io.WriteCloser {
+		return brotli.NewWriterLevel(w, brotli.DefaultCompression)
+	}},
+	{name: "gzip", newWriter: func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	}},
+}
+
+// compressibleTypes типы содержимого, которые имеет смысл сжимать.
+var compressibleTypes = []string{
+	"application/json", "application/x-ndjson", "application/xml", "application/yaml",
+	"application/javascript", "text/",
+}
+
+// compressMiddleware сжатие ответа кодировкой, выбранной по заголовку Accept-Encoding.
+// Решение принимается при записи заголовков ответа: сжимаются только ответы подходящего типа
+// без собственной кодировки и неизвестной длины либо не короче minCompressSize.
+func (s *Server) compressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		enc, ok := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoder: enc}
+		next.ServeHTTP(cw, r)
+		if err := cw.Close(); err != nil {
+			s.Logger.Error("compress %s: %s", enc.name, err.Error())
+		}
+	})
+}
+
+// negotiateEncoding кодировка с наибольшим весом q из Accept-Encoding, при равных весах -
+// предпочтительная для сервера.
+func negotiateEncoding(header string) (encoder, bool) {
+	if header == "" {
+		return encoder{}, false
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+	var (
+		best       encoder
+		bestWeight float64
+	)
+	for _, enc := range encoders {
+		weight, ok := weights[enc.name]
+		if !ok {
+			weight = weights["*"]
+		}
+		if weight > bestWeight {
+			best, bestWeight = enc, weight
+		}
+	}
+	return best, bestWeight > 0
+}
+
+func compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// compressWriter откладывает выбор между сжатием и прямой записью до заголовков ответа.
+type compressWriter struct {
+	http.ResponseWriter
+	encoder encoder
+	writer  io.WriteCloser
+	decided bool
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if !cw.decided {
+		cw.decide(code)
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *compressWriter) decide(code int) {
+	cw.decided = true
+	header := cw.Header()
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" || !compressible(header.Get("Content-Type")) {
+		return
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < minCompressSize {
+		return
+	}
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", cw.encoder.name)
+	cw.writer = cw.encoder.newWriter(cw.ResponseWriter)
+}
+
+func (cw *compressWriter) Write(data []byte) (int, error) {
+	if !cw.decided {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.writer != nil {
+		return cw.writer.Write(data)
+	}
+	return cw.ResponseWriter.Write(data)
+}
+
+// Flush передача клиенту уже сжатых данных, нужна потоковым ответам.
+func (cw *compressWriter) Flush() {
+	if flusher, ok := cw.writer.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *compressWriter) Close() error {
+	if cw.writer == nil {
+		return nil
+	}
+	return cw.writer.Close()
+}
+
+// Unwrap исходный ResponseWriter для http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
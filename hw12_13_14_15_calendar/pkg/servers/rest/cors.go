@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
+)
+
+// routeMethods методы, перечисляемые в ответе на OPTIONS.
+var routeMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// corsHeaders заголовки запроса, разрешенные всегда.
+var corsHeaders = []string{"Authorization", "Content-Type", "Idempotency-Key", requestid.Header}
+
+// corsExposed заголовки ответа, доступные скриптам браузера.
+var corsExposed = []string{requestid.Header, "Retry-After", "Location"}
+
+// corsMiddleware заголовки CORS для разрешенных источников и ответ на OPTIONS с перечнем
+// методов пути, в том числе на предварительные запросы браузера. Запросы OPTIONS к маршрутам,
+// обрабатывающим его сами (например, CalDAV), передаются дальше.
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		if s.cors.Enabled() {
+			header.Add("Vary", "Origin")
+		}
+		origin := s.allowedOrigin(r.Header.Get("Origin"))
+		if origin != "" {
+			header.Set("Access-Control-Allow-Origin", origin)
+			if s.cors.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		if r.Method != http.MethodOptions || s.matches(r, http.MethodOptions) {
+			if origin != "" {
+				header.Set("Access-Control-Expose-Headers", strings.Join(corsExposed, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		var methods []string
+		for _, method := range routeMethods {
+			if s.matches(r, method) {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		allow := strings.Join(append(methods, http.MethodOptions), ", ")
+		header.Set("Allow", allow)
+		if origin != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", allow)
+			header.Set("Access-Control-Allow-Headers", s.allowedHeaders(r))
+			if s.cors.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(s.cors.MaxAge.Seconds())))
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// matches есть ли маршрут для пути запроса с методом method.
+func (s *Server) matches(r *http.Request, method string) bool {
+	probe := r.Clone(r.Context())
+	probe.Method = method
+	var match mux.RouteMatch
+	return s.router.Match(probe, &match) && match.MatchErr == nil
+}
+
+// allowedOrigin значение Access-Control-Allow-Origin, пустое - источник не разрешен.
+// Браузер не принимает "*" в ответе на запрос с учетными данными, поэтому в этом случае
+// возвращается источник запроса.
+func (s *Server) allowedOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	for _, allowed := range s.cors.AllowedOrigins {
+		if allowed == "*" {
+			if s.cors.AllowCredentials {
+				return origin
+			}
+			return "*"
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// allowedHeaders значение Access-Control-Allow-Headers: стандартные и настроенные заголовки,
+// при "*" в настройках - запрошенные браузером.
+func (s *Server) allowedHeaders(r *http.Request) string {
+	headers := append([]string{}, corsHeaders...)
+	for _, allowed := range s.cors.AllowedHeaders {
+		if allowed == "*" {
+			if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				return requested
+			}
+			continue
+		}
+		headers = append(headers, allowed)
+	}
+	return strings.Join(headers, ", ")
+}
@@ -0,0 +1,56 @@
+package rqres
+
+import (
+	"io"
+	"net/http"
+)
+
+// FileResp ответ HTTPCode = 200 содержимым файла для скачивания. Содержимое закрывает сервер
+// после записи ответа.
+type FileResp struct {
+	name        string
+	contentType string
+	size        int64
+	content     io.ReadCloser
+}
+
+// File size < 0 - размер неизвестен.
+func File(name, contentType string, size int64, content io.ReadCloser) *FileResp {
+	return &FileResp{name: name, contentType: contentType, size: size, content: content}
+}
+
+func (res FileResp) GetHTTPCode() int {
+	return http.StatusOK
+}
+
+// GetHTTPResp содержимое передается при записи, см. Content.
+func (res FileResp) GetHTTPResp() interface{} {
+	return nil
+}
+
+func (res FileResp) Success() bool {
+	return true
+}
+
+func (res FileResp) Message() string {
+	return ""
+}
+
+func (res FileResp) Name() string {
+	return res.name
+}
+
+func (res FileResp) ContentType() string {
+	if res.contentType == "" {
+		return "application/octet-stream"
+	}
+	return res.contentType
+}
+
+func (res FileResp) Size() int64 {
+	return res.size
+}
+
+func (res FileResp) Content() io.ReadCloser {
+	return res.content
+}
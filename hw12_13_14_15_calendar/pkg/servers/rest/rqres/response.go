@@ -3,6 +3,7 @@ package rqres
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
@@ -16,12 +17,19 @@ type Response interface {
 	Success() bool
 }
 
+// Traceable ответ, в который сервер добавляет идентификатор запроса.
+type Traceable interface {
+	SetRequestID(id string)
+}
+
 // Base общий вид ответа сервера.
 type Base struct {
 	success bool
 	code    int
 	message string
 	data    interface{}
+	// requestID идентификатор запроса, выводится только в ответах с ошибкой.
+	requestID string
 }
 
 func (res Base) GetHTTPCode() int {
@@ -33,10 +41,11 @@ func (res Base) GetHTTPCode() int {
 
 func (res Base) GetHTTPResp() interface{} {
 	resp := &struct {
-		Status  string      `json:"status"`
-		Code    int         `json:"code"`
-		Message string      `json:"message"`
-		Data    interface{} `json:"data,omitempty"`
+		Status    string      `json:"status"`
+		Code      int         `json:"code"`
+		Message   string      `json:"message"`
+		Data      interface{} `json:"data,omitempty"`
+		RequestID string      `json:"requestId,omitempty"`
 	}{
 		Code:    res.code,
 		Message: res.message,
@@ -46,6 +55,7 @@ func (res Base) GetHTTPResp() interface{} {
 		resp.Status = "success"
 	} else {
 		resp.Status = "error"
+		resp.RequestID = res.requestID
 	}
 	return resp
 }
@@ -63,6 +73,10 @@ func (res *Base) SetData(data interface{}) *Base {
 	return res
 }
 
+func (res *Base) SetRequestID(id string) {
+	res.requestID = id
+}
+
 // OKResp Удачный ответ на запросы (команды) типа POST/PUT/PATCH/DELETE HTTPCode = 200.
 type OKResp struct {
 	Base
@@ -76,7 +90,7 @@ func OK(message string, data interface{}) *OKResp {
 	if message == "" {
 		message = "Успешно"
 	}
-	return &OKResp{Base{true, http.StatusOK, message, data}}
+	return &OKResp{Base{success: true, code: http.StatusOK, message: message, data: data}}
 }
 
 // DataResp Удачный ответ на запрос GET на получение объекта HTTPCode = 200.
@@ -136,7 +150,7 @@ type BadResp struct {
 }
 
 func BadRequest(message string, code int) *BadResp {
-	return &BadResp{Base{false, code, message, nil}}
+	return &BadResp{Base{success: false, code: code, message: message}}
 }
 
 // UnAuthResp Попытка совершить операцию без авторизации HTTPCode = 401.
@@ -151,7 +165,7 @@ func (res UnAuthResp) GetHTTPCode() int {
 func UnAuth() *UnAuthResp {
 	message := "Вы не авторизованы"
 	return &UnAuthResp{
-		Base{false, http.StatusUnauthorized, message, nil},
+		Base{success: false, code: http.StatusUnauthorized, message: message},
 	}
 }
 
@@ -169,7 +183,7 @@ func NotFound(message string, params interface{}) *NotFoundResp {
 		message = "Объект не найден"
 	}
 	return &NotFoundResp{
-		Base{false, http.StatusNotFound, message, params},
+		Base{success: false, code: http.StatusNotFound, message: message, data: params},
 	}
 }
 
@@ -184,14 +198,16 @@ func (res InvalidResp) GetHTTPCode() int {
 
 func (res InvalidResp) GetHTTPResp() interface{} {
 	resp := &struct {
-		Status  string            `json:"status"`
-		Code    int               `json:"code"`
-		Message string            `json:"message"`
-		Errors  map[string]string `json:"errors"`
+		Status    string            `json:"status"`
+		Code      int               `json:"code"`
+		Message   string            `json:"message"`
+		Errors    map[string]string `json:"errors"`
+		RequestID string            `json:"requestId,omitempty"`
 	}{
-		Status:  "error",
-		Code:    res.code,
-		Message: res.message,
+		Status:    "error",
+		Code:      res.code,
+		Message:   res.message,
+		RequestID: res.requestID,
 	}
 	if res.data != nil {
 		resp.Errors = make(map[string]string)
@@ -216,7 +232,27 @@ func Invalid(message string, errs errx.NamedErrors) *InvalidResp {
 		message = "Ошибка при проверке данных"
 	}
 	return &InvalidResp{
-		Base{false, http.StatusUnprocessableEntity, message, errs},
+		Base{success: false, code: http.StatusUnprocessableEntity, message: message, data: errs},
+	}
+}
+
+// TooManyResp Превышен лимит частоты запросов HTTPCode = 429.
+type TooManyResp struct {
+	Base
+}
+
+func (res TooManyResp) GetHTTPCode() int {
+	return http.StatusTooManyRequests
+}
+
+// TooMany retryAfter - через сколько секунд можно повторить запрос.
+func TooMany(retryAfter int) *TooManyResp {
+	message := fmt.Sprintf("Слишком много запросов, повторите через %d с", retryAfter)
+	return &TooManyResp{
+		Base{
+			success: false, code: http.StatusTooManyRequests, message: message,
+			data: map[string]int{"retryAfter": retryAfter},
+		},
 	}
 }
 
@@ -231,21 +267,23 @@ func (res *InternalResp) GetHTTPCode() int {
 
 func (res *InternalResp) GetHTTPResp() interface{} {
 	return &struct {
-		Status  string      `json:"status"`
-		Code    int         `json:"code"`
-		Message string      `json:"message"`
-		Data    interface{} `json:"data"`
+		Status    string      `json:"status"`
+		Code      int         `json:"code"`
+		Message   string      `json:"message"`
+		Data      interface{} `json:"data"`
+		RequestID string      `json:"requestId,omitempty"`
 	}{
-		Status:  "error",
-		Code:    res.code,
-		Message: "Внутренняя ошибка",
-		Data:    res.data,
+		Status:    "error",
+		Code:      res.code,
+		Message:   "Внутренняя ошибка",
+		Data:      res.data,
+		RequestID: res.requestID,
 	}
 }
 
 func Internal(message string) *InternalResp {
 	return &InternalResp{
-		Base{false, http.StatusInternalServerError, message, nil},
+		Base{success: false, code: http.StatusInternalServerError, message: message},
 	}
 }
 
@@ -275,6 +313,14 @@ func FromError(err error) Response {
 	return BadRequest(err.Error(), http.StatusBadRequest)
 }
 
+// WithRequestID добавляет идентификатор запроса в ответ, выводится он только при ошибке.
+func WithRequestID(resp Response, id string) Response {
+	if traceable, ok := resp.(Traceable); ok {
+		traceable.SetRequestID(id)
+	}
+	return resp
+}
+
 func ParseResponse(status int, data []byte, dataObj interface{}, reqResp bool) error {
 	var resp struct {
 		Status  string            `json:"status"`
@@ -0,0 +1,113 @@
+package rqres
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// StreamFormat формат потокового ответа.
+type StreamFormat int
+
+const (
+	// NDJSON объекты по одному в строке, application/x-ndjson.
+	NDJSON StreamFormat = iota
+	// JSONArray JSON-массив, передаваемый частями.
+	JSONArray
+)
+
+// Emit передача клиенту очередного элемента потокового ответа.
+type Emit func(item interface{}) error
+
+// Producer формирует элементы потокового ответа, ошибка Emit (клиент отключился,
+// истекло время) должна прекращать формирование.
+type Producer func(ctx context.Context, emit Emit) error
+
+// StreamResp потоковый ответ HTTPCode = 200: элементы записываются по мере формирования
+// и не накапливаются в памяти. Ошибка после начала записи код ответа уже не меняет: в NDJSON
+// она передается последней строкой в общей обертке, JSON-массив остается незакрытым.
+type StreamResp struct {
+	format    StreamFormat
+	produce   Producer
+	requestID string
+}
+
+func Stream(format StreamFormat, produce Producer) *StreamResp {
+	return &StreamResp{format: format, produce: produce}
+}
+
+func (res StreamResp) GetHTTPCode() int {
+	return http.StatusOK
+}
+
+// GetHTTPResp содержимое формируется при записи, см. WriteTo.
+func (res StreamResp) GetHTTPResp() interface{} {
+	return nil
+}
+
+func (res StreamResp) Success() bool {
+	return true
+}
+
+func (res StreamResp) Message() string {
+	return ""
+}
+
+func (res *StreamResp) SetRequestID(id string) {
+	res.requestID = id
+}
+
+func (res StreamResp) Format() StreamFormat {
+	return res.format
+}
+
+func (res StreamResp) ContentType() string {
+	if res.format == NDJSON {
+		return "application/x-ndjson; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// WriteTo запись элементов в w, после каждого элемента вызывается flush.
+func (res StreamResp) WriteTo(ctx context.Context, w io.Writer, flush func()) error {
+	enc := json.NewEncoder(w)
+	count := 0
+	emit := func(item interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if res.format == JSONArray {
+			separator := ","
+			if count == 0 {
+				separator = "["
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+		count++
+		flush()
+		return nil
+	}
+	if err := res.produce(ctx, emit); err != nil {
+		if res.format == NDJSON {
+			_ = enc.Encode(WithRequestID(FromError(err), res.requestID).GetHTTPResp())
+			flush()
+		}
+		return err
+	}
+	if res.format == JSONArray {
+		closing := "]\n"
+		if count == 0 {
+			closing = "[]\n"
+		}
+		if _, err := io.WriteString(w, closing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
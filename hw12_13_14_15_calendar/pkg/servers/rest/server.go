@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net"
 	"net/http"
 	"runtime/debug"
@@ -12,10 +14,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/felixge/httpsnoop"
 	"github.com/gorilla/mux"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/requestid"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/ratelimit"
 	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
 	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
 )
@@ -26,105 +31,295 @@ type Server struct {
 	Logger      logger.Logger
 	AuthService servers.AuthService
 	router      *mux.Router
+	// public шаблоны путей, доступных без авторизации.
+	public map[string]struct{}
+	// limiter ограничение частоты запросов, nil - без ограничений.
+	limiter  *ratelimit.Limiter
+	timeouts servers.Timeouts
+	cors     servers.CORS
+	tls      servers.TLS
+	// handler маршрутизатор с обработкой, не зависящей от маршрута: журнал, CORS, сжатие.
+	handler http.Handler
+}
+
+// Route зарегистрированный маршрут: метод и шаблон пути.
+type Route struct {
+	Method string
+	Path   string
 }
 
 type HandlerFunc func(r *rs.Request) rs.Response
 
+// RouteOption настройка маршрута при регистрации.
+type RouteOption func(*routeConfig)
+
+type routeConfig struct {
+	timeout time.Duration
+}
+
+// WithTimeout предельное время обработки запроса маршрута вместо общего, на это же время
+// продлеваются чтение запроса и запись ответа. Нужно для выгрузок и потоковых ответов.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(rc *routeConfig) {
+		rc.timeout = timeout
+	}
+}
+
+// deadlineGrace запас к времени обработки маршрута на запись ответа.
+const deadlineGrace = 5 * time.Second
+
 func NewServer(cfg servers.Config, authSrv servers.AuthService, logger logger.Logger) *Server {
 	listenAddress := net.JoinHostPort(cfg.GetHost(), strconv.Itoa(cfg.GetPort()))
 	s := &Server{
 		Logger:      logger,
 		AuthService: authSrv,
 		router:      mux.NewRouter(),
+		public:      make(map[string]struct{}),
+		timeouts:    cfg.GetTimeouts(),
+		cors:        cfg.GetCORS(),
+		tls:         cfg.GetTLS(),
+	}
+	if rateLimit := cfg.GetRateLimit(); rateLimit.Enabled() {
+		s.limiter = ratelimit.New(rateLimit, clock.New())
 	}
 	s.router.Use(
-		s.loggingMiddleware,
+		s.ipLimitMiddleware,
 		s.authMiddleware,
+		s.userLimitMiddleware,
 	)
+	// журнал и CORS охватывают и запросы без маршрута: 404, 405 и ответы на OPTIONS.
+	s.handler = s.loggingMiddleware(s.corsMiddleware(s.compressMiddleware(s.router)))
 	s.Server = http.Server{
 		Addr:              listenAddress,
-		Handler:           s.router,
-		ReadTimeout:       1 * time.Second,
-		WriteTimeout:      1 * time.Second,
+		Handler:           s.handler,
+		ReadTimeout:       s.timeouts.Read,
+		WriteTimeout:      s.timeouts.Write,
 		ReadHeaderTimeout: 2 * time.Second,
 	}
 	return s
 }
 
 func (s *Server) Start() error {
-	err := s.Server.ListenAndServe()
+	var err error
+	if s.tls.Enabled() {
+		err = s.listenAndServeTLS()
+	} else {
+		err = s.Server.ListenAndServe()
+	}
 	if err == nil || errors.Is(err, http.ErrServerClosed) {
 		return nil
 	}
 	return err
 }
 
+// listenAndServeTLS сертификаты перечитываются при изменении файлов, поэтому передаются
+// через TLSConfig, а не именами файлов.
+func (s *Server) listenAndServeTLS() error {
+	source, err := s.tls.Source(s.Logger)
+	if err != nil {
+		return err
+	}
+	if s.Server.TLSConfig, err = source.ServerConfig("h2", "http/1.1"); err != nil {
+		return err
+	}
+	return s.Server.ListenAndServeTLS("", "")
+}
+
 func (s *Server) Stop(ctx context.Context) error {
 	return s.Server.Shutdown(ctx)
 }
 
-func (s *Server) GET(pattern string, handler HandlerFunc) {
-	s.router.HandleFunc(pattern, s.wrapHandler(handler)).Methods("GET")
+// GET обработчик GET-запросов, он же отвечает на HEAD: тело ответа не передается.
+func (s *Server) GET(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodGet, http.MethodHead)
 }
 
-func (s *Server) POST(pattern string, handler HandlerFunc) {
-	s.router.HandleFunc(pattern, s.wrapHandler(handler)).Methods("POST")
+func (s *Server) HEAD(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodHead)
 }
 
-func (s *Server) PUT(pattern string, handler HandlerFunc) {
-	s.router.HandleFunc(pattern, s.wrapHandler(handler)).Methods("PUT")
+func (s *Server) POST(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodPost)
 }
 
-func (s *Server) DELETE(pattern string, handler HandlerFunc) {
-	s.router.HandleFunc(pattern, s.wrapHandler(handler)).Methods("DELETE")
+func (s *Server) PUT(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodPut)
+}
+
+func (s *Server) PATCH(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodPatch)
+}
+
+func (s *Server) DELETE(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodDelete)
+}
+
+// OPTIONS собственный обработчик OPTIONS, без него сервер отвечает перечнем методов пути.
+func (s *Server) OPTIONS(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	s.handle(pattern, handler, opts, http.MethodOptions)
+}
+
+func (s *Server) handle(pattern string, handler HandlerFunc, opts []RouteOption, methods ...string) {
+	var route routeConfig
+	for _, opt := range opts {
+		opt(&route)
+	}
+	s.router.HandleFunc(pattern, s.wrapHandler(handler, route)).Methods(methods...)
+}
+
+// Mount подключает обработчик всех методов для путей с префиксом prefix, ответ формирует сам обработчик.
+func (s *Server) Mount(prefix string, handler http.Handler) {
+	s.router.PathPrefix(prefix).Handler(handler)
+}
+
+// Public подключает обработчик GET-запросов, доступный без авторизации, например документацию.
+func (s *Server) Public(pattern string, handler http.Handler) {
+	s.public[pattern] = struct{}{}
+	s.router.Handle(pattern, handler).Methods("GET", "HEAD")
+}
+
+// Routes маршруты с явно заданными методами, подключенные через Mount не включаются.
+func (s *Server) Routes() []Route {
+	var routes []Route
+	_ = s.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil //nolint:nilerr // маршрут без пути пропускается.
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil //nolint:nilerr // маршрут без методов пропускается.
+		}
+		for _, method := range methods {
+			routes = append(routes, Route{Method: method, Path: path})
+		}
+		return nil
+	})
+	return routes
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	s.router.ServeHTTP(w, r)
+	s.handler.ServeHTTP(w, r)
 }
 
-func (s *Server) wrapHandler(handler HandlerFunc) http.HandlerFunc {
+func (s *Server) wrapHandler(handler HandlerFunc, route routeConfig) http.HandlerFunc {
 	return func(writer http.ResponseWriter, rq *http.Request) {
 		var response rs.Response
-		if rq.Method == "OPTIONS" {
-			writer.WriteHeader(http.StatusOK)
-			return
+		timeout := s.timeouts.Handler
+		if route.timeout > 0 {
+			timeout = route.timeout
+			extendDeadlines(writer, timeout+deadlineGrace)
 		}
-		ctx, cancel := context.WithTimeout(rq.Context(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(rq.Context(), timeout)
 		defer cancel()
 		rq = rq.WithContext(ctx)
 		request := &rs.Request{Request: rq, Params: mux.Vars(rq)}
-		doneChan := make(chan bool)
+		// буфер, чтобы обработчик, завершившийся после таймаута, не блокировался.
+		doneChan := make(chan rs.Response, 1)
 		go func() {
 			defer func() {
 				if r := recover(); r != nil {
 					err := fmt.Errorf("%+v\n%+v", r, string(debug.Stack()))
-					s.Logger.Error(err.Error())
-					response = rs.FromError(errx.FatalNew(err))
-					close(doneChan)
+					logger.FromContext(ctx, s.Logger).Error(err.Error())
+					doneChan <- rs.FromError(errx.FatalNew(err))
 				}
 			}()
-			response = handler(request)
-			close(doneChan)
+			doneChan <- handler(request)
 		}()
 		select {
 		case <-request.Context().Done():
 			err := errors.New("abort in timeout")
 			response = rs.FromError(errx.FatalNew(err))
-			s.Logger.Error("Abort in timeout (%ds). %s %s, ", 30, request.Method, request.URL)
-		case <-doneChan:
+			logger.FromContext(ctx, s.Logger).Error(
+				"Abort in timeout (%s). %s %s, ", timeout.String(), request.Method, request.URL,
+			)
+			go closeLate(doneChan)
+		case response = <-doneChan:
 		}
-		s.showResponse(writer, response)
+		s.showResponse(writer, rq, response)
 	}
 }
 
-func (s *Server) showResponse(w http.ResponseWriter, resp rs.Response) {
+// extendDeadlines продление чтения запроса и записи ответа сверх общих ограничений сервера,
+// если ResponseWriter это не поддерживает - ограничения остаются прежними.
+func extendDeadlines(w http.ResponseWriter, timeout time.Duration) {
+	controller := http.NewResponseController(w)
+	deadline := time.Now().Add(timeout)
+	_ = controller.SetReadDeadline(deadline)
+	_ = controller.SetWriteDeadline(deadline)
+}
+
+// showResponse вывод ответа, в ответ с ошибкой добавляется идентификатор запроса.
+func (s *Server) showResponse(w http.ResponseWriter, r *http.Request, resp rs.Response) {
+	resp = rs.WithRequestID(resp, requestid.FromContext(r.Context()))
+	if stream, ok := resp.(*rs.StreamResp); ok {
+		s.showStream(w, r, stream)
+		return
+	}
+	if file, ok := resp.(*rs.FileResp); ok {
+		s.showFile(w, r, file)
+		return
+	}
+	data, _ := json.Marshal(resp.GetHTTPResp())
 	w.Header().Set("Content-type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
 	w.WriteHeader(resp.GetHTTPCode())
-	data, _ := json.Marshal(resp.GetHTTPResp())
 	_, _ = w.Write(data)
 }
 
+// showStream запись потокового ответа частями. Оборванный из-за ошибки JSON-массив не должен
+// выглядеть полным, поэтому соединение прерывается без завершающего блока.
+func (s *Server) showStream(w http.ResponseWriter, r *http.Request, stream *rs.StreamResp) {
+	w.Header().Set("Content-type", stream.ContentType())
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(stream.GetHTTPCode())
+	if r.Method == http.MethodHead {
+		return
+	}
+	controller := http.NewResponseController(w)
+	err := stream.WriteTo(r.Context(), w, func() {
+		_ = controller.Flush()
+	})
+	if err == nil {
+		return
+	}
+	logger.FromContext(r.Context(), s.Logger).Error("stream %s %s: %s", r.Method, r.URL, err.Error())
+	if stream.Format() == rs.JSONArray {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// showFile запись файла для скачивания: браузер не должен открывать его как страницу сайта.
+func (s *Server) showFile(w http.ResponseWriter, r *http.Request, file *rs.FileResp) {
+	content := file.Content()
+	defer func() {
+		_ = content.Close()
+	}()
+	w.Header().Set("Content-type", file.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": file.Name(),
+	}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	if file.Size() >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(file.Size(), 10))
+	}
+	w.WriteHeader(file.GetHTTPCode())
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, content); err != nil {
+		logger.FromContext(r.Context(), s.Logger).Error("file %s %s: %s", r.Method, r.URL, err.Error())
+	}
+}
+
+// closeLate освобождает содержимое ответа обработчика, завершившегося после таймаута.
+func closeLate(doneChan <-chan rs.Response) {
+	if file, ok := (<-doneChan).(*rs.FileResp); ok {
+		_ = file.Content().Close()
+	}
+}
+
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		var statusCode int
@@ -137,8 +332,12 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 				}
 			},
 		})
+		// поля логгера, добавленные обработчиками запроса (пользователь), попадут в итоговую запись.
+		id := requestid.Accept(req.Header.Get(requestid.Header))
+		req = req.WithContext(requestid.NewContext(logger.NewContext(req.Context()), id))
+		w.Header().Set(requestid.Header, id)
 		next.ServeHTTP(wrapped, req)
-		s.Logger.Info(
+		logger.FromContext(req.Context(), s.Logger).Info(
 			fmt.Sprintf(
 				"%s %s %s %s %d %s \"%s\"",
 				strings.Split(req.RemoteAddr, ":")[0],
@@ -155,19 +354,34 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.isPublic(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		ctx := r.Context()
-		authCredentials := r.Header.Get("Authorization")
-		user, err := s.AuthService.Authorize(ctx, authCredentials)
+		login := authLogin(r)
+		// клиент с сертификатом авторизуется только сопоставленной ему учетной записью.
+		if account, ok := s.tls.PeerAccount(r.TLS); ok {
+			login = account
+		} else if s.tls.IsCertRequired(r.URL.Path) {
+			response := rs.FromError(errx.PermsNew(fmt.Errorf("требуется сертификат клиента")))
+			s.showResponse(w, r, response)
+			return
+		}
+		user, err := s.AuthService.Authorize(ctx, login)
 		if err != nil {
 			response := rs.FromError(errx.FatalNew(fmt.Errorf("error auth-service: %w", err)))
-			s.showResponse(w, response)
+			s.showResponse(w, r, response)
 			return
 		}
 		if user == nil {
+			// клиенты, использующие HTTP Basic (например, CalDAV), запрашивают учетные данные по заголовку.
+			w.Header().Set("WWW-Authenticate", `Basic realm="calendar", charset="UTF-8"`)
 			response := rs.FromError(errx.PermsNew(fmt.Errorf("нет доступа")))
-			s.showResponse(w, response)
+			s.showResponse(w, r, response)
 			return
 		}
+		ctx = logger.WithField(ctx, logger.FieldUserID, user.ID)
 		r = r.WithContext(context.WithValue(ctx, servers.CtxKey{}, map[string]string{ //nolint:go-staticcheck // fdddd
 			"id":    user.ID,
 			"name":  user.Name,
@@ -176,3 +390,76 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// ipLimitMiddleware лимит по адресу клиента проверяется до авторизации.
+func (s *Server) ipLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limiter != nil {
+			if ok, wait := s.limiter.AllowIP(clientIP(r), requestKind(r)); !ok {
+				s.tooManyRequests(w, r, wait)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) userLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limiter != nil {
+			user, _ := r.Context().Value(servers.CtxKey{}).(map[string]string)
+			if userID := user["id"]; userID != "" {
+				if ok, wait := s.limiter.AllowUser(userID, requestKind(r)); !ok {
+					s.tooManyRequests(w, r, wait)
+					return
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) tooManyRequests(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	retryAfter := ratelimit.RetryAfter(wait)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	s.showResponse(w, r, rs.TooMany(retryAfter))
+}
+
+// requestKind запросы, не изменяющие данные, расходуют бюджет чтения.
+func requestKind(r *http.Request) ratelimit.Kind {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", "REPORT":
+		return ratelimit.Read
+	}
+	return ratelimit.Write
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (s *Server) isPublic(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	_, ok := s.public[path]
+	return ok
+}
+
+// authLogin логин из заголовка Authorization: при схеме Basic - имя пользователя,
+// иначе значение заголовка целиком. Пароль сервисом авторизации не проверяется.
+func authLogin(r *http.Request) string {
+	if login, _, ok := r.BasicAuth(); ok {
+		return login
+	}
+	return r.Header.Get("Authorization")
+}
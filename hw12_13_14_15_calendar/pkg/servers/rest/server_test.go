@@ -0,0 +1,304 @@
+package rest
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/logger"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers"
+	rs "github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/servers/rest/rqres"
+	"github.com/vitermakov/otusgo-hw/hw12_13_14_15_calendar/pkg/utils/errx"
+)
+
+// allowAll сервис авторизации, пропускающий любой запрос.
+type allowAll struct{}
+
+func (allowAll) Authorize(context.Context, string) (*servers.AuthUser, error) {
+	return &servers.AuthUser{ID: "user"}, nil
+}
+
+func newTestServer(t *testing.T, cfg servers.Config, register func(s *Server)) *httptest.Server {
+	t.Helper()
+	logs, err := logger.NewLogrus(logger.Config{Level: logger.LevelFatal})
+	require.NoError(t, err)
+	s := NewServer(cfg, allowAll{}, logs)
+	register(s)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// doRequest запрос без автоматической распаковки ответа.
+func doRequest(t *testing.T, method, url string, header map[string]string) (*http.Response, []byte) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	require.NoError(t, err)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	body, _ := io.ReadAll(res.Body)
+	return res, body
+}
+
+func items(n int) rs.Producer {
+	return func(ctx context.Context, emit rs.Emit) error {
+		for i := 0; i < n; i++ {
+			if err := emit(map[string]int{"n": i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestStream(t *testing.T) {
+	errBroken := errx.LogicNew(errors.New("источник недоступен"), 1000)
+	server := newTestServer(t, servers.Config{}, func(s *Server) {
+		s.GET("/ndjson", func(*rs.Request) rs.Response { return rs.Stream(rs.NDJSON, items(3)) })
+		s.GET("/array", func(*rs.Request) rs.Response { return rs.Stream(rs.JSONArray, items(3)) })
+		s.GET("/empty", func(*rs.Request) rs.Response { return rs.Stream(rs.JSONArray, items(0)) })
+		s.GET("/broken", func(*rs.Request) rs.Response {
+			return rs.Stream(rs.NDJSON, func(ctx context.Context, emit rs.Emit) error {
+				_ = emit(map[string]int{"n": 0})
+				return errBroken
+			})
+		})
+		s.GET("/broken-array", func(*rs.Request) rs.Response {
+			return rs.Stream(rs.JSONArray, func(ctx context.Context, emit rs.Emit) error {
+				_ = emit(map[string]int{"n": 0})
+				return errBroken
+			})
+		})
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		res, body := doRequest(t, http.MethodGet, server.URL+"/ndjson", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "application/x-ndjson; charset=utf-8", res.Header.Get("Content-Type"))
+		require.Equal(t, []string{"chunked"}, res.TransferEncoding)
+		require.Equal(t, "{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n", string(body))
+	})
+
+	t.Run("array", func(t *testing.T) {
+		_, body := doRequest(t, http.MethodGet, server.URL+"/array", nil)
+		var result []map[string]int
+		require.NoError(t, json.Unmarshal(body, &result))
+		require.Len(t, result, 3)
+		_, body = doRequest(t, http.MethodGet, server.URL+"/empty", nil)
+		require.JSONEq(t, "[]", string(body))
+	})
+
+	t.Run("head", func(t *testing.T) {
+		res, body := doRequest(t, http.MethodHead, server.URL+"/ndjson", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, body)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		res, body := doRequest(t, http.MethodGet, server.URL+"/broken", map[string]string{"X-Request-ID": "req-1"})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		require.Len(t, lines, 2)
+		require.JSONEq(t, `{"status": "error", "code": 1000, "message": "источник недоступен", "requestId": "req-1"}`,
+			lines[1])
+
+		// незакрытый массив не должен выглядеть полным ответом.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/broken-array", nil)
+		require.NoError(t, err)
+		res, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		_, err = io.ReadAll(res.Body)
+		require.Error(t, err)
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for header, expected := range map[string]string{
+		"":                        "",
+		"gzip":                    "gzip",
+		"gzip, br":                "br",
+		"br;q=0.5, gzip":          "gzip",
+		"*":                       "br",
+		"br;q=0, *;q=0.1":         "gzip",
+		"identity":                "",
+		"gzip;q=0, br;q=0":        "",
+		"deflate, GZIP;q=0.8":     "gzip",
+		"br;q=bad, gzip;q=0.1, *": "br",
+	} {
+		enc, ok := negotiateEncoding(header)
+		require.Equal(t, expected != "", ok, header)
+		require.Equal(t, expected, enc.name, header)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	server := newTestServer(t, servers.Config{}, func(s *Server) {
+		s.GET("/large", func(*rs.Request) rs.Response { return rs.Data(strings.Repeat("данные ", 500)) })
+		s.GET("/small", func(*rs.Request) rs.Response { return rs.Data("данные") })
+		s.GET("/stream", func(*rs.Request) rs.Response { return rs.Stream(rs.NDJSON, items(3)) })
+	})
+
+	res, body := doRequest(t, http.MethodGet, server.URL+"/large", map[string]string{"Accept-Encoding": "gzip"})
+	require.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+	require.Contains(t, res.Header.Values("Vary"), "Accept-Encoding")
+	gz, err := gzip.NewReader(strings.NewReader(string(body)))
+	require.NoError(t, err)
+	plain, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, `"`+strings.Repeat("данные ", 500)+`"`, string(plain))
+
+	res, body = doRequest(t, http.MethodGet, server.URL+"/stream", map[string]string{"Accept-Encoding": "br"})
+	require.Equal(t, "br", res.Header.Get("Content-Encoding"))
+	plain, err = io.ReadAll(brotli.NewReader(strings.NewReader(string(body))))
+	require.NoError(t, err)
+	require.Equal(t, "{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n", string(plain))
+
+	// короткие ответы и ответы без Accept-Encoding не сжимаются.
+	res, body = doRequest(t, http.MethodGet, server.URL+"/small", map[string]string{"Accept-Encoding": "gzip"})
+	require.Empty(t, res.Header.Get("Content-Encoding"))
+	require.Equal(t, `"данные"`, string(body))
+	res, _ = doRequest(t, http.MethodGet, server.URL+"/large", nil)
+	require.Empty(t, res.Header.Get("Content-Encoding"))
+}
+
+func TestRouteMethods(t *testing.T) {
+	ok := func(message string) HandlerFunc {
+		return func(*rs.Request) rs.Response { return rs.OK(message, nil) }
+	}
+	cors := servers.CORS{AllowedOrigins: []string{"https://app.example"}, MaxAge: 10 * time.Minute}
+	server := newTestServer(t, servers.Config{}.WithCORS(cors), func(s *Server) {
+		s.GET("/items", ok("get"))
+		s.PATCH("/items", ok("patch"))
+		s.DELETE("/items", ok("delete"))
+		s.OPTIONS("/custom", ok("options"))
+	})
+
+	t.Run("head and patch", func(t *testing.T) {
+		res, body := doRequest(t, http.MethodHead, server.URL+"/items", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, body)
+		res, body = doRequest(t, http.MethodPatch, server.URL+"/items", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, string(body), "patch")
+		res, _ = doRequest(t, http.MethodPut, server.URL+"/items", nil)
+		require.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		res, _ := doRequest(t, http.MethodOptions, server.URL+"/items", nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		require.Equal(t, "GET, HEAD, PATCH, DELETE, OPTIONS", res.Header.Get("Allow"))
+		require.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+
+		res, body := doRequest(t, http.MethodOptions, server.URL+"/custom", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, string(body), "options")
+
+		res, _ = doRequest(t, http.MethodOptions, server.URL+"/unknown", nil)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("cors", func(t *testing.T) {
+		res, _ := doRequest(t, http.MethodOptions, server.URL+"/items", map[string]string{
+			"Origin":                         "https://app.example",
+			"Access-Control-Request-Method":  "PATCH",
+			"Access-Control-Request-Headers": "Content-Type",
+		})
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		require.Equal(t, "https://app.example", res.Header.Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "GET, HEAD, PATCH, DELETE, OPTIONS", res.Header.Get("Access-Control-Allow-Methods"))
+		require.Contains(t, res.Header.Get("Access-Control-Allow-Headers"), "Content-Type")
+		require.Equal(t, "600", res.Header.Get("Access-Control-Max-Age"))
+
+		res, _ = doRequest(t, http.MethodGet, server.URL+"/items", map[string]string{"Origin": "https://app.example"})
+		require.Equal(t, "https://app.example", res.Header.Get("Access-Control-Allow-Origin"))
+		require.Contains(t, res.Header.Get("Access-Control-Expose-Headers"), "X-Request-ID")
+
+		res, _ = doRequest(t, http.MethodGet, server.URL+"/items", map[string]string{"Origin": "https://evil.example"})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("any origin", func(t *testing.T) {
+		cors := servers.CORS{AllowedOrigins: []string{"*"}}
+		server := newTestServer(t, servers.Config{}.WithCORS(cors), func(s *Server) {
+			s.GET("/items", ok("get"))
+		})
+		res, _ := doRequest(t, http.MethodGet, server.URL+"/items", map[string]string{"Origin": "https://app.example"})
+		require.Equal(t, "*", res.Header.Get("Access-Control-Allow-Origin"))
+
+		// с учетными данными источник запроса возвращается явно.
+		cors.AllowCredentials = true
+		server = newTestServer(t, servers.Config{}.WithCORS(cors), func(s *Server) {
+			s.GET("/items", ok("get"))
+		})
+		res, _ = doRequest(t, http.MethodGet, server.URL+"/items", map[string]string{"Origin": "https://app.example"})
+		require.Equal(t, "https://app.example", res.Header.Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "true", res.Header.Get("Access-Control-Allow-Credentials"))
+		require.Contains(t, res.Header.Values("Vary"), "Origin")
+	})
+}
+
+func TestRouteTimeout(t *testing.T) {
+	slow := func(*rs.Request) rs.Response {
+		time.Sleep(100 * time.Millisecond)
+		return rs.OK("done", nil)
+	}
+	cfg := servers.Config{}.WithTimeouts(servers.Timeouts{Handler: 20 * time.Millisecond, Write: 50 * time.Millisecond})
+	server := newTestServer(t, cfg, func(s *Server) {
+		s.GET("/slow", slow)
+		s.GET("/export", slow, WithTimeout(time.Second))
+		s.GET("/stream", func(*rs.Request) rs.Response {
+			return rs.Stream(rs.NDJSON, func(ctx context.Context, emit rs.Emit) error {
+				for i := 0; i < 3; i++ {
+					time.Sleep(30 * time.Millisecond)
+					if err := emit(i); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}, WithTimeout(time.Second))
+	})
+
+	res, _ := doRequest(t, http.MethodGet, server.URL+"/slow", nil)
+	require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+
+	res, body := doRequest(t, http.MethodGet, server.URL+"/export", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Contains(t, string(body), "done")
+
+	// поток дольше общего времени записи сервера.
+	res, body = doRequest(t, http.MethodGet, server.URL+"/stream", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	lines := 0
+	for scanner.Scan() {
+		lines++
+	}
+	require.Equal(t, 3, lines)
+}
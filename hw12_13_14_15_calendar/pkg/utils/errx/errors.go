@@ -74,6 +74,10 @@ func (err Invalid) Error() string {
 	return fmt.Sprintf("%s: %s", err.title, err.errors.Error())
 }
 
+func (err Invalid) Title() string {
+	return err.title
+}
+
 func (err Invalid) Errors() []NamedError {
 	return err.errors
 }
@@ -1,6 +1,7 @@
 package errx
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -14,6 +15,10 @@ func (ve NamedError) Error() string {
 	return fmt.Sprintf("{%s} - %s", ve.Field, ve.Err.Error())
 }
 
+func (ve NamedError) Unwrap() error {
+	return ve.Err
+}
+
 type NamedErrors []NamedError
 
 func (vs NamedErrors) Error() string {
@@ -22,14 +27,24 @@ func (vs NamedErrors) Error() string {
 	}
 	err := strings.Builder{}
 	for i, ve := range vs {
-		err.WriteString(ve.Error())
 		if i > 0 {
 			err.WriteString("; ")
 		}
+		err.WriteString(ve.Error())
 	}
 	return err.String()
 }
 
+// Is для errors.Is: совпадение с ошибкой любого из полей.
+func (vs NamedErrors) Is(target error) bool {
+	for _, ve := range vs {
+		if errors.Is(ve.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func (vs *NamedErrors) Add(ve NamedError) {
 	*vs = append(*vs, ve)
 }